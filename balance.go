@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/user/ageforge/balance"
+)
+
+// runBalance implements `ageforge balance`: balance tables generated from config
+func runBalance(args []string) int {
+	fs := flag.NewFlagSet("balance", flag.ContinueOnError)
+	format := fs.String("format", "md", "output format: md or csv")
+	out := fs.String("out", "", "write files into this directory instead of stdout")
+	ref := fs.Int("ref", balance.DefaultOptions().RefCount, "buildings of each type assumed owned for storage/income estimates")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ageforge balance [--format md|csv] [--out dir] [--ref n]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	opts := balance.DefaultOptions()
+	opts.RefCount = *ref
+	report := balance.Build(opts)
+
+	if *out != "" {
+		files, err := balance.WriteFiles(*out, *format, report)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		for _, f := range files {
+			fmt.Println(f)
+		}
+		return 0
+	}
+
+	var err error
+	switch *format {
+	case "md":
+		err = balance.WriteMarkdown(os.Stdout, report)
+	case "csv":
+		err = balance.WriteCSV(os.Stdout, report)
+	default:
+		err = fmt.Errorf("unknown format: %s (use md or csv)", *format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+package balance
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteMarkdown renders the whole report as a single Markdown document
+func WriteMarkdown(w io.Writer, r Report) error {
+	var sb strings.Builder
+	sb.WriteString("# AgeForge Balance Report\n\n")
+	fmt.Fprintf(&sb, "Reference count: %d of each building. Tick length: %.1fs.\n\n",
+		r.Options.RefCount, r.Options.TickSecs)
+
+	sb.WriteString("| Table | Rows | Flagged |\n|---|---|---|\n")
+	for _, t := range r.Tables {
+		fmt.Fprintf(&sb, "| %s | %d | %d |\n", t.Title, len(t.Rows), t.Flagged)
+	}
+	sb.WriteString("\n")
+
+	for _, t := range r.Tables {
+		fmt.Fprintf(&sb, "## %s\n\n%s\n\n", t.Title, t.Notes)
+		sb.WriteString("| " + strings.Join(t.Columns, " | ") + " |\n")
+		sb.WriteString("|" + strings.Repeat("---|", len(t.Columns)) + "\n")
+		for _, row := range t.Rows {
+			cells := make([]string, len(row))
+			copy(cells, row)
+			if last := len(cells) - 1; cells[last] != "" {
+				cells[last] = "**" + cells[last] + "**"
+			}
+			sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteCSV renders every table to one stream, each preceded by a "# title" line
+func WriteCSV(w io.Writer, r Report) error {
+	for i, t := range r.Tables {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# %s\n", t.Title); err != nil {
+			return err
+		}
+		if err := writeTableCSV(w, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFiles writes the report into dir: balance.md, or one <table>.csv per table
+func WriteFiles(dir, format string, r Report) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	var written []string
+	switch format {
+	case "md":
+		path := filepath.Join(dir, "balance.md")
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := WriteMarkdown(f, r); err != nil {
+			return nil, err
+		}
+		written = append(written, path)
+	case "csv":
+		for _, t := range r.Tables {
+			path := filepath.Join(dir, t.Key+".csv")
+			f, err := os.Create(path)
+			if err != nil {
+				return written, err
+			}
+			err = writeTableCSV(f, t)
+			f.Close()
+			if err != nil {
+				return written, err
+			}
+			written = append(written, path)
+		}
+	default:
+		return nil, fmt.Errorf("unknown format: %s (use md or csv)", format)
+	}
+	return written, nil
+}
+
+func writeTableCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
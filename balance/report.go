@@ -0,0 +1,478 @@
+package balance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/user/ageforge/config"
+	"github.com/user/ageforge/game"
+)
+
+// Options tunes the assumptions the report makes about a "typical" player
+type Options struct {
+	RefCount  int   // buildings of each type assumed owned when estimating storage/income
+	CurvePts  []int // building counts to sample on each cost curve
+	TickSecs  float64
+	MinRatio  float64 // age req / reachable storage below this is flagged as trivial
+	FailShare float64 // share of loot recovered on a failed expedition (mirrors MilitaryManager)
+}
+
+// DefaultOptions returns the assumptions used by `ageforge balance`
+func DefaultOptions() Options {
+	return Options{
+		RefCount:  10,
+		CurvePts:  []int{1, 5, 10, 25, 50},
+		TickSecs:  game.BaseTickInterval.Seconds(),
+		MinRatio:  0.01,
+		FailShare: 0.3,
+	}
+}
+
+// Table is one section of the balance report
+type Table struct {
+	Key     string // short slug, used as the CSV file name
+	Title   string
+	Notes   string
+	Columns []string
+	Rows    [][]string
+	Flagged int // number of rows with a non-empty Flag column
+}
+
+// Report is the full set of balance tables generated from config
+type Report struct {
+	Options Options
+	Tables  []Table
+}
+
+// Build generates every balance table from the current content definitions
+func Build(opts Options) Report {
+	if opts.RefCount <= 0 {
+		opts.RefCount = 1
+	}
+	if len(opts.CurvePts) == 0 {
+		opts.CurvePts = DefaultOptions().CurvePts
+	}
+	if opts.TickSecs <= 0 {
+		opts.TickSecs = game.BaseTickInterval.Seconds()
+	}
+
+	c := newContext()
+	return Report{
+		Options: opts,
+		Tables: []Table{
+			costCurves(c, opts),
+			productionPerCost(c, opts),
+			ageRequirements(c, opts),
+			techCosts(c, opts),
+			expeditionValue(c, opts),
+		},
+	}
+}
+
+// context caches config lookups shared by all tables
+type context struct {
+	ageOrder  map[string]int
+	ageKeys   []string
+	buildings []config.BuildingDef
+	resources map[string]config.ResourceDef
+}
+
+func newContext() *context {
+	c := &context{
+		ageOrder:  make(map[string]int),
+		ageKeys:   config.AgeOrder(),
+		buildings: config.BaseBuildings(),
+		resources: config.ResourceByKey(),
+	}
+	for i, k := range c.ageKeys {
+		c.ageOrder[k] = i
+	}
+	return c
+}
+
+// ownedCount caps the reference count by a building's MaxCount
+func ownedCount(b config.BuildingDef, ref int) int {
+	if b.MaxCount > 0 && b.MaxCount < ref {
+		return b.MaxCount
+	}
+	return ref
+}
+
+// reachableStorage estimates a resource's storage cap for a player who owns
+// RefCount of every storage building whose age order is <= maxOrder
+func (c *context) reachableStorage(resource string, maxOrder, ref int) float64 {
+	total := c.resources[resource].BaseStorage
+	for _, b := range c.buildings {
+		if c.ageOrder[b.RequiredAge] > maxOrder {
+			continue
+		}
+		n := float64(ownedCount(b, ref))
+		for _, e := range b.Effects {
+			if e.Type == "storage" && (e.Target == "all" || e.Target == resource) {
+				total += e.Value * n
+			}
+		}
+	}
+	return total
+}
+
+// productionIncome estimates per-tick production of a resource from RefCount
+// of every building whose age order is <= maxOrder
+func (c *context) productionIncome(resource string, maxOrder, ref int) float64 {
+	total := 0.0
+	for _, b := range c.buildings {
+		if c.ageOrder[b.RequiredAge] > maxOrder {
+			continue
+		}
+		n := float64(ownedCount(b, ref))
+		for _, e := range b.Effects {
+			if e.Type == "production" && e.Target == resource {
+				total += e.Value * n
+			}
+		}
+	}
+	return total
+}
+
+func sumCost(cost map[string]float64) float64 {
+	total := 0.0
+	for _, v := range cost {
+		total += v
+	}
+	return total
+}
+
+// costAt returns the total cost of the nth building (1-based), matching BuildingManager.GetCost
+func costAt(b config.BuildingDef, n int) float64 {
+	total := 0.0
+	for _, v := range b.BaseCost {
+		total += math.Floor(v * math.Pow(b.CostScale, float64(n-1)))
+	}
+	return total
+}
+
+func costCurves(c *context, opts Options) Table {
+	t := Table{
+		Key:   "cost_curves",
+		Title: "Building Cost Curves",
+		Notes: "Total cost (all resources summed) of the Nth copy, using BaseCost * CostScale^(N-1). " +
+			"CostScale is compared against other buildings in the same category.",
+		Columns: []string{"Building", "Age", "Category", "CostScale"},
+	}
+	for _, n := range opts.CurvePts {
+		t.Columns = append(t.Columns, fmt.Sprintf("#%d", n))
+	}
+	t.Columns = append(t.Columns, "Flag")
+
+	byCategory := make(map[string][]float64)
+	for _, b := range c.buildings {
+		if b.MaxCount == 1 {
+			continue
+		}
+		byCategory[b.Category] = append(byCategory[b.Category], b.CostScale)
+	}
+	fences := make(map[string][2]float64)
+	for cat, vals := range byCategory {
+		lo, hi := tukeyFences(vals)
+		fences[cat] = [2]float64{lo, hi}
+	}
+
+	for _, b := range c.buildings {
+		row := []string{b.Key, b.RequiredAge, b.Category, num(b.CostScale)}
+		for _, n := range opts.CurvePts {
+			if b.MaxCount > 0 && n > b.MaxCount {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, num(costAt(b, n)))
+		}
+
+		var flag string
+		switch {
+		case b.MaxCount == 1:
+			// single-copy buildings never walk the curve
+		case b.CostScale <= 1:
+			flag = "cost never increases"
+		default:
+			f := fences[b.Category]
+			if b.CostScale < f[0] {
+				flag = "scale low for category"
+			} else if b.CostScale > f[1] {
+				flag = "scale high for category"
+			}
+		}
+		t.addRow(row, flag)
+	}
+	return t
+}
+
+func productionPerCost(c *context, opts Options) Table {
+	t := Table{
+		Key:   "production_per_cost",
+		Title: "Production per Cost",
+		Notes: "Base production per tick of the first copy divided by its total cost. " +
+			"Compared on a log scale against other producers of the same age.",
+		Columns: []string{"Building", "Age", "Produces", "Per Tick", "Cost", "Per Tick / 1k Cost", "Flag"},
+	}
+
+	type entry struct {
+		def   config.BuildingDef
+		prod  float64
+		cost  float64
+		ratio float64
+	}
+	var entries []entry
+	byAge := make(map[string][]float64)
+	for _, b := range c.buildings {
+		prod := 0.0
+		for _, e := range b.Effects {
+			if e.Type == "production" {
+				prod += e.Value
+			}
+		}
+		cost := sumCost(b.BaseCost)
+		if prod <= 0 || cost <= 0 {
+			continue
+		}
+		ratio := prod / cost * 1000
+		entries = append(entries, entry{b, prod, cost, ratio})
+		byAge[b.RequiredAge] = append(byAge[b.RequiredAge], math.Log10(ratio))
+	}
+
+	for _, e := range entries {
+		var targets []string
+		for _, eff := range e.def.Effects {
+			if eff.Type == "production" {
+				targets = append(targets, eff.Target)
+			}
+		}
+		flag := ""
+		lo, hi := tukeyFences(byAge[e.def.RequiredAge])
+		if lr := math.Log10(e.ratio); lr < lo {
+			flag = "weak for age"
+		} else if lr > hi {
+			flag = "strong for age"
+		}
+		t.addRow([]string{
+			e.def.Key, e.def.RequiredAge, strings.Join(targets, "+"),
+			num(e.prod), num(e.cost), num(e.ratio),
+		}, flag)
+	}
+	return t
+}
+
+func ageRequirements(c *context, opts Options) Table {
+	t := Table{
+		Key:   "age_requirements",
+		Title: "Age Requirements vs Reachable Storage",
+		Notes: fmt.Sprintf("Resource requirement to enter each age against the storage cap of a player owning %d "+
+			"of every storage building from earlier ages. A ratio above 1 cannot be met.", opts.RefCount),
+		Columns: []string{"Age", "Resource", "Required", "Reachable Storage", "Ratio", "Flag"},
+	}
+
+	for i, a := range config.Ages() {
+		if len(a.ResourceReqs) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(a.ResourceReqs))
+		for k := range a.ResourceReqs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		total := 0.0
+		for _, res := range keys {
+			req := a.ResourceReqs[res]
+			total += req
+			// Requirements must be met while still in the previous age
+			reach := c.reachableStorage(res, i-1, opts.RefCount)
+			ratio := 0.0
+			if reach > 0 {
+				ratio = req / reach
+			}
+			flag := ""
+			switch {
+			case reach <= 0:
+				flag = "no storage for resource"
+			case ratio > 1:
+				flag = "exceeds reachable storage"
+			case ratio < opts.MinRatio:
+				flag = "trivial vs storage"
+			}
+			t.addRow([]string{a.Key, res, num(req), num(reach), num(ratio)}, flag)
+		}
+		t.addRow([]string{a.Key, "(total)", num(total), "", ""}, "")
+	}
+	return t
+}
+
+func techCosts(c *context, opts Options) Table {
+	t := Table{
+		Key:   "tech_costs",
+		Title: "Tech Cost vs Knowledge Income",
+		Notes: fmt.Sprintf("Knowledge income assumes %d of every knowledge-producing building available in the tech's age "+
+			"(no villagers or bonuses). Time to afford is compared on a log scale across all techs.", opts.RefCount),
+		Columns: []string{"Tech", "Age", "Cost", "Income/Tick", "Ticks to Afford", "Minutes", "Knowledge Cap", "Flag"},
+	}
+
+	techs := config.Technologies()
+	ticks := make([]float64, len(techs))
+	incomes := make([]float64, len(techs))
+	for i, tech := range techs {
+		incomes[i] = c.productionIncome("knowledge", c.ageOrder[tech.Age], opts.RefCount)
+		if incomes[i] > 0 {
+			ticks[i] = tech.Cost / incomes[i]
+		}
+	}
+	var logs []float64
+	for _, v := range ticks {
+		if v > 0 {
+			logs = append(logs, math.Log10(v))
+		}
+	}
+	lo, hi := tukeyFences(logs)
+
+	for i, tech := range techs {
+		knowCap := c.reachableStorage("knowledge", c.ageOrder[tech.Age], opts.RefCount)
+		flag := ""
+		switch {
+		case incomes[i] <= 0:
+			flag = "no knowledge income"
+		case tech.Cost > knowCap:
+			flag = "cost exceeds knowledge cap"
+		case math.Log10(ticks[i]) < lo:
+			flag = "cheap vs income"
+		case math.Log10(ticks[i]) > hi:
+			flag = "expensive vs income"
+		}
+		minutes := ticks[i] * opts.TickSecs / 60
+		t.addRow([]string{
+			tech.Key, tech.Age, num(tech.Cost), num(incomes[i]),
+			num(ticks[i]), num(minutes), num(knowCap),
+		}, flag)
+	}
+	return t
+}
+
+func expeditionValue(c *context, opts Options) Table {
+	t := Table{
+		Key:   "expedition_value",
+		Title: "Expedition Expected Value",
+		Notes: fmt.Sprintf("Battles are deterministic. Each expedition is fought by SoldiersNeeded of the newest unit "+
+			"type of its age with no military bonus; campaigns assume the player never retreats. A lost stage recovers "+
+			"%.1f of the haul so far plus that stage's loot. Loot per soldier-tick is compared on a log scale across "+
+			"all expeditions.", opts.FailShare),
+		Columns: []string{"Expedition", "Age", "Army", "Ticks", "Outcome", "Loot",
+			"Losses", "Loot / Soldier-Tick", "Flag"},
+	}
+
+	exps := config.Expeditions()
+	ageOrder := make(map[string]int)
+	for _, a := range config.Ages() {
+		ageOrder[a.Key] = a.Order
+	}
+
+	perST := make([]float64, len(exps))
+	loots := make([]float64, len(exps))
+	losses := make([]int, len(exps))
+	won := make([]bool, len(exps))
+	armies := make([]map[string]int, len(exps))
+	var logs []float64
+	for i, e := range exps {
+		armies[i] = referenceArmy(e, ageOrder)
+		loots[i], losses[i], won[i] = expeditionExpectation(e, armies[i], opts.FailShare)
+		perST[i] = loots[i] / float64(e.SoldiersNeeded*e.TotalDuration())
+		logs = append(logs, math.Log10(perST[i]))
+	}
+	lo, hi := tukeyFences(logs)
+
+	for i, e := range exps {
+		outcome, flag := "won", ""
+		if !won[i] {
+			outcome, flag = "lost", "unwinnable with age's units"
+		} else if l := math.Log10(perST[i]); l < lo {
+			flag = "poor loot per soldier"
+		} else if l > hi {
+			flag = "rich loot per soldier"
+		}
+		t.addRow([]string{
+			e.Key, e.MinAge, game.FormatUnits(armies[i]), fmt.Sprintf("%d", e.TotalDuration()),
+			outcome, num(loots[i]), fmt.Sprintf("%d", losses[i]), num(perST[i]),
+		}, flag)
+	}
+	return t
+}
+
+// referenceArmy returns SoldiersNeeded of the newest unit type reached by an
+// expedition's age
+func referenceArmy(e config.ExpeditionDef, ageOrder map[string]int) map[string]int {
+	best := ""
+	for _, u := range config.Units() {
+		if ageOrder[u.MinAge] <= ageOrder[e.MinAge] {
+			best = u.Key
+		}
+	}
+	return map[string]int{best: e.SoldiersNeeded}
+}
+
+// expeditionExpectation fights every stage of an expedition with an army and
+// returns the loot brought home, the soldiers lost and whether every stage was won
+func expeditionExpectation(e config.ExpeditionDef, army map[string]int, failShare float64) (loot float64, losses int, won bool) {
+	haul := 0.0
+	for _, s := range e.StageList() {
+		report := game.ResolveBattle(army, s.Enemy, 0, 0)
+		losses += game.UnitTotal(report.AttackerLosses)
+		army = report.AttackerLeft
+		stageLoot := sumCost(s.Rewards)
+		if !report.AttackerWon {
+			if game.UnitTotal(army) > 0 {
+				loot = failShare * (haul + stageLoot)
+			}
+			return loot, losses, false
+		}
+		haul += stageLoot
+	}
+	return haul, losses, true
+}
+
+func (t *Table) addRow(row []string, flag string) {
+	if flag != "" {
+		t.Flagged++
+	}
+	t.Rows = append(t.Rows, append(row, flag))
+}
+
+// tukeyFences returns the lower/upper outlier fences (Q1-1.5*IQR, Q3+1.5*IQR).
+// Fewer than 4 samples yields infinite fences so nothing is flagged.
+func tukeyFences(vals []float64) (float64, float64) {
+	if len(vals) < 4 {
+		return math.Inf(-1), math.Inf(1)
+	}
+	s := append([]float64(nil), vals...)
+	sort.Float64s(s)
+	q1 := quantile(s, 0.25)
+	q3 := quantile(s, 0.75)
+	iqr := q3 - q1
+	return q1 - 1.5*iqr, q3 + 1.5*iqr
+}
+
+// quantile linearly interpolates the q-th quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + (sorted[i+1]-sorted[i])*frac
+}
+
+// num formats a number compactly for both Markdown and CSV output
+func num(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e9 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.4g", v)
+}
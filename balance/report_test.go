@@ -0,0 +1,74 @@
+package balance
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/user/ageforge/config"
+)
+
+func TestBuild_TablesWellFormed(t *testing.T) {
+	r := Build(DefaultOptions())
+	if len(r.Tables) != 5 {
+		t.Fatalf("tables = %d, want 5", len(r.Tables))
+	}
+	for _, tbl := range r.Tables {
+		if len(tbl.Rows) == 0 {
+			t.Errorf("%s: no rows", tbl.Key)
+		}
+		flagged := 0
+		for i, row := range tbl.Rows {
+			if len(row) != len(tbl.Columns) {
+				t.Errorf("%s row %d: %d cells, want %d", tbl.Key, i, len(row), len(tbl.Columns))
+			}
+			if row[len(row)-1] != "" {
+				flagged++
+			}
+		}
+		if flagged != tbl.Flagged {
+			t.Errorf("%s: Flagged = %d, counted %d", tbl.Key, tbl.Flagged, flagged)
+		}
+	}
+}
+
+func TestCostAt_MatchesScaling(t *testing.T) {
+	hut := config.BuildingByKey()["hut"]
+	want := 0.0
+	for _, v := range hut.BaseCost {
+		want += math.Floor(v * math.Pow(hut.CostScale, 4))
+	}
+	if got := costAt(hut, 5); got != want {
+		t.Errorf("costAt(hut, 5) = %v, want %v", got, want)
+	}
+}
+
+func TestTukeyFences_FlagsOutlier(t *testing.T) {
+	vals := []float64{1, 1.1, 1.2, 1.15, 1.05, 5}
+	lo, hi := tukeyFences(vals)
+	if 5 <= hi {
+		t.Errorf("5 should be above upper fence %v", hi)
+	}
+	if 1 < lo {
+		t.Errorf("1 should not be below lower fence %v", lo)
+	}
+
+	lo, hi = tukeyFences([]float64{1, 100})
+	if !math.IsInf(lo, -1) || !math.IsInf(hi, 1) {
+		t.Error("fewer than 4 samples should never flag")
+	}
+}
+
+func TestWriteMarkdown_IncludesAllTables(t *testing.T) {
+	r := Build(DefaultOptions())
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, tbl := range r.Tables {
+		if !strings.Contains(buf.String(), "## "+tbl.Title) {
+			t.Errorf("markdown missing section %q", tbl.Title)
+		}
+	}
+}
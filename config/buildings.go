@@ -15,13 +15,21 @@ type BuildingDef struct {
 	BaseCost     map[string]float64
 	CostScale    float64 // each subsequent costs CostScale * previous
 	Effects      []Effect
-	BuildTicks   int    // 0 = instant
-	RequiredAge  string // minimum age key
-	RequiredTech string // required tech key (empty = none)
-	MaxCount     int    // 0 = unlimited
+	Inputs       map[string]float64 // per building per tick; production slows when short
+	PowerDemand  float64            // electricity drawn from the grid per building per tick
+	PowerPrio    int                // higher stays powered longer when the grid is short
+	Upkeep       map[string]float64 // per running building per tick, e.g. gold or electricity
+	BuildTicks   int                // 0 = instant
+	RequiredAge  string             // minimum age key
+	RequiredTech string             // required tech key (empty = none)
+	MaxCount     int                // 0 = unlimited
 	Description  string
 }
 
+// DemolishRefund is the share of a building's last-paid cost returned when
+// it is demolished
+const DemolishRefund = 0.5
+
 // BaseBuildings returns all building definitions
 // Cost scaling: each age's buildings cost ~5x the previous age
 func BaseBuildings() []BuildingDef {
@@ -191,18 +199,23 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"stone": 10000, "iron": 6000, "coal": 2000},
 			CostScale:   1.4,
 			Effects:     []Effect{{Type: "production", Target: "steel", Value: 0.1}},
+			Inputs:      map[string]float64{"iron": 0.1, "coal": 0.05},
 			BuildTicks:  12,
 			RequiredAge: "iron_age",
-			Description: "Forges steel from iron and coal. +0.1 steel/tick.",
+			Description: "Forges steel from iron and coal. Burns 0.1 iron + 0.05 coal for +0.1 steel/tick.",
 		},
 		{
 			Name: "Barracks", Key: "barracks", Category: "military",
-			BaseCost:    map[string]float64{"wood": 12000, "stone": 10000, "iron": 5000},
-			CostScale:   1.4,
-			Effects:     []Effect{{Type: "capacity", Target: "military", Value: 10}},
+			BaseCost:  map[string]float64{"wood": 12000, "stone": 10000, "iron": 5000},
+			CostScale: 1.4,
+			Effects: []Effect{
+				{Type: "capacity", Target: "military", Value: 10},
+				{Type: "capacity", Target: "expedition_slots", Value: 1},
+				{Type: "capacity", Target: "training_slots", Value: 1},
+			},
 			BuildTicks:  12,
 			RequiredAge: "iron_age",
-			Description: "Trains soldiers. +10 military cap.",
+			Description: "Trains soldiers. +10 military cap, +1 expedition slot (once per type), +1 training lane.",
 		},
 		{
 			Name: "Granary", Key: "granary", Category: "storage",
@@ -285,13 +298,17 @@ func BaseBuildings() []BuildingDef {
 		},
 		{
 			Name: "Castle", Key: "castle", Category: "military",
-			BaseCost:    map[string]float64{"stone": 360000, "iron": 120000, "gold": 60000},
-			CostScale:   1.5,
-			Effects:     []Effect{{Type: "capacity", Target: "military", Value: 25}},
+			BaseCost:  map[string]float64{"stone": 360000, "iron": 120000, "gold": 60000},
+			CostScale: 1.5,
+			Effects: []Effect{
+				{Type: "capacity", Target: "military", Value: 25},
+				{Type: "capacity", Target: "expedition_slots", Value: 1},
+				{Type: "capacity", Target: "training_slots", Value: 2},
+			},
 			BuildTicks:  20,
 			RequiredAge: "medieval_age",
 			MaxCount:    3,
-			Description: "Stronghold. +25 military cap. Max 3.",
+			Description: "Stronghold. +25 military cap, +1 expedition slot (once per type), +2 training lanes. Max 3.",
 		},
 		{
 			Name: "Keep", Key: "keep", Category: "storage",
@@ -426,8 +443,9 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 150e6, "coal": 100e6, "gold": 125e6},
 			CostScale:   1.45,
 			Effects:     []Effect{{Type: "production", Target: "electricity", Value: 0.8}},
+			Inputs:      map[string]float64{"coal": 0.3},
 			RequiredAge: "victorian_age",
-			Description: "Steam-powered electrical generation. +0.8 electricity/tick.",
+			Description: "Steam-powered electrical generation. Burns 0.3 coal for +0.8 electricity/tick.",
 		},
 		{
 			Name: "Telegraph", Key: "telegraph", Category: "research",
@@ -444,9 +462,10 @@ func BaseBuildings() []BuildingDef {
 			Effects: []Effect{
 				{Type: "bonus", Target: "production_all", Value: 0.05},
 			},
+			Upkeep:      map[string]float64{"gold": 0.2},
 			RequiredAge: "victorian_age",
 			MaxCount:    5,
-			Description: "Precision timekeeping boosts efficiency. +5% all production. Max 5.",
+			Description: "Precision timekeeping boosts efficiency. +5% all production. Max 5. Upkeep 0.2 gold/tick.",
 		},
 		{
 			Name: "Victorian Vault", Key: "victorian_vault", Category: "storage",
@@ -466,6 +485,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "steel", Value: 1.5},
 				{Type: "production", Target: "iron", Value: 3.0},
 			},
+			PowerDemand: 0.4,
+			PowerPrio:   2,
 			RequiredAge: "electric_age",
 			Description: "Electric-powered manufacturing. +1.5 steel, +3.0 iron/tick.",
 		},
@@ -474,6 +495,7 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 750e6, "electricity": 200e6, "gold": 500e6},
 			CostScale:   1.4,
 			Effects:     []Effect{{Type: "production", Target: "knowledge", Value: 2.0}},
+			PowerDemand: 0.2,
 			RequiredAge: "electric_age",
 			Description: "Connected communication network. +2.0 knowledge/tick.",
 		},
@@ -485,6 +507,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "gold", Value: 4.0},
 				{Type: "storage", Target: "all", Value: 100e6},
 			},
+			PowerDemand: 0.3,
+			PowerPrio:   1,
 			RequiredAge: "electric_age",
 			Description: "Rail transport hub. +4.0 gold/tick, +100M storage.",
 		},
@@ -493,8 +517,9 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 750e6, "electricity": 125e6, "iron": 500e6},
 			CostScale:   1.4,
 			Effects:     []Effect{{Type: "storage", Target: "all", Value: 3.5e9}},
+			Upkeep:      map[string]float64{"electricity": 0.1},
 			RequiredAge: "electric_age",
-			Description: "Climate-controlled storage. +3.5B storage.",
+			Description: "Climate-controlled storage. +3.5B storage. Upkeep 0.1 electricity/tick.",
 		},
 
 		// ===== ATOMIC AGE (costs: 3B-10B) =====
@@ -511,20 +536,26 @@ func BaseBuildings() []BuildingDef {
 		},
 		{
 			Name: "Bunker", Key: "bunker", Category: "military",
-			BaseCost:    map[string]float64{"steel": 6e9, "stone": 10e9, "iron": 4e9},
-			CostScale:   1.45,
-			Effects:     []Effect{{Type: "capacity", Target: "military", Value: 50}},
+			BaseCost:  map[string]float64{"steel": 6e9, "stone": 10e9, "iron": 4e9},
+			CostScale: 1.45,
+			Effects: []Effect{
+				{Type: "capacity", Target: "military", Value: 50},
+				{Type: "capacity", Target: "expedition_slots", Value: 1},
+				{Type: "capacity", Target: "training_slots", Value: 3},
+			},
+			Upkeep:      map[string]float64{"gold": 0.5},
 			RequiredAge: "atomic_age",
-			Description: "Fortified underground shelter. +50 military cap.",
+			Description: "Fortified underground shelter. +50 military cap, +1 expedition slot (once per type), +3 training lanes. Upkeep 0.5 gold/tick.",
 		},
 		{
 			Name: "Missile Silo", Key: "missile_silo", Category: "military",
 			BaseCost:    map[string]float64{"steel": 10e9, "uranium": 500e6, "gold": 7.5e9},
 			CostScale:   1.5,
 			Effects:     []Effect{{Type: "bonus", Target: "military_power", Value: 0.3}},
+			Upkeep:      map[string]float64{"gold": 1.0},
 			RequiredAge: "atomic_age",
 			MaxCount:    5,
-			Description: "Nuclear deterrent. +30% military power. Max 5.",
+			Description: "Nuclear deterrent. +30% military power. Max 5. Upkeep 1.0 gold/tick.",
 		},
 		{
 			Name: "Atomic Vault", Key: "atomic_vault", Category: "storage",
@@ -541,14 +572,16 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 30e9, "oil": 15e9, "gold": 40e9},
 			CostScale:   1.5,
 			Effects:     []Effect{{Type: "production", Target: "electricity", Value: 8.0}},
+			Inputs:      map[string]float64{"oil": 0.6},
 			RequiredAge: "modern_age",
-			Description: "Advanced power generation. +8.0 electricity/tick.",
+			Description: "Advanced power generation. Burns 0.6 oil for +8.0 electricity/tick.",
 		},
 		{
 			Name: "Research Lab", Key: "research_lab", Category: "research",
 			BaseCost:    map[string]float64{"steel": 25e9, "gold": 30e9, "electricity": 10e9},
 			CostScale:   1.5,
 			Effects:     []Effect{{Type: "production", Target: "knowledge", Value: 3.0}},
+			PowerDemand: 1.5,
 			RequiredAge: "modern_age",
 			Description: "Cutting-edge research. +3.0 knowledge/tick.",
 		},
@@ -557,8 +590,9 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 40e9, "gold": 25e9, "electricity": 8e9},
 			CostScale:   1.5,
 			Effects:     []Effect{{Type: "capacity", Target: "population", Value: 50}},
+			Upkeep:      map[string]float64{"electricity": 0.5},
 			RequiredAge: "modern_age",
-			Description: "Massive housing. +50 pop cap.",
+			Description: "Massive housing. +50 pop cap. Upkeep 0.5 electricity/tick.",
 		},
 		{
 			Name: "Modern Depot", Key: "modern_depot", Category: "storage",
@@ -578,6 +612,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "data", Value: 2.0},
 				{Type: "production", Target: "knowledge", Value: 2.0},
 			},
+			PowerDemand: 2,
+			PowerPrio:   1,
 			RequiredAge: "information_age",
 			Description: "Data processing center. +2.0 data, +2.0 knowledge/tick.",
 		},
@@ -586,6 +622,8 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 100e9, "gold": 75e9, "electricity": 40e9},
 			CostScale:   1.45,
 			Effects:     []Effect{{Type: "production", Target: "data", Value: 3.0}},
+			PowerDemand: 1.5,
+			PowerPrio:   1,
 			RequiredAge: "information_age",
 			Description: "High-speed network infrastructure. +3.0 data/tick.",
 		},
@@ -597,6 +635,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "culture", Value: 3.0},
 				{Type: "production", Target: "gold", Value: 5.0},
 			},
+			PowerDemand: 1,
+			PowerPrio:   1,
 			RequiredAge: "information_age",
 			Description: "Digital entertainment. +3.0 culture, +5.0 gold/tick.",
 		},
@@ -615,8 +655,10 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 750e9, "electricity": 400e9, "data": 10e9},
 			CostScale:   1.5,
 			Effects:     []Effect{{Type: "production", Target: "data", Value: 8.0}},
+			PowerDemand: 2,
+			PowerPrio:   1,
 			RequiredAge: "digital_age",
-			Description: "Massive data processing. +8.0 data/tick.",
+			Description: "Massive data processing. Draws 2.0 electricity for +8.0 data/tick.",
 		},
 		{
 			Name: "AI Lab", Key: "ai_lab", Category: "research",
@@ -626,6 +668,7 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "knowledge", Value: 6.0},
 				{Type: "production", Target: "data", Value: 3.0},
 			},
+			PowerDemand: 4,
 			RequiredAge: "digital_age",
 			Description: "Artificial intelligence research. +6.0 knowledge, +3.0 data/tick.",
 		},
@@ -658,6 +701,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "bonus", Target: "gather_rate", Value: 0.1},
 				{Type: "production", Target: "crypto", Value: 1.0},
 			},
+			PowerDemand: 2,
+			PowerPrio:   1,
 			RequiredAge: "cyberpunk_age",
 			Description: "Cybernetic enhancements. +10% gather rate, +1.0 crypto/tick.",
 		},
@@ -666,8 +711,9 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"steel": 3.6e12, "electricity": 2e12, "gold": 2.4e12},
 			CostScale:   1.5,
 			Effects:     []Effect{{Type: "capacity", Target: "population", Value: 100}},
+			Upkeep:      map[string]float64{"electricity": 1.0},
 			RequiredAge: "cyberpunk_age",
-			Description: "Towering arcology. +100 pop cap.",
+			Description: "Towering arcology. +100 pop cap. Upkeep 1.0 electricity/tick.",
 		},
 		{
 			Name: "Black Market", Key: "black_market", Category: "production",
@@ -677,6 +723,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "crypto", Value: 3.0},
 				{Type: "production", Target: "gold", Value: 10.0},
 			},
+			PowerDemand: 3,
+			PowerPrio:   1,
 			RequiredAge: "cyberpunk_age",
 			Description: "Underground economy. +3.0 crypto, +10.0 gold/tick.",
 		},
@@ -709,6 +757,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "steel", Value: 10.0},
 				{Type: "production", Target: "plasma", Value: 1.5},
 			},
+			PowerDemand: 10,
+			PowerPrio:   2,
 			RequiredAge: "fusion_age",
 			Description: "Plasma-based manufacturing. +10.0 steel, +1.5 plasma/tick.",
 		},
@@ -720,6 +770,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "gold", Value: 20.0},
 				{Type: "bonus", Target: "production_all", Value: 0.05},
 			},
+			PowerDemand: 6,
+			PowerPrio:   2,
 			RequiredAge: "fusion_age",
 			Description: "Magnetic levitation transport. +20.0 gold/tick, +5% all production.",
 		},
@@ -741,6 +793,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "titanium", Value: 2.0},
 				{Type: "production", Target: "knowledge", Value: 12.0},
 			},
+			PowerDemand: 15,
+			PowerPrio:   2,
 			RequiredAge: "space_age",
 			Description: "Orbital launch facility. +2.0 titanium, +12.0 knowledge/tick.",
 		},
@@ -752,6 +806,7 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "knowledge", Value: 20.0},
 				{Type: "production", Target: "data", Value: 20.0},
 			},
+			PowerDemand: 20,
 			RequiredAge: "space_age",
 			Description: "Orbital research platform. +20.0 knowledge, +20.0 data/tick.",
 		},
@@ -760,8 +815,9 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"titanium": 6e12, "steel": 60e12, "plasma": 4e12},
 			CostScale:   1.5,
 			Effects:     []Effect{{Type: "capacity", Target: "population", Value: 200}},
+			Upkeep:      map[string]float64{"electricity": 2.0},
 			RequiredAge: "space_age",
-			Description: "Space habitat ring. +200 pop cap.",
+			Description: "Space habitat ring. +200 pop cap. Upkeep 2.0 electricity/tick.",
 		},
 		{
 			Name: "Orbital Depot", Key: "orbital_depot", Category: "storage",
@@ -781,6 +837,8 @@ func BaseBuildings() []BuildingDef {
 				{Type: "production", Target: "dark_matter", Value: 2.0},
 				{Type: "bonus", Target: "production_all", Value: 0.08},
 			},
+			PowerDemand: 40,
+			PowerPrio:   2,
 			RequiredAge: "interstellar_age",
 			Description: "Faster-than-light gate. +2.0 dark matter/tick, +8% all production.",
 		},
@@ -833,6 +891,8 @@ func BaseBuildings() []BuildingDef {
 			BaseCost:    map[string]float64{"dark_matter": 200e12, "plasma": 1e15, "electricity": 5e15},
 			CostScale:   1.55,
 			Effects:     []Effect{{Type: "production", Target: "antimatter", Value: 3.0}},
+			PowerDemand: 60,
+			PowerPrio:   2,
 			RequiredAge: "galactic_age",
 			Description: "Produces antimatter from dark energy. +3.0 antimatter/tick.",
 		},
@@ -0,0 +1,108 @@
+package config
+
+// FactionRequestDef defines a demand a faction can make of the player.
+// Tribute and resource requests ask for a share of the current stockpile;
+// military aid asks for an expedition to be won before the deadline.
+type FactionRequestDef struct {
+	Name        string
+	Key         string
+	Kind        string  // "tribute" (gold), "resources" (the faction's Wants) or "military_aid"
+	Share       float64 // of the stockpile demanded by tribute and resource requests
+	MinAmount   float64 // smallest delivery demanded
+	Deadline    int     // ticks to comply
+	Reward      int     // opinion gained when the request is met
+	Penalty     int     // opinion lost when it is refused or lapses
+	Description string
+}
+
+// TreatyDef defines a timed treaty a faction can propose. Forbids lists the
+// obligations the player takes on; doing any of them breaks the treaty.
+type TreatyDef struct {
+	Name         string
+	Key          string
+	Duration     int // ticks the treaty lasts once signed
+	MinOpinion   int // proposed only while opinion is in [MinOpinion, MaxOpinion]
+	MaxOpinion   int
+	Forbids      []string // "rival" or "embargo" against the faction, "ally_other" for allying anyone else
+	NoRaids      bool     // the faction never counts as hostile while it holds
+	TradeBonus   float64  // extra bonus on the faction's specialty while it holds
+	Effects      []Effect // bonuses while it holds
+	SignReward   int      // opinion gained on signing
+	KeepReward   int      // opinion gained when it runs its full term
+	BreakPenalty int      // opinion lost when the player breaks it
+	Description  string
+}
+
+// FactionRequests returns all faction request definitions
+func FactionRequests() []FactionRequestDef {
+	return []FactionRequestDef{
+		{
+			Name: "Tribute", Key: "tribute", Kind: "tribute",
+			Share: 0.10, MinAmount: 100, Deadline: 60,
+			Reward: 10, Penalty: 15,
+			Description: "A payment of gold to show good faith.",
+		},
+		{
+			Name: "Supply Request", Key: "supplies", Kind: "resources",
+			Share: 0.15, MinAmount: 50, Deadline: 80,
+			Reward: 12, Penalty: 10,
+			Description: "A shipment of the resource the faction is short of.",
+		},
+		{
+			Name: "Military Aid", Key: "military_aid", Kind: "military_aid",
+			Deadline: 250,
+			Reward:   20, Penalty: 10,
+			Description: "Win an expedition on the faction's behalf.",
+		},
+	}
+}
+
+// FactionRequestByKey returns faction requests keyed by request key
+func FactionRequestByKey() map[string]FactionRequestDef {
+	out := make(map[string]FactionRequestDef)
+	for _, def := range FactionRequests() {
+		out[def.Key] = def
+	}
+	return out
+}
+
+// Treaties returns all treaty definitions
+func Treaties() []TreatyDef {
+	return []TreatyDef{
+		{
+			Name: "Non-Aggression Pact", Key: "non_aggression",
+			Duration: 300, MinOpinion: -100, MaxOpinion: 10,
+			Forbids:    []string{"rival", "embargo"},
+			NoRaids:    true,
+			SignReward: 5, KeepReward: 10, BreakPenalty: 30,
+			Description: "The faction stops raiding you; you keep from declaring rivalry or an embargo.",
+		},
+		{
+			Name: "Exclusive Trade", Key: "exclusive_trade",
+			Duration: 400, MinOpinion: 25, MaxOpinion: 100,
+			Forbids:    []string{"rival", "embargo", "ally_other"},
+			TradeBonus: 0.15,
+			SignReward: 5, KeepReward: 15, BreakPenalty: 35,
+			Description: "Better terms on the faction's specialty; you ally with no one else.",
+		},
+		{
+			Name: "Research Sharing", Key: "research_sharing",
+			Duration: 300, MinOpinion: 10, MaxOpinion: 100,
+			Forbids: []string{"rival", "embargo"},
+			Effects: []Effect{
+				{Type: "bonus", Target: "knowledge_rate", Value: 0.15},
+			},
+			SignReward: 5, KeepReward: 10, BreakPenalty: 25,
+			Description: "Scholars trade findings; you keep relations open.",
+		},
+	}
+}
+
+// TreatyByKey returns treaties keyed by treaty key
+func TreatyByKey() map[string]TreatyDef {
+	out := make(map[string]TreatyDef)
+	for _, def := range Treaties() {
+		out[def.Key] = def
+	}
+	return out
+}
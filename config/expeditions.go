@@ -0,0 +1,259 @@
+package config
+
+// ExpeditionStageDef defines one stage of a multi-stage campaign
+type ExpeditionStageDef struct {
+	Name        string
+	Duration    int            // ticks
+	Enemy       map[string]int // defending army, keyed by unit
+	Rewards     map[string]float64
+	Description string
+}
+
+// ExpeditionDef defines a military expedition. Each expedition is a battle
+// against its Enemy army. Expeditions with Stages are campaigns: each stage
+// is fought in turn, and the player may retreat with the loot gathered so far
+// between stages.
+type ExpeditionDef struct {
+	Name           string
+	Key            string
+	MinAge         string
+	SoldiersNeeded int
+	Duration       int            // ticks (single-stage expeditions only)
+	Enemy          map[string]int // defending army (single-stage only)
+	Rewards        map[string]float64
+	Description    string
+	Stages         []ExpeditionStageDef
+	// Granted once when every stage of a campaign is won
+	DiscoverFaction string // faction key discovered ahead of its age
+	UnlockTech      string // campaign-only tech key
+}
+
+// IsCampaign returns whether the expedition has multiple stages
+func (d ExpeditionDef) IsCampaign() bool {
+	return len(d.Stages) > 0
+}
+
+// StageList returns the expedition's stages. A single-stage expedition is
+// returned as one stage built from its own duration, enemy and rewards.
+func (d ExpeditionDef) StageList() []ExpeditionStageDef {
+	if d.IsCampaign() {
+		return d.Stages
+	}
+	return []ExpeditionStageDef{{
+		Name:        d.Name,
+		Duration:    d.Duration,
+		Enemy:       d.Enemy,
+		Rewards:     d.Rewards,
+		Description: d.Description,
+	}}
+}
+
+// TotalDuration returns the ticks needed to finish every stage
+func (d ExpeditionDef) TotalDuration() int {
+	total := 0
+	for _, s := range d.StageList() {
+		total += s.Duration
+	}
+	return total
+}
+
+// Expeditions returns all expedition and campaign definitions
+func Expeditions() []ExpeditionDef {
+	return []ExpeditionDef{
+		{
+			Name: "Scout Nearby Ruins", Key: "scout_ruins",
+			MinAge: "bronze_age", SoldiersNeeded: 2, Duration: 10,
+			Enemy:       map[string]int{"infantry": 1},
+			Rewards:     map[string]float64{"food": 30, "wood": 20, "stone": 15},
+			Description: "Send scouts to explore nearby ruins for resources.",
+		},
+		{
+			Name: "Raid Bandit Camp", Key: "raid_bandits",
+			MinAge: "bronze_age", SoldiersNeeded: 5, Duration: 15,
+			Enemy:       map[string]int{"infantry": 4},
+			Rewards:     map[string]float64{"gold": 30, "iron": 15, "food": 20},
+			Description: "Attack a bandit encampment and seize their loot.",
+		},
+		{
+			Name: "Trade Escort", Key: "trade_escort",
+			MinAge: "iron_age", SoldiersNeeded: 3, Duration: 12,
+			Enemy:       map[string]int{"infantry": 1, "archers": 1},
+			Rewards:     map[string]float64{"gold": 50, "knowledge": 10},
+			Description: "Escort merchants on a dangerous trade route.",
+		},
+		{
+			Name: "Conquer Territory", Key: "conquer_territory",
+			MinAge: "iron_age", SoldiersNeeded: 10, Duration: 25,
+			Enemy:       map[string]int{"infantry": 2, "archers": 4},
+			Rewards:     map[string]float64{"gold": 80, "iron": 40, "food": 50},
+			Description: "Conquer a neighboring territory for its resources.",
+		},
+		{
+			Name: "Siege Enemy Castle", Key: "siege_castle",
+			MinAge: "medieval_age", SoldiersNeeded: 15, Duration: 30,
+			Enemy:       map[string]int{"infantry": 4, "archers": 6},
+			Rewards:     map[string]float64{"gold": 150, "steel": 30, "faith": 20},
+			Description: "Lay siege to an enemy stronghold.",
+		},
+		{
+			Name: "Naval Expedition", Key: "naval_expedition",
+			MinAge: "renaissance_age", SoldiersNeeded: 10, Duration: 35,
+			Enemy:       map[string]int{"archers": 3, "cavalry": 5},
+			Rewards:     map[string]float64{"gold": 200, "culture": 30, "knowledge": 40},
+			Description: "Explore distant lands by sea.",
+		},
+		{
+			Name: "Colonial Campaign", Key: "colonial_campaign",
+			MinAge: "industrial_age", SoldiersNeeded: 20, Duration: 40,
+			Enemy:       map[string]int{"archers": 6, "cavalry": 9},
+			Rewards:     map[string]float64{"gold": 300, "oil": 50, "steel": 40},
+			Description: "Establish colonial presence in new territories.",
+		},
+		{
+			Name: "World Domination", Key: "world_domination",
+			MinAge: "modern_age", SoldiersNeeded: 50, Duration: 60,
+			Enemy:       map[string]int{"cavalry": 15, "musketeers": 23},
+			Rewards:     map[string]float64{"gold": 1000, "electricity": 200, "knowledge": 500},
+			Description: "Launch a global military campaign for world domination.",
+		},
+		{
+			Name: "Cyber Raid", Key: "cyber_raid",
+			MinAge: "information_age", SoldiersNeeded: 30, Duration: 45,
+			Enemy:       map[string]int{"cavalry": 9, "musketeers": 13},
+			Rewards:     map[string]float64{"data": 200, "crypto": 50, "gold": 500},
+			Description: "Hack into enemy networks and steal digital assets.",
+		},
+		{
+			Name: "Neon Heist", Key: "neon_heist",
+			MinAge: "cyberpunk_age", SoldiersNeeded: 25, Duration: 35,
+			Enemy:       map[string]int{"musketeers": 8, "mechanised": 12},
+			Rewards:     map[string]float64{"crypto": 100, "data": 150, "gold": 800},
+			Description: "Pull off a daring heist in the neon-lit underworld.",
+		},
+		{
+			Name: "Fusion Plant Assault", Key: "fusion_assault",
+			MinAge: "fusion_age", SoldiersNeeded: 35, Duration: 40,
+			Enemy:       map[string]int{"musketeers": 12, "mechanised": 17},
+			Rewards:     map[string]float64{"plasma": 120, "electricity": 500, "uranium": 50},
+			Description: "Capture a rival's fusion power facility.",
+		},
+		{
+			Name: "Orbital Strike", Key: "orbital_strike",
+			MinAge: "space_age", SoldiersNeeded: 40, Duration: 50,
+			Enemy:       map[string]int{"musketeers": 14, "mechanised": 20},
+			Rewards:     map[string]float64{"titanium": 100, "plasma": 80, "knowledge": 300},
+			Description: "Deploy orbital weapons platform against hostile targets.",
+		},
+		{
+			Name: "Warp Invasion", Key: "warp_invasion",
+			MinAge: "interstellar_age", SoldiersNeeded: 60, Duration: 65,
+			Enemy:       map[string]int{"musketeers": 21, "mechanised": 31},
+			Rewards:     map[string]float64{"dark_matter": 50, "titanium": 200, "gold": 2000},
+			Description: "Invade a neighboring star system through warp gates.",
+		},
+		{
+			Name: "Galactic Conquest", Key: "galactic_conquest",
+			MinAge: "galactic_age", SoldiersNeeded: 80, Duration: 80,
+			Enemy:       map[string]int{"musketeers": 28, "mechanised": 43},
+			Rewards:     map[string]float64{"antimatter": 30, "dark_matter": 100, "gold": 5000},
+			Description: "Conquer an entire galactic sector.",
+		},
+		{
+			Name: "Quantum Incursion", Key: "quantum_incursion",
+			MinAge: "quantum_age", SoldiersNeeded: 100, Duration: 90,
+			Enemy:       map[string]int{"musketeers": 36, "mechanised": 53},
+			Rewards:     map[string]float64{"quantum_flux": 20, "antimatter": 50, "knowledge": 5000},
+			Description: "Launch an incursion across quantum realities.",
+		},
+
+		// === CAMPAIGNS ===
+		{
+			Name: "Frontier Campaign", Key: "frontier_campaign",
+			MinAge: "medieval_age", SoldiersNeeded: 12,
+			Description: "Push the frontier outward, one fortified valley at a time.",
+			Stages: []ExpeditionStageDef{
+				{
+					Name: "March to the Frontier", Duration: 10,
+					Enemy:       map[string]int{"infantry": 3, "archers": 4},
+					Rewards:     map[string]float64{"food": 40, "wood": 40},
+					Description: "Forage and clear a road through the borderlands.",
+				},
+				{
+					Name: "Storm the Outposts", Duration: 15,
+					Enemy:       map[string]int{"infantry": 3, "archers": 5},
+					Rewards:     map[string]float64{"gold": 60, "iron": 30},
+					Description: "Take the watchtowers guarding the valley.",
+				},
+				{
+					Name: "Hold the Pass", Duration: 15,
+					Enemy:       map[string]int{"infantry": 4, "archers": 5},
+					Rewards:     map[string]float64{"gold": 100, "steel": 25},
+					Description: "Beat back the counterattack and hold the mountain pass.",
+				},
+			},
+			UnlockTech: "frontier_doctrine",
+		},
+		{
+			Name: "Spice Road Campaign", Key: "spice_road",
+			MinAge: "renaissance_age", SoldiersNeeded: 10,
+			Description: "Open an overland road to the distant trading cities.",
+			Stages: []ExpeditionStageDef{
+				{
+					Name: "Chart the Road", Duration: 12,
+					Enemy:       map[string]int{"archers": 2, "cavalry": 4},
+					Rewards:     map[string]float64{"knowledge": 40, "food": 50},
+					Description: "Map the caravan trails across the desert.",
+				},
+				{
+					Name: "Clear the Brigands", Duration: 15,
+					Enemy:       map[string]int{"archers": 3, "cavalry": 5},
+					Rewards:     map[string]float64{"gold": 120, "iron": 30},
+					Description: "Drive off the brigands preying on the caravans.",
+				},
+				{
+					Name: "Reach the Guildhall", Duration: 12,
+					Enemy:       map[string]int{"archers": 3, "cavalry": 4},
+					Rewards:     map[string]float64{"gold": 200, "culture": 30},
+					Description: "Present your envoys at the great guildhall.",
+				},
+			},
+			DiscoverFaction: "merchant_guild",
+		},
+		{
+			Name: "Rift Survey Campaign", Key: "rift_survey",
+			MinAge: "space_age", SoldiersNeeded: 45,
+			Description: "Chart a tear in spacetime and whatever waits beyond it.",
+			Stages: []ExpeditionStageDef{
+				{
+					Name: "Approach the Rift", Duration: 20,
+					Enemy:       map[string]int{"musketeers": 13, "mechanised": 19},
+					Rewards:     map[string]float64{"titanium": 80, "knowledge": 200},
+					Description: "Fly survey drones through the rift's outer bands.",
+				},
+				{
+					Name: "Secure the Anchor", Duration: 25,
+					Enemy:       map[string]int{"musketeers": 10, "mechanised": 14},
+					Rewards:     map[string]float64{"plasma": 100, "titanium": 60},
+					Description: "Land troops and secure the rift's anchor point.",
+				},
+				{
+					Name: "Cross Over", Duration: 25,
+					Enemy:       map[string]int{"musketeers": 8, "mechanised": 12},
+					Rewards:     map[string]float64{"titanium": 150, "knowledge": 500},
+					Description: "Send a company through to make first contact.",
+				},
+			},
+			DiscoverFaction: "quantum_collective",
+			UnlockTech:      "rift_harmonics",
+		},
+	}
+}
+
+// ExpeditionByKey returns expeditions keyed by expedition key
+func ExpeditionByKey() map[string]ExpeditionDef {
+	out := make(map[string]ExpeditionDef)
+	for _, def := range Expeditions() {
+		out[def.Key] = def
+	}
+	return out
+}
@@ -9,7 +9,8 @@ type TechDef struct {
 	Prerequisites []string // tech keys that must be researched first
 	Effects       []Effect
 	Description   string
-	ResearchTicks int // how many ticks to complete (0 = instant)
+	ResearchTicks int    // how many ticks to complete (0 = instant)
+	Campaign      string // expedition key that grants this tech; it can't be researched
 }
 
 // Technologies returns all tech tree definitions
@@ -299,6 +300,7 @@ func Technologies() []TechDef {
 			Description: "Detailed maps enable global exploration.",
 			Effects: []Effect{
 				{Type: "bonus", Target: "expedition_reward", Value: 0.5},
+				{Type: "bonus", Target: "expedition_slots", Value: 1},
 				{Type: "bonus", Target: "gold_rate", Value: 0.5},
 			},
 		},
@@ -625,6 +627,7 @@ func Technologies() []TechDef {
 			Effects: []Effect{
 				{Type: "production", Target: "titanium", Value: 1.0},
 				{Type: "bonus", Target: "expedition_reward", Value: 1.0},
+				{Type: "bonus", Target: "expedition_slots", Value: 1},
 			},
 		},
 		{
@@ -734,6 +737,26 @@ func Technologies() []TechDef {
 				{Type: "production", Target: "quantum_flux", Value: 10.0},
 			},
 		},
+
+		// === CAMPAIGN TECHS === (granted by finishing a campaign)
+		{
+			Name: "Frontier Doctrine", Key: "frontier_doctrine",
+			Age: "medieval_age", Campaign: "frontier_campaign",
+			Description: "Veterans of the frontier drill every recruit in their hard-won tactics.",
+			Effects: []Effect{
+				{Type: "bonus", Target: "military_power", Value: 0.2},
+				{Type: "bonus", Target: "expedition_reward", Value: 0.1},
+			},
+		},
+		{
+			Name: "Rift Harmonics", Key: "rift_harmonics",
+			Age: "space_age", Campaign: "rift_survey",
+			Description: "Readings from beyond the rift upend half of known physics.",
+			Effects: []Effect{
+				{Type: "bonus", Target: "research_speed", Value: 0.1},
+				{Type: "production", Target: "titanium", Value: 0.5},
+			},
+		},
 	}
 }
 
@@ -0,0 +1,114 @@
+package config
+
+// Terrain types of settlement tiles
+const (
+	TerrainGrass  = "grass"
+	TerrainWater  = "water"
+	TerrainForest = "forest"
+	TerrainHill   = "hill"
+	TerrainDesert = "desert"
+)
+
+// Terrains returns every terrain type a tile can have
+func Terrains() []string {
+	return []string{TerrainGrass, TerrainWater, TerrainForest, TerrainHill, TerrainDesert}
+}
+
+// BiomeRadius is how far from the town centre, in tiles, the land counts
+// towards a world's biome mix
+const BiomeRadius = 16
+
+// BiomeDef is what a kind of land means for the whole settlement. Its
+// bonuses and event weights scale with the terrain's share of the land
+// within BiomeRadius, so a world that is 30% forest gets 30% of them.
+type BiomeDef struct {
+	Terrain     string
+	Name        string
+	Bonuses     map[string]float64 // resource -> production bonus at full coverage
+	Events      map[string]float64 // event key -> extra weight at full coverage (−1 halves it at 50%)
+	Only        []string           // events that can only fire in worlds with this terrain
+	Description string
+}
+
+// Biomes returns the biome of every terrain type
+func Biomes() []BiomeDef {
+	return []BiomeDef{
+		{
+			Terrain: TerrainGrass, Name: "Plains",
+			Bonuses:     map[string]float64{"food": 0.3},
+			Events:      map[string]float64{"bountiful_harvest": 1},
+			Description: "Open grassland for grazing and fields",
+		},
+		{
+			Terrain: TerrainWater, Name: "River",
+			Bonuses:     map[string]float64{"food": 2.0, "gold": 1.0},
+			Events:      map[string]float64{"storm": 2, "plague": 2, "drought": -4},
+			Only:        []string{"pirate_attack"},
+			Description: "Fish, irrigation and a trade route, but floods and fevers",
+		},
+		{
+			Terrain: TerrainForest, Name: "Forest",
+			Bonuses:     map[string]float64{"wood": 1.0},
+			Events:      map[string]float64{"bandit_raid": 2},
+			Description: "Timber on the doorstep and cover for bandits",
+		},
+		{
+			Terrain: TerrainHill, Name: "Mountains",
+			Bonuses:     map[string]float64{"stone": 1.0, "iron": 0.6, "gold": 0.2},
+			Events:      map[string]float64{"earthquake": 3, "gold_rush": 2},
+			Only:        []string{"mine_collapse"},
+			Description: "Stone and ore close to the surface, on shaky ground",
+		},
+		{
+			Terrain: TerrainDesert, Name: "Desert",
+			Bonuses:     map[string]float64{"food": -0.5, "oil": 1.5, "stone": 0.3},
+			Events:      map[string]float64{"drought": 4, "storm": 1},
+			Description: "Poor farmland over rich oil fields",
+		},
+	}
+}
+
+// BiomeByTerrain returns the biome of each terrain type
+func BiomeByTerrain() map[string]BiomeDef {
+	m := make(map[string]BiomeDef)
+	for _, b := range Biomes() {
+		m[b.Terrain] = b
+	}
+	return m
+}
+
+// MaxHousingNeighbors caps how many of the eight tiles around a housing
+// building may also hold housing, so homes can't be packed solid
+const MaxHousingNeighbors = 4
+
+// AdjacencyDef is a placement bonus: each instance of Building placed next
+// to Terrain produces Bonus more of what it already produces
+type AdjacencyDef struct {
+	Building    string
+	Terrain     string
+	Bonus       float64 // share of the building's base production
+	Description string
+}
+
+// AdjacencyRules returns all placement bonuses
+func AdjacencyRules() []AdjacencyDef {
+	return []AdjacencyDef{
+		{Building: "farm", Terrain: TerrainWater, Bonus: 0.25, Description: "Farms by water are irrigated"},
+		{Building: "woodcutter_camp", Terrain: TerrainForest, Bonus: 0.25, Description: "Woodcutters by a forest walk less"},
+		{Building: "lumber_mill", Terrain: TerrainForest, Bonus: 0.25, Description: "Mills by a forest haul less timber"},
+		{Building: "stone_pit", Terrain: TerrainHill, Bonus: 0.25, Description: "Stone pits dug into a hillside"},
+		{Building: "quarry", Terrain: TerrainHill, Bonus: 0.25, Description: "Quarries cut into a hillside"},
+		{Building: "mine", Terrain: TerrainHill, Bonus: 0.20, Description: "Mines sunk into a hill reach richer seams"},
+		{Building: "port", Terrain: TerrainWater, Bonus: 0.20, Description: "Ports need a waterfront"},
+		{Building: "plantation", Terrain: TerrainWater, Bonus: 0.15, Description: "Plantations by water are irrigated"},
+	}
+}
+
+// AdjacencyByBuilding returns the placement bonus for each building that has one
+func AdjacencyByBuilding() map[string]AdjacencyDef {
+	m := make(map[string]AdjacencyDef)
+	for _, r := range AdjacencyRules() {
+		m[r.Building] = r
+	}
+	return m
+}
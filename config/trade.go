@@ -15,6 +15,7 @@ type TradeRouteDef struct {
 	MinAge      string
 	RequiredBld string             // building key required (market, port, etc.)
 	MinCount    int                // minimum building count needed
+	Partner     string             // faction key the route trades with, "" for local routes
 	TicksPerRun int                // ticks per trade cycle
 	Export      map[string]float64 // resources consumed per cycle
 	Import      map[string]float64 // resources gained per cycle
@@ -26,8 +27,12 @@ type FactionDef struct {
 	Name        string
 	Key         string
 	MinAge      string
-	Specialty   string  // resource key they're good at
-	TradeBonus  float64 // fractional bonus on trades with them when allied
+	Specialty   string             // resource key they're good at
+	TradeBonus  float64            // fractional bonus on trades with them when allied
+	Wants       string             // resource key they ask for in supply requests
+	Produces    map[string]float64 // per tick, into the faction's stockpile
+	Consumes    map[string]float64 // per tick, out of the faction's stockpile
+	Allies      []string           // faction keys that may join its wars
 	Description string
 }
 
@@ -128,7 +133,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Spice Trade", Key: "spice_trade",
-			MinAge: "colonial_age", RequiredBld: "port", MinCount: 1,
+			MinAge: "colonial_age", RequiredBld: "port", MinCount: 1, Partner: "merchant_guild",
 			TicksPerRun: 18,
 			Export:       map[string]float64{"gold": 100},
 			Import:       map[string]float64{"food": 200, "culture": 50},
@@ -136,7 +141,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Colonial Exports", Key: "colonial_exports",
-			MinAge: "colonial_age", RequiredBld: "port", MinCount: 2,
+			MinAge: "colonial_age", RequiredBld: "port", MinCount: 2, Partner: "merchant_guild",
 			TicksPerRun: 15,
 			Export:       map[string]float64{"food": 500},
 			Import:       map[string]float64{"gold": 150},
@@ -144,7 +149,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Rail Freight", Key: "rail_freight",
-			MinAge: "industrial_age", RequiredBld: "train_station", MinCount: 1,
+			MinAge: "industrial_age", RequiredBld: "train_station", MinCount: 1, Partner: "artisan_league",
 			TicksPerRun: 12,
 			Export:       map[string]float64{"iron": 200},
 			Import:       map[string]float64{"gold": 100, "coal": 50},
@@ -152,7 +157,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Oil Pipeline", Key: "oil_pipeline",
-			MinAge: "victorian_age", RequiredBld: "oil_well", MinCount: 2,
+			MinAge: "victorian_age", RequiredBld: "oil_well", MinCount: 2, Partner: "artisan_league",
 			TicksPerRun: 15,
 			Export:       map[string]float64{"oil": 100},
 			Import:       map[string]float64{"gold": 300},
@@ -160,7 +165,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Power Exchange", Key: "power_exchange",
-			MinAge: "electric_age", RequiredBld: "power_grid", MinCount: 1,
+			MinAge: "electric_age", RequiredBld: "power_grid", MinCount: 1, Partner: "artisan_league",
 			TicksPerRun: 10,
 			Export:       map[string]float64{"electricity": 500},
 			Import:       map[string]float64{"gold": 200},
@@ -168,7 +173,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Data Trade", Key: "data_trade",
-			MinAge: "information_age", RequiredBld: "fiber_hub", MinCount: 1,
+			MinAge: "information_age", RequiredBld: "fiber_hub", MinCount: 1, Partner: "tech_consortium",
 			TicksPerRun: 10,
 			Export:       map[string]float64{"data": 100},
 			Import:       map[string]float64{"gold": 500},
@@ -176,7 +181,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Crypto Market", Key: "crypto_market",
-			MinAge: "cyberpunk_age", RequiredBld: "black_market", MinCount: 1,
+			MinAge: "cyberpunk_age", RequiredBld: "black_market", MinCount: 1, Partner: "shadow_syndicate",
 			TicksPerRun: 8,
 			Export:       map[string]float64{"crypto": 50},
 			Import:       map[string]float64{"gold": 1000},
@@ -184,7 +189,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Fusion Export", Key: "fusion_export",
-			MinAge: "fusion_age", RequiredBld: "fusion_reactor", MinCount: 1,
+			MinAge: "fusion_age", RequiredBld: "fusion_reactor", MinCount: 1, Partner: "tech_consortium",
 			TicksPerRun: 12,
 			Export:       map[string]float64{"electricity": 200},
 			Import:       map[string]float64{"gold": 1000},
@@ -192,7 +197,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Warp Commerce", Key: "warp_commerce",
-			MinAge: "space_age", RequiredBld: "warp_gate", MinCount: 1,
+			MinAge: "space_age", RequiredBld: "warp_gate", MinCount: 1, Partner: "stellar_federation",
 			TicksPerRun: 15,
 			Export:       map[string]float64{"gold": 500},
 			Import:       map[string]float64{"dark_matter": 200},
@@ -200,7 +205,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Stellar Exchange", Key: "stellar_exchange",
-			MinAge: "galactic_age", RequiredBld: "galactic_hub", MinCount: 1,
+			MinAge: "galactic_age", RequiredBld: "galactic_hub", MinCount: 1, Partner: "stellar_federation",
 			TicksPerRun: 20,
 			Export:       map[string]float64{"dark_matter": 100},
 			Import:       map[string]float64{"gold": 2000},
@@ -208,7 +213,7 @@ func BaseTradeRoutes() []TradeRouteDef {
 		},
 		{
 			Name: "Quantum Trade", Key: "quantum_trade",
-			MinAge: "quantum_age", RequiredBld: "quantum_computer", MinCount: 1,
+			MinAge: "quantum_age", RequiredBld: "quantum_computer", MinCount: 1, Partner: "quantum_collective",
 			TicksPerRun: 10,
 			Export:       map[string]float64{"quantum_flux": 50},
 			Import:       map[string]float64{"gold": 5000},
@@ -231,32 +236,50 @@ func BaseFactions() []FactionDef {
 	return []FactionDef{
 		{
 			Name: "Merchant Guild", Key: "merchant_guild",
-			MinAge: "colonial_age", Specialty: "gold", TradeBonus: 0.20,
+			MinAge: "colonial_age", Specialty: "gold", TradeBonus: 0.20, Wants: "coal",
+			Produces:    map[string]float64{"gold": 3, "food": 4},
+			Consumes:    map[string]float64{"coal": 2, "wood": 2},
+			Allies:      []string{"artisan_league"},
 			Description: "A powerful guild of traders and financiers.",
 		},
 		{
 			Name: "Artisan League", Key: "artisan_league",
-			MinAge: "industrial_age", Specialty: "culture", TradeBonus: 0.15,
+			MinAge: "industrial_age", Specialty: "culture", TradeBonus: 0.15, Wants: "steel",
+			Produces:    map[string]float64{"culture": 3, "stone": 3},
+			Consumes:    map[string]float64{"steel": 2, "gold": 1},
+			Allies:      []string{"merchant_guild"},
 			Description: "Master craftspeople and cultural preservationists.",
 		},
 		{
 			Name: "Tech Consortium", Key: "tech_consortium",
-			MinAge: "information_age", Specialty: "data", TradeBonus: 0.20,
+			MinAge: "information_age", Specialty: "data", TradeBonus: 0.20, Wants: "electricity",
+			Produces:    map[string]float64{"data": 3, "electricity": 1},
+			Consumes:    map[string]float64{"electricity": 2, "gold": 1},
+			Allies:      []string{"stellar_federation"},
 			Description: "A coalition of technology companies and innovators.",
 		},
 		{
 			Name: "Shadow Syndicate", Key: "shadow_syndicate",
-			MinAge: "cyberpunk_age", Specialty: "crypto", TradeBonus: 0.25,
+			MinAge: "cyberpunk_age", Specialty: "crypto", TradeBonus: 0.25, Wants: "data",
+			Produces:    map[string]float64{"crypto": 2, "data": 1},
+			Consumes:    map[string]float64{"data": 2, "gold": 1},
+			Allies:      []string{"quantum_collective"},
 			Description: "An underground network dealing in digital currencies.",
 		},
 		{
 			Name: "Stellar Federation", Key: "stellar_federation",
-			MinAge: "space_age", Specialty: "dark_matter", TradeBonus: 0.20,
+			MinAge: "space_age", Specialty: "dark_matter", TradeBonus: 0.20, Wants: "titanium",
+			Produces:    map[string]float64{"dark_matter": 2, "gold": 2},
+			Consumes:    map[string]float64{"titanium": 2, "oil": 1},
+			Allies:      []string{"tech_consortium"},
 			Description: "An interstellar alliance of spacefaring civilizations.",
 		},
 		{
 			Name: "Quantum Collective", Key: "quantum_collective",
-			MinAge: "quantum_age", Specialty: "quantum_flux", TradeBonus: 0.30,
+			MinAge: "quantum_age", Specialty: "quantum_flux", TradeBonus: 0.30, Wants: "antimatter",
+			Produces:    map[string]float64{"quantum_flux": 2},
+			Consumes:    map[string]float64{"antimatter": 1, "dark_matter": 1, "gold": 1},
+			Allies:      []string{"shadow_syndicate"},
 			Description: "Beings who exist across multiple dimensions.",
 		},
 	}
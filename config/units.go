@@ -0,0 +1,86 @@
+package config
+
+// UnitDef defines a military unit type. Soldiers recruit as infantry and can
+// be trained into other unit types once the age and tech allow it. Recruits
+// and trainees wait in the training queue for TrainTicks each.
+type UnitDef struct {
+	Name         string
+	Key          string
+	MinAge       string
+	RequiredTech string // tech key that must be researched first, "" for none
+	Attack       float64
+	Defense      float64            // damage a unit absorbs before it falls
+	Counters     []string           // unit keys this unit deals bonus damage against
+	TrainCost    map[string]float64 // per unit, paid when training from infantry
+	TrainTicks   int                // ticks to train a unit in one training lane
+	Upkeep       map[string]float64 // per unit per tick, on top of the soldier's food
+	Description  string
+}
+
+// Units returns all military unit definitions, weakest era first
+func Units() []UnitDef {
+	return []UnitDef{
+		{
+			Name: "Infantry", Key: "infantry",
+			MinAge: "bronze_age", Attack: 2, Defense: 2,
+			Counters:    []string{"cavalry"},
+			TrainTicks:  4,
+			Upkeep:      map[string]float64{"gold": 0.01},
+			Description: "Spear-armed foot soldiers. Every recruit starts here; braces well against a charge.",
+		},
+		{
+			Name: "Archers", Key: "archers",
+			MinAge: "bronze_age", RequiredTech: "military_tactics", Attack: 3, Defense: 1.5,
+			Counters:    []string{"infantry"},
+			TrainCost:   map[string]float64{"wood": 15},
+			TrainTicks:  5,
+			Upkeep:      map[string]float64{"wood": 0.02, "gold": 0.015},
+			Description: "Volleys thin out massed infantry before it closes.",
+		},
+		{
+			Name: "Cavalry", Key: "cavalry",
+			MinAge: "iron_age", RequiredTech: "animal_husbandry", Attack: 4, Defense: 3,
+			Counters:    []string{"archers"},
+			TrainCost:   map[string]float64{"food": 25, "iron": 10},
+			TrainTicks:  6,
+			Upkeep:      map[string]float64{"food": 0.1, "gold": 0.02},
+			Description: "Fast riders that run down skirmishers.",
+		},
+		{
+			Name: "Musketeers", Key: "musketeers",
+			MinAge: "renaissance_age", RequiredTech: "gunpowder", Attack: 6, Defense: 4,
+			Counters:    []string{"infantry", "cavalry"},
+			TrainCost:   map[string]float64{"iron": 20, "gold": 15},
+			TrainTicks:  6,
+			Upkeep:      map[string]float64{"gold": 0.04},
+			Description: "Massed gunpowder volleys break pike squares and charges alike.",
+		},
+		{
+			Name: "Mechanised Infantry", Key: "mechanised",
+			MinAge: "victorian_age", RequiredTech: "mass_production", Attack: 12, Defense: 10,
+			Counters:    []string{"infantry", "archers", "cavalry", "musketeers"},
+			TrainCost:   map[string]float64{"steel": 40, "oil": 20},
+			TrainTicks:  8,
+			Upkeep:      map[string]float64{"oil": 0.05, "gold": 0.05},
+			Description: "Armoured vehicles that roll over any pre-industrial army.",
+		},
+		{
+			Name: "Drones", Key: "drones",
+			MinAge: "digital_age", RequiredTech: "machine_learning", Attack: 16, Defense: 8,
+			Counters:    []string{"mechanised", "musketeers"},
+			TrainCost:   map[string]float64{"electricity": 60, "data": 30},
+			TrainTicks:  8,
+			Upkeep:      map[string]float64{"electricity": 0.1, "gold": 0.08},
+			Description: "Autonomous swarms that hunt armour from the sky.",
+		},
+	}
+}
+
+// UnitByKey returns units keyed by unit key
+func UnitByKey() map[string]UnitDef {
+	out := make(map[string]UnitDef)
+	for _, def := range Units() {
+		out[def.Key] = def
+	}
+	return out
+}
@@ -110,6 +110,16 @@ func validList(keys map[string]bool, max int) string {
 	return strings.Join(sorted, ", ")
 }
 
+// containsString reports whether list holds s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // ---------------------------------------------------------------------------
 // Age config validation
 // ---------------------------------------------------------------------------
@@ -215,6 +225,79 @@ func TestConfig_BuildingKeysExist(t *testing.T) {
 					bld.Key, bld.Name, res, hintFromMap(res, resourceKeys))
 			}
 		}
+		for res, amount := range bld.Inputs {
+			if _, ok := resourceKeys[res]; !ok {
+				t.Errorf("\n"+
+					"  Bad resource key in building recipe\n"+
+					"  File:     config/buildings.go\n"+
+					"  Building: %q (%s)\n"+
+					"  Field:    Inputs\n"+
+					"  Got:      %q  <-- this resource doesn't exist\n"+
+					"  Fix:      Check config/resources.go for valid resource keys%s\n",
+					bld.Key, bld.Name, res, hintFromMap(res, resourceKeys))
+			}
+			if amount <= 0 {
+				t.Errorf("\n"+
+					"  Bad input amount in building recipe\n"+
+					"  File:     config/buildings.go\n"+
+					"  Building: %q (%s)\n"+
+					"  Field:    Inputs[%q]\n"+
+					"  Got:      %v  <-- inputs must be consumed, so use a positive amount\n",
+					bld.Key, bld.Name, res, amount)
+			}
+		}
+		if len(bld.Inputs) > 0 {
+			produces := false
+			for _, eff := range bld.Effects {
+				produces = produces || eff.Type == "production"
+			}
+			if !produces {
+				t.Errorf("\n"+
+					"  Building recipe has nothing to slow down\n"+
+					"  File:     config/buildings.go\n"+
+					"  Building: %q (%s)\n"+
+					"  Field:    Inputs\n"+
+					"  Fix:      Give it a production effect, or drop its inputs\n",
+					bld.Key, bld.Name)
+			}
+		}
+		for res, amount := range bld.Upkeep {
+			if _, ok := resourceKeys[res]; !ok || amount <= 0 {
+				t.Errorf("\n"+
+					"  Bad building upkeep\n"+
+					"  File:     config/buildings.go\n"+
+					"  Building: %q (%s)\n"+
+					"  Field:    Upkeep\n"+
+					"  Got:      %q: %v  <-- needs an existing resource and a positive amount\n"+
+					"  Fix:      Check config/resources.go for valid resource keys%s\n",
+					bld.Key, bld.Name, res, amount, hintFromMap(res, resourceKeys))
+			}
+		}
+		if bld.PowerDemand < 0 || bld.PowerPrio < 0 {
+			t.Errorf("\n"+
+				"  Bad power draw in building definition\n"+
+				"  File:     config/buildings.go\n"+
+				"  Building: %q (%s)\n"+
+				"  Field:    PowerDemand / PowerPrio\n"+
+				"  Got:      %v / %d  <-- neither may be negative\n",
+				bld.Key, bld.Name, bld.PowerDemand, bld.PowerPrio)
+		}
+		if bld.PowerDemand > 0 {
+			produces, generates := false, false
+			for _, eff := range bld.Effects {
+				produces = produces || eff.Type == "production"
+				generates = generates || (eff.Type == "production" && eff.Target == "electricity")
+			}
+			if !produces || generates {
+				t.Errorf("\n"+
+					"  Powered building must produce something other than electricity\n"+
+					"  File:     config/buildings.go\n"+
+					"  Building: %q (%s)\n"+
+					"  Field:    PowerDemand\n"+
+					"  Fix:      Power only scales production, and a generator can't run off its own grid\n",
+					bld.Key, bld.Name)
+			}
+		}
 	}
 }
 
@@ -336,6 +419,11 @@ func TestConfig_TradeKeysExist(t *testing.T) {
 	resourceKeys := ResourceByKey()
 	ageKeys := buildKeySet(Ages(), func(a AgeDef) string { return a.Key })
 	buildingKeys := BuildingByKey()
+	factionKeys := FactionByKey()
+	ageOrder := make(map[string]int)
+	for _, a := range Ages() {
+		ageOrder[a.Key] = a.Order
+	}
 
 	for _, rate := range BaseExchangeRates() {
 		if _, ok := resourceKeys[rate.From]; !ok {
@@ -393,6 +481,27 @@ func TestConfig_TradeKeysExist(t *testing.T) {
 					route.Key, route.Name, route.RequiredBld, hintFromMap(route.RequiredBld, buildingKeys))
 			}
 		}
+		if route.Partner != "" {
+			partner, ok := factionKeys[route.Partner]
+			if !ok {
+				t.Errorf("\n"+
+					"  Bad faction key in trade route\n"+
+					"  File:     config/trade.go (BaseTradeRoutes)\n"+
+					"  Route:    %q (%s)\n"+
+					"  Field:    Partner\n"+
+					"  Got:      %q  <-- this faction doesn't exist\n"+
+					"  Fix:      Check BaseFactions in config/trade.go for valid faction keys%s\n",
+					route.Key, route.Name, route.Partner, hintFromMap(route.Partner, factionKeys))
+			} else if ageOrder[partner.MinAge] > ageOrder[route.MinAge] {
+				t.Errorf("\n"+
+					"  Trade route opens before its partner appears\n"+
+					"  File:     config/trade.go (BaseTradeRoutes)\n"+
+					"  Route:    %q (%s)\n"+
+					"  Got:      MinAge %q, partner %q appears in %q\n"+
+					"  Fix:      Raise the route's MinAge or pick a partner from an earlier age\n",
+					route.Key, route.Name, route.MinAge, route.Partner, partner.MinAge)
+			}
+		}
 		for res := range route.Export {
 			if _, ok := resourceKeys[res]; !ok {
 				t.Errorf("\n"+
@@ -442,6 +551,131 @@ func TestConfig_TradeKeysExist(t *testing.T) {
 					faction.Key, faction.Name, faction.Specialty, hintFromMap(faction.Specialty, resourceKeys))
 			}
 		}
+		if _, ok := resourceKeys[faction.Wants]; !ok {
+			t.Errorf("\n"+
+				"  Bad resource key in faction wants\n"+
+				"  File:     config/trade.go (BaseFactions)\n"+
+				"  Faction:  %q (%s)\n"+
+				"  Field:    Wants\n"+
+				"  Got:      %q  <-- this resource doesn't exist\n"+
+				"  Fix:      Check config/resources.go for valid resource keys; supply requests ask for it%s\n",
+				faction.Key, faction.Name, faction.Wants, hintFromMap(faction.Wants, resourceKeys))
+		}
+		for field, flow := range map[string]map[string]float64{"Produces": faction.Produces, "Consumes": faction.Consumes} {
+			for res, amount := range flow {
+				if _, ok := resourceKeys[res]; !ok || amount <= 0 {
+					t.Errorf("\n"+
+						"  Bad resource in faction economy\n"+
+						"  File:     config/trade.go (BaseFactions)\n"+
+						"  Faction:  %q (%s)\n"+
+						"  Field:    %s\n"+
+						"  Got:      %q: %v\n"+
+						"  Fix:      Use a resource key from config/resources.go with a positive per-tick amount%s\n",
+						faction.Key, faction.Name, field, res, amount, hintFromMap(res, resourceKeys))
+				}
+			}
+		}
+		if faction.Produces[faction.Specialty] <= 0 || faction.Consumes[faction.Wants] <= 0 {
+			t.Errorf("\n"+
+				"  Faction economy doesn't match its trade\n"+
+				"  File:     config/trade.go (BaseFactions)\n"+
+				"  Faction:  %q (%s)\n"+
+				"  Got:      Produces %v, Consumes %v\n"+
+				"  Fix:      Produce the Specialty (%s) and consume the Wants (%s)\n",
+				faction.Key, faction.Name, faction.Produces, faction.Consumes, faction.Specialty, faction.Wants)
+		}
+		for _, ally := range faction.Allies {
+			other, ok := FactionByKey()[ally]
+			if !ok || ally == faction.Key {
+				t.Errorf("\n"+
+					"  Bad faction key in faction allies\n"+
+					"  File:     config/trade.go (BaseFactions)\n"+
+					"  Faction:  %q (%s)\n"+
+					"  Field:    Allies\n"+
+					"  Got:      %q  <-- not another faction\n"+
+					"  Fix:      List other faction keys from BaseFactions%s\n",
+					faction.Key, faction.Name, ally, hintFromMap(ally, FactionByKey()))
+				continue
+			}
+			if !containsString(other.Allies, faction.Key) {
+				t.Errorf("\n"+
+					"  One-sided faction alliance\n"+
+					"  File:     config/trade.go (BaseFactions)\n"+
+					"  Faction:  %q (%s)\n"+
+					"  Field:    Allies\n"+
+					"  Got:      %q doesn't list %q back\n"+
+					"  Fix:      Add %q to %s's Allies so both join each other's wars\n",
+					faction.Key, faction.Name, ally, faction.Key, faction.Key, other.Name)
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Diplomacy config validation
+// ---------------------------------------------------------------------------
+
+func TestConfig_DiplomacyDefsValid(t *testing.T) {
+	resourceKeys := ResourceByKey()
+	kinds := map[string]bool{"tribute": true, "resources": true, "military_aid": true}
+	forbids := map[string]bool{"rival": true, "embargo": true, "ally_other": true}
+
+	for _, req := range FactionRequests() {
+		if !kinds[req.Kind] {
+			t.Errorf("\n"+
+				"  Bad kind in faction request\n"+
+				"  File:     config/diplomacy.go (FactionRequests)\n"+
+				"  Request:  %q (%s)\n"+
+				"  Field:    Kind\n"+
+				"  Got:      %q\n"+
+				"  Fix:      Use tribute, resources or military_aid\n",
+				req.Key, req.Name, req.Kind)
+		}
+		if req.Deadline <= 0 || req.Reward <= 0 || req.Penalty <= 0 {
+			t.Errorf("\n"+
+				"  Faction request can't be answered\n"+
+				"  File:     config/diplomacy.go (FactionRequests)\n"+
+				"  Request:  %q (%s)\n"+
+				"  Got:      Deadline %d, Reward %d, Penalty %d\n"+
+				"  Fix:      Give it a positive Deadline, Reward and Penalty\n",
+				req.Key, req.Name, req.Deadline, req.Reward, req.Penalty)
+		}
+	}
+
+	for _, treaty := range Treaties() {
+		for _, f := range treaty.Forbids {
+			if !forbids[f] {
+				t.Errorf("\n"+
+					"  Bad obligation in treaty\n"+
+					"  File:     config/diplomacy.go (Treaties)\n"+
+					"  Treaty:   %q (%s)\n"+
+					"  Field:    Forbids\n"+
+					"  Got:      %q\n"+
+					"  Fix:      Use rival, embargo or ally_other\n",
+					treaty.Key, treaty.Name, f)
+			}
+		}
+		for _, eff := range treaty.Effects {
+			if _, ok := resourceKeys[eff.Target]; !ok && !isSpecialTarget(eff.Target) {
+				t.Errorf("\n"+
+					"  Bad effect target in treaty\n"+
+					"  File:     config/diplomacy.go (Treaties)\n"+
+					"  Treaty:   %q (%s)\n"+
+					"  Field:    Effects[].Target\n"+
+					"  Got:      %q  <-- not a valid resource or bonus key\n"+
+					"  Fix:      Use a bonus key like knowledge_rate or gold_rate%s\n",
+					treaty.Key, treaty.Name, eff.Target, hintFromMap(eff.Target, resourceKeys))
+			}
+		}
+		if treaty.Duration <= 0 || treaty.MinOpinion > treaty.MaxOpinion || len(treaty.Forbids) == 0 {
+			t.Errorf("\n"+
+				"  Treaty can never hold\n"+
+				"  File:     config/diplomacy.go (Treaties)\n"+
+				"  Treaty:   %q (%s)\n"+
+				"  Got:      Duration %d, opinion window %d..%d, %d obligation(s)\n"+
+				"  Fix:      Give it a positive Duration, MinOpinion <= MaxOpinion, and at least one Forbids entry\n",
+				treaty.Key, treaty.Name, treaty.Duration, treaty.MinOpinion, treaty.MaxOpinion, len(treaty.Forbids))
+		}
 	}
 }
 
@@ -482,6 +716,237 @@ func TestConfig_EventKeysExist(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Expedition config validation
+// ---------------------------------------------------------------------------
+
+func TestConfig_ExpeditionKeysExist(t *testing.T) {
+	resourceKeys := ResourceByKey()
+	ageKeys := buildKeySet(Ages(), func(a AgeDef) string { return a.Key })
+	factionKeys := FactionByKey()
+	techKeys := TechByKey()
+	unitKeys := UnitByKey()
+
+	checkEnemy := func(exp ExpeditionDef, field string, enemy map[string]int) {
+		for unit := range enemy {
+			if _, ok := unitKeys[unit]; !ok {
+				t.Errorf("\n"+
+					"  Bad unit key in expedition enemy\n"+
+					"  File:     config/expeditions.go\n"+
+					"  Expedition: %q (%s)\n"+
+					"  Field:    %s\n"+
+					"  Got:      %q  <-- this unit doesn't exist\n"+
+					"  Fix:      Check config/units.go for valid unit keys%s\n",
+					exp.Key, exp.Name, field, unit, hintFromMap(unit, unitKeys))
+			}
+		}
+	}
+
+	checkRewards := func(exp ExpeditionDef, field string, rewards map[string]float64) {
+		for res := range rewards {
+			if _, ok := resourceKeys[res]; !ok {
+				t.Errorf("\n"+
+					"  Bad resource key in expedition rewards\n"+
+					"  File:     config/expeditions.go\n"+
+					"  Expedition: %q (%s)\n"+
+					"  Field:    %s\n"+
+					"  Got:      %q  <-- this resource doesn't exist\n"+
+					"  Fix:      Check config/resources.go for valid resource keys%s\n",
+					exp.Key, exp.Name, field, res, hintFromMap(res, resourceKeys))
+			}
+		}
+	}
+
+	for _, exp := range Expeditions() {
+		if !ageKeys[exp.MinAge] {
+			t.Errorf("\n"+
+				"  Bad age key in expedition definition\n"+
+				"  File:     config/expeditions.go\n"+
+				"  Expedition: %q (%s)\n"+
+				"  Field:    MinAge\n"+
+				"  Got:      %q  <-- this age doesn't exist\n"+
+				"  Fix:      Check config/ages.go for valid age keys%s\n",
+				exp.Key, exp.Name, exp.MinAge, hint(exp.MinAge, ageKeys))
+		}
+		checkRewards(exp, "Rewards", exp.Rewards)
+		checkEnemy(exp, "Enemy", exp.Enemy)
+		for i, stage := range exp.Stages {
+			checkRewards(exp, fmt.Sprintf("Stages[%d].Rewards", i), stage.Rewards)
+			checkEnemy(exp, fmt.Sprintf("Stages[%d].Enemy", i), stage.Enemy)
+		}
+		if exp.DiscoverFaction != "" {
+			if _, ok := factionKeys[exp.DiscoverFaction]; !ok {
+				t.Errorf("\n"+
+					"  Bad faction key in expedition definition\n"+
+					"  File:     config/expeditions.go\n"+
+					"  Expedition: %q (%s)\n"+
+					"  Field:    DiscoverFaction\n"+
+					"  Got:      %q  <-- this faction doesn't exist\n"+
+					"  Fix:      Check config/trade.go (BaseFactions) for valid faction keys%s\n",
+					exp.Key, exp.Name, exp.DiscoverFaction, hintFromMap(exp.DiscoverFaction, factionKeys))
+			}
+		}
+		if exp.UnlockTech != "" {
+			tech, ok := techKeys[exp.UnlockTech]
+			if !ok {
+				t.Errorf("\n"+
+					"  Bad tech key in expedition definition\n"+
+					"  File:     config/expeditions.go\n"+
+					"  Expedition: %q (%s)\n"+
+					"  Field:    UnlockTech\n"+
+					"  Got:      %q  <-- this technology doesn't exist\n"+
+					"  Fix:      Check config/research.go for valid tech keys%s\n",
+					exp.Key, exp.Name, exp.UnlockTech, hintFromMap(exp.UnlockTech, techKeys))
+			} else if tech.Campaign != exp.Key {
+				t.Errorf("\n"+
+					"  Campaign grants a tech that doesn't name it\n"+
+					"  File:     config/expeditions.go + config/research.go\n"+
+					"  Expedition: %q (%s)\n"+
+					"  Field:    UnlockTech\n"+
+					"  Got:      %q has Campaign %q\n"+
+					"  Fix:      Set Campaign: %q on the tech so it can't also be researched\n",
+					exp.Key, exp.Name, exp.UnlockTech, tech.Campaign, exp.Key)
+			}
+		}
+	}
+
+	expeditions := ExpeditionByKey()
+	for _, tech := range Technologies() {
+		if tech.Campaign == "" {
+			continue
+		}
+		if exp, ok := expeditions[tech.Campaign]; !ok || exp.UnlockTech != tech.Key {
+			t.Errorf("\n"+
+				"  Campaign tech is never granted\n"+
+				"  File:     config/research.go + config/expeditions.go\n"+
+				"  Tech:     %q (%s)\n"+
+				"  Field:    Campaign\n"+
+				"  Got:      %q  <-- no campaign with this key grants the tech\n"+
+				"  Fix:      Set UnlockTech: %q on the campaign%s\n",
+				tech.Key, tech.Name, tech.Campaign, tech.Key, hintFromMap(tech.Campaign, expeditions))
+		}
+	}
+}
+
+func TestConfig_ExpeditionStagesValid(t *testing.T) {
+	bad := func(exp ExpeditionDef, problem, fix string) {
+		t.Errorf("\n"+
+			"  Invalid expedition definition\n"+
+			"  File:     config/expeditions.go\n"+
+			"  Expedition: %q (%s)\n"+
+			"  Problem:  %s\n"+
+			"  Fix:      %s\n",
+			exp.Key, exp.Name, problem, fix)
+	}
+
+	for _, exp := range Expeditions() {
+		if exp.SoldiersNeeded <= 0 {
+			bad(exp, "SoldiersNeeded must be positive", "Set SoldiersNeeded to at least 1")
+		}
+		if !exp.IsCampaign() {
+			if exp.Duration <= 0 {
+				bad(exp, "Duration must be positive", "Set Duration, or give the expedition Stages")
+			}
+			if exp.DiscoverFaction != "" || exp.UnlockTech != "" {
+				bad(exp, "only campaigns grant completion rewards", "Add Stages, or drop DiscoverFaction/UnlockTech")
+			}
+			if len(exp.Enemy) == 0 {
+				bad(exp, "expedition has no Enemy", "Give the expedition an enemy army to fight")
+			}
+			continue
+		}
+		if exp.Duration != 0 || len(exp.Enemy) != 0 || len(exp.Rewards) != 0 {
+			bad(exp, "campaign sets Duration, Enemy or Rewards", "Move them into the Stages; they are ignored on campaigns")
+		}
+		if len(exp.Stages) < 2 {
+			bad(exp, "campaign has a single stage", "Add more stages, or make it a plain expedition")
+		}
+		for i, stage := range exp.Stages {
+			if stage.Duration <= 0 {
+				bad(exp, fmt.Sprintf("Stages[%d] (%s) has no duration", i, stage.Name), "Set a positive Duration")
+			}
+			if len(stage.Enemy) == 0 {
+				bad(exp, fmt.Sprintf("Stages[%d] (%s) has no Enemy", i, stage.Name), "Give the stage an enemy army to fight")
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Unit config validation
+// ---------------------------------------------------------------------------
+
+func TestConfig_UnitKeysExist(t *testing.T) {
+	resourceKeys := ResourceByKey()
+	ageKeys := buildKeySet(Ages(), func(a AgeDef) string { return a.Key })
+	techKeys := TechByKey()
+	unitKeys := UnitByKey()
+
+	badKey := func(u UnitDef, kind, field, got, file, suggestion string) {
+		t.Errorf("\n"+
+			"  Bad %s key in unit definition\n"+
+			"  File:     config/units.go\n"+
+			"  Unit:     %q (%s)\n"+
+			"  Field:    %s\n"+
+			"  Got:      %q  <-- this %s doesn't exist\n"+
+			"  Fix:      Check %s for valid %s keys%s\n",
+			kind, u.Key, u.Name, field, got, kind, file, kind, suggestion)
+	}
+
+	for _, u := range Units() {
+		if !ageKeys[u.MinAge] {
+			badKey(u, "age", "MinAge", u.MinAge, "config/ages.go", hint(u.MinAge, ageKeys))
+		}
+		if u.RequiredTech != "" {
+			if _, ok := techKeys[u.RequiredTech]; !ok {
+				badKey(u, "tech", "RequiredTech", u.RequiredTech, "config/research.go", hintFromMap(u.RequiredTech, techKeys))
+			}
+		}
+		for _, c := range u.Counters {
+			if _, ok := unitKeys[c]; !ok {
+				badKey(u, "unit", "Counters", c, "config/units.go", hintFromMap(c, unitKeys))
+			}
+		}
+		for res := range u.TrainCost {
+			if _, ok := resourceKeys[res]; !ok {
+				badKey(u, "resource", "TrainCost", res, "config/resources.go", hintFromMap(res, resourceKeys))
+			}
+		}
+		for res := range u.Upkeep {
+			if _, ok := resourceKeys[res]; !ok {
+				badKey(u, "resource", "Upkeep", res, "config/resources.go", hintFromMap(res, resourceKeys))
+			}
+		}
+		if u.Attack <= 0 || u.Defense <= 0 {
+			t.Errorf("\n"+
+				"  Unit can't fight\n"+
+				"  File:     config/units.go\n"+
+				"  Unit:     %q (%s)\n"+
+				"  Got:      Attack %.1f, Defense %.1f\n"+
+				"  Fix:      Give the unit positive Attack and Defense\n",
+				u.Key, u.Name, u.Attack, u.Defense)
+		}
+		if u.TrainTicks <= 0 {
+			t.Errorf("\n"+
+				"  Unit trains instantly\n"+
+				"  File:     config/units.go\n"+
+				"  Unit:     %q (%s)\n"+
+				"  Got:      TrainTicks %d\n"+
+				"  Fix:      Give the unit a positive TrainTicks; recruits and trainees wait in the training queue\n",
+				u.Key, u.Name, u.TrainTicks)
+		}
+	}
+
+	// Recruits start as infantry, so it must exist and need nothing to train
+	inf, ok := unitKeys["infantry"]
+	if !ok || inf.RequiredTech != "" || len(inf.TrainCost) != 0 {
+		t.Errorf("\n" +
+			"  Infantry must exist with no RequiredTech or TrainCost\n" +
+			"  File:     config/units.go\n" +
+			"  Fix:      Every recruited soldier starts as infantry; keep it free and always available\n")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Upgrade config validation
 // ---------------------------------------------------------------------------
@@ -524,6 +989,123 @@ func TestConfig_UpgradeKeysExist(t *testing.T) {
 	}
 }
 
+func TestConfig_AdjacencyRulesValid(t *testing.T) {
+	buildingKeys := BuildingByKey()
+	terrains := buildKeySet(Terrains(), func(k string) string { return k })
+	seen := make(map[string]bool)
+
+	for _, rule := range AdjacencyRules() {
+		def, ok := buildingKeys[rule.Building]
+		if !ok {
+			t.Errorf("\n"+
+				"  Bad building key in adjacency rule\n"+
+				"  File:     config/settlement.go\n"+
+				"  Field:    Building\n"+
+				"  Got:      %q  <-- this building doesn't exist\n"+
+				"  Fix:      Check config/buildings.go for valid building keys%s\n",
+				rule.Building, hintFromMap(rule.Building, buildingKeys))
+			continue
+		}
+		if seen[rule.Building] {
+			t.Errorf("\n"+
+				"  Duplicate adjacency rule\n"+
+				"  File:     config/settlement.go\n"+
+				"  Building: %s\n"+
+				"  Fix:      Give each building at most one placement bonus\n",
+				rule.Building)
+		}
+		seen[rule.Building] = true
+		if !terrains[rule.Terrain] || rule.Terrain == TerrainGrass {
+			t.Errorf("\n"+
+				"  Bad terrain in adjacency rule\n"+
+				"  File:     config/settlement.go\n"+
+				"  Building: %s\n"+
+				"  Field:    Terrain\n"+
+				"  Got:      %q  <-- not a terrain a bonus can come from\n"+
+				"  Fix:      Use TerrainWater, TerrainForest or TerrainHill\n",
+				rule.Building, rule.Terrain)
+		}
+		if rule.Bonus <= 0 {
+			t.Errorf("\n"+
+				"  Non-positive adjacency bonus\n"+
+				"  File:     config/settlement.go\n"+
+				"  Building: %s\n"+
+				"  Got:      %v\n"+
+				"  Fix:      Bonus is a share of production, e.g. 0.25\n",
+				rule.Building, rule.Bonus)
+		}
+		producer := false
+		for _, eff := range def.Effects {
+			if eff.Type == "production" {
+				producer = true
+			}
+		}
+		if !producer {
+			t.Errorf("\n"+
+				"  Adjacency rule on a building that produces nothing\n"+
+				"  File:     config/settlement.go\n"+
+				"  Building: %s\n"+
+				"  Fix:      Placement bonuses scale production; pick a producing building\n",
+				rule.Building)
+		}
+	}
+}
+
+func TestConfig_BiomesValid(t *testing.T) {
+	resourceKeys := buildKeySet(BaseResources(), func(r ResourceDef) string { return r.Key })
+	eventKeys := EventByKey()
+	seen := make(map[string]bool)
+
+	for _, b := range Biomes() {
+		if !containsString(Terrains(), b.Terrain) || seen[b.Terrain] {
+			t.Errorf("\n"+
+				"  Bad or duplicate terrain in biome definition\n"+
+				"  File:     config/settlement.go\n"+
+				"  Biome:    %s\n"+
+				"  Got:      %q\n"+
+				"  Fix:      Give each terrain in Terrains() exactly one biome\n",
+				b.Name, b.Terrain)
+		}
+		seen[b.Terrain] = true
+		for res := range b.Bonuses {
+			if !resourceKeys[res] {
+				t.Errorf("\n"+
+					"  Bad resource key in biome bonus\n"+
+					"  File:     config/settlement.go\n"+
+					"  Biome:    %s\n"+
+					"  Got:      %q  <-- this resource doesn't exist\n"+
+					"  Fix:      Check config/resources.go for valid resource keys%s\n",
+					b.Name, res, hint(res, resourceKeys))
+			}
+		}
+		keys := append(mapKeysOf(b.Events), b.Only...)
+		for _, key := range keys {
+			if _, ok := eventKeys[key]; !ok {
+				t.Errorf("\n"+
+					"  Bad event key in biome definition\n"+
+					"  File:     config/settlement.go\n"+
+					"  Biome:    %s\n"+
+					"  Got:      %q  <-- this event doesn't exist\n"+
+					"  Fix:      Check config/events.go for valid event keys%s\n",
+					b.Name, key, hintFromMap(key, eventKeys))
+			}
+		}
+	}
+	for _, terrain := range Terrains() {
+		if !seen[terrain] {
+			t.Errorf("terrain %q has no biome in config/settlement.go", terrain)
+		}
+	}
+}
+
+func mapKeysOf(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 // ---------------------------------------------------------------------------
 // Duplicate key detection
 // ---------------------------------------------------------------------------
@@ -544,7 +1126,7 @@ func TestConfig_NoDuplicateKeys(t *testing.T) {
 		}
 	}
 
-	var resourceKeys, buildingKeys, techKeys, ageKeys, milestoneKeys, eventKeys []string
+	var resourceKeys, buildingKeys, techKeys, ageKeys, milestoneKeys, eventKeys, expeditionKeys, unitKeys, treatyKeys, requestKeys []string
 	for _, r := range BaseResources() {
 		resourceKeys = append(resourceKeys, r.Key)
 	}
@@ -563,6 +1145,18 @@ func TestConfig_NoDuplicateKeys(t *testing.T) {
 	for _, e := range RandomEvents() {
 		eventKeys = append(eventKeys, e.Key)
 	}
+	for _, e := range Expeditions() {
+		expeditionKeys = append(expeditionKeys, e.Key)
+	}
+	for _, u := range Units() {
+		unitKeys = append(unitKeys, u.Key)
+	}
+	for _, tr := range Treaties() {
+		treatyKeys = append(treatyKeys, tr.Key)
+	}
+	for _, r := range FactionRequests() {
+		requestKeys = append(requestKeys, r.Key)
+	}
 
 	checkDupes("resource", "config/resources.go", resourceKeys)
 	checkDupes("building", "config/buildings.go", buildingKeys)
@@ -570,6 +1164,10 @@ func TestConfig_NoDuplicateKeys(t *testing.T) {
 	checkDupes("age", "config/ages.go", ageKeys)
 	checkDupes("milestone", "config/milestones.go", milestoneKeys)
 	checkDupes("event", "config/events.go", eventKeys)
+	checkDupes("expedition", "config/expeditions.go", expeditionKeys)
+	checkDupes("unit", "config/units.go", unitKeys)
+	checkDupes("treaty", "config/diplomacy.go", treatyKeys)
+	checkDupes("faction request", "config/diplomacy.go", requestKeys)
 }
 
 // ---------------------------------------------------------------------------
@@ -681,7 +1279,7 @@ func TestConfig_EffectTargetsValid(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestConfig_Summary(t *testing.T) {
-	t.Logf("Config inventory: %d ages, %d resources, %d buildings, %d techs, %d milestones, %d events, %d trade routes, %d factions, %d upgrades",
+	t.Logf("Config inventory: %d ages, %d resources, %d buildings, %d techs, %d milestones, %d events, %d trade routes, %d factions, %d upgrades, %d expeditions, %d units",
 		len(Ages()),
 		len(BaseResources()),
 		len(BaseBuildings()),
@@ -691,6 +1289,8 @@ func TestConfig_Summary(t *testing.T) {
 		len(BaseTradeRoutes()),
 		len(BaseFactions()),
 		len(BuildingUpgrades()),
+		len(Expeditions()),
+		len(Units()),
 	)
 }
 
@@ -705,6 +1305,7 @@ func isSpecialTarget(target string) bool {
 		"knowledge_rate": true, "build_cost": true, "tick_speed": true,
 		"storage": true, "trade_rate": true, "research_speed": true,
 		"build_speed": true, "military_power": true,
+		"expedition_slots": true, "training_slots": true,
 		"food_rate": true, "gold_rate": true, "iron_rate": true,
 		"stone_rate": true, "wood_rate": true, "coal_rate": true,
 		"steel_rate": true, "oil_rate": true, "electricity_rate": true,
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/ageforge/config"
+)
+
+const (
+	battleMaxRounds   = 8
+	battleLethality   = 0.25 // share of a unit's attack that lands each round
+	counterMultiplier = 1.5  // damage bonus against a countered unit type
+)
+
+// BattleReport is the outcome of a battle between two armies
+type BattleReport struct {
+	AttackerWon    bool
+	Rounds         int
+	AttackerLosses map[string]int
+	DefenderLosses map[string]int
+	AttackerLeft   map[string]int
+	DefenderLeft   map[string]int
+}
+
+// ResolveBattle fights attackers against defenders, keyed by unit type.
+// Each round both sides strike at once: a unit's attack is spread over the
+// enemy by headcount, counters deal extra damage, and a unit falls for each
+// full Defense worth of damage its type has taken (the remainder carries
+// over). The attacker wins only by wiping out the defenders; if both sides
+// are still standing after the last round the defender holds the field.
+// The result is deterministic.
+func ResolveBattle(attackers, defenders map[string]int, attackBonus, defenseBonus float64) BattleReport {
+	units := config.UnitByKey()
+	report := BattleReport{
+		AttackerLosses: make(map[string]int),
+		DefenderLosses: make(map[string]int),
+		AttackerLeft:   knownUnits(attackers, units),
+		DefenderLeft:   knownUnits(defenders, units),
+	}
+	att, def := report.AttackerLeft, report.DefenderLeft
+	attCarry := make(map[string]float64)
+	defCarry := make(map[string]float64)
+
+	for round := 1; round <= battleMaxRounds; round++ {
+		if UnitTotal(att) == 0 || UnitTotal(def) == 0 {
+			break
+		}
+		report.Rounds = round
+		toDef := battleDamage(att, def, units, attackBonus)
+		toAtt := battleDamage(def, att, units, defenseBonus)
+		applyBattleDamage(def, toDef, defCarry, units, report.DefenderLosses)
+		applyBattleDamage(att, toAtt, attCarry, units, report.AttackerLosses)
+	}
+
+	report.AttackerWon = UnitTotal(att) > 0 && UnitTotal(def) == 0
+	return report
+}
+
+// battleDamage returns the damage one side deals to each enemy unit type in a round
+func battleDamage(from, to map[string]int, units map[string]config.UnitDef, bonus float64) map[string]float64 {
+	dmg := make(map[string]float64)
+	total := float64(UnitTotal(to))
+	if total == 0 {
+		return dmg
+	}
+	for _, u := range sortedUnitKeys(from) {
+		n := from[u]
+		if n == 0 {
+			continue
+		}
+		def := units[u]
+		for _, e := range sortedUnitKeys(to) {
+			m := to[e]
+			if m == 0 {
+				continue
+			}
+			mult := 1.0
+			if counters(def, e) {
+				mult = counterMultiplier
+			}
+			dmg[e] += float64(n) * def.Attack * battleLethality * mult * (1 + bonus) * float64(m) / total
+		}
+	}
+	return dmg
+}
+
+// applyBattleDamage removes the units a round's damage kills
+func applyBattleDamage(side map[string]int, dmg, carry map[string]float64, units map[string]config.UnitDef, losses map[string]int) {
+	for _, u := range sortedUnitKeys(side) {
+		carry[u] += dmg[u]
+		hp := units[u].Defense
+		killed := int(carry[u] / hp)
+		if killed > side[u] {
+			killed = side[u]
+		}
+		carry[u] -= float64(killed) * hp
+		side[u] -= killed
+		if killed > 0 {
+			losses[u] += killed
+		}
+		if side[u] == 0 {
+			carry[u] = 0
+		}
+	}
+}
+
+// counters returns whether a unit type deals bonus damage against another
+func counters(def config.UnitDef, target string) bool {
+	for _, c := range def.Counters {
+		if c == target {
+			return true
+		}
+	}
+	return false
+}
+
+// knownUnits copies an army, dropping empty entries and unknown unit types
+func knownUnits(army map[string]int, units map[string]config.UnitDef) map[string]int {
+	out := make(map[string]int)
+	for k, n := range army {
+		if def, ok := units[k]; ok && n > 0 && def.Defense > 0 {
+			out[k] = n
+		}
+	}
+	return out
+}
+
+// UnitTotal returns the headcount of an army
+func UnitTotal(army map[string]int) int {
+	total := 0
+	for _, n := range army {
+		total += n
+	}
+	return total
+}
+
+// ArmyPower returns the summed attack and defense of an army, for display
+func ArmyPower(army map[string]int) float64 {
+	units := config.UnitByKey()
+	power := 0.0
+	for k, n := range army {
+		power += float64(n) * (units[k].Attack + units[k].Defense)
+	}
+	return power
+}
+
+// FormatUnits formats an army as "3 Infantry, 2 Archers" in config order
+func FormatUnits(army map[string]int) string {
+	var parts []string
+	for _, def := range config.Units() {
+		if n := army[def.Key]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, def.Name))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedUnitKeys(army map[string]int) []string {
+	keys := make([]string, 0, len(army))
+	for k := range army {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
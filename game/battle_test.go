@@ -0,0 +1,88 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/ageforge/config"
+)
+
+func TestResolveBattle_Deterministic(t *testing.T) {
+	att := map[string]int{"archers": 6, "infantry": 4}
+	def := map[string]int{"infantry": 5, "cavalry": 2}
+	first := ResolveBattle(att, def, 0.1, 0)
+	for i := 0; i < 10; i++ {
+		if got := ResolveBattle(att, def, 0.1, 0); !reflect.DeepEqual(got, first) {
+			t.Fatalf("battle %d = %+v, want %+v", i, got, first)
+		}
+	}
+	if att["archers"] != 6 || def["infantry"] != 5 {
+		t.Error("ResolveBattle modified its input armies")
+	}
+}
+
+func TestResolveBattle_OverwhelmingAttackerWins(t *testing.T) {
+	r := ResolveBattle(map[string]int{"infantry": 20}, map[string]int{"infantry": 2}, 0, 0)
+	if !r.AttackerWon {
+		t.Fatal("20 infantry lost to 2")
+	}
+	if r.DefenderLosses["infantry"] != 2 || UnitTotal(r.DefenderLeft) != 0 {
+		t.Errorf("defender losses = %v, left = %v, want all 2 dead", r.DefenderLosses, r.DefenderLeft)
+	}
+	if got := UnitTotal(r.AttackerLosses) + UnitTotal(r.AttackerLeft); got != 20 {
+		t.Errorf("attacker losses + left = %d, want 20", got)
+	}
+}
+
+func TestResolveBattle_DefenderHoldsMutualDestruction(t *testing.T) {
+	r := ResolveBattle(map[string]int{"infantry": 1}, map[string]int{"infantry": 1}, 0, 0)
+	if r.AttackerWon {
+		t.Error("attacker won an even fight, want defender to hold")
+	}
+	if UnitTotal(r.AttackerLeft) != 0 || UnitTotal(r.DefenderLeft) != 0 {
+		t.Errorf("left = %v vs %v, want both wiped out", r.AttackerLeft, r.DefenderLeft)
+	}
+}
+
+func TestResolveBattle_OutclassedAttackerFalls(t *testing.T) {
+	r := ResolveBattle(map[string]int{"infantry": 3}, map[string]int{"mechanised": 2}, 0, 0)
+	if r.AttackerWon {
+		t.Fatal("3 infantry beat 2 mechanised")
+	}
+	if r.AttackerLosses["infantry"] != 3 || r.Rounds > battleMaxRounds {
+		t.Errorf("losses = %v in %d rounds, want 3 infantry within %d", r.AttackerLosses, r.Rounds, battleMaxRounds)
+	}
+}
+
+func TestResolveBattle_CountersAndBonus(t *testing.T) {
+	units := config.UnitByKey()
+	archers := map[string]int{"archers": 1}
+	vsInfantry := battleDamage(archers, map[string]int{"infantry": 1}, units, 0)
+	vsCavalry := battleDamage(archers, map[string]int{"cavalry": 1}, units, 0)
+	if vsInfantry["infantry"] != vsCavalry["cavalry"]*counterMultiplier {
+		t.Errorf("archers vs infantry = %v, want %v× their %v vs cavalry",
+			vsInfantry["infantry"], counterMultiplier, vsCavalry["cavalry"])
+	}
+
+	without := ResolveBattle(map[string]int{"infantry": 6}, map[string]int{"infantry": 6}, 0, 0)
+	with := ResolveBattle(map[string]int{"infantry": 6}, map[string]int{"infantry": 6}, 1.0, 0)
+	if without.AttackerWon || !with.AttackerWon {
+		t.Errorf("won without bonus = %v, with +100%% = %v, want false, true", without.AttackerWon, with.AttackerWon)
+	}
+}
+
+func TestResolveBattle_IgnoresUnknownUnits(t *testing.T) {
+	r := ResolveBattle(map[string]int{"infantry": 3, "dragons": 5}, map[string]int{"infantry": 1}, 0, 0)
+	if _, ok := r.AttackerLeft["dragons"]; ok {
+		t.Error("unknown unit type took part in battle")
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	if got := FormatUnits(map[string]int{"cavalry": 2, "infantry": 3}); got != "3 Infantry, 2 Cavalry" {
+		t.Errorf("FormatUnits = %q, want %q", got, "3 Infantry, 2 Cavalry")
+	}
+	if got := FormatUnits(nil); got != "none" {
+		t.Errorf("FormatUnits(nil) = %q, want none", got)
+	}
+}
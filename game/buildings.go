@@ -1,24 +1,33 @@
 package game
 
 import (
+	"fmt"
 	"math"
+	"sort"
 
 	"github.com/user/ageforge/config"
 )
 
 // BuildingManager manages all buildings
 type BuildingManager struct {
-	counts   map[string]int
-	defs     map[string]config.BuildingDef
-	unlocked map[string]bool
+	counts     map[string]int
+	defs       map[string]config.BuildingDef
+	unlocked   map[string]bool
+	disabled   map[string]int     // building key -> instances switched off
+	throughput map[string]float64 // recipe building key -> share of full output, set by RunRecipes
+	powered    map[string]float64 // powered building key -> share of its demand served, set by RunGrid
+	grid       PowerState
 }
 
 // NewBuildingManager creates a building manager
 func NewBuildingManager() *BuildingManager {
 	return &BuildingManager{
-		counts:   make(map[string]int),
-		defs:     config.BuildingByKey(),
-		unlocked: make(map[string]bool),
+		counts:     make(map[string]int),
+		defs:       config.BuildingByKey(),
+		unlocked:   make(map[string]bool),
+		disabled:   make(map[string]int),
+		throughput: make(map[string]float64),
+		powered:    make(map[string]float64),
 	}
 }
 
@@ -89,6 +98,34 @@ func (bm *BuildingManager) GetCount(key string) int {
 	return bm.counts[key]
 }
 
+// Disabled returns how many of a building are switched off
+func (bm *BuildingManager) Disabled(key string) int {
+	return bm.disabled[key]
+}
+
+// active returns how many of a building are switched on
+func (bm *BuildingManager) active(key string) int {
+	return bm.counts[key] - bm.disabled[key]
+}
+
+// SetDisabled switches off n of a building's instances and the rest on.
+// Switched-off buildings grant no effects and pay no upkeep.
+func (bm *BuildingManager) SetDisabled(key string, n int) error {
+	def, ok := bm.defs[key]
+	if !ok {
+		return fmt.Errorf("unknown building: %s", key)
+	}
+	if n < 0 || n > bm.counts[key] {
+		return fmt.Errorf("can switch off 0 to %d %s", bm.counts[key], def.Name)
+	}
+	if n == 0 {
+		delete(bm.disabled, key)
+	} else {
+		bm.disabled[key] = n
+	}
+	return nil
+}
+
 // GetCost calculates the cost for the next building of this type (with scaling)
 func (bm *BuildingManager) GetCost(key string) map[string]float64 {
 	def, ok := bm.defs[key]
@@ -103,6 +140,19 @@ func (bm *BuildingManager) GetCost(key string) map[string]float64 {
 	return cost
 }
 
+// DemolishRefund returns what demolishing n of a building gives back: a
+// share of what the last n built cost
+func (bm *BuildingManager) DemolishRefund(key string, n int) map[string]float64 {
+	def := bm.defs[key]
+	refund := make(map[string]float64)
+	for i := 1; i <= n && i <= bm.counts[key]; i++ {
+		for res, base := range def.BaseCost {
+			refund[res] += math.Floor(base*math.Pow(def.CostScale, float64(bm.counts[key]-i))) * config.DemolishRefund
+		}
+	}
+	return refund
+}
+
 // Build constructs a building. Returns false if can't afford or not unlocked.
 func (bm *BuildingManager) Build(key string, resources *ResourceManager) bool {
 	if !bm.unlocked[key] {
@@ -123,53 +173,192 @@ func (bm *BuildingManager) Build(key string, resources *ResourceManager) bool {
 	return true
 }
 
-// GetEffects returns the total effects from all built buildings
-func (bm *BuildingManager) GetEffects() []config.Effect {
-	var effects []config.Effect
-	for key, count := range bm.counts {
-		if count == 0 {
-			continue
+// Destroy removes up to count buildings of a type (e.g. burned in a raid),
+// switched-off ones first. Returns how many were removed.
+func (bm *BuildingManager) Destroy(key string, count int) int {
+	if count > bm.counts[key] {
+		count = bm.counts[key]
+	}
+	bm.counts[key] -= count
+	if off := bm.disabled[key] - count; off > 0 {
+		bm.disabled[key] = off
+	} else {
+		delete(bm.disabled, key)
+	}
+	return count
+}
+
+// Modifiers returns the modifiers granted by built buildings, in key order
+func (bm *BuildingManager) Modifiers() []Modifier {
+	keys := make([]string, 0, len(bm.counts))
+	for key := range bm.counts {
+		if bm.active(key) > 0 {
+			keys = append(keys, key)
 		}
+	}
+	sort.Strings(keys)
+	var mods []Modifier
+	for _, key := range keys {
 		def := bm.defs[key]
+		count := float64(bm.active(key))
+		run := bm.Throughput(key)
+		name := fmt.Sprintf("%s ×%d", def.Name, bm.active(key))
+		if run < 1 {
+			name += fmt.Sprintf(" at %.0f%%", run*100)
+		}
 		for _, eff := range def.Effects {
-			scaled := config.Effect{
-				Type:   eff.Type,
-				Target: eff.Target,
-				Value:  eff.Value * float64(count),
+			m, ok := effectModifier(eff, SourceBuilding, key, name)
+			if !ok {
+				continue
+			}
+			m.Value *= count
+			switch eff.Type {
+			case "production":
+				m.Value *= run
+			case "storage":
+			default:
+				// Bonuses fade with the power like output does
+				if p, ok := bm.powered[key]; ok {
+					m.Value *= p
+				}
 			}
-			effects = append(effects, scaled)
+			mods = append(mods, m)
+		}
+		inputs := mapKeys(def.Inputs)
+		sort.Strings(inputs)
+		for _, res := range inputs {
+			mods = append(mods, Modifier{
+				Stat: res, Layer: LayerConsumption, Value: -def.Inputs[res] * count * run,
+				Source: SourceBuilding, Key: key, Name: name,
+			})
+		}
+		if def.PowerDemand > 0 {
+			mods = append(mods, Modifier{
+				Stat: "electricity", Layer: LayerConsumption, Value: -def.PowerDemand * count * bm.powered[key],
+				Source: SourceBuilding, Key: key, Name: name,
+			})
+		}
+		upkeep := mapKeys(def.Upkeep)
+		sort.Strings(upkeep)
+		for _, res := range upkeep {
+			mods = append(mods, Modifier{
+				Stat: res, Layer: LayerFinal, Value: -def.Upkeep[res] * count,
+				Source: SourceBuilding, Key: key, Name: name + " upkeep",
+			})
 		}
 	}
-	return effects
+	return mods
 }
 
-// GetPopCapacity returns total population capacity from housing buildings
-func (bm *BuildingManager) GetPopCapacity() int {
-	cap := 0
-	for key, count := range bm.counts {
+// RunRecipes decides how fully each building with inputs runs this tick.
+// Buildings sharing an input split its stock in proportion to their demand,
+// and each runs as fully as its scarcest input allows.
+func (bm *BuildingManager) RunRecipes(resources *ResourceManager) {
+	demand := make(map[string]float64)
+	for key := range bm.counts {
+		for res, amount := range bm.defs[key].Inputs {
+			demand[res] += amount * float64(bm.active(key))
+		}
+	}
+	bm.throughput = make(map[string]float64)
+	for key := range bm.counts {
 		def := bm.defs[key]
-		for _, eff := range def.Effects {
-			if eff.Type == "capacity" && eff.Target == "population" {
-				cap += int(eff.Value) * count
+		if bm.active(key) == 0 || len(def.Inputs) == 0 {
+			continue
+		}
+		run := 1.0
+		for res := range def.Inputs {
+			run = math.Min(run, resources.Get(res)/demand[res])
+		}
+		bm.throughput[key] = run
+	}
+}
+
+// RunGrid shares this tick's electricity generation among buildings that
+// draw power. Higher priorities are served first; a priority the grid can't
+// fully cover splits what's left in proportion to demand, and everything
+// below it goes dark.
+func (bm *BuildingManager) RunGrid(supply float64) PowerState {
+	demand := make(map[int]float64)
+	for key := range bm.counts {
+		if def := bm.defs[key]; bm.active(key) > 0 && def.PowerDemand > 0 {
+			demand[def.PowerPrio] += def.PowerDemand * float64(bm.active(key))
+		}
+	}
+	prios := make([]int, 0, len(demand))
+	for prio := range demand {
+		prios = append(prios, prio)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(prios)))
+
+	grid := PowerState{Supply: supply}
+	share := make(map[int]float64)
+	left := supply
+	for _, prio := range prios {
+		served := math.Max(0, math.Min(1, left/demand[prio]))
+		share[prio] = served
+		left -= demand[prio] * served
+		grid.Demand += demand[prio]
+		grid.Served += demand[prio] * served
+	}
+
+	bm.powered = make(map[string]float64)
+	for key := range bm.counts {
+		if def := bm.defs[key]; bm.active(key) > 0 && def.PowerDemand > 0 {
+			bm.powered[key] = share[def.PowerPrio]
+		}
+	}
+	bm.grid = grid
+	return grid
+}
+
+// Grid returns the power grid as of the last RunGrid
+func (bm *BuildingManager) Grid() PowerState {
+	return bm.grid
+}
+
+// Throughput returns the share of full output a building type ran at this
+// tick: 1 unless its inputs or its power ran short
+func (bm *BuildingManager) Throughput(key string) float64 {
+	run := 1.0
+	if r, ok := bm.throughput[key]; ok {
+		run = r
+	}
+	if p, ok := bm.powered[key]; ok {
+		run *= p
+	}
+	return run
+}
+
+// ExpeditionSlots returns extra expedition slots from military buildings.
+// Each building type grants its slots once, however many are built.
+func (bm *BuildingManager) ExpeditionSlots() int {
+	slots := 0
+	for key := range bm.counts {
+		if bm.active(key) == 0 {
+			continue
+		}
+		for _, eff := range bm.defs[key].Effects {
+			if eff.Type == "capacity" && eff.Target == "expedition_slots" {
+				slots += int(eff.Value)
 			}
 		}
 	}
-	return cap
+	return slots
 }
 
-// GetStorageBonuses returns per-resource storage bonuses from buildings
-// "all" key means it applies to every resource
-func (bm *BuildingManager) GetStorageBonuses() map[string]float64 {
-	bonuses := make(map[string]float64)
-	for key, count := range bm.counts {
-		def := bm.defs[key]
-		for _, eff := range def.Effects {
-			if eff.Type == "storage" {
-				bonuses[eff.Target] += eff.Value * float64(count)
+// TrainingSlots returns extra training lanes from military buildings. Unlike
+// expedition slots, every building counts.
+func (bm *BuildingManager) TrainingSlots() int {
+	slots := 0
+	for key := range bm.counts {
+		for _, eff := range bm.defs[key].Effects {
+			if eff.Type == "capacity" && eff.Target == "training_slots" {
+				slots += int(eff.Value) * bm.active(key)
 			}
 		}
 	}
-	return bonuses
+	return slots
 }
 
 // GetAll returns building counts (for save)
@@ -188,6 +377,28 @@ func (bm *BuildingManager) LoadCounts(counts map[string]int) {
 	}
 }
 
+// GetDisabled returns the switched-off building counts (for save)
+func (bm *BuildingManager) GetDisabled() map[string]int {
+	out := make(map[string]int)
+	for key, n := range bm.disabled {
+		out[key] = n
+	}
+	return out
+}
+
+// LoadDisabled restores switched-off building counts from save data
+func (bm *BuildingManager) LoadDisabled(disabled map[string]int) {
+	bm.disabled = make(map[string]int)
+	for key, n := range disabled {
+		if n > bm.counts[key] {
+			n = bm.counts[key]
+		}
+		if n > 0 {
+			bm.disabled[key] = n
+		}
+	}
+}
+
 // Snapshot returns building states for UI
 func (bm *BuildingManager) Snapshot(resources *ResourceManager) map[string]BuildingState {
 	out := make(map[string]BuildingState)
@@ -195,12 +406,18 @@ func (bm *BuildingManager) Snapshot(resources *ResourceManager) map[string]Build
 		cost := bm.GetCost(key)
 		out[key] = BuildingState{
 			Count:       bm.counts[key],
+			Disabled:    bm.disabled[key],
 			Name:        def.Name,
 			Category:    def.Category,
 			Description: def.Description,
 			Unlocked:    bm.unlocked[key],
 			NextCost:    cost,
 			CanBuild:    bm.unlocked[key] && resources.CanAfford(cost),
+			Inputs:      def.Inputs,
+			PowerDemand: def.PowerDemand,
+			PowerPrio:   def.PowerPrio,
+			Upkeep:      def.Upkeep,
+			Throughput:  bm.Throughput(key),
 		}
 	}
 	return out
@@ -2,7 +2,10 @@ package game
 
 import (
 	"math"
+	"os"
 	"testing"
+
+	"github.com/user/ageforge/config"
 )
 
 func TestBuildingManager_UnlockAndCount(t *testing.T) {
@@ -53,18 +56,23 @@ func TestBuildingManager_CostScaling(t *testing.T) {
 	}
 }
 
-func TestBuildingManager_PopCapacity(t *testing.T) {
-	bm := NewBuildingManager()
-	bm.UnlockBuilding("hut")
-
-	if got := bm.GetPopCapacity(); got != 0 {
+func TestEngine_PopCapacity(t *testing.T) {
+	ge := NewGameEngine()
+	if got := ge.popCap(); got != 0 {
 		t.Errorf("pop cap with no buildings = %v, want 0", got)
 	}
 
-	bm.counts["hut"] = 3
-	cap := bm.GetPopCapacity()
-	if cap <= 0 {
-		t.Errorf("pop cap with 3 huts = %v, want > 0", cap)
+	ge.Buildings.counts["hut"] = 3
+	ge.recalculateRates()
+	if got := ge.popCap(); got != 6 {
+		t.Errorf("pop cap with 3 huts = %v, want 6", got)
+	}
+
+	// Prestige adds to what housing gives
+	ge.Prestige.upgrades["population_cap"] = 2
+	ge.recalculateRates()
+	if got := ge.popCap(); got != 10 {
+		t.Errorf("pop cap with 3 huts and 2 prestige tiers = %v, want 10", got)
 	}
 }
 
@@ -90,3 +98,235 @@ func TestBuildingManager_LoadCounts(t *testing.T) {
 		t.Errorf("loaded farm count = %v, want 2", bm.GetCount("farm"))
 	}
 }
+
+func TestEngine_RecipesShareShortInputs(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"smithy": 2, "power_grid": 1})
+	for _, key := range []string{"iron", "coal", "steel", "electricity"} {
+		ge.Resources.UnlockResource(key)
+	}
+	ge.Resources.Add("iron", 10)
+	ge.Resources.Add("coal", 0.2)
+	ge.recalculateRates()
+
+	// Smithies want 0.1 coal and the grid 0.3, so 0.2 in stock runs both at half
+	for _, key := range []string{"smithy", "power_grid"} {
+		if got := ge.Buildings.Throughput(key); math.Abs(got-0.5) > 1e-9 {
+			t.Errorf("%s throughput = %v, want 0.5", key, got)
+		}
+	}
+	coal := ge.Resources.resources["coal"]
+	if math.Abs(coal.Rate+0.2) > 1e-9 || math.Abs(coal.Breakdown.Consumption+0.2) > 1e-9 {
+		t.Errorf("coal rate %v, consumption %v; want the whole 0.2 burned", coal.Rate, coal.Breakdown.Consumption)
+	}
+	if got := ge.Resources.resources["steel"].Rate; math.Abs(got-0.1) > 1e-9 {
+		t.Errorf("steel rate = %v, want 0.1 from two smithies at half", got)
+	}
+	iron := ge.Resources.resources["iron"]
+	sum := 0.0
+	for _, src := range iron.Breakdown.Sources {
+		sum += src.Amount
+		if src.Layer != LayerConsumption || src.Name != "Smithy ×2 at 50%" {
+			t.Errorf("iron source %+v, want the smithies' consumption", src)
+		}
+	}
+	if math.Abs(sum-iron.Rate) > 1e-9 || math.Abs(iron.Rate+0.1) > 1e-9 {
+		t.Errorf("iron sources sum to %v, rate %v; want −0.1", sum, iron.Rate)
+	}
+
+	// With plenty of stock they run fully
+	ge.Resources.Add("coal", 10)
+	ge.recalculateRates()
+	if got := ge.Buildings.Throughput("power_grid"); got != 1 {
+		t.Errorf("power grid throughput = %v with coal in stock, want 1", got)
+	}
+}
+
+func TestBuildingManager_GridServesByPriority(t *testing.T) {
+	bm := NewBuildingManager()
+	bm.LoadCounts(map[string]int{"electric_mill": 2, "server_farm": 1, "research_lab": 1})
+
+	// Mills (priority 2) take 0.8, the server farm (1) half of its 2.0, the lab (0) nothing
+	grid := bm.RunGrid(1.8)
+	if math.Abs(grid.Demand-4.3) > 1e-9 || math.Abs(grid.Served-1.8) > 1e-9 {
+		t.Errorf("grid %+v, want demand 4.3 with all 1.8 served", grid)
+	}
+	for key, want := range map[string]float64{"electric_mill": 1, "server_farm": 0.5, "research_lab": 0} {
+		if got := bm.Throughput(key); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s throughput = %v, want %v", key, got, want)
+		}
+	}
+
+	if grid := bm.RunGrid(10); grid.Served != grid.Demand || bm.Throughput("research_lab") != 1 {
+		t.Errorf("grid %+v with spare supply, want everything powered", grid)
+	}
+}
+
+func TestBuildingManager_DarkGridDropsBonuses(t *testing.T) {
+	bm := NewBuildingManager()
+	bm.LoadCounts(map[string]int{"maglev_station": 2})
+
+	bonus := func() float64 {
+		total := 0.0
+		for _, m := range bm.Modifiers() {
+			if m.Stat == "production_all" {
+				total += m.Value
+			}
+		}
+		return total
+	}
+	bm.RunGrid(0)
+	if got := bonus(); got != 0 {
+		t.Errorf("production_all from unpowered maglevs = %v, want 0", got)
+	}
+	bm.RunGrid(6)
+	if got := bonus(); math.Abs(got-0.05) > 1e-9 {
+		t.Errorf("production_all from half-powered maglevs = %v, want 0.05", got)
+	}
+	bm.RunGrid(12)
+	if got := bonus(); math.Abs(got-0.1) > 1e-9 {
+		t.Errorf("production_all from powered maglevs = %v, want 0.1", got)
+	}
+}
+
+func TestEngine_PowerGrid(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Resources.UnlockResource("electricity")
+	ge.Resources.Add("electricity", 40)
+	ge.Buildings.LoadCounts(map[string]int{"data_center": 2, "ai_lab": 1})
+
+	// A stockpile can't power buildings
+	ge.recalculateRates()
+	if got := ge.Buildings.Throughput("data_center"); got != 0 {
+		t.Errorf("data center throughput = %v with no generators, want 0", got)
+	}
+
+	// A reactor's 5.0 covers both data centers (4.0) and a quarter of the lab
+	ge.Buildings.LoadCounts(map[string]int{"data_center": 2, "ai_lab": 1, "reactor": 1})
+	ge.recalculateRates()
+	if got := ge.Buildings.Throughput("ai_lab"); math.Abs(got-0.25) > 1e-9 {
+		t.Errorf("AI lab throughput = %v, want 0.25", got)
+	}
+	elec := ge.Resources.resources["electricity"]
+	if math.Abs(elec.Rate) > 1e-9 || math.Abs(elec.Breakdown.Consumption+5) > 1e-9 {
+		t.Errorf("electricity rate %v, consumption %v; want all 5.0 drawn", elec.Rate, elec.Breakdown.Consumption)
+	}
+	if got := ge.GetState().Power; got.Supply != 5 || got.Demand != 8 || got.Served != 5 {
+		t.Errorf("power state = %+v, want 5 of 8 served", got)
+	}
+}
+
+func TestBuildingManager_DisabledGrantNothing(t *testing.T) {
+	bm := NewBuildingManager()
+	bm.LoadCounts(map[string]int{"bunker": 3})
+	for _, n := range []int{-1, 4} {
+		if err := bm.SetDisabled("bunker", n); err == nil {
+			t.Errorf("SetDisabled(bunker, %d) succeeded, want error", n)
+		}
+	}
+	if err := bm.SetDisabled("bunker", 2); err != nil {
+		t.Fatalf("SetDisabled failed: %v", err)
+	}
+
+	if got := bm.TrainingSlots(); got != 3 {
+		t.Errorf("training slots = %d, want 3 from the one running bunker", got)
+	}
+	stack := &ModifierStack{}
+	stack.Add(bm.Modifiers()...)
+	if got := stack.Sum("gold", LayerFinal); got != -0.5 {
+		t.Errorf("gold upkeep = %v, want −0.5 for the one running bunker", got)
+	}
+
+	// Losses come out of the switched-off bunkers first
+	bm.Destroy("bunker", 1)
+	if bm.Disabled("bunker") != 1 || bm.active("bunker") != 1 {
+		t.Errorf("after a loss %d off and %d running, want 1 and 1", bm.Disabled("bunker"), bm.active("bunker"))
+	}
+	bm.Destroy("bunker", 2)
+	if got := bm.Disabled("bunker"); got != 0 {
+		t.Errorf("%d off with none left, want 0", got)
+	}
+}
+
+func TestEngine_SaveLoadDisabled(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"farm": 4})
+	if err := ge.SetDisabled("farm", 3); err != nil {
+		t.Fatalf("SetDisabled failed: %v", err)
+	}
+	if got := ge.Resources.resources["food"].Breakdown.SourceTotal(SourceBuilding); math.Abs(got-0.25) > 1e-9 {
+		t.Errorf("farms make %v food, want 0.25 from the one running", got)
+	}
+	if err := ge.SaveGame("test_disabled"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_disabled.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_disabled"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	if got := ge2.GetState().Buildings["farm"]; got.Count != 4 || got.Disabled != 3 {
+		t.Errorf("farms %d with %d off, want 4 with 3 off", got.Count, got.Disabled)
+	}
+}
+
+func TestEngine_DisableFullHousing(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"hut": 3})
+	ge.Villagers.UnlockType("worker")
+	ge.recalculateRates()
+	cap := ge.popCap()
+	ge.Villagers.Recruit("worker", cap, cap)
+	ge.Villagers.Assign("worker", "food", 1)
+
+	if err := ge.SetDisabled("hut", 2); err != nil {
+		t.Fatalf("SetDisabled failed: %v", err)
+	}
+	pop, newCap := ge.Villagers.TotalPop(), ge.popCap()
+	if newCap >= cap {
+		t.Fatalf("cap %d after switching off huts, want below %d", newCap, cap)
+	}
+	if pop > newCap {
+		t.Errorf("population %d over the cap of %d", pop, newCap)
+	}
+	if got := ge.Villagers.types["worker"].assignment["food"]; got != 1 {
+		t.Errorf("%d gatherers left, want idle workers dismissed first", got)
+	}
+}
+
+func TestEngine_Demolish(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"hut": 3, "sacred_grove": 1})
+	ge.Villagers.UnlockType("worker")
+	ge.recalculateRates()
+	cap := ge.popCap()
+	ge.Villagers.Recruit("worker", cap, cap)
+	ge.Villagers.Assign("worker", "food", 2)
+	ge.Resources.LoadAmounts(map[string]float64{"wood": 0})
+
+	// The last two huts cost 50 and 39 wood
+	n, err := ge.Demolish("hut", 2)
+	if err != nil || n != 2 {
+		t.Fatalf("Demolish = %d, %v; want 2 huts", n, err)
+	}
+	if got, want := ge.Resources.Get("wood"), (50+39)*config.DemolishRefund; got != want {
+		t.Errorf("refund = %v wood, want %v", got, want)
+	}
+	if pop, cap := ge.Villagers.TotalPop(), ge.popCap(); pop > cap {
+		t.Errorf("population %d over the cap of %d", pop, cap)
+	}
+	if got := ge.Villagers.types["worker"].assignment["food"]; got != 2 {
+		t.Errorf("%d gatherers left, want idle workers dismissed first", got)
+	}
+
+	if _, err := ge.Demolish("sacred_grove", 1); err == nil {
+		t.Error("demolished a wonder")
+	}
+	if n, _ := ge.Demolish("hut", 5); n != 1 {
+		t.Errorf("demolished %d huts, want the 1 left", n)
+	}
+	if _, err := ge.Demolish("hut", 1); err == nil {
+		t.Error("demolished a hut that isn't there")
+	}
+}
@@ -2,10 +2,22 @@ package game
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/user/ageforge/config"
 )
 
+const (
+	agendaInterval = 150 // ticks between a faction's agenda items
+	agendaStagger  = 37  // offset between factions so they don't all speak at once
+	proposalTicks  = 60  // ticks a treaty proposal stays on the table
+	declinePenalty = 3   // opinion lost when turning down a proposal
+
+	routeCycleOpinion = 1 // opinion a partner gains per completed route cycle
+)
+
 // DiplomacyManager handles NPC factions and diplomatic relations
 type DiplomacyManager struct {
 	factions map[string]*FactionState
@@ -15,8 +27,28 @@ type DiplomacyManager struct {
 type FactionState struct {
 	Discovered bool
 	Opinion    int    // -100 to 100
-	Status     string // "neutral", "friendly", "allied", "rival", "embargo"
+	Status     string // "neutral", "friendly", "allied", "rival", "embargo", "war"
 	TradeCount int
+	Incidents  int // border incidents since opinion fell past incidentOpinion, see war.go
+	WarScore   int // battles won minus raids lost while at war
+
+	Request         *FactionRequest // open demand, nil when none
+	Proposal        string          // treaty key on the table, "" when none
+	ProposalExpires int             // tick the proposal is withdrawn
+	Treaty          string          // treaty key in force, "" when none
+	TreatyExpires   int             // tick the treaty runs its full term
+	Agendas         int             // agenda items issued so far; picks what comes next
+
+	Stock      map[string]float64 // the faction's own stockpiles, see economy.go
+	Disruption float64            // 0-1, grows while a rival or under embargo
+}
+
+// FactionRequest is a demand a faction has made of the player
+type FactionRequest struct {
+	Key        string             // config.FactionRequestDef key
+	Resources  map[string]float64 // delivery demanded by tribute and supply requests
+	Expedition string             // expedition to win for military aid
+	Deadline   int                // tick the request lapses
 }
 
 // NewDiplomacyManager creates a new diplomacy manager
@@ -34,17 +66,39 @@ func (dm *DiplomacyManager) DiscoverFactions(age string, ageOrder map[string]int
 			continue
 		}
 		if ageOrder[age] >= ageOrder[def.MinAge] {
-			dm.factions[def.Key] = &FactionState{
+			fs := &FactionState{
 				Discovered: true,
 				Opinion:    0,
 				Status:     "neutral",
 			}
+			fs.seedEconomy(def)
+			dm.factions[def.Key] = fs
 			discovered = append(discovered, def.Key)
 		}
 	}
 	return discovered
 }
 
+// Discover makes a faction known ahead of its age (e.g. a campaign reward).
+// Returns false if it was already discovered or doesn't exist.
+func (dm *DiplomacyManager) Discover(factionKey string) bool {
+	def, ok := config.FactionByKey()[factionKey]
+	if !ok {
+		return false
+	}
+	if _, exists := dm.factions[factionKey]; exists {
+		return false
+	}
+	fs := &FactionState{
+		Discovered: true,
+		Opinion:    0,
+		Status:     "neutral",
+	}
+	fs.seedEconomy(def)
+	dm.factions[factionKey] = fs
+	return true
+}
+
 // SetStatus changes diplomatic status with a faction
 func (dm *DiplomacyManager) SetStatus(factionKey, status string, gold float64) (float64, error) {
 	defs := config.FactionByKey()
@@ -57,6 +111,9 @@ func (dm *DiplomacyManager) SetStatus(factionKey, status string, gold float64) (
 	if !ok || !fs.Discovered {
 		return 0, fmt.Errorf("%s has not been discovered yet", def.Name)
 	}
+	if fs.Status == "war" {
+		return 0, fmt.Errorf("at war with %s — make peace first (diplomacy peace %s)", def.Name, def.Key)
+	}
 
 	var cost float64
 	switch status {
@@ -83,58 +140,77 @@ func (dm *DiplomacyManager) SetStatus(factionKey, status string, gold float64) (
 	return cost, nil
 }
 
-// SendGift sends a gift to a faction, increasing opinion
-func (dm *DiplomacyManager) SendGift(factionKey string, gold float64) (float64, error) {
+// SendGift sends a gift of gold to a faction, increasing opinion by more the
+// scarcer gold is in its stockpile. Returns the gold spent and opinion gained.
+func (dm *DiplomacyManager) SendGift(factionKey string, gold float64) (float64, int, error) {
 	defs := config.FactionByKey()
 	def, ok := defs[factionKey]
 	if !ok {
-		return 0, fmt.Errorf("unknown faction: %s", factionKey)
+		return 0, 0, fmt.Errorf("unknown faction: %s", factionKey)
 	}
 
 	fs, ok := dm.factions[factionKey]
 	if !ok || !fs.Discovered {
-		return 0, fmt.Errorf("%s has not been discovered yet", def.Name)
+		return 0, 0, fmt.Errorf("%s has not been discovered yet", def.Name)
 	}
 
 	cost := 200.0
 	if gold < cost {
-		return 0, fmt.Errorf("not enough gold to send gift (have: %.0f, need: %.0f)", gold, cost)
+		return 0, 0, fmt.Errorf("not enough gold to send gift (have: %.0f, need: %.0f)", gold, cost)
 	}
 
-	fs.Opinion += 15
-	if fs.Opinion > 100 {
-		fs.Opinion = 100
+	gain := giftValue(def, fs)
+	fs.adjustOpinion(gain)
+	if c := economyCapacity(def, "gold"); c > 0 {
+		fs.Stock["gold"] = math.Min(c, fs.Stock["gold"]+cost)
 	}
 
-	// Auto-upgrade to friendly if opinion hits 25+
-	if fs.Status == "neutral" && fs.Opinion >= 25 {
-		fs.Status = "friendly"
-	}
-
-	return cost, nil
+	return cost, gain, nil
 }
 
-// GetTradeBonus returns the sum of bonuses from allied factions for a resource
+// GetTradeBonus returns the sum of bonuses from allied factions and trade
+// treaties for a resource
 func (dm *DiplomacyManager) GetTradeBonus(resourceKey string) float64 {
 	defs := config.FactionByKey()
 	bonus := 0.0
 	for key, fs := range dm.factions {
-		if fs.Status != "allied" {
-			continue
-		}
 		def, ok := defs[key]
-		if !ok {
+		if !ok || def.Specialty != resourceKey {
 			continue
 		}
-		if def.Specialty == resourceKey {
+		if fs.Status == "allied" {
 			bonus += def.TradeBonus
 		}
+		if fs.Treaty != "" {
+			bonus += config.TreatyByKey()[fs.Treaty].TradeBonus
+		}
 	}
 	return bonus
 }
 
-// Tick processes diplomacy each game tick
-func (dm *DiplomacyManager) Tick(age string, ageOrder map[string]int, tick int) []string {
+// HostileFactions returns the keys of discovered factions that are rivals,
+// under embargo, at war, or whose opinion has fallen to -50 or below. A
+// treaty that promises no raids keeps a faction off the list.
+func (dm *DiplomacyManager) HostileFactions() []string {
+	var keys []string
+	for key, fs := range dm.factions {
+		if !fs.Discovered {
+			continue
+		}
+		if fs.Treaty != "" && config.TreatyByKey()[fs.Treaty].NoRaids {
+			continue
+		}
+		if fs.Status == "rival" || fs.Status == "embargo" || fs.Status == "war" || fs.Opinion <= -50 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Tick processes diplomacy each game tick. stock holds the unlocked resource
+// amounts and expeditions the expedition keys available, both used to draw
+// up faction requests.
+func (dm *DiplomacyManager) Tick(age string, ageOrder map[string]int, tick int, stock map[string]float64, expeditions []string) []string {
 	var messages []string
 
 	// Discover new factions
@@ -151,9 +227,9 @@ func (dm *DiplomacyManager) Tick(age string, ageOrder map[string]int, tick int)
 			continue
 		}
 
-		// Rival/embargo: -5 per 50 ticks
+		// Rival/embargo/war: -5 per 50 ticks
 		if tick%50 == 0 {
-			if fs.Status == "rival" || fs.Status == "embargo" {
+			if fs.Status == "rival" || fs.Status == "embargo" || fs.Status == "war" {
 				fs.Opinion -= 5
 				if fs.Opinion < -100 {
 					fs.Opinion = -100
@@ -171,26 +247,396 @@ func (dm *DiplomacyManager) Tick(age string, ageOrder map[string]int, tick int)
 		}
 	}
 
+	messages = append(messages, dm.tickConflict(tick)...)
+	dm.tickEconomy()
+
+	// Agendas: lapse overdue items, then each faction speaks in turn
+	for i, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if !ok || !fs.Discovered {
+			continue
+		}
+		messages = append(messages, expireAgenda(def, fs, tick)...)
+		if (tick+i*agendaStagger)%agendaInterval == 0 {
+			if msg := issueAgenda(def, fs, tick, stock, expeditions); msg != "" {
+				messages = append(messages, msg)
+			}
+		}
+	}
+
 	return messages
 }
 
-// RecordTrade records a trade cycle completion for faction opinion
-func (dm *DiplomacyManager) RecordTrade() {
-	// Each active trade gives +1 opinion to all discovered factions
-	for _, fs := range dm.factions {
-		if !fs.Discovered {
+// expireAgenda lapses a faction's overdue request and proposal and ends a
+// treaty that has run its term
+func expireAgenda(def config.FactionDef, fs *FactionState, tick int) []string {
+	var messages []string
+	if fs.Request != nil && tick >= fs.Request.Deadline {
+		rdef := config.FactionRequestByKey()[fs.Request.Key]
+		fs.Request = nil
+		fs.adjustOpinion(-rdef.Penalty)
+		messages = append(messages, fmt.Sprintf("%s's %s went unanswered (-%d opinion)", def.Name, rdef.Name, rdef.Penalty))
+	}
+	if fs.Proposal != "" && tick >= fs.ProposalExpires {
+		tdef := config.TreatyByKey()[fs.Proposal]
+		fs.Proposal = ""
+		messages = append(messages, fmt.Sprintf("%s withdrew its %s proposal", def.Name, tdef.Name))
+	}
+	if fs.Treaty != "" && tick >= fs.TreatyExpires {
+		tdef := config.TreatyByKey()[fs.Treaty]
+		fs.Treaty = ""
+		fs.adjustOpinion(tdef.KeepReward)
+		messages = append(messages, fmt.Sprintf("The %s with %s ran its full term (+%d opinion)", tdef.Name, def.Name, tdef.KeepReward))
+	}
+	return messages
+}
+
+// issueAgenda has a faction propose a treaty or make a request, alternating
+// between the two. Factions under embargo, at war or with an open item stay
+// quiet.
+func issueAgenda(def config.FactionDef, fs *FactionState, tick int, stock map[string]float64, expeditions []string) string {
+	if fs.Status == "embargo" || fs.Status == "war" || fs.Request != nil || fs.Proposal != "" {
+		return ""
+	}
+	fs.Agendas++
+	if fs.Agendas%2 == 1 && fs.Treaty == "" {
+		if tdef, ok := pickTreaty(fs); ok {
+			fs.Proposal = tdef.Key
+			fs.ProposalExpires = tick + proposalTicks
+			return fmt.Sprintf("%s proposes a %s for %d ticks: %s", def.Name, tdef.Name, tdef.Duration, tdef.Description)
+		}
+	}
+	req, ok := newRequest(def, fs.Agendas, tick, stock, expeditions)
+	if !ok {
+		return ""
+	}
+	fs.Request = req
+	rdef := config.FactionRequestByKey()[req.Key]
+	what := formatCost(req.Resources)
+	if req.Expedition != "" {
+		what = "a victory in " + config.ExpeditionByKey()[req.Expedition].Name
+	}
+	return fmt.Sprintf("%s requests %s: %s within %d ticks", def.Name, rdef.Name, what, rdef.Deadline)
+}
+
+// pickTreaty returns the next treaty, in rotation, whose opinion window
+// covers the faction's opinion
+func pickTreaty(fs *FactionState) (config.TreatyDef, bool) {
+	treaties := config.Treaties()
+	for i := range treaties {
+		tdef := treaties[(fs.Agendas/2+i)%len(treaties)]
+		if fs.Opinion >= tdef.MinOpinion && fs.Opinion <= tdef.MaxOpinion {
+			return tdef, true
+		}
+	}
+	return config.TreatyDef{}, false
+}
+
+// newRequest draws up the next request, in rotation, that the player can
+// act on. Tribute and supply requests ask for a share of the stockpile.
+func newRequest(def config.FactionDef, agendas, tick int, stock map[string]float64, expeditions []string) (*FactionRequest, bool) {
+	requests := config.FactionRequests()
+	for i := range requests {
+		rdef := requests[(agendas/2+i)%len(requests)]
+		req := &FactionRequest{Key: rdef.Key, Deadline: tick + rdef.Deadline}
+		switch rdef.Kind {
+		case "tribute", "resources":
+			res := "gold"
+			if rdef.Kind == "resources" {
+				res = def.Wants
+			}
+			have, unlocked := stock[res]
+			if !unlocked {
+				continue
+			}
+			req.Resources = map[string]float64{res: math.Max(rdef.MinAmount, math.Round(have*rdef.Share))}
+		case "military_aid":
+			if len(expeditions) == 0 {
+				continue
+			}
+			// Factions want help with the toughest fights on offer
+			n := len(expeditions)
+			if n > 3 {
+				n = 3
+			}
+			req.Expedition = expeditions[len(expeditions)-1-(agendas/2)%n]
+		default:
 			continue
 		}
-		fs.TradeCount++
-		fs.Opinion++
-		if fs.Opinion > 100 {
-			fs.Opinion = 100
+		return req, true
+	}
+	return nil, false
+}
+
+// lookup returns a discovered faction by key
+func (dm *DiplomacyManager) lookup(factionKey string) (config.FactionDef, *FactionState, error) {
+	def, ok := config.FactionByKey()[factionKey]
+	if !ok {
+		return def, nil, fmt.Errorf("unknown faction: %s", factionKey)
+	}
+	fs, ok := dm.factions[factionKey]
+	if !ok || !fs.Discovered {
+		return def, nil, fmt.Errorf("%s has not been discovered yet", def.Name)
+	}
+	return def, fs, nil
+}
+
+// AcceptTreaty signs the treaty a faction has proposed
+func (dm *DiplomacyManager) AcceptTreaty(factionKey string, tick int) (string, error) {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil {
+		return "", err
+	}
+	if fs.Proposal == "" {
+		return "", fmt.Errorf("%s has no treaty on the table", def.Name)
+	}
+	tdef := config.TreatyByKey()[fs.Proposal]
+	for _, f := range tdef.Forbids {
+		switch f {
+		case "rival", "embargo":
+			if fs.Status == f {
+				return "", fmt.Errorf("the %s rules out %s status — set %s to neutral first", tdef.Name, f, def.Key)
+			}
+		case "ally_other":
+			if other := dm.alliedOther(factionKey); other != "" {
+				return "", fmt.Errorf("the %s rules out other alliances — you are allied with %s", tdef.Name, config.FactionByKey()[other].Name)
+			}
 		}
 	}
+	fs.Proposal = ""
+	fs.Treaty = tdef.Key
+	fs.TreatyExpires = tick + tdef.Duration
+	fs.adjustOpinion(tdef.SignReward)
+	return fmt.Sprintf("Signed the %s with %s for %d ticks (+%d opinion)", tdef.Name, def.Name, tdef.Duration, tdef.SignReward), nil
+}
+
+// DeclineTreaty turns down the treaty a faction has proposed
+func (dm *DiplomacyManager) DeclineTreaty(factionKey string) (string, error) {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil {
+		return "", err
+	}
+	if fs.Proposal == "" {
+		return "", fmt.Errorf("%s has no treaty on the table", def.Name)
+	}
+	tdef := config.TreatyByKey()[fs.Proposal]
+	fs.Proposal = ""
+	fs.adjustOpinion(-declinePenalty)
+	return fmt.Sprintf("Declined the %s with %s (-%d opinion)", tdef.Name, def.Name, declinePenalty), nil
+}
+
+// BreakTreaty walks out of the treaty in force with a faction
+func (dm *DiplomacyManager) BreakTreaty(factionKey string) (string, error) {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil {
+		return "", err
+	}
+	if fs.Treaty == "" {
+		return "", fmt.Errorf("no treaty in force with %s", def.Name)
+	}
+	return breakTreaty(def, fs), nil
+}
+
+// BreakViolated breaks every treaty that setting a faction's status violates.
+// Call it after SetStatus succeeds.
+func (dm *DiplomacyManager) BreakViolated(factionKey, status string) []string {
+	var messages []string
+	for _, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if !ok || fs.Treaty == "" {
+			continue
+		}
+		for _, f := range config.TreatyByKey()[fs.Treaty].Forbids {
+			ownStatus := def.Key == factionKey && f == status
+			otherAlly := def.Key != factionKey && f == "ally_other" && status == "allied"
+			if ownStatus || otherAlly {
+				messages = append(messages, breakTreaty(def, fs))
+				break
+			}
+		}
+	}
+	return messages
+}
+
+// breakTreaty ends the treaty in force and charges its opinion penalty
+func breakTreaty(def config.FactionDef, fs *FactionState) string {
+	tdef := config.TreatyByKey()[fs.Treaty]
+	fs.Treaty = ""
+	fs.TreatyExpires = 0
+	fs.adjustOpinion(-tdef.BreakPenalty)
+	return fmt.Sprintf("Broke the %s with %s (-%d opinion)", tdef.Name, def.Name, tdef.BreakPenalty)
+}
+
+// alliedOther returns a faction other than factionKey the player is allied
+// with, or ""
+func (dm *DiplomacyManager) alliedOther(factionKey string) string {
+	for _, def := range config.BaseFactions() {
+		if fs, ok := dm.factions[def.Key]; ok && def.Key != factionKey && fs.Status == "allied" {
+			return def.Key
+		}
+	}
+	return ""
+}
+
+// RequestDue returns what it takes to meet a faction's open tribute or supply
+// request. Military aid is met by winning the expedition instead.
+func (dm *DiplomacyManager) RequestDue(factionKey string) (map[string]float64, error) {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil {
+		return nil, err
+	}
+	if fs.Request == nil {
+		return nil, fmt.Errorf("%s has no open request", def.Name)
+	}
+	if fs.Request.Expedition != "" {
+		exp := config.ExpeditionByKey()[fs.Request.Expedition]
+		return nil, fmt.Errorf("%s wants military aid — win %s (expedition %s) to meet it", def.Name, exp.Name, exp.Key)
+	}
+	return fs.Request.Resources, nil
+}
+
+// CompleteRequest closes a faction's open request as met
+func (dm *DiplomacyManager) CompleteRequest(factionKey string) string {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil || fs.Request == nil {
+		return ""
+	}
+	return completeRequest(def, fs)
+}
+
+// completeRequest closes the open request and grants its opinion reward
+func completeRequest(def config.FactionDef, fs *FactionState) string {
+	rdef := config.FactionRequestByKey()[fs.Request.Key]
+	fs.Request = nil
+	fs.adjustOpinion(rdef.Reward)
+	return fmt.Sprintf("%s's %s met (+%d opinion)", def.Name, rdef.Name, rdef.Reward)
+}
+
+// RefuseRequest turns down a faction's open request
+func (dm *DiplomacyManager) RefuseRequest(factionKey string) (string, error) {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil {
+		return "", err
+	}
+	if fs.Request == nil {
+		return "", fmt.Errorf("%s has no open request", def.Name)
+	}
+	rdef := config.FactionRequestByKey()[fs.Request.Key]
+	fs.Request = nil
+	fs.adjustOpinion(-rdef.Penalty)
+	return fmt.Sprintf("Refused %s's %s (-%d opinion)", def.Name, rdef.Name, rdef.Penalty), nil
+}
+
+// RecordExpedition meets any military aid request for a won expedition
+func (dm *DiplomacyManager) RecordExpedition(key string) []string {
+	var messages []string
+	for _, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if ok && fs.Request != nil && fs.Request.Expedition == key {
+			messages = append(messages, completeRequest(def, fs))
+		}
+	}
+	return messages
+}
+
+// Modifiers returns the bonuses factions grant: an ally's or trade
+// treaty's boost to its specialty, and the effects of treaties in force
+func (dm *DiplomacyManager) Modifiers() []Modifier {
+	var mods []Modifier
+	for _, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if !ok {
+			continue
+		}
+		if fs.Status == "allied" && def.Specialty != "" && def.TradeBonus != 0 {
+			mods = append(mods, Modifier{
+				Stat: def.Specialty + "_rate", Layer: LayerMultiplicative, Value: def.TradeBonus,
+				Source: SourceFaction, Key: def.Key, Name: def.Name + " alliance",
+			})
+		}
+		if fs.Treaty == "" {
+			continue
+		}
+		treaty := config.TreatyByKey()[fs.Treaty]
+		key := def.Key + ":" + treaty.Key
+		name := fmt.Sprintf("%s with %s", treaty.Name, def.Name)
+		if def.Specialty != "" && treaty.TradeBonus != 0 {
+			mods = append(mods, Modifier{
+				Stat: def.Specialty + "_rate", Layer: LayerMultiplicative, Value: treaty.TradeBonus,
+				Source: SourceFaction, Key: key, Name: name,
+			})
+		}
+		mods = append(mods, effectModifiers(treaty.Effects, 1, SourceFaction, key, name)...)
+	}
+	return mods
+}
+
+// adjustOpinion moves opinion by delta within -100..100. Like gifts, a rise
+// to 25 or more makes a neutral faction friendly.
+func (fs *FactionState) adjustOpinion(delta int) {
+	fs.Opinion += delta
+	if fs.Opinion > 100 {
+		fs.Opinion = 100
+	}
+	if fs.Opinion < -100 {
+		fs.Opinion = -100
+	}
+	if delta > 0 && fs.Status == "neutral" && fs.Opinion >= 25 {
+		fs.Status = "friendly"
+	}
+}
+
+// treatyTerms describes what a treaty gives and what it asks of the player
+func treatyTerms(tdef config.TreatyDef, def config.FactionDef) (benefits, obligations []string) {
+	if tdef.NoRaids {
+		benefits = append(benefits, "no raids")
+	}
+	if tdef.TradeBonus > 0 {
+		benefits = append(benefits, fmt.Sprintf("+%.0f%% %s", tdef.TradeBonus*100, def.Specialty))
+	}
+	for _, eff := range tdef.Effects {
+		benefits = append(benefits, fmt.Sprintf("+%.0f%% %s", eff.Value*100, strings.TrimSuffix(eff.Target, "_rate")))
+	}
+	for _, f := range tdef.Forbids {
+		switch f {
+		case "rival":
+			obligations = append(obligations, "no rivalry")
+		case "embargo":
+			obligations = append(obligations, "no embargo")
+		case "ally_other":
+			obligations = append(obligations, "no other alliances")
+		}
+	}
+	return benefits, obligations
+}
+
+// RouteStanding returns how relations with a route's partner scale its
+// imports, from 0.5 at -100 opinion to 1.5 at 100, and whether the partner
+// trades at all. Rivals, embargoed, warring and unknown factions don't.
+func (dm *DiplomacyManager) RouteStanding(factionKey string) (float64, bool) {
+	if dm == nil {
+		return 1, true
+	}
+	fs, ok := dm.factions[factionKey]
+	if !ok || !fs.Discovered || fs.Status == "rival" || fs.Status == "embargo" || fs.Status == "war" {
+		return 0, false
+	}
+	return 1 + float64(fs.Opinion)/200, true
+}
+
+// RecordRouteCycle records a completed trade route cycle with a partner
+// faction, raising its opinion
+func (dm *DiplomacyManager) RecordRouteCycle(factionKey string) {
+	fs, ok := dm.factions[factionKey]
+	if !ok || !fs.Discovered {
+		return
+	}
+	fs.TradeCount++
+	fs.adjustOpinion(routeCycleOpinion)
 }
 
 // Snapshot returns diplomacy state for UI
-func (dm *DiplomacyManager) Snapshot(age string, ageOrder map[string]int) DiplomacyState {
+func (dm *DiplomacyManager) Snapshot(age string, ageOrder map[string]int, tick int) DiplomacyState {
 	defs := config.FactionByKey()
 	factions := make(map[string]FactionInfo)
 
@@ -200,12 +646,54 @@ func (dm *DiplomacyManager) Snapshot(age string, ageOrder map[string]int) Diplom
 			Name:       def.Name,
 			Specialty:  def.Specialty,
 			TradeBonus: def.TradeBonus,
+			Wants:      def.Wants,
 		}
 		if exists && fs.Discovered {
 			info.Discovered = true
 			info.Opinion = fs.Opinion
 			info.Status = fs.Status
 			info.TradeCount = fs.TradeCount
+			info.Incidents = fs.Incidents
+			info.WarIncidents = warIncidents
+			if fs.Status == "war" {
+				info.WarScore = fs.WarScore
+				info.SurrenderAt = surrenderScore
+				info.PeaceTribute = PeaceTribute(fs.WarScore, age, ageOrder)
+			}
+			if fs.Request != nil {
+				rdef := config.FactionRequestByKey()[fs.Request.Key]
+				info.Request = FactionRequestInfo{
+					Key:        rdef.Key,
+					Name:       rdef.Name,
+					Resources:  fs.Request.Resources,
+					Expedition: fs.Request.Expedition,
+					TicksLeft:  fs.Request.Deadline - tick,
+					Reward:     rdef.Reward,
+					Penalty:    rdef.Penalty,
+				}
+				if fs.Request.Expedition != "" {
+					info.Request.ExpeditionName = config.ExpeditionByKey()[fs.Request.Expedition].Name
+				}
+			}
+			if fs.Proposal != "" {
+				info.Proposal = treatyInfo(config.TreatyByKey()[fs.Proposal], def, fs.ProposalExpires-tick)
+			}
+			if fs.Treaty != "" {
+				info.Treaty = treatyInfo(config.TreatyByKey()[fs.Treaty], def, fs.TreatyExpires-tick)
+			}
+			info.Disruption = fs.Disruption
+			for res := range economyGoods(def) {
+				info.Economy = append(info.Economy, FactionGoodInfo{
+					Resource: res,
+					Stock:    fs.Stock[res],
+					Capacity: economyCapacity(def, res),
+					Produces: def.Produces[res],
+					Consumes: def.Consumes[res],
+				})
+			}
+			sort.Slice(info.Economy, func(i, j int) bool {
+				return info.Economy[i].Resource < info.Economy[j].Resource
+			})
 		} else if ageOrder[age] >= ageOrder[def.MinAge] {
 			// Should be discovered but isn't yet (will be next tick)
 			info.Discovered = false
@@ -219,18 +707,50 @@ func (dm *DiplomacyManager) Snapshot(age string, ageOrder map[string]int) Diplom
 	}
 }
 
+// treatyInfo builds the UI view of a treaty proposed or in force
+func treatyInfo(tdef config.TreatyDef, def config.FactionDef, ticksLeft int) TreatyInfo {
+	benefits, obligations := treatyTerms(tdef, def)
+	return TreatyInfo{
+		Key:          tdef.Key,
+		Name:         tdef.Name,
+		Duration:     tdef.Duration,
+		TicksLeft:    ticksLeft,
+		Benefits:     benefits,
+		Obligations:  obligations,
+		BreakPenalty: tdef.BreakPenalty,
+	}
+}
+
 // LoadState restores diplomacy state from save
 func (dm *DiplomacyManager) LoadState(factions map[string]FactionStateSave) {
 	if factions == nil {
 		return
 	}
+	defs := config.FactionByKey()
 	for k, v := range factions {
-		dm.factions[k] = &FactionState{
+		fs := &FactionState{
 			Discovered: v.Discovered,
 			Opinion:    v.Opinion,
 			Status:     v.Status,
 			TradeCount: v.TradeCount,
+			Incidents:  v.Incidents,
+			WarScore:   v.WarScore,
+
+			Request:         v.Request,
+			Proposal:        v.Proposal,
+			ProposalExpires: v.ProposalExpires,
+			Treaty:          v.Treaty,
+			TreatyExpires:   v.TreatyExpires,
+			Agendas:         v.Agendas,
+
+			Stock:      v.Stock,
+			Disruption: v.Disruption,
 		}
+		// Saves from before faction economies start with half-full stockpiles
+		if def, ok := defs[k]; ok && fs.Stock == nil {
+			fs.seedEconomy(def)
+		}
+		dm.factions[k] = fs
 	}
 }
 
@@ -240,6 +760,18 @@ type FactionStateSave struct {
 	Opinion    int    `json:"opinion"`
 	Status     string `json:"status"`
 	TradeCount int    `json:"trade_count"`
+	Incidents  int    `json:"incidents,omitempty"`
+	WarScore   int    `json:"war_score,omitempty"`
+
+	Request         *FactionRequest `json:"request,omitempty"`
+	Proposal        string          `json:"proposal,omitempty"`
+	ProposalExpires int             `json:"proposal_expires,omitempty"`
+	Treaty          string          `json:"treaty,omitempty"`
+	TreatyExpires   int             `json:"treaty_expires,omitempty"`
+	Agendas         int             `json:"agendas,omitempty"`
+
+	Stock      map[string]float64 `json:"stock,omitempty"`
+	Disruption float64            `json:"disruption,omitempty"`
 }
 
 // GetFactionsForSave returns faction states for serialization
@@ -251,6 +783,18 @@ func (dm *DiplomacyManager) GetFactionsForSave() map[string]FactionStateSave {
 			Opinion:    fs.Opinion,
 			Status:     fs.Status,
 			TradeCount: fs.TradeCount,
+			Incidents:  fs.Incidents,
+			WarScore:   fs.WarScore,
+
+			Request:         fs.Request,
+			Proposal:        fs.Proposal,
+			ProposalExpires: fs.ProposalExpires,
+			Treaty:          fs.Treaty,
+			TreatyExpires:   fs.TreatyExpires,
+			Agendas:         fs.Agendas,
+
+			Stock:      fs.Stock,
+			Disruption: fs.Disruption,
 		}
 	}
 	return out
@@ -0,0 +1,240 @@
+package game
+
+import (
+	"os"
+	"testing"
+)
+
+// tickAgenda runs diplomacy ticks up to and including tick
+func tickAgenda(dm *DiplomacyManager, from, to int, stock map[string]float64, expeditions []string) []string {
+	var messages []string
+	for tick := from; tick <= to; tick++ {
+		messages = append(messages, dm.Tick("colonial_age", raidAgeOrder(), tick, stock, expeditions)...)
+	}
+	return messages
+}
+
+func TestDiplomacyManager_AgendaAlternatesTreatyAndRequest(t *testing.T) {
+	dm := NewDiplomacyManager()
+	stock := map[string]float64{"gold": 2000, "coal": 1000}
+
+	tickAgenda(dm, 1, agendaInterval, stock, nil)
+	fs := dm.factions["merchant_guild"]
+	if fs.Proposal != "non_aggression" || fs.Request != nil {
+		t.Fatalf("first agenda: proposal %q, request %v; want non_aggression and no request", fs.Proposal, fs.Request)
+	}
+	if _, err := dm.DeclineTreaty("merchant_guild"); err != nil {
+		t.Fatalf("DeclineTreaty failed: %v", err)
+	}
+	if fs.Opinion != -declinePenalty {
+		t.Errorf("opinion after decline = %d, want %d", fs.Opinion, -declinePenalty)
+	}
+
+	tickAgenda(dm, agendaInterval+1, 2*agendaInterval, stock, nil)
+	if fs.Request == nil || fs.Request.Key != "supplies" {
+		t.Fatalf("second agenda request = %+v, want supplies", fs.Request)
+	}
+	if got := fs.Request.Resources["coal"]; got != 150 {
+		t.Errorf("supplies = %v coal, want 150 (15%% of 1000)", got)
+	}
+	if fs.Proposal != "" {
+		t.Errorf("proposal = %q alongside a request, want none", fs.Proposal)
+	}
+}
+
+func TestDiplomacyManager_SkipsRequestsPlayerCantMeet(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	fs.Agendas = 1 // next agenda is a request
+
+	// Coal isn't unlocked and there are no expeditions, so only tribute is left
+	tickAgenda(dm, agendaInterval, agendaInterval, map[string]float64{"gold": 50}, nil)
+	if fs.Request == nil || fs.Request.Key != "tribute" {
+		t.Fatalf("request = %+v, want tribute", fs.Request)
+	}
+	if got := fs.Request.Resources["gold"]; got != 100 {
+		t.Errorf("tribute = %v gold, want the 100 minimum", got)
+	}
+}
+
+func TestDiplomacyManager_RequestLapses(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	fs.Request = &FactionRequest{Key: "tribute", Resources: map[string]float64{"gold": 100}, Deadline: 10}
+
+	tickAgenda(dm, 1, 9, nil, nil)
+	if fs.Request == nil {
+		t.Fatal("request lapsed before its deadline")
+	}
+	tickAgenda(dm, 10, 10, nil, nil)
+	if fs.Request != nil {
+		t.Fatal("request still open after its deadline")
+	}
+	if fs.Opinion != -15 {
+		t.Errorf("opinion = %d, want -15", fs.Opinion)
+	}
+}
+
+func TestDiplomacyManager_NonAggressionPact(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	fs.Opinion = -60
+	fs.Proposal = "non_aggression"
+	fs.ProposalExpires = 100
+
+	if _, err := dm.AcceptTreaty("merchant_guild", 0); err != nil {
+		t.Fatalf("AcceptTreaty failed: %v", err)
+	}
+	if hostile := dm.HostileFactions(); len(hostile) != 0 {
+		t.Errorf("hostile = %v under a non-aggression pact, want none", hostile)
+	}
+
+	if _, err := dm.SetStatus("merchant_guild", "rival", 0); err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	if broken := dm.BreakViolated("merchant_guild", "rival"); len(broken) != 1 {
+		t.Fatalf("broken = %v, want the pact", broken)
+	}
+	if fs.Treaty != "" {
+		t.Errorf("treaty = %q after rivalry, want broken", fs.Treaty)
+	}
+	if fs.Opinion != -60+5-30 {
+		t.Errorf("opinion = %d, want %d", fs.Opinion, -60+5-30)
+	}
+	if hostile := dm.HostileFactions(); len(hostile) != 1 {
+		t.Errorf("hostile = %v after breaking the pact, want merchant_guild", hostile)
+	}
+}
+
+func TestDiplomacyManager_ExclusiveTrade(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	dm.Discover("artisan_league")
+	dm.factions["artisan_league"].Status = "allied"
+	fs := dm.factions["merchant_guild"]
+	fs.Opinion = 40
+	fs.Proposal = "exclusive_trade"
+
+	if _, err := dm.AcceptTreaty("merchant_guild", 0); err == nil {
+		t.Fatal("signed exclusive trade while allied with another faction")
+	}
+	dm.factions["artisan_league"].Status = "neutral"
+	if _, err := dm.AcceptTreaty("merchant_guild", 0); err != nil {
+		t.Fatalf("AcceptTreaty failed: %v", err)
+	}
+	if got := dm.GetTradeBonus("gold"); got != 0.15 {
+		t.Errorf("gold trade bonus = %v, want 0.15", got)
+	}
+
+	// Allying with the treaty partner itself is fine; anyone else breaks it
+	if broken := dm.BreakViolated("merchant_guild", "allied"); len(broken) != 0 {
+		t.Errorf("allying the partner broke %v", broken)
+	}
+	if broken := dm.BreakViolated("artisan_league", "allied"); len(broken) != 1 {
+		t.Errorf("allying another faction broke %v, want the exclusive trade", broken)
+	}
+}
+
+func TestDiplomacyManager_TreatyRunsItsTerm(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	fs.Opinion = 20
+	fs.Treaty = "research_sharing"
+	fs.TreatyExpires = 5
+
+	bonuses := &ModifierStack{}
+	bonuses.Add(dm.Modifiers()...)
+	if got := bonuses.Total("knowledge_rate"); got != 0.15 {
+		t.Errorf("knowledge_rate bonus = %v, want 0.15", got)
+	}
+	tickAgenda(dm, 1, 5, nil, nil)
+	if fs.Treaty != "" {
+		t.Fatalf("treaty = %q past its term", fs.Treaty)
+	}
+	if fs.Opinion != 30 {
+		t.Errorf("opinion = %d, want 30", fs.Opinion)
+	}
+	if mods := dm.Modifiers(); len(mods) != 0 {
+		t.Errorf("modifiers = %v after the treaty ended", mods)
+	}
+}
+
+func TestDiplomacyManager_MilitaryAid(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	fs.Request = &FactionRequest{Key: "military_aid", Expedition: "raid_bandits", Deadline: 100}
+
+	if _, err := dm.RequestDue("merchant_guild"); err == nil {
+		t.Error("military aid can be paid for, want it met by winning the expedition")
+	}
+	if msgs := dm.RecordExpedition("scout_ruins"); len(msgs) != 0 {
+		t.Errorf("another expedition met the request: %v", msgs)
+	}
+	if msgs := dm.RecordExpedition("raid_bandits"); len(msgs) != 1 {
+		t.Fatalf("messages = %v, want the request met", msgs)
+	}
+	if fs.Request != nil || fs.Opinion != 20 {
+		t.Errorf("request = %v, opinion = %d; want met and 20", fs.Request, fs.Opinion)
+	}
+}
+
+func TestEngine_FulfillRequest(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Diplomacy.Discover("merchant_guild")
+	ge.Diplomacy.factions["merchant_guild"].Request = &FactionRequest{
+		Key: "tribute", Resources: map[string]float64{"gold": 100}, Deadline: 100,
+	}
+
+	ge.Resources.UnlockResource("gold")
+	ge.Resources.AddStorage("gold", 1000)
+	ge.Resources.Add("gold", 50)
+	if err := ge.FulfillRequest("merchant_guild"); err == nil {
+		t.Fatal("paid tribute without enough gold")
+	}
+	ge.Resources.Add("gold", 100)
+	if err := ge.FulfillRequest("merchant_guild"); err != nil {
+		t.Fatalf("FulfillRequest failed: %v", err)
+	}
+	if got := ge.Resources.Get("gold"); got != 50 {
+		t.Errorf("gold = %v, want 50", got)
+	}
+	if got := ge.Diplomacy.factions["merchant_guild"].Opinion; got != 10 {
+		t.Errorf("opinion = %d, want 10", got)
+	}
+}
+
+func TestEngine_SaveLoadAgenda(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Diplomacy.Discover("merchant_guild")
+	fs := ge.Diplomacy.factions["merchant_guild"]
+	fs.Request = &FactionRequest{Key: "supplies", Resources: map[string]float64{"coal": 150}, Deadline: 80}
+	fs.Proposal = "research_sharing"
+	fs.ProposalExpires = 60
+	fs.Treaty = "non_aggression"
+	fs.TreatyExpires = 300
+	fs.Agendas = 3
+	if err := ge.SaveGame("test_agenda"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_agenda.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_agenda"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	got := ge2.Diplomacy.factions["merchant_guild"]
+	if got.Request == nil || got.Request.Resources["coal"] != 150 || got.Request.Deadline != 80 {
+		t.Errorf("request = %+v, want 150 coal by tick 80", got.Request)
+	}
+	if got.Proposal != "research_sharing" || got.ProposalExpires != 60 {
+		t.Errorf("proposal = %q until %d", got.Proposal, got.ProposalExpires)
+	}
+	if got.Treaty != "non_aggression" || got.TreatyExpires != 300 || got.Agendas != 3 {
+		t.Errorf("treaty = %q until %d, agendas %d", got.Treaty, got.TreatyExpires, got.Agendas)
+	}
+}
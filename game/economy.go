@@ -0,0 +1,168 @@
+package game
+
+import (
+	"math"
+
+	"github.com/user/ageforge/config"
+)
+
+// Faction economies: every discovered faction produces and consumes a few
+// resources each tick into its own stockpile. How full the stockpiles are,
+// summed across factions, sets the market price of each resource; exchange
+// rates, route yields and the worth of a gift all follow from it.
+
+const (
+	economyStockTicks = 200  // a stockpile holds this many ticks of a faction's flow
+	priceSpread       = 0.25 // price index runs from 1-spread (full) to 1+spread (empty)
+	marketNudge       = 0.05 // share of capacity one player trade moves
+	disruptionRise    = 0.02 // per tick while a faction is a rival, under embargo or at war
+	disruptionFall    = 0.01 // per tick once relations are back to normal
+	giftOpinion       = 15   // opinion a gift earns at an index of 1
+)
+
+// seedEconomy fills a newly met faction's stockpiles halfway
+func (fs *FactionState) seedEconomy(def config.FactionDef) {
+	fs.Stock = make(map[string]float64)
+	for res := range economyGoods(def) {
+		fs.Stock[res] = economyCapacity(def, res) / 2
+	}
+}
+
+// economyGoods returns the resources a faction produces or consumes
+func economyGoods(def config.FactionDef) map[string]bool {
+	goods := make(map[string]bool)
+	for res := range def.Produces {
+		goods[res] = true
+	}
+	for res := range def.Consumes {
+		goods[res] = true
+	}
+	return goods
+}
+
+// economyCapacity is how much of a resource a faction's stockpile holds
+func economyCapacity(def config.FactionDef, res string) float64 {
+	return (def.Produces[res] + def.Consumes[res]) * economyStockTicks
+}
+
+// fillIndex maps how full a stockpile is to a price index
+func fillIndex(stock, capacity float64) float64 {
+	if capacity <= 0 {
+		return 1
+	}
+	fill := math.Max(0, math.Min(1, stock/capacity))
+	return 1 + priceSpread*(1-2*fill)
+}
+
+// tickEconomy runs one tick of every discovered faction's economy. Rivals,
+// embargoed and warring factions drift toward full disruption, which cuts
+// their output and hides their stockpiles from the market.
+func (dm *DiplomacyManager) tickEconomy() {
+	for _, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if !ok || !fs.Discovered {
+			continue
+		}
+		if fs.Stock == nil {
+			fs.seedEconomy(def)
+		}
+		if fs.Status == "rival" || fs.Status == "embargo" || fs.Status == "war" {
+			fs.Disruption = math.Min(1, fs.Disruption+disruptionRise)
+		} else {
+			fs.Disruption = math.Max(0, fs.Disruption-disruptionFall)
+		}
+		output := 1 - fs.Disruption/2
+		for res := range economyGoods(def) {
+			stock := fs.Stock[res] + def.Produces[res]*output - def.Consumes[res]
+			fs.Stock[res] = math.Max(0, math.Min(economyCapacity(def, res), stock))
+		}
+	}
+}
+
+// PriceIndex returns the market price of a resource relative to normal:
+// above 1 when faction stockpiles run low, below 1 when they overflow. A
+// disrupted faction's stockpile doesn't reach the market but still counts
+// toward what the market expects, so cutting off a supplier raises prices.
+func (dm *DiplomacyManager) PriceIndex(res string) float64 {
+	if dm == nil {
+		return 1
+	}
+	var supply, capacity float64
+	for _, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if !ok || !fs.Discovered || fs.Stock == nil {
+			continue
+		}
+		c := economyCapacity(def, res)
+		if c <= 0 {
+			continue
+		}
+		supply += fs.Stock[res] * (1 - fs.Disruption)
+		capacity += c
+	}
+	return fillIndex(supply, capacity)
+}
+
+// MarketTrade moves faction stockpiles after the player sells and buys:
+// sold resources flow into every trading faction, bought ones out of them
+func (dm *DiplomacyManager) MarketTrade(sold, bought []string) {
+	if dm == nil {
+		return
+	}
+	for _, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if !ok || !fs.Discovered || fs.Stock == nil {
+			continue
+		}
+		share := marketNudge * (1 - fs.Disruption)
+		for _, res := range sold {
+			if c := economyCapacity(def, res); c > 0 {
+				fs.Stock[res] = math.Min(c, fs.Stock[res]+c*share)
+			}
+		}
+		for _, res := range bought {
+			if c := economyCapacity(def, res); c > 0 {
+				fs.Stock[res] = math.Max(0, fs.Stock[res]-c*share)
+			}
+		}
+	}
+}
+
+// MarketMultiplier returns how faction supply and demand scale an exchange
+// of from into to, clamped to [0.5, 2]
+func (dm *DiplomacyManager) MarketMultiplier(from, to string) float64 {
+	return clampMarket(dm.PriceIndex(from) / dm.PriceIndex(to))
+}
+
+// RouteYield returns how faction supply and demand scale a trade route's
+// imports: scarce exports buy more, scarce imports cost more
+func (dm *DiplomacyManager) RouteYield(export, imports map[string]float64) float64 {
+	return clampMarket(dm.averageIndex(export) / dm.averageIndex(imports))
+}
+
+// averageIndex returns the mean price index of a resource bundle
+func (dm *DiplomacyManager) averageIndex(bundle map[string]float64) float64 {
+	if len(bundle) == 0 {
+		return 1
+	}
+	total := 0.0
+	for res := range bundle {
+		total += dm.PriceIndex(res)
+	}
+	return total / float64(len(bundle))
+}
+
+// clampMarket keeps a market multiplier within [0.5, 2]
+func clampMarket(m float64) float64 {
+	return math.Max(0.5, math.Min(2, m))
+}
+
+// giftValue returns the opinion a gold gift earns with a faction: more when
+// its own gold stockpile runs low, less when it is full
+func giftValue(def config.FactionDef, fs *FactionState) int {
+	index := 1.0
+	if c := economyCapacity(def, "gold"); c > 0 && fs.Stock != nil {
+		index = fillIndex(fs.Stock["gold"], c)
+	}
+	return int(math.Round(giftOpinion * index))
+}
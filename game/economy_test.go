@@ -0,0 +1,127 @@
+package game
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+func TestFactionEconomy_PricesFollowStockpiles(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	if got := dm.PriceIndex("coal"); got != 1 {
+		t.Fatalf("coal index on discovery = %v, want 1 (half-full stockpile)", got)
+	}
+
+	for i := 0; i < economyStockTicks; i++ {
+		dm.tickEconomy()
+	}
+	// The guild burns coal and mints gold
+	if got := dm.PriceIndex("coal"); got != 1+priceSpread {
+		t.Errorf("coal index = %v, want %v once the guild runs out", got, 1+priceSpread)
+	}
+	if got := dm.PriceIndex("gold"); got != 1-priceSpread {
+		t.Errorf("gold index = %v, want %v once the guild's vaults are full", got, 1-priceSpread)
+	}
+	if got := dm.PriceIndex("stone"); got != 1 {
+		t.Errorf("stone index = %v, want 1 for a resource no faction trades", got)
+	}
+
+	tm := NewTradeManager()
+	base := 0.08 // coal:gold in config/trade.go
+	if got, want := tm.GetExchangeRate("coal", "gold", dm), base*(1+priceSpread)/(1-priceSpread); math.Abs(got-want) > 1e-9 {
+		t.Errorf("coal→gold = %v, want %v", got, want)
+	}
+}
+
+func TestFactionEconomy_EmbargoDisruptsPricesOverTime(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	for i := 0; i < economyStockTicks; i++ {
+		dm.tickEconomy()
+	}
+	before := dm.PriceIndex("gold")
+
+	dm.factions["merchant_guild"].Status = "embargo"
+	dm.tickEconomy()
+	soon := dm.PriceIndex("gold")
+	if soon <= before || soon >= 1 {
+		t.Errorf("gold index one tick into the embargo = %v, want a little above %v", soon, before)
+	}
+	for i := 0; i < 60; i++ {
+		dm.tickEconomy()
+	}
+	if got := dm.PriceIndex("gold"); got != 1+priceSpread {
+		t.Errorf("gold index after a long embargo = %v, want %v", got, 1+priceSpread)
+	}
+
+	// Lifting it lets the guild back into the market gradually
+	dm.factions["merchant_guild"].Status = "neutral"
+	dm.tickEconomy()
+	if fs := dm.factions["merchant_guild"]; fs.Disruption != 1-disruptionFall {
+		t.Errorf("disruption = %v, want %v", fs.Disruption, 1-disruptionFall)
+	}
+}
+
+func TestFactionEconomy_RouteYieldAndMarketTrade(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	for i := 0; i < economyStockTicks; i++ {
+		dm.tickEconomy()
+	}
+	// Coal is scarce and gold plentiful, so selling coal for gold pays
+	yield := dm.RouteYield(map[string]float64{"coal": 10}, map[string]float64{"gold": 1})
+	if yield <= 1 {
+		t.Errorf("coal→gold yield = %v, want above 1", yield)
+	}
+
+	dm.MarketTrade([]string{"coal"}, []string{"gold"})
+	fs := dm.factions["merchant_guild"]
+	if got, want := fs.Stock["coal"], marketNudge*400; got != want {
+		t.Errorf("coal stock after selling = %v, want %v", got, want)
+	}
+	if dm.RouteYield(map[string]float64{"coal": 10}, map[string]float64{"gold": 1}) >= yield {
+		t.Error("yield didn't drop after the market took coal and gave gold")
+	}
+}
+
+func TestFactionEconomy_GiftsWorthMoreWhenShort(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("artisan_league")
+	fs := dm.factions["artisan_league"]
+	fs.Stock["gold"] = 0
+
+	if _, gain, err := dm.SendGift("artisan_league", 1000); err != nil || gain != 19 {
+		t.Fatalf("first gift gain = %d (err %v), want 19", gain, err)
+	}
+	if _, gain, _ := dm.SendGift("artisan_league", 1000); gain != 11 {
+		t.Errorf("second gift gain = %d, want 11 once the league's gold is full", gain)
+	}
+}
+
+func TestEngine_SaveLoadFactionEconomy(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Diplomacy.Discover("merchant_guild")
+	fs := ge.Diplomacy.factions["merchant_guild"]
+	fs.Stock["coal"] = 42
+	fs.Disruption = 0.3
+	if err := ge.SaveGame("test_economy"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_economy.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_economy"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	got := ge2.Diplomacy.factions["merchant_guild"]
+	if got.Stock["coal"] != 42 || got.Disruption != 0.3 {
+		t.Errorf("stock coal = %v, disruption = %v; want 42 and 0.3", got.Stock["coal"], got.Disruption)
+	}
+
+	// Older saves have no stockpiles and start half full
+	ge2.Diplomacy.LoadState(map[string]FactionStateSave{"artisan_league": {Discovered: true, Status: "neutral"}})
+	if got := ge2.Diplomacy.factions["artisan_league"].Stock["steel"]; got != 200 {
+		t.Errorf("legacy steel stock = %v, want 200", got)
+	}
+}
@@ -2,6 +2,9 @@ package game
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -27,11 +30,13 @@ type GameEngine struct {
 	Villagers  *VillagerManager
 	Research   *ResearchManager
 	Military   *MilitaryManager
+	Raids      *RaidManager
 	Events     *EventManager
 	Milestones *MilestoneManager
 	Prestige   *PrestigeManager
 	Trade      *TradeManager
 	Diplomacy  *DiplomacyManager
+	Settlement *SettlementManager
 	Stats      *GameStats
 	Bus        *EventBus
 
@@ -42,12 +47,18 @@ type GameEngine struct {
 	stopCh     chan struct{}
 	stopOnce   sync.Once
 
-	// Permanent bonuses from milestones
-	permanentBonuses map[string]float64
+	// Modifier stack as of the last recalculateRates; derived stats read it
+	stack *ModifierStack
 
 	// Dynamic tick speed
 	tickSpeedBonus  float64
 	speedMultiplier float64
+
+	// Recent resource states, oldest first, for ExplainResource
+	rateHistory []rateSample
+
+	// Building counts over this run, oldest first, for the map timelapse
+	timelapse []TimelapseFrame
 }
 
 // BuildQueueItem represents a building under construction
@@ -66,15 +77,16 @@ func NewGameEngine() *GameEngine {
 		Villagers:        NewVillagerManager(),
 		Research:         NewResearchManager(),
 		Military:         NewMilitaryManager(),
+		Raids:            NewRaidManager(),
 		Events:           NewEventManager(),
 		Milestones:       NewMilestoneManager(),
 		Prestige:         NewPrestigeManager(),
 		Trade:            NewTradeManager(),
 		Diplomacy:        NewDiplomacyManager(),
+		Settlement:       NewSettlementManager(newWorldSeed()),
 		Stats:            NewGameStats(),
 		Bus:              NewEventBus(),
 		progress:         NewProgressManager(),
-		permanentBonuses: make(map[string]float64),
 		speedMultiplier:  1.0,
 		stopCh:           make(chan struct{}),
 	}
@@ -82,6 +94,7 @@ func NewGameEngine() *GameEngine {
 	// Give starting resources — enough for first hut + a little food
 	ge.Resources.Add("food", 15)
 	ge.Resources.Add("wood", 12)
+	ge.recalculateRates()
 	// Startup tutorial
 	ge.addLog("event", "Welcome to AgeForge! You have nothing but your hands.")
 	ge.addLog("info", "[gold]Getting Started:[-]")
@@ -164,18 +177,11 @@ func (ge *GameEngine) getTickInterval() time.Duration {
 	return interval
 }
 
-// recalculateTickSpeed sums all tick speed bonuses (must be called with lock held)
+// recalculateTickSpeed sums all tick speed bonuses, event boosts included
+// (must be called with lock held)
 func (ge *GameEngine) recalculateTickSpeed() {
 	oldBonus := ge.tickSpeedBonus
-	bonus := ge.Research.GetBonus("tick_speed") +
-		ge.permanentBonuses["tick_speed"] +
-		ge.Prestige.GetBonuses()["tick_speed"]
-	// Add active event tick_speed effects (e.g., milestone chain boosts)
-	for _, eff := range ge.Events.GetActiveEffects() {
-		if eff.Type == "tick_speed" {
-			bonus += eff.Value
-		}
-	}
+	bonus := ge.stack.Total("tick_speed")
 	ge.tickSpeedBonus = bonus
 
 	if bonus != oldBonus {
@@ -253,6 +259,9 @@ func (ge *GameEngine) doTick() {
 		ge.addLog("debug", fmt.Sprintf("Build queue: %d item(s) in progress", len(ge.buildQueue)))
 	}
 
+	// Process soldier training
+	ge.processTraining()
+
 	// Process research
 	ge.processResearch()
 
@@ -262,6 +271,10 @@ func (ge *GameEngine) doTick() {
 	// Process expeditions
 	ge.processExpeditions()
 
+	// Process incoming raids
+	ge.processRaids()
+	ge.Military.Reconcile(ge.soldierCount())
+
 	// Process trade routes
 	ge.processTrade()
 
@@ -274,6 +287,13 @@ func (ge *GameEngine) doTick() {
 	// Apply resource rates (production - consumption)
 	ge.Resources.ApplyRates()
 
+	// Soldiers whose upkeep went unpaid lose morale
+	ge.processMorale()
+	ge.recordRates()
+	if ge.tick%TimelapseInterval == 0 {
+		ge.recordTimelapse()
+	}
+
 	// Log net food rate and capped resources every 10 ticks
 	if ge.tick%10 == 0 {
 		snap := ge.Resources.Snapshot()
@@ -340,7 +360,7 @@ func (ge *GameEngine) processResearch() {
 // processEvents handles random events
 func (ge *GameEngine) processEvents() {
 	ageOrder := ge.progress.GetAgeOrder()
-	triggered, expired := ge.Events.Tick(ge.tick, ge.age, ageOrder)
+	triggered, expired := ge.Events.Tick(ge.tick, ge.age, ageOrder, ge.Settlement.World().EventWeight)
 
 	for _, def := range triggered {
 		ge.addLog("debug", fmt.Sprintf("Event triggered: %s (sentiment: %s)", def.Name, def.Sentiment))
@@ -372,26 +392,256 @@ func (ge *GameEngine) processEvents() {
 
 // processExpeditions handles military expedition progress
 func (ge *GameEngine) processExpeditions() {
-	prestigeBonuses := ge.Prestige.GetBonuses()
-	militaryBonus := ge.Research.GetBonus("military_power") + ge.permanentBonuses["military_power"] + prestigeBonuses["military_power"]
-	expeditionBonus := ge.Research.GetBonus("expedition_reward") + ge.permanentBonuses["expedition_reward"] + prestigeBonuses["expedition_reward"]
+	militaryBonus := ge.militaryBonus()
+	expeditionBonus := ge.expeditionBonus()
 
-	if ge.Military.active != nil {
-		ge.addLog("debug", fmt.Sprintf("Expedition: %s %d ticks left", ge.Military.active.Name, ge.Military.active.TicksLeft))
+	for _, a := range ge.Military.active {
+		ge.addLog("debug", fmt.Sprintf("Expedition: %s %d ticks left", a.Name, a.TicksLeft))
 	}
-	rewards, message, soldiersLost := ge.Military.Tick(militaryBonus, expeditionBonus)
-	if message != "" {
-		ge.addLog("debug", fmt.Sprintf("Expedition resolved (soldiers lost: %d, rewards: %d types)", soldiersLost, len(rewards)))
-		ge.addLog("event", message)
+	for _, res := range ge.Military.Tick(militaryBonus, expeditionBonus) {
+		ge.addLog("debug", fmt.Sprintf("Expedition resolved (soldiers lost: %d, rewards: %d types)", res.SoldiersLost, len(res.Rewards)))
+		ge.addLog("event", res.Message)
 		// Add rewards to resources
-		for res, amount := range rewards {
-			ge.Resources.Add(res, amount)
+		for r, amount := range res.Rewards {
+			ge.Resources.Add(r, amount)
 		}
 		// Remove lost soldiers
-		if soldiersLost > 0 {
-			ge.Villagers.RemoveSoldiers(soldiersLost)
+		if res.SoldiersLost > 0 {
+			ge.Villagers.RemoveSoldiers(res.SoldiersLost)
+		}
+		ge.applyCampaignRewards(res)
+		if res.Won {
+			for _, msg := range ge.Diplomacy.RecordExpedition(res.Key) {
+				ge.addLog("success", msg)
+			}
+			if faction, ok := warFrontFaction(res.Key); ok {
+				if msg := ge.Diplomacy.RecordWarVictory(faction, ge.tick); msg != "" {
+					ge.addLog("success", msg)
+					ge.recalculateRates()
+				}
+			}
+		}
+	}
+}
+
+// applyCampaignRewards grants the faction and tech earned by completing a
+// campaign (must be called with lock held)
+func (ge *GameEngine) applyCampaignRewards(res ExpeditionResult) {
+	if res.Faction != "" && ge.Diplomacy.Discover(res.Faction) {
+		def := config.FactionByKey()[res.Faction]
+		ge.addLog("success", fmt.Sprintf("Discovered faction: %s — %s", def.Name, def.Description))
+	}
+	if res.Tech != "" && ge.Research.Grant(res.Tech) {
+		def := config.TechByKey()[res.Tech]
+		ge.addLog("success", fmt.Sprintf("Unique technology acquired: %s!", def.Name))
+		ge.Bus.Publish(EventData{
+			Type:    EventResearchDone,
+			Payload: map[string]interface{}{"tech": res.Tech},
+		})
+	}
+}
+
+// expeditionSlots returns how many expeditions may run at once (must be called with lock held)
+func (ge *GameEngine) expeditionSlots() int {
+	return BaseExpeditionSlots + ge.Buildings.ExpeditionSlots() + int(ge.stack.Total("expedition_slots"))
+}
+
+// trainingSlots returns how many units train at once (must be called with lock held)
+func (ge *GameEngine) trainingSlots() int {
+	return BaseTrainingSlots + ge.Buildings.TrainingSlots()
+}
+
+// militaryBonus returns the total military_power bonus (must be called with lock held)
+func (ge *GameEngine) militaryBonus() float64 {
+	return ge.stack.Total("military_power")
+}
+
+// expeditionBonus returns the total expedition_reward bonus (must be called with lock held)
+func (ge *GameEngine) expeditionBonus() float64 {
+	return ge.stack.Total("expedition_reward")
+}
+
+// defenseRating returns the defense of soldiers at home (must be called with lock held)
+func (ge *GameEngine) defenseRating() float64 {
+	return ge.Military.CalculateDefense(ge.Military.HomeUnits(ge.soldierCount()), ge.militaryBonus())
+}
+
+// soldierCount returns how many soldiers there are, home and away (must be called with lock held)
+func (ge *GameEngine) soldierCount() int {
+	if st, ok := ge.Villagers.types["soldier"]; ok {
+		return st.count
+	}
+	return 0
+}
+
+// processTraining advances the training queue and musters finished recruits
+func (ge *GameEngine) processTraining() {
+	done := ge.Military.TickTraining(ge.trainingSlots())
+	if len(done) == 0 {
+		return
+	}
+	if n := done[infantryUnit]; n > 0 {
+		ge.Villagers.types["soldier"].count += n
+		ge.Stats.RecordRecruit(n)
+	}
+	ge.addLog("success", fmt.Sprintf("Finished training: %s", FormatUnits(done)))
+	ge.recalculateRates()
+}
+
+// processMorale lowers morale while any unit upkeep resource has run out and
+// removes deserters once morale collapses
+func (ge *GameEngine) processMorale() {
+	soldiers := ge.soldierCount()
+	var unpaid []string
+	for res, cost := range ge.Military.Upkeep(soldiers) {
+		if cost > 0 && ge.Resources.Get(res) <= 0 {
+			unpaid = append(unpaid, res)
+		}
+	}
+	sort.Strings(unpaid)
+
+	before := ge.Military.Morale()
+	deserters := ge.Military.TickMorale(len(unpaid) == 0, soldiers)
+	if len(unpaid) > 0 && before == moraleMax {
+		ge.addLog("warning", fmt.Sprintf("Your soldiers are going unpaid (no %s)! Morale is falling.", strings.Join(unpaid, ", ")))
+	}
+	if UnitTotal(deserters) > 0 {
+		ge.removeHomeCasualties(deserters)
+		ge.Military.Reconcile(ge.soldierCount())
+		ge.addLog("warning", fmt.Sprintf("Unpaid soldiers deserted: %s (morale %.0f%%)", FormatUnits(deserters), ge.Military.Morale()))
+	}
+}
+
+// removeHomeCasualties removes soldiers killed defending the settlement
+// (must be called with lock held)
+func (ge *GameEngine) removeHomeCasualties(losses map[string]int) {
+	for u, n := range losses {
+		if u != infantryUnit {
+			ge.Military.units[u] -= n
+		}
+	}
+	ge.Villagers.RemoveSoldiers(UnitTotal(losses))
+}
+
+// processRaids handles raid warnings and resolves raids that arrive
+func (ge *GameEngine) processRaids() {
+	ageOrder := ge.progress.GetAgeOrder()
+	sighted, arrived := ge.Raids.Tick(ge.tick, ge.age, ageOrder, ge.Diplomacy.HostileFactions(), ge.Diplomacy.AtWar())
+	if sighted != nil {
+		ge.addLog("warning", fmt.Sprintf("⚔ %s sighted! They attack in %d ticks (%s vs your defense %.0f)",
+			sighted.DisplayName(), sighted.TicksLeft, FormatUnits(sighted.Enemy), ge.defenseRating()))
+	}
+	if arrived == nil {
+		return
+	}
+
+	defenders := ge.Military.HomeUnits(ge.soldierCount())
+	outcome := ge.Raids.Resolve(arrived, defenders, ge.militaryBonus())
+	ge.addLog("debug", fmt.Sprintf("Raid resolved: %s (%s) vs %s in %d round(s) (won: %v, severity: %.2f)",
+		arrived.DisplayName(), FormatUnits(arrived.Enemy), FormatUnits(defenders), outcome.Battle.Rounds, outcome.Won, outcome.Severity))
+	ge.removeHomeCasualties(outcome.Battle.DefenderLosses)
+	fallen := ""
+	if lost := outcome.Battle.DefenderLosses; UnitTotal(lost) > 0 {
+		fallen = fmt.Sprintf(" Fell: %s.", FormatUnits(lost))
+	}
+
+	var msg string
+	if outcome.Won {
+		loot := make(map[string]float64)
+		for res, amount := range RaidLoot(arrived.Strength) {
+			if ge.Resources.IsUnlocked(res) {
+				ge.Resources.Add(res, amount)
+				loot[res] = amount
+			}
+		}
+		msg = fmt.Sprintf("Repelled %s! Spoils: %s.%s", arrived.DisplayName(), formatCost(loot), fallen)
+		ge.addLog("success", msg)
+	} else {
+		msg = fmt.Sprintf("%s overran your defenses!%s", arrived.DisplayName(), fallen)
+		if losses := ge.applyRaidLosses(outcome.Severity); losses != "" {
+			msg += " " + losses
+		}
+		ge.addLog("warning", msg)
+		if war := ge.Diplomacy.RecordRaidLost(arrived.Faction); war != "" {
+			ge.addLog("warning", war)
+		}
+	}
+	ge.Raids.SetResult(msg)
+}
+
+// applyRaidLosses steals resources, burns buildings and kills civilians after
+// a lost raid; fallen soldiers come from the battle. Returns a summary of the
+// damage (must be called with lock held).
+func (ge *GameEngine) applyRaidLosses(severity float64) string {
+	var parts []string
+
+	// Stolen stockpiles; knowledge can't be carried off
+	share := RaidTheftShare(severity)
+	stolen := make(map[string]float64)
+	for key, amount := range ge.Resources.GetAll() {
+		if key == "knowledge" || amount <= 0 || !ge.Resources.IsUnlocked(key) {
+			continue
+		}
+		take := math.Floor(amount * share)
+		if take > 0 {
+			ge.Resources.Remove(key, take)
+			stolen[key] = take
+		}
+	}
+	if len(stolen) > 0 {
+		parts = append(parts, "Stolen: "+formatCost(stolen)+".")
+	}
+
+	// Burned buildings; wonders are spared
+	if n := RaidBuildingsDamaged(severity); n > 0 {
+		burned := make(map[string]int)
+		for i := 0; i < n; i++ {
+			var candidates []string
+			for key, count := range ge.Buildings.counts {
+				if count > 0 && ge.Buildings.defs[key].Category != "wonder" {
+					candidates = append(candidates, key)
+				}
+			}
+			if len(candidates) == 0 {
+				break
+			}
+			sort.Strings(candidates)
+			key := candidates[rand.Intn(len(candidates))]
+			burned[key] += ge.Buildings.Destroy(key, 1)
+		}
+		if len(burned) > 0 {
+			var names []string
+			for key, count := range burned {
+				names = append(names, fmt.Sprintf("%d %s", count, ge.Buildings.defs[key].Name))
+			}
+			sort.Strings(names)
+			parts = append(parts, "Burned: "+strings.Join(names, ", ")+".")
+			ge.recalculateRates()
+			ge.dismissHomeless()
 		}
 	}
+
+	// Civilians die when the rout is complete
+	if n := RaidVillagersLost(severity); n > 0 {
+		killed := 0
+		for i := 0; i < n; i++ {
+			var candidates []string
+			for key, rt := range ge.Villagers.types {
+				if key != "soldier" && rt.count > 0 {
+					candidates = append(candidates, key)
+				}
+			}
+			if len(candidates) == 0 {
+				break
+			}
+			sort.Strings(candidates)
+			killed += ge.Villagers.RemoveVillagers(candidates[rand.Intn(len(candidates))], 1)
+		}
+		if killed > 0 {
+			parts = append(parts, fmt.Sprintf("%d villager(s) killed.", killed))
+		}
+	}
+	return strings.Join(parts, " ")
 }
 
 // processTrade handles trade route ticks
@@ -405,10 +655,39 @@ func (ge *GameEngine) processTrade() {
 // processDiplomacy handles diplomacy ticks
 func (ge *GameEngine) processDiplomacy() {
 	ageOrder := ge.progress.GetAgeOrder()
-	messages := ge.Diplomacy.Tick(ge.age, ageOrder, ge.tick)
+	stock := make(map[string]float64)
+	for key, rs := range ge.Resources.Snapshot() {
+		if rs.Unlocked {
+			stock[key] = rs.Amount
+		}
+	}
+	var expeditions []string
+	for _, def := range ge.Military.GetAvailableExpeditions(ge.age, ageOrder) {
+		expeditions = append(expeditions, def.Key)
+	}
+	messages := ge.Diplomacy.Tick(ge.age, ageOrder, ge.tick, stock, expeditions)
 	for _, msg := range messages {
 		ge.addLog("event", msg)
 	}
+	ge.refreshWarFronts()
+}
+
+// refreshWarFronts offers a war front expedition against each faction at
+// war, keeping fronts with troops still out so they can come home (must be
+// called with lock held)
+func (ge *GameEngine) refreshWarFronts() {
+	ageOrder := ge.progress.GetAgeOrder()
+	factions := ge.Diplomacy.AtWar()
+	for _, a := range ge.Military.active {
+		if faction, ok := warFrontFaction(a.Key); ok && !containsKey(factions, faction) {
+			factions = append(factions, faction)
+		}
+	}
+	var fronts []config.ExpeditionDef
+	for _, faction := range factions {
+		fronts = append(fronts, WarFront(faction, ge.age, ageOrder))
+	}
+	ge.Military.SetWarFronts(fronts)
 }
 
 // checkMilestones checks for newly completed milestones and chains
@@ -447,13 +726,10 @@ func (ge *GameEngine) checkMilestones() {
 	for _, ms := range completed {
 		rewardText := formatMilestoneRewards(ms.Rewards)
 		ge.addLog("success", fmt.Sprintf("Milestone achieved: %s!", ms.Name))
-		// Apply rewards
+		// Apply instant rewards; permanent bonuses come from Milestones.Modifiers
 		for _, eff := range ms.Rewards {
-			switch eff.Type {
-			case "instant_resource":
+			if eff.Type == "instant_resource" {
 				ge.Resources.Add(eff.Target, eff.Value)
-			case "permanent_bonus":
-				ge.permanentBonuses[eff.Target] += eff.Value
 			}
 		}
 		// Publish milestone event
@@ -493,170 +769,74 @@ func (ge *GameEngine) checkMilestones() {
 
 	// Recalculate title
 	ge.Milestones.recalculateTitle()
-}
-
-// recalculateRates recalculates all resource production rates
-func (ge *GameEngine) recalculateRates() {
-	// Reset all rates and breakdowns
-	for _, def := range ge.Resources.defs {
-		r := ge.Resources.resources[def.Key]
-		if r != nil {
-			r.Rate = 0
-			r.Breakdown = RateBreakdown{}
-		}
-	}
-
-	// Building production
-	for _, eff := range ge.Buildings.GetEffects() {
-		if eff.Type == "production" {
-			r := ge.Resources.resources[eff.Target]
-			if r != nil {
-				r.Rate += eff.Value
-				r.Breakdown.BuildingRate += eff.Value
-			}
-		}
-	}
-
-	// Villager production
-	for res, rate := range ge.Villagers.GetProductionRates() {
-		r := ge.Resources.resources[res]
-		if r != nil {
-			r.Rate += rate
-			r.Breakdown.VillagerRate += rate
-		}
-	}
-
-	// Research bonuses to production rates
-	researchBonuses := ge.Research.GetBonuses()
-	permanentBonuses := make(map[string]float64)
-	for k, v := range ge.permanentBonuses {
-		permanentBonuses[k] = v
-	}
-	// Add prestige bonuses
-	for k, v := range ge.Prestige.GetBonuses() {
-		permanentBonuses[k] += v
-	}
-
-	// Apply production_all bonus (multiplier on all positive rates)
-	prodAllBonus := researchBonuses["production_all"] + permanentBonuses["production_all"]
-	if prodAllBonus > 0 {
-		for _, def := range ge.Resources.defs {
-			r := ge.Resources.resources[def.Key]
-			if r != nil && r.Rate > 0 {
-				r.Rate *= (1.0 + prodAllBonus)
-			}
-		}
-	}
-
-	// Apply per-resource rate bonuses (e.g., "gold_rate", "iron_rate")
-	for _, def := range ge.Resources.defs {
-		bonusKey := def.Key + "_rate"
-		bonus := researchBonuses[bonusKey] + permanentBonuses[bonusKey]
-		if bonus > 0 {
-			r := ge.Resources.resources[def.Key]
-			if r != nil && r.Rate > 0 {
-				r.Rate *= (1.0 + bonus)
-			}
-		}
-	}
-
-	// Apply gather_rate bonus to villager-generated rates
-	gatherBonus := researchBonuses["gather_rate"] + permanentBonuses["gather_rate"]
-	if gatherBonus > 0 {
-		// Already applied via multiplier above
-		// This is additive on base villager rates — re-add the bonus portion
-		for res, rate := range ge.Villagers.GetProductionRates() {
-			r := ge.Resources.resources[res]
-			if r != nil {
-				r.Rate += rate * gatherBonus
-			}
-		}
-	}
-
-	// Research production effects (direct production from techs)
-	for _, eff := range ge.getAllResearchProductionEffects() {
-		if eff.Type == "production" {
-			r := ge.Resources.resources[eff.Target]
-			if r != nil {
-				r.Rate += eff.Value
-				r.Breakdown.ResearchRate += eff.Value
-			}
-		}
-	}
-
-	// Active event effects on production
-	for _, eff := range ge.Events.GetActiveEffects() {
-		if eff.Type == "production" {
-			r := ge.Resources.resources[eff.Target]
-			if r != nil {
-				r.Rate += eff.Value
-				r.Breakdown.EventRate += eff.Value
-			}
-		}
+	if len(completed) > 0 || len(newChains) > 0 {
+		ge.stack = ge.modifiers()
 	}
+}
 
-	// Diplomacy trade bonuses on specific resource rates
-	for _, def := range ge.Resources.defs {
-		bonus := ge.Diplomacy.GetTradeBonus(def.Key)
-		if bonus > 0 {
-			r := ge.Resources.resources[def.Key]
-			if r != nil && r.Rate > 0 {
-				tradeBonus := r.Rate * bonus
-				r.Rate += tradeBonus
-				r.Breakdown.TradeRate += tradeBonus
-			}
-		}
-	}
+// modifiers collects the modifier stack from every source (must be called
+// with lock held). Most callers want ge.stack, which recalculateRates keeps.
+func (ge *GameEngine) modifiers() *ModifierStack {
+	stack := &ModifierStack{}
+	stack.Add(ge.Buildings.Modifiers()...)
+	stack.Add(ge.Villagers.Modifiers()...)
+	stack.Add(ge.Research.Modifiers()...)
+	stack.Add(ge.Milestones.Modifiers()...)
+	stack.Add(ge.Prestige.Modifiers()...)
+	stack.Add(ge.Events.Modifiers()...)
+	stack.Add(ge.Diplomacy.Modifiers()...)
+	stack.Add(ge.Settlement.Modifiers(ge.Buildings)...)
+	stack.Add(ge.Settlement.World().Modifiers()...)
+
+	// Military unit upkeep
+	upkeep := ge.Military.Upkeep(ge.soldierCount())
+	resources := make([]string, 0, len(upkeep))
+	for res := range upkeep {
+		resources = append(resources, res)
+	}
+	sort.Strings(resources)
+	for _, res := range resources {
+		stack.Add(Modifier{Stat: res, Layer: LayerFinal, Value: -upkeep[res], Source: SourceMilitary, Key: "upkeep", Name: "Unit upkeep"})
+	}
+	return stack
+}
 
-	// Food consumption
-	drain := ge.Villagers.FoodDrain()
-	if drain > 0 {
-		r := ge.Resources.resources["food"]
-		if r != nil {
-			r.Rate -= drain
-			r.Breakdown.FoodDrain = -drain
-		}
-	}
+// popCap returns the population capacity (must be called with lock held)
+func (ge *GameEngine) popCap() int {
+	return int(ge.stack.Total("population"))
+}
 
-	// Calculate bonus rates (the difference from multipliers)
+// recalculateRates lays out any new or lost buildings, decides how fully
+// recipe buildings run and how far the power grid reaches, then rebuilds the
+// modifier stack and recalculates all resource production rates and storage
+// caps from it
+func (ge *GameEngine) recalculateRates() {
+	ge.Settlement.Sync(ge.Buildings.counts)
+	ge.Buildings.RunRecipes(ge.Resources)
+	stack := ge.modifiers()
+	ge.Buildings.RunGrid(ge.powerSupply(stack))
+	// Only buildings and their placement bonuses follow the grid
+	stack.Replace(SourceBuilding, ge.Buildings.Modifiers())
+	stack.Replace(SourceSettlement, ge.Settlement.Modifiers(ge.Buildings))
+	ge.stack = stack
 	for _, def := range ge.Resources.defs {
 		r := ge.Resources.resources[def.Key]
-		if r != nil {
-			knownComponents := r.Breakdown.BuildingRate + r.Breakdown.VillagerRate +
-				r.Breakdown.ResearchRate + r.Breakdown.EventRate + r.Breakdown.TradeRate + r.Breakdown.FoodDrain
-			r.Breakdown.BonusRate = r.Rate - knownComponents
+		if r == nil {
+			continue
 		}
-	}
-
-	// Recalculate storage from buildings + research + milestones
-	storageBonuses := ge.Buildings.GetStorageBonuses()
-	allBonus := storageBonuses["all"]
-	// Add storage bonuses from research
-	allBonus += researchBonuses["all"] // storage type effects
-	allBonus += permanentBonuses["all"]
-
-	for _, def := range ge.Resources.defs {
-		specific := storageBonuses[def.Key]
-		specific += researchBonuses[def.Key]
-		specific += permanentBonuses[def.Key]
-		ge.Resources.resources[def.Key].Storage = def.BaseStorage + allBonus + specific
+		r.Rate, r.Breakdown = stack.Rate(def.Key)
+		r.Storage = def.BaseStorage + stack.Storage(def.Key)
 	}
 }
 
-// getAllResearchProductionEffects returns production effects from researched techs
-func (ge *GameEngine) getAllResearchProductionEffects() []config.Effect {
-	var effects []config.Effect
-	allTechs := config.TechByKey()
-	for _, key := range ge.Research.GetResearched() {
-		if def, ok := allTechs[key]; ok {
-			for _, eff := range def.Effects {
-				if eff.Type == "production" {
-					effects = append(effects, eff)
-				}
-			}
-		}
+// powerSupply returns this tick's electricity generation: the rate before
+// anything draws on it. The stockpile can't power buildings.
+func (ge *GameEngine) powerSupply(stack *ModifierStack) float64 {
+	_, b := stack.Rate("electricity")
+	if b.Base <= 0 {
+		return 0
 	}
-	return effects
+	return b.Base * b.Multiplier
 }
 
 // advanceAge advances to the next age
@@ -665,6 +845,7 @@ func (ge *GameEngine) advanceAge(newAge string) {
 	ge.age = newAge
 	ge.applyAgeUnlocks(newAge)
 	ge.Stats.RecordAge(newAge)
+	ge.recordTimelapse()
 
 	// Reduce all resources to 25% on age transition
 	for _, r := range ge.Resources.resources {
@@ -881,6 +1062,74 @@ func (ge *GameEngine) BuildMultiple(key string, count int) (int, error) {
 	return built, nil
 }
 
+// Demolish tears down up to n of a building, switched-off ones first, and
+// refunds part of what they cost. Villagers beyond the smaller population
+// cap are dismissed. Returns how many were demolished.
+func (ge *GameEngine) Demolish(key string, n int) (int, error) {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	def, ok := ge.Buildings.defs[key]
+	if !ok {
+		return 0, fmt.Errorf("unknown building: %s", key)
+	}
+	if def.Category == "wonder" {
+		return 0, fmt.Errorf("%s is a wonder and can't be demolished", def.Name)
+	}
+	count := ge.Buildings.GetCount(key)
+	if count == 0 {
+		return 0, fmt.Errorf("no %s to demolish", def.Name)
+	}
+	if n > count {
+		n = count
+	}
+
+	refund := ge.Buildings.DemolishRefund(key, n)
+	ge.Buildings.Destroy(key, n)
+	ge.recalculateRates()
+	ge.Resources.ClampToStorage()
+	for res, amount := range refund {
+		ge.Resources.Add(res, amount)
+	}
+	ge.addLog("info", fmt.Sprintf("Demolished %d %s (refund: %s)", n, def.Name, formatCost(refund)))
+	ge.dismissHomeless()
+	return n, nil
+}
+
+// dismissHomeless sends away villagers the housing can no longer hold,
+// idle ones first (must be called with lock held)
+func (ge *GameEngine) dismissHomeless() {
+	excess := ge.Villagers.TotalPop() - ge.popCap()
+	if excess <= 0 {
+		return
+	}
+	dismissed := ge.Villagers.Dismiss(excess)
+	types := make([]string, 0, len(dismissed))
+	for vType, k := range dismissed {
+		types = append(types, fmt.Sprintf("%d %s", k, vType))
+	}
+	sort.Strings(types)
+	if len(types) > 0 {
+		ge.addLog("warning", fmt.Sprintf("Not enough housing: dismissed %s", strings.Join(types, ", ")))
+		ge.recalculateRates()
+	}
+}
+
+// SetDisabled switches off n of a building's instances and the rest back on
+func (ge *GameEngine) SetDisabled(key string, n int) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	if err := ge.Buildings.SetDisabled(key, n); err != nil {
+		return err
+	}
+	count := ge.Buildings.GetCount(key)
+	ge.addLog("info", fmt.Sprintf("%s: %d of %d running", ge.Buildings.defs[key].Name, count-n, count))
+	ge.recalculateRates()
+	ge.dismissHomeless()
+	return nil
+}
+
 // RecruitMax recruits as many villagers as possible up to the pop cap
 func (ge *GameEngine) RecruitMax(vType string) (int, error) {
 	ge.mu.Lock()
@@ -890,14 +1139,19 @@ func (ge *GameEngine) RecruitMax(vType string) (int, error) {
 		return 0, fmt.Errorf("villager type '%s' is not yet unlocked", vType)
 	}
 
-	popCap := ge.Buildings.GetPopCapacity()
-	popCap += int(ge.Research.GetBonus("population") + ge.permanentBonuses["population"] + ge.Prestige.GetBonuses()["population"])
+	popCap := ge.popCap()
+
+	// Recruits in training already hold their place in the population
+	popCap -= ge.Military.QueuedRecruits()
 
 	available := popCap - ge.Villagers.TotalPop()
 	if available <= 0 {
 		return 0, fmt.Errorf("population cap reached (%d/%d)", ge.Villagers.TotalPop(), popCap)
 	}
 
+	if vType == "soldier" {
+		return available, ge.queueRecruits(available)
+	}
 	if !ge.Villagers.Recruit(vType, available, popCap) {
 		return 0, fmt.Errorf("cannot recruit %s(s)", vType)
 	}
@@ -911,10 +1165,15 @@ func (ge *GameEngine) RecruitVillager(vType string, count int) error {
 	ge.mu.Lock()
 	defer ge.mu.Unlock()
 
-	popCap := ge.Buildings.GetPopCapacity()
-	// Add population capacity from research/milestones/prestige
-	popCap += int(ge.Research.GetBonus("population") + ge.permanentBonuses["population"] + ge.Prestige.GetBonuses()["population"])
+	popCap := ge.popCap()
+	// Recruits in training already hold their place in the population
+	popCap -= ge.Military.QueuedRecruits()
 
+	// Soldiers go to the training queue; if they can't be recruited, the
+	// usual errors below explain why
+	if vType == "soldier" && ge.Villagers.IsUnlocked(vType) && ge.Villagers.TotalPop()+count <= popCap {
+		return ge.queueRecruits(count)
+	}
 	if !ge.Villagers.Recruit(vType, count, popCap) {
 		totalPop := ge.Villagers.TotalPop()
 		if !ge.Villagers.IsUnlocked(vType) {
@@ -928,6 +1187,17 @@ func (ge *GameEngine) RecruitVillager(vType string, count int) error {
 	return nil
 }
 
+// queueRecruits sends new soldiers to the training queue as infantry
+// (must be called with lock held)
+func (ge *GameEngine) queueRecruits(count int) error {
+	if err := ge.Military.QueueTraining(infantryUnit, count, ge.soldierCount()); err != nil {
+		return err
+	}
+	ge.addLog("info", fmt.Sprintf("Queued %d soldier(s) for training (%d ticks until the queue is done)",
+		count, ge.Military.TrainingTicks(ge.trainingSlots())))
+	return nil
+}
+
 // AssignVillager assigns villagers to gather a resource
 func (ge *GameEngine) AssignVillager(vType, resource string, count int) error {
 	ge.mu.Lock()
@@ -1003,7 +1273,8 @@ func (ge *GameEngine) StartResearch(techKey string) error {
 	ageOrder := ge.progress.GetAgeOrder()
 	knowledge := ge.Resources.Get("knowledge")
 
-	if err := ge.Research.StartResearch(techKey, ge.age, ageOrder, knowledge); err != nil {
+	speedBonus := ge.stack.Total("research_speed")
+	if err := ge.Research.StartResearch(techKey, ge.age, ageOrder, knowledge, speedBonus); err != nil {
 		return err
 	}
 
@@ -1029,24 +1300,137 @@ func (ge *GameEngine) CancelResearch() error {
 	return nil
 }
 
-// LaunchExpedition starts a military expedition
-func (ge *GameEngine) LaunchExpedition(key string) error {
+// LaunchExpedition starts a military expedition. army picks the units to
+// send by key; nil sends the strongest soldiers at home.
+func (ge *GameEngine) LaunchExpedition(key string, army map[string]int) error {
 	ge.mu.Lock()
 	defer ge.mu.Unlock()
 
 	ageOrder := ge.progress.GetAgeOrder()
-	soldierCount := 0
-	if st, ok := ge.Villagers.types["soldier"]; ok {
-		soldierCount = st.count
+	soldierCount := ge.soldierCount()
+	militaryBonus := ge.militaryBonus()
+
+	slots := ge.expeditionSlots()
+	if err := ge.Military.LaunchExpedition(key, army, soldierCount, slots, ge.age, ageOrder); err != nil {
+		return err
 	}
-	militaryBonus := ge.Research.GetBonus("military_power") + ge.permanentBonuses["military_power"] + ge.Prestige.GetBonuses()["military_power"]
 
-	if err := ge.Military.LaunchExpedition(key, soldierCount, ge.age, ageOrder, militaryBonus); err != nil {
+	launched := ge.Military.active[len(ge.Military.active)-1]
+	ge.addLog("debug", fmt.Sprintf("Expedition start: %s (soldiers: %d/%d, slots: %d/%d, bonus: %.1f%%)", launched.Name, launched.Soldiers, soldierCount, ge.Military.ActiveCount(), slots, militaryBonus*100))
+	ge.addLog("info", fmt.Sprintf("Expedition launched: %s with %s", launched.Name, FormatUnits(launched.Units)))
+	return nil
+}
+
+// ForecastExpedition predicts how an army would fare on an expedition with
+// the current bonuses. army may include units that aren't at home; nil
+// forecasts the army a launch would send.
+func (ge *GameEngine) ForecastExpedition(key string, army map[string]int) (ExpeditionForecast, error) {
+	ge.mu.RLock()
+	defer ge.mu.RUnlock()
+
+	return ge.Military.Forecast(key, army, ge.soldierCount(), ge.militaryBonus(), ge.expeditionBonus())
+}
+
+// TrainUnits trains idle infantry at home into another unit type
+func (ge *GameEngine) TrainUnits(key string, count int) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	def, err := ge.trainableUnit(key)
+	if err != nil {
 		return err
 	}
+	return ge.trainUnits(def, count)
+}
+
+// TrainMax trains as many idle infantry as resources allow into a unit type
+func (ge *GameEngine) TrainMax(key string) (int, error) {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
 
-	ge.addLog("debug", fmt.Sprintf("Expedition start: %s (soldiers: %d, bonus: %.1f%%)", ge.Military.active.Name, soldierCount, militaryBonus*100))
-	ge.addLog("info", fmt.Sprintf("Expedition launched: %s", ge.Military.active.Name))
+	def, err := ge.trainableUnit(key)
+	if err != nil {
+		return 0, err
+	}
+	count := ge.Military.HomeUnits(ge.soldierCount())[infantryUnit]
+	for res, amount := range def.TrainCost {
+		if amount > 0 {
+			if n := int(ge.Resources.Get(res) / amount); n < count {
+				count = n
+			}
+		}
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("cannot train any %s (need: %s and an idle infantry at home)", def.Name, formatCost(def.TrainCost))
+	}
+	return count, ge.trainUnits(def, count)
+}
+
+// trainableUnit returns a unit's definition if the age and research allow
+// training it (must be called with lock held)
+func (ge *GameEngine) trainableUnit(key string) (config.UnitDef, error) {
+	def, ok := config.UnitByKey()[key]
+	if !ok {
+		return def, fmt.Errorf("unknown unit: %s", key)
+	}
+	ageOrder := ge.progress.GetAgeOrder()
+	if ageOrder[def.MinAge] > ageOrder[ge.age] {
+		return def, fmt.Errorf("%s requires %s age", def.Name, def.MinAge)
+	}
+	if def.RequiredTech != "" && !ge.Research.IsResearched(def.RequiredTech) {
+		return def, fmt.Errorf("%s requires %s research", def.Name, config.TechByKey()[def.RequiredTech].Name)
+	}
+	return def, nil
+}
+
+// trainUnits pays for and trains units (must be called with lock held)
+func (ge *GameEngine) trainUnits(def config.UnitDef, count int) error {
+	if count <= 0 {
+		return fmt.Errorf("count must be positive")
+	}
+
+	cost := make(map[string]float64)
+	for res, amount := range def.TrainCost {
+		cost[res] = amount * float64(count)
+	}
+	if !ge.Resources.CanAfford(cost) {
+		return fmt.Errorf("cannot afford %d %s (need: %s)", count, def.Name, formatCost(cost))
+	}
+	if err := ge.Military.QueueTraining(def.Key, count, ge.soldierCount()); err != nil {
+		return err
+	}
+	ge.Resources.Pay(cost)
+	ge.addLog("info", fmt.Sprintf("Queued %d %s for training (%d ticks until the queue is done)",
+		count, def.Name, ge.Military.TrainingTicks(ge.trainingSlots())))
+	return nil
+}
+
+// AdvanceExpedition sends a campaign holding between stages into its next stage
+func (ge *GameEngine) AdvanceExpedition(key string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	if err := ge.Military.AdvanceExpedition(key); err != nil {
+		return err
+	}
+	a := ge.Military.findActive(key)
+	ge.addLog("info", fmt.Sprintf("%s advances to stage %d", a.Name, a.Stage+1))
+	return nil
+}
+
+// RetreatExpedition brings a campaign holding between stages home with its loot
+func (ge *GameEngine) RetreatExpedition(key string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	res, err := ge.Military.RetreatExpedition(key)
+	if err != nil {
+		return err
+	}
+	for r, amount := range res.Rewards {
+		ge.Resources.Add(r, amount)
+	}
+	ge.addLog("event", res.Message)
 	return nil
 }
 
@@ -1077,14 +1461,17 @@ func (ge *GameEngine) DoPrestige() error {
 	ge.Villagers = NewVillagerManager()
 	ge.Research = NewResearchManager()
 	ge.Military = NewMilitaryManager()
+	ge.Raids = NewRaidManager()
 	ge.Events = NewEventManager()
 	ge.Milestones = NewMilestoneManager()
 	ge.Trade = NewTradeManager()
 	ge.Diplomacy = NewDiplomacyManager()
+	ge.Settlement = NewSettlementManager(ge.Settlement.Seed())
 	ge.Stats = NewGameStats()
 	ge.Bus = NewEventBus()
-	ge.permanentBonuses = make(map[string]float64)
 	ge.buildQueue = nil
+	ge.rateHistory = nil
+	ge.timelapse = nil
 	ge.log = nil
 
 	// Apply age unlocks for primitive age
@@ -1115,6 +1502,7 @@ func (ge *GameEngine) BuyPrestigeUpgrade(key string) error {
 	if err := ge.Prestige.BuyUpgrade(key); err != nil {
 		return err
 	}
+	ge.recalculateRates()
 	ge.addLog("success", fmt.Sprintf("Purchased prestige upgrade: %s", key))
 	return nil
 }
@@ -1131,22 +1519,26 @@ func (ge *GameEngine) Reset() {
 	ge.Villagers = NewVillagerManager()
 	ge.Research = NewResearchManager()
 	ge.Military = NewMilitaryManager()
+	ge.Raids = NewRaidManager()
 	ge.Events = NewEventManager()
 	ge.Milestones = NewMilestoneManager()
 	ge.Prestige = NewPrestigeManager()
 	ge.Trade = NewTradeManager()
 	ge.Diplomacy = NewDiplomacyManager()
+	ge.Settlement = NewSettlementManager(newWorldSeed())
 	ge.Stats = NewGameStats()
 	ge.Bus = NewEventBus()
-	ge.permanentBonuses = make(map[string]float64)
 	ge.tickSpeedBonus = 0
 	ge.speedMultiplier = 1.0
 	ge.buildQueue = nil
+	ge.rateHistory = nil
+	ge.timelapse = nil
 	ge.log = nil
 
 	ge.applyAgeUnlocks("primitive_age")
 	ge.Resources.Add("food", 15)
 	ge.Resources.Add("wood", 12)
+	ge.recalculateRates()
 
 	ge.addLog("event", "Game wiped! Starting fresh.")
 	ge.addLog("info", "Type [cyan]help[-] for commands.")
@@ -1157,8 +1549,7 @@ func (ge *GameEngine) GetState() GameState {
 	ge.mu.RLock()
 	defer ge.mu.RUnlock()
 
-	popCap := ge.Buildings.GetPopCapacity()
-	popCap += int(ge.Research.GetBonus("population") + ge.permanentBonuses["population"] + ge.Prestige.GetBonuses()["population"])
+	popCap := ge.popCap()
 	nextAge := ge.progress.GetNextAge(ge.age)
 
 	logCopy := make([]LogEntry, len(ge.log))
@@ -1187,9 +1578,8 @@ func (ge *GameEngine) GetState() GameState {
 	if st, ok := ge.Villagers.types["soldier"]; ok {
 		soldierCount = st.count
 	}
-	prestigeBonuses := ge.Prestige.GetBonuses()
-	militaryBonus := ge.Research.GetBonus("military_power") + ge.permanentBonuses["military_power"] + prestigeBonuses["military_power"]
-	expeditionBonus := ge.Research.GetBonus("expedition_reward") + ge.permanentBonuses["expedition_reward"] + prestigeBonuses["expedition_reward"]
+	militaryBonus := ge.militaryBonus()
+	expeditionBonus := ge.expeditionBonus()
 
 	// Prestige snapshot with pending points
 	prestigeSnap := ge.Prestige.Snapshot()
@@ -1223,7 +1613,7 @@ func (ge *GameEngine) GetState() GameState {
 		BuildQueue:       queue,
 		Villagers:        ge.Villagers.Snapshot(popCap),
 		Research:         ge.Research.Snapshot(ge.age, ageOrder),
-		Military:         ge.Military.Snapshot(ge.age, ageOrder, soldierCount, militaryBonus, expeditionBonus),
+		Military:         ge.Military.Snapshot(ge.age, ageOrder, ge.getResearchedTechMap(), soldierCount, ge.expeditionSlots(), ge.trainingSlots(), militaryBonus, expeditionBonus, ge.Raids.Snapshot(ge.tick)),
 		Milestones: ge.Milestones.Snapshot(MilestoneSnapshotParams{
 			Tick:            ge.tick,
 			Age:             ge.age,
@@ -1240,8 +1630,10 @@ func (ge *GameEngine) GetState() GameState {
 		}),
 		ActiveEvents:     ge.Events.GetActive(),
 		Prestige:         prestigeSnap,
-		Trade:            ge.Trade.Snapshot(ge.age, ageOrder, ge.Buildings),
-		Diplomacy:        ge.Diplomacy.Snapshot(ge.age, ageOrder),
+		Trade:            ge.Trade.Snapshot(ge.age, ageOrder, ge.Buildings, ge.Diplomacy),
+		Diplomacy:        ge.Diplomacy.Snapshot(ge.age, ageOrder, ge.tick),
+		Power:            ge.Buildings.Grid(),
+		Settlement:       ge.Settlement.Snapshot(),
 		Log:              logCopy,
 		Stats:            ge.Stats.Snapshot(),
 		SaveExists:       SaveExists("autosave"),
@@ -1351,7 +1743,7 @@ func (ge *GameEngine) ExchangeResources(from, to string, amount float64) (float6
 	ge.mu.Lock()
 	defer ge.mu.Unlock()
 
-	got, err := ge.Trade.Exchange(from, to, amount, ge.Resources, ge.Buildings, ge.tick)
+	got, err := ge.Trade.Exchange(from, to, amount, ge.Resources, ge.Buildings, ge.Diplomacy, ge.tick)
 	if err != nil {
 		return 0, err
 	}
@@ -1365,7 +1757,7 @@ func (ge *GameEngine) StartTradeRoute(key string) error {
 	defer ge.mu.Unlock()
 
 	ageOrder := ge.progress.GetAgeOrder()
-	if err := ge.Trade.StartRoute(key, ge.Buildings, ge.age, ageOrder); err != nil {
+	if err := ge.Trade.StartRoute(key, ge.Buildings, ge.Diplomacy, ge.age, ageOrder); err != nil {
 		return err
 	}
 	ge.addLog("info", fmt.Sprintf("Trade route started: %s", key))
@@ -1398,6 +1790,29 @@ func (ge *GameEngine) SetDiplomaticStatus(factionKey, status string) error {
 		ge.Resources.Remove("gold", cost)
 	}
 	ge.addLog("info", fmt.Sprintf("Diplomatic status with %s set to %s", factionKey, status))
+	for _, msg := range ge.Diplomacy.BreakViolated(factionKey, status) {
+		ge.addLog("warning", msg)
+	}
+	ge.recalculateRates()
+	return nil
+}
+
+// MakePeace pays a faction at war the tribute it asks and ends the war
+func (ge *GameEngine) MakePeace(factionKey string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	gold := ge.Resources.Get("gold")
+	cost, msg, err := ge.Diplomacy.MakePeace(factionKey, gold, ge.age, ge.progress.GetAgeOrder(), ge.tick)
+	if err != nil {
+		return err
+	}
+	if cost > 0 {
+		ge.Resources.Remove("gold", cost)
+	}
+	ge.addLog("success", msg)
+	ge.refreshWarFronts()
+	ge.recalculateRates()
 	return nil
 }
 
@@ -1407,12 +1822,87 @@ func (ge *GameEngine) SendGift(factionKey string) error {
 	defer ge.mu.Unlock()
 
 	gold := ge.Resources.Get("gold")
-	cost, err := ge.Diplomacy.SendGift(factionKey, gold)
+	cost, gain, err := ge.Diplomacy.SendGift(factionKey, gold)
 	if err != nil {
 		return err
 	}
 	ge.Resources.Remove("gold", cost)
-	ge.addLog("info", fmt.Sprintf("Sent gift to %s (+15 opinion)", factionKey))
+	ge.addLog("info", fmt.Sprintf("Sent gift to %s (+%d opinion)", factionKey, gain))
+	return nil
+}
+
+// AcceptTreaty signs the treaty a faction has proposed
+func (ge *GameEngine) AcceptTreaty(factionKey string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	msg, err := ge.Diplomacy.AcceptTreaty(factionKey, ge.tick)
+	if err != nil {
+		return err
+	}
+	ge.addLog("success", msg)
+	ge.recalculateRates()
+	return nil
+}
+
+// DeclineTreaty turns down the treaty a faction has proposed
+func (ge *GameEngine) DeclineTreaty(factionKey string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	msg, err := ge.Diplomacy.DeclineTreaty(factionKey)
+	if err != nil {
+		return err
+	}
+	ge.addLog("info", msg)
+	return nil
+}
+
+// BreakTreaty walks out of the treaty in force with a faction
+func (ge *GameEngine) BreakTreaty(factionKey string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	msg, err := ge.Diplomacy.BreakTreaty(factionKey)
+	if err != nil {
+		return err
+	}
+	ge.addLog("warning", msg)
+	ge.recalculateRates()
+	return nil
+}
+
+// FulfillRequest pays what a faction's open tribute or supply request asks for
+func (ge *GameEngine) FulfillRequest(factionKey string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	due, err := ge.Diplomacy.RequestDue(factionKey)
+	if err != nil {
+		return err
+	}
+	for res, amount := range due {
+		if have := ge.Resources.Get(res); have < amount {
+			return fmt.Errorf("not enough %s (have: %.0f, need: %.0f)", res, have, amount)
+		}
+	}
+	for res, amount := range due {
+		ge.Resources.Remove(res, amount)
+	}
+	ge.addLog("success", ge.Diplomacy.CompleteRequest(factionKey))
+	return nil
+}
+
+// RefuseRequest turns down a faction's open request
+func (ge *GameEngine) RefuseRequest(factionKey string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	msg, err := ge.Diplomacy.RefuseRequest(factionKey)
+	if err != nil {
+		return err
+	}
+	ge.addLog("warning", msg)
 	return nil
 }
 
@@ -1466,7 +1956,7 @@ func (ge *GameEngine) UpgradeBuilding(fromKey string) (int, error) {
 		if !ge.Resources.Pay(cost) {
 			break
 		}
-		ge.Buildings.counts[fromKey]--
+		ge.Buildings.Destroy(fromKey, 1)
 		ge.Buildings.counts[upg.To]++
 		upgraded++
 	}
@@ -1527,7 +2017,7 @@ func (ge *GameEngine) UpgradeAll() (map[string]int, error) {
 			if !ge.Resources.Pay(cost) {
 				break
 			}
-			ge.Buildings.counts[upg.From]--
+			ge.Buildings.Destroy(upg.From, 1)
 			ge.Buildings.counts[upg.To]++
 			result[upg.From]++
 		}
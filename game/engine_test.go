@@ -99,6 +99,7 @@ func TestEngine_RecruitVillager(t *testing.T) {
 	ge.mu.Lock()
 	ge.Resources.Add("wood", 5000)
 	ge.Buildings.counts["hut"] = 5
+	ge.recalculateRates()
 	ge.mu.Unlock()
 
 	err := ge.RecruitVillager("worker", 2)
@@ -126,6 +127,7 @@ func TestEngine_AssignVillager(t *testing.T) {
 
 	ge.mu.Lock()
 	ge.Buildings.counts["hut"] = 5
+	ge.recalculateRates()
 	ge.mu.Unlock()
 
 	ge.RecruitVillager("worker", 3)
@@ -292,6 +294,7 @@ func TestEngine_SaveLoadRoundTrip(t *testing.T) {
 	ge.Resources.Add("wood", 500)
 	ge.Resources.Add("food", 200)
 	ge.Buildings.counts["hut"] = 3
+	ge.recalculateRates()
 	ge.mu.Unlock()
 
 	ge.RecruitVillager("worker", 2)
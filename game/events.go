@@ -43,8 +43,9 @@ func NewEventManager() *EventManager {
 }
 
 // Tick processes one tick: checks for new events, processes active event durations.
+// weight scales each event's chance by key (the world's biome); nil leaves them as defined.
 // Returns list of newly triggered events and list of expired events.
-func (em *EventManager) Tick(tick int, currentAge string, ageOrder map[string]int) (triggered []config.EventDef, expired []string) {
+func (em *EventManager) Tick(tick int, currentAge string, ageOrder map[string]int, weight func(key string) float64) (triggered []config.EventDef, expired []string) {
 	// Process active events first - decrement durations
 	var stillActive []ActiveEvent
 	for _, ae := range em.active {
@@ -72,18 +73,23 @@ func (em *EventManager) Tick(tick int, currentAge string, ageOrder map[string]in
 	}
 
 	// Weighted random selection
-	totalWeight := 0
-	for _, def := range eligible {
-		totalWeight += def.Weight
+	weights := make([]float64, len(eligible))
+	totalWeight := 0.0
+	for i, def := range eligible {
+		weights[i] = float64(def.Weight)
+		if weight != nil {
+			weights[i] *= weight(def.Key)
+		}
+		totalWeight += weights[i]
 	}
 	if totalWeight == 0 {
 		return
 	}
 
-	roll := rand.Intn(totalWeight)
-	cumulative := 0
-	for _, def := range eligible {
-		cumulative += def.Weight
+	roll := rand.Float64() * totalWeight
+	cumulative := 0.0
+	for i, def := range eligible {
+		cumulative += weights[i]
 		if roll < cumulative {
 			em.lastFired[def.Key] = tick
 			triggered = append(triggered, def)
@@ -199,6 +205,25 @@ func (em *EventManager) GetActiveEffects() []config.Effect {
 	return effects
 }
 
+// Modifiers returns the effects of active timed events. Event production
+// lands in the final layer, so booms and blights aren't scaled by bonuses.
+func (em *EventManager) Modifiers() []Modifier {
+	var mods []Modifier
+	for _, ae := range em.active {
+		for _, eff := range ae.Effects {
+			m, ok := effectModifier(eff, SourceEvent, ae.Key, ae.Name)
+			if !ok {
+				continue
+			}
+			if eff.Type == "production" {
+				m.Layer = LayerFinal
+			}
+			mods = append(mods, m)
+		}
+	}
+	return mods
+}
+
 // GetActive returns active events for UI display
 func (em *EventManager) GetActive() []ActiveEventState {
 	var out []ActiveEventState
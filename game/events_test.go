@@ -58,7 +58,7 @@ func TestEventManager_InjectedEventExpires(t *testing.T) {
 	ageOrder := map[string]int{"primitive_age": 0}
 
 	// Tick 1: still active
-	em.Tick(1, "primitive_age", ageOrder)
+	em.Tick(1, "primitive_age", ageOrder, nil)
 	active := em.GetActive()
 	found := false
 	for _, a := range active {
@@ -71,7 +71,7 @@ func TestEventManager_InjectedEventExpires(t *testing.T) {
 	}
 
 	// Tick 2: should expire
-	_, expired := em.Tick(2, "primitive_age", ageOrder)
+	_, expired := em.Tick(2, "primitive_age", ageOrder, nil)
 	foundExpired := false
 	for _, key := range expired {
 		if key == "short_boost" {
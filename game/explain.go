@@ -0,0 +1,114 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// How far back ExplainResource compares: by default, and at most (the
+// ticks of resource history kept)
+const (
+	DefaultWhyTicks = 10
+	MaxWhyTicks     = 100
+)
+
+// rateSample is the resource state at the end of one tick
+type rateSample struct {
+	tick      int
+	resources map[string]ResourceState
+}
+
+// recordRates keeps this tick's resource states so ExplainResource can
+// compare against them later (must be called with lock held)
+func (ge *GameEngine) recordRates() {
+	ge.rateHistory = append(ge.rateHistory, rateSample{tick: ge.tick, resources: ge.Resources.Snapshot()})
+	if len(ge.rateHistory) > MaxWhyTicks+1 {
+		ge.rateHistory = ge.rateHistory[1:]
+	}
+}
+
+// ExplainResource returns the full derivation of a resource's rate and
+// storage cap, and what changed over the last ticks.
+func (ge *GameEngine) ExplainResource(key string, ticks int) (ResourceExplanation, error) {
+	ge.mu.RLock()
+	defer ge.mu.RUnlock()
+
+	def, ok := ge.Resources.defs[key]
+	if !ok {
+		return ResourceExplanation{}, fmt.Errorf("unknown resource: %s", key)
+	}
+	if !ge.Resources.IsUnlocked(key) {
+		return ResourceExplanation{}, fmt.Errorf("%s is not unlocked yet", def.Name)
+	}
+	if ticks < 1 || ticks > MaxWhyTicks {
+		return ResourceExplanation{}, fmt.Errorf("can look back 1 to %d ticks", MaxWhyTicks)
+	}
+
+	r := ge.Resources.resources[key]
+	exp := ResourceExplanation{
+		Key:            key,
+		Name:           def.Name,
+		Amount:         r.Amount,
+		Rate:           r.Rate,
+		Breakdown:      r.Breakdown,
+		Storage:        r.Storage,
+		BaseStorage:    def.BaseStorage,
+		StorageSources: ge.stack.StorageSources(key),
+		Routes:         ge.Trade.RouteFlows(key, ge.Diplomacy),
+	}
+
+	// Compare with the oldest sample in range
+	for _, sample := range ge.rateHistory {
+		if sample.tick < ge.tick-ticks || sample.tick >= ge.tick {
+			continue
+		}
+		then := sample.resources[key]
+		exp.Since = ge.tick - sample.tick
+		exp.WasAmount = then.Amount
+		exp.WasRate = then.Rate
+		exp.WasStorage = then.Storage
+		exp.Changes = rateChanges(then.Breakdown.Sources, r.Breakdown.Sources)
+		break
+	}
+	return exp, nil
+}
+
+// rateChanges compares two breakdowns source by source, largest change first
+func rateChanges(was, now []RateSource) []RateChange {
+	type id struct{ layer, source, key string }
+	changes := make(map[id]*RateChange)
+	var order []id
+	add := func(rs RateSource, then bool) {
+		k := id{rs.Layer, rs.Source, rs.Key}
+		c, ok := changes[k]
+		if !ok {
+			c = &RateChange{Source: rs.Source, Name: rs.Name}
+			changes[k] = c
+			order = append(order, k)
+		}
+		if then {
+			c.Was += rs.Amount
+		} else {
+			c.Now += rs.Amount
+			c.Name = rs.Name
+		}
+	}
+	for _, rs := range was {
+		add(rs, true)
+	}
+	for _, rs := range now {
+		add(rs, false)
+	}
+
+	var out []RateChange
+	for _, k := range order {
+		if c := changes[k]; math.Abs(c.Now-c.Was) > 1e-9 {
+			out = append(out, *c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return math.Abs(out[i].Now-out[i].Was) > math.Abs(out[j].Now-out[j].Was)
+	})
+	return out
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/user/ageforge/config"
+)
+
+// explainTick runs the rate and history steps of a tick
+func explainTick(ge *GameEngine) {
+	ge.tick++
+	ge.recalculateRates()
+	ge.recordRates()
+}
+
+func TestEngine_ExplainResource(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Resources.UnlockResource("gold")
+	ge.Buildings.LoadCounts(map[string]int{"stash": 2})
+	ge.Events.InjectEvent(ActiveEvent{
+		Key: "gold_rush", Name: "Gold Rush", TicksLeft: 10,
+		Effects: []config.Effect{{Type: "production", Target: "gold", Value: 2}},
+	})
+	for i := 0; i < 3; i++ {
+		explainTick(ge)
+	}
+	ge.Events.active = nil
+	explainTick(ge)
+
+	exp, err := ge.ExplainResource("gold", 2)
+	if err != nil {
+		t.Fatalf("ExplainResource failed: %v", err)
+	}
+	if exp.Rate != 0 || len(exp.Breakdown.Sources) != 0 {
+		t.Errorf("rate %v from %v, want nothing once the event ended", exp.Rate, exp.Breakdown.Sources)
+	}
+	if exp.Since != 2 || exp.WasRate != 2 {
+		t.Errorf("compared %d ticks back at rate %v, want 2 ticks at 2", exp.Since, exp.WasRate)
+	}
+	if len(exp.Changes) != 1 || exp.Changes[0].Name != "Gold Rush" || exp.Changes[0].Now != 0 {
+		t.Errorf("changes = %+v, want the Gold Rush gone", exp.Changes)
+	}
+	if len(exp.StorageSources) != 1 || exp.StorageSources[0].Name != "Stash ×2" {
+		t.Errorf("storage sources = %+v, want the stashes", exp.StorageSources)
+	}
+	if want := exp.BaseStorage + exp.StorageSources[0].Amount; exp.Storage != want {
+		t.Errorf("storage = %v, want %v", exp.Storage, want)
+	}
+
+	for _, tc := range []struct {
+		key   string
+		ticks int
+	}{{"gold", 0}, {"gold", MaxWhyTicks + 1}, {"mithril", 10}, {"uranium", 10}} {
+		if _, err := ge.ExplainResource(tc.key, tc.ticks); err == nil {
+			t.Errorf("ExplainResource(%q, %d) succeeded, want error", tc.key, tc.ticks)
+		}
+	}
+}
+
+func TestEngine_RateHistoryIsBounded(t *testing.T) {
+	ge := NewGameEngine()
+	for i := 0; i < MaxWhyTicks+20; i++ {
+		explainTick(ge)
+	}
+	if got := len(ge.rateHistory); got != MaxWhyTicks+1 {
+		t.Errorf("history holds %d ticks, want %d", got, MaxWhyTicks+1)
+	}
+	exp, err := ge.ExplainResource("food", MaxWhyTicks)
+	if err != nil {
+		t.Fatalf("ExplainResource failed: %v", err)
+	}
+	if exp.Since != MaxWhyTicks {
+		t.Errorf("compared %d ticks back, want %d", exp.Since, MaxWhyTicks)
+	}
+}
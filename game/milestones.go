@@ -210,6 +210,17 @@ func (mm *MilestoneManager) GetCompleted() []string {
 	return keys
 }
 
+// Modifiers returns the permanent bonuses rewarded by completed milestones
+func (mm *MilestoneManager) Modifiers() []Modifier {
+	var mods []Modifier
+	for _, def := range mm.defs {
+		if mm.completed[def.Key] {
+			mods = append(mods, effectModifiers(def.Rewards, 1, SourceMilestone, def.Key, def.Name)...)
+		}
+	}
+	return mods
+}
+
 // GetChainsCompleted returns all completed chain keys
 func (mm *MilestoneManager) GetChainsCompleted() []string {
 	var keys []string
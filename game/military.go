@@ -2,33 +2,78 @@ package game
 
 import (
 	"fmt"
-	"math/rand"
-)
+	"math"
+	"sort"
 
-// ExpeditionDef defines an available expedition
-type ExpeditionDef struct {
-	Name           string
-	Key            string
-	MinAge         string
-	SoldiersNeeded int
-	Duration       int     // ticks
-	DifficultyBase float64 // 0.0 - 1.0, higher = harder
-	Rewards        map[string]float64
-	Description    string
-}
+	"github.com/user/ageforge/config"
+)
 
 // ActiveExpedition represents an ongoing expedition
 type ActiveExpedition struct {
-	Key       string
-	Name      string
-	Soldiers  int
-	TicksLeft int
+	Key        string
+	Name       string
+	Soldiers   int
+	Units      map[string]int // the army sent, keyed by unit
+	TicksLeft  int
+	TotalTicks int
+	Stage      int                // index of the current stage
+	Holding    bool               // campaign stage won, waiting to advance or retreat
+	Haul       map[string]float64 // loot from won stages, delivered on return
+}
+
+// ExpeditionResult is the outcome of a finished expedition or campaign stage
+type ExpeditionResult struct {
+	Key          string
+	Rewards      map[string]float64
+	Message      string
+	SoldiersLost int
+	Losses       map[string]int // units lost in the battle
+	StageWon     bool           // a campaign stage was won and the troops are holding
+	Won          bool           // the expedition or whole campaign succeeded
+	Faction      string         // faction discovered by completing a campaign
+	Tech         string         // tech granted by completing a campaign
+}
+
+// TrainingOrder is a batch of units waiting in the training queue. Infantry
+// orders are new recruits; other orders retrain infantry already at home.
+type TrainingOrder struct {
+	Unit       string
+	Count      int
+	TicksLeft  int // until the group in training finishes
+	TotalTicks int // per group
 }
 
+// BaseExpeditionSlots is how many expeditions can run at once before
+// buildings and techs add more
+const BaseExpeditionSlots = 1
+
+// BaseTrainingSlots is how many units train at once before military
+// buildings add lanes
+const BaseTrainingSlots = 1
+
+// Morale falls while unit upkeep goes unpaid and recovers once it's paid
+const (
+	moraleMax       = 100.0
+	moraleDecay     = 2.0  // per unpaid tick
+	moraleRecovery  = 1.0  // per paid tick
+	desertionMorale = 25.0 // below this, soldiers at home desert
+	desertionShare  = 0.02 // share of the soldiers at home who desert each tick
+)
+
+// expeditionFailShare is the share of loot recovered when an expedition fails
+const expeditionFailShare = 0.3
+
+// infantryUnit is the unit every soldier starts as
+const infantryUnit = "infantry"
+
 // MilitaryManager handles soldiers, defense, and expeditions
 type MilitaryManager struct {
-	expeditions    []ExpeditionDef
-	active         *ActiveExpedition
+	expeditions    []config.ExpeditionDef
+	fronts         []config.ExpeditionDef // war fronts against factions at war, see war.go
+	units          map[string]int         // trained soldiers by unit, home and away; the rest are infantry
+	training       []*TrainingOrder
+	morale         float64
+	active         []*ActiveExpedition
 	completedCount int
 	totalLoot      map[string]float64
 	defenseRating  float64
@@ -37,131 +82,49 @@ type MilitaryManager struct {
 // NewMilitaryManager creates a military manager
 func NewMilitaryManager() *MilitaryManager {
 	return &MilitaryManager{
-		totalLoot: make(map[string]float64),
-		expeditions: []ExpeditionDef{
-			{
-				Name: "Scout Nearby Ruins", Key: "scout_ruins",
-				MinAge: "bronze_age", SoldiersNeeded: 2, Duration: 10,
-				DifficultyBase: 0.2,
-				Rewards:        map[string]float64{"food": 30, "wood": 20, "stone": 15},
-				Description:    "Send scouts to explore nearby ruins for resources.",
-			},
-			{
-				Name: "Raid Bandit Camp", Key: "raid_bandits",
-				MinAge: "bronze_age", SoldiersNeeded: 5, Duration: 15,
-				DifficultyBase: 0.4,
-				Rewards:        map[string]float64{"gold": 30, "iron": 15, "food": 20},
-				Description:    "Attack a bandit encampment and seize their loot.",
-			},
-			{
-				Name: "Trade Escort", Key: "trade_escort",
-				MinAge: "iron_age", SoldiersNeeded: 3, Duration: 12,
-				DifficultyBase: 0.3,
-				Rewards:        map[string]float64{"gold": 50, "knowledge": 10},
-				Description:    "Escort merchants on a dangerous trade route.",
-			},
-			{
-				Name: "Conquer Territory", Key: "conquer_territory",
-				MinAge: "iron_age", SoldiersNeeded: 10, Duration: 25,
-				DifficultyBase: 0.6,
-				Rewards:        map[string]float64{"gold": 80, "iron": 40, "food": 50},
-				Description:    "Conquer a neighboring territory for its resources.",
-			},
-			{
-				Name: "Siege Enemy Castle", Key: "siege_castle",
-				MinAge: "medieval_age", SoldiersNeeded: 15, Duration: 30,
-				DifficultyBase: 0.7,
-				Rewards:        map[string]float64{"gold": 150, "steel": 30, "faith": 20},
-				Description:    "Lay siege to an enemy stronghold.",
-			},
-			{
-				Name: "Naval Expedition", Key: "naval_expedition",
-				MinAge: "renaissance_age", SoldiersNeeded: 10, Duration: 35,
-				DifficultyBase: 0.5,
-				Rewards:        map[string]float64{"gold": 200, "culture": 30, "knowledge": 40},
-				Description:    "Explore distant lands by sea.",
-			},
-			{
-				Name: "Colonial Campaign", Key: "colonial_campaign",
-				MinAge: "industrial_age", SoldiersNeeded: 20, Duration: 40,
-				DifficultyBase: 0.6,
-				Rewards:        map[string]float64{"gold": 300, "oil": 50, "steel": 40},
-				Description:    "Establish colonial presence in new territories.",
-			},
-			{
-				Name: "World Domination", Key: "world_domination",
-				MinAge: "modern_age", SoldiersNeeded: 50, Duration: 60,
-				DifficultyBase: 0.8,
-				Rewards:        map[string]float64{"gold": 1000, "electricity": 200, "knowledge": 500},
-				Description:    "Launch a global military campaign for world domination.",
-			},
-			{
-				Name: "Cyber Raid", Key: "cyber_raid",
-				MinAge: "information_age", SoldiersNeeded: 30, Duration: 45,
-				DifficultyBase: 0.6,
-				Rewards:        map[string]float64{"data": 200, "crypto": 50, "gold": 500},
-				Description:    "Hack into enemy networks and steal digital assets.",
-			},
-			{
-				Name: "Neon Heist", Key: "neon_heist",
-				MinAge: "cyberpunk_age", SoldiersNeeded: 25, Duration: 35,
-				DifficultyBase: 0.55,
-				Rewards:        map[string]float64{"crypto": 100, "data": 150, "gold": 800},
-				Description:    "Pull off a daring heist in the neon-lit underworld.",
-			},
-			{
-				Name: "Fusion Plant Assault", Key: "fusion_assault",
-				MinAge: "fusion_age", SoldiersNeeded: 35, Duration: 40,
-				DifficultyBase: 0.65,
-				Rewards:        map[string]float64{"plasma": 120, "electricity": 500, "uranium": 50},
-				Description:    "Capture a rival's fusion power facility.",
-			},
-			{
-				Name: "Orbital Strike", Key: "orbital_strike",
-				MinAge: "space_age", SoldiersNeeded: 40, Duration: 50,
-				DifficultyBase: 0.7,
-				Rewards:        map[string]float64{"titanium": 100, "plasma": 80, "knowledge": 300},
-				Description:    "Deploy orbital weapons platform against hostile targets.",
-			},
-			{
-				Name: "Warp Invasion", Key: "warp_invasion",
-				MinAge: "interstellar_age", SoldiersNeeded: 60, Duration: 65,
-				DifficultyBase: 0.75,
-				Rewards:        map[string]float64{"dark_matter": 50, "titanium": 200, "gold": 2000},
-				Description:    "Invade a neighboring star system through warp gates.",
-			},
-			{
-				Name: "Galactic Conquest", Key: "galactic_conquest",
-				MinAge: "galactic_age", SoldiersNeeded: 80, Duration: 80,
-				DifficultyBase: 0.8,
-				Rewards:        map[string]float64{"antimatter": 30, "dark_matter": 100, "gold": 5000},
-				Description:    "Conquer an entire galactic sector.",
-			},
-			{
-				Name: "Quantum Incursion", Key: "quantum_incursion",
-				MinAge: "quantum_age", SoldiersNeeded: 100, Duration: 90,
-				DifficultyBase: 0.85,
-				Rewards:        map[string]float64{"quantum_flux": 20, "antimatter": 50, "knowledge": 5000},
-				Description:    "Launch an incursion across quantum realities.",
-			},
-		},
-	}
-}
-
-// LaunchExpedition starts an expedition, consuming soldiers
-func (mm *MilitaryManager) LaunchExpedition(key string, soldierCount int, currentAge string, ageOrder map[string]int, militaryBonus float64) error {
-	if mm.active != nil {
-		return fmt.Errorf("expedition '%s' already in progress (%d ticks left)", mm.active.Name, mm.active.TicksLeft)
-	}
-
-	var def *ExpeditionDef
-	for i := range mm.expeditions {
-		if mm.expeditions[i].Key == key {
-			def = &mm.expeditions[i]
-			break
+		totalLoot:   make(map[string]float64),
+		expeditions: config.Expeditions(),
+		units:       make(map[string]int),
+		morale:      moraleMax,
+	}
+}
+
+// findDef returns the definition of an expedition
+func (mm *MilitaryManager) findDef(key string) (config.ExpeditionDef, bool) {
+	for _, def := range mm.expeditions {
+		if def.Key == key {
+			return def, true
 		}
 	}
-	if def == nil {
+	for _, def := range mm.fronts {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return config.ExpeditionDef{}, false
+}
+
+// SetWarFronts replaces the war front expeditions on offer
+func (mm *MilitaryManager) SetWarFronts(fronts []config.ExpeditionDef) {
+	mm.fronts = fronts
+}
+
+// findActive returns an active expedition by key, or nil
+func (mm *MilitaryManager) findActive(key string) *ActiveExpedition {
+	for _, a := range mm.active {
+		if a.Key == key {
+			return a
+		}
+	}
+	return nil
+}
+
+// LaunchExpedition starts an expedition, committing soldiers until it returns.
+// army picks the units to send; if empty, the strongest units at home are
+// sent, SoldiersNeeded of them.
+func (mm *MilitaryManager) LaunchExpedition(key string, army map[string]int, soldierCount, slots int, currentAge string, ageOrder map[string]int) error {
+	def, ok := mm.findDef(key)
+	if !ok {
 		return fmt.Errorf("unknown expedition: %s", key)
 	}
 
@@ -169,89 +132,336 @@ func (mm *MilitaryManager) LaunchExpedition(key string, soldierCount int, curren
 		return fmt.Errorf("%s requires %s age", def.Name, def.MinAge)
 	}
 
-	if soldierCount < def.SoldiersNeeded {
-		return fmt.Errorf("%s needs %d soldiers (have: %d)", def.Name, def.SoldiersNeeded, soldierCount)
+	if a := mm.findActive(key); a != nil {
+		if a.Holding {
+			return fmt.Errorf("%s is already underway (holding for orders)", def.Name)
+		}
+		return fmt.Errorf("%s is already underway (%d ticks left)", def.Name, a.TicksLeft)
+	}
+	if len(mm.active) >= slots {
+		return fmt.Errorf("all %d expedition slot(s) are in use — build military buildings or research cartography for more", slots)
 	}
 
-	mm.active = &ActiveExpedition{
-		Key:       key,
-		Name:      def.Name,
-		Soldiers:  def.SoldiersNeeded,
-		TicksLeft: def.Duration,
+	home := mm.HomeUnits(soldierCount)
+	available := UnitTotal(home)
+	if available < def.SoldiersNeeded {
+		return fmt.Errorf("%s needs %d soldiers (available: %d, deployed: %d)", def.Name, def.SoldiersNeeded, available, mm.DeployedSoldiers())
 	}
+
+	if len(army) == 0 {
+		army = pickUnits(home, def.SoldiersNeeded)
+	}
+	units := config.UnitByKey()
+	sent := make(map[string]int)
+	for _, u := range sortedUnitKeys(army) {
+		n := army[u]
+		if n <= 0 {
+			continue
+		}
+		if _, ok := units[u]; !ok {
+			return fmt.Errorf("unknown unit: %s", u)
+		}
+		if n > home[u] {
+			return fmt.Errorf("only %d %s at home (asked for %d)", home[u], units[u].Name, n)
+		}
+		sent[u] = n
+	}
+	if UnitTotal(sent) < def.SoldiersNeeded {
+		return fmt.Errorf("%s needs at least %d soldiers (sending %d)", def.Name, def.SoldiersNeeded, UnitTotal(sent))
+	}
+
+	first := def.StageList()[0]
+	mm.active = append(mm.active, &ActiveExpedition{
+		Key:        key,
+		Name:       def.Name,
+		Soldiers:   UnitTotal(sent),
+		Units:      sent,
+		TicksLeft:  first.Duration,
+		TotalTicks: first.Duration,
+	})
 	return nil
 }
 
-// Tick processes expedition progress. Returns (rewards, message, soldiers_lost) if completed.
-func (mm *MilitaryManager) Tick(militaryBonus, expeditionBonus float64) (rewards map[string]float64, message string, soldiersLost int) {
-	if mm.active == nil {
-		return nil, "", 0
+// AdvanceExpedition sends a holding campaign on to its next stage
+func (mm *MilitaryManager) AdvanceExpedition(key string) error {
+	a := mm.findActive(key)
+	if a == nil {
+		return fmt.Errorf("no active expedition: %s", key)
+	}
+	if !a.Holding {
+		return fmt.Errorf("%s is not waiting for orders (%d ticks left)", a.Name, a.TicksLeft)
 	}
+	def, ok := mm.findDef(key)
+	if !ok {
+		return fmt.Errorf("unknown expedition: %s", key)
+	}
+	stage := def.StageList()[a.Stage]
+	a.Holding = false
+	a.TicksLeft = stage.Duration
+	a.TotalTicks = stage.Duration
+	return nil
+}
 
-	mm.active.TicksLeft--
-	if mm.active.TicksLeft > 0 {
-		return nil, "", 0
+// RetreatExpedition brings a holding campaign home with the loot gathered so far
+func (mm *MilitaryManager) RetreatExpedition(key string) (ExpeditionResult, error) {
+	a := mm.findActive(key)
+	if a == nil {
+		return ExpeditionResult{}, fmt.Errorf("no active expedition: %s", key)
 	}
+	if !a.Holding {
+		return ExpeditionResult{}, fmt.Errorf("%s can only retreat between stages (%d ticks left)", a.Name, a.TicksLeft)
+	}
+	def, _ := mm.findDef(key)
 
-	// Expedition complete - calculate results
-	var def *ExpeditionDef
-	for i := range mm.expeditions {
-		if mm.expeditions[i].Key == mm.active.Key {
-			def = &mm.expeditions[i]
-			break
+	mm.removeActive(a)
+	return ExpeditionResult{
+		Key:     key,
+		Rewards: mm.deliver(a.Haul),
+		Message: fmt.Sprintf("%s retreated after %d of %d stages with the loot gathered so far.",
+			a.Name, a.Stage, len(def.StageList())),
+	}, nil
+}
+
+// removeActive drops an expedition from the active list
+func (mm *MilitaryManager) removeActive(target *ActiveExpedition) {
+	for i, a := range mm.active {
+		if a == target {
+			mm.active = append(mm.active[:i], mm.active[i+1:]...)
+			return
 		}
 	}
-	if def == nil {
-		mm.active = nil
-		return nil, "", 0
+}
+
+// deliver records loot as returned and returns it
+func (mm *MilitaryManager) deliver(loot map[string]float64) map[string]float64 {
+	out := make(map[string]float64)
+	for r, amount := range loot {
+		out[r] = amount
+		mm.totalLoot[r] += amount
+	}
+	return out
+}
+
+// Tick advances every active expedition and returns the results of stages
+// that finished. Expeditions holding between campaign stages don't advance.
+func (mm *MilitaryManager) Tick(militaryBonus, expeditionBonus float64) []ExpeditionResult {
+	var results []ExpeditionResult
+	remaining := mm.active[:0]
+	for _, a := range mm.active {
+		if a.Holding {
+			remaining = append(remaining, a)
+			continue
+		}
+		a.TicksLeft--
+		if a.TicksLeft > 0 {
+			remaining = append(remaining, a)
+			continue
+		}
+		res, done, ok := mm.resolveStage(a, militaryBonus, expeditionBonus)
+		if ok {
+			results = append(results, res)
+		}
+		if !done {
+			remaining = append(remaining, a)
+		}
 	}
+	mm.active = remaining
+	return results
+}
 
-	// Success calculation: military bonus reduces difficulty
-	difficulty := def.DifficultyBase - (militaryBonus * 0.3)
-	if difficulty < 0.05 {
-		difficulty = 0.05
+// resolveStage fights the battle of the current stage. done reports whether
+// the expedition is over; ok is false if its definition no longer exists.
+func (mm *MilitaryManager) resolveStage(a *ActiveExpedition, militaryBonus, expeditionBonus float64) (res ExpeditionResult, done, ok bool) {
+	def, ok := mm.findDef(a.Key)
+	stages := def.StageList()
+	if !ok || a.Stage >= len(stages) {
+		return ExpeditionResult{}, true, false
 	}
+	stage := stages[a.Stage]
+
+	report := ResolveBattle(a.Units, stage.Enemy, militaryBonus, 0)
+	mm.applyLosses(a, report.AttackerLosses)
 
-	successRoll := rand.Float64()
-	success := successRoll > difficulty
+	res = ExpeditionResult{Key: a.Key, Losses: report.AttackerLosses, SoldiersLost: UnitTotal(report.AttackerLosses)}
+	lostMsg := ""
+	if res.SoldiersLost > 0 {
+		lostMsg = fmt.Sprintf(" Lost %s.", FormatUnits(report.AttackerLosses))
+	}
+	name := def.Name
+	if def.IsCampaign() {
+		name = fmt.Sprintf("%s (%s)", def.Name, stage.Name)
+	}
 
-	rewards = make(map[string]float64)
-	if success {
+	if report.AttackerWon {
 		// Apply expedition reward bonus
 		rewardMult := 1.0 + expeditionBonus
-		for res, amount := range def.Rewards {
-			rewards[res] = amount * rewardMult
-			mm.totalLoot[res] += rewards[res]
+		if a.Haul == nil {
+			a.Haul = make(map[string]float64)
+		}
+		for r, amount := range stage.Rewards {
+			a.Haul[r] += amount * rewardMult
+		}
+
+		if a.Stage+1 < len(stages) {
+			a.Stage++
+			a.Holding = true
+			a.TicksLeft = 0
+			res.StageWon = true
+			res.Rewards = map[string]float64{}
+			res.Message = fmt.Sprintf("%s: %s won!%s Holding for orders — advance or retreat.", def.Name, stage.Name, lostMsg)
+			return res, false, true
 		}
-		message = fmt.Sprintf("%s succeeded! Gained loot.", def.Name)
 
-		// Small chance to lose soldiers even on success
-		if rand.Float64() < difficulty*0.3 {
-			soldiersLost = 1
-			message += " (1 soldier lost)"
+		res.Rewards = mm.deliver(a.Haul)
+		res.Won = true
+		if def.IsCampaign() {
+			res.Message = fmt.Sprintf("%s complete! Gained loot.", def.Name)
+			res.Faction = def.DiscoverFaction
+			res.Tech = def.UnlockTech
+		} else {
+			res.Message = fmt.Sprintf("%s succeeded! Gained loot.", def.Name)
 		}
+		res.Message += lostMsg
+	} else if a.Soldiers == 0 {
+		res.Rewards = map[string]float64{}
+		res.Message = fmt.Sprintf("%s was wiped out!%s", name, lostMsg)
 	} else {
-		// Partial rewards on failure
-		for res, amount := range def.Rewards {
-			partial := amount * 0.3
-			rewards[res] = partial
-			mm.totalLoot[res] += partial
+		// Survivors fall back with a share of the haul and of this stage's loot
+		partial := make(map[string]float64)
+		for r, amount := range a.Haul {
+			partial[r] += amount * expeditionFailShare
 		}
-		soldiersLost = 1 + rand.Intn(2)
-		if soldiersLost > mm.active.Soldiers {
-			soldiersLost = mm.active.Soldiers
+		for r, amount := range stage.Rewards {
+			partial[r] += amount * expeditionFailShare
 		}
-		message = fmt.Sprintf("%s failed! Partial loot recovered. Lost %d soldier(s).", def.Name, soldiersLost)
+		res.Rewards = mm.deliver(partial)
+		res.Message = fmt.Sprintf("%s failed! Partial loot recovered.%s", name, lostMsg)
 	}
 
 	mm.completedCount++
-	mm.active = nil
-	return rewards, message, soldiersLost
+	return res, true, true
+}
+
+// applyLosses removes an expedition's battle casualties from its army and
+// from the trained roster
+func (mm *MilitaryManager) applyLosses(a *ActiveExpedition, losses map[string]int) {
+	for u, n := range losses {
+		a.Units[u] -= n
+		a.Soldiers -= n
+		if u != infantryUnit {
+			mm.units[u] -= n
+		}
+	}
+}
+
+// pickUnits chooses up to n of the strongest units from an army
+func pickUnits(home map[string]int, n int) map[string]int {
+	defs := config.Units()
+	sort.SliceStable(defs, func(i, j int) bool {
+		return defs[i].Attack+defs[i].Defense > defs[j].Attack+defs[j].Defense
+	})
+	picked := make(map[string]int)
+	for _, def := range defs {
+		take := home[def.Key]
+		if take > n {
+			take = n
+		}
+		if take > 0 {
+			picked[def.Key] = take
+			n -= take
+		}
+	}
+	return picked
+}
+
+// launchArmy returns the army a launch sends by default: the strongest
+// soldiers at home, topped up with infantry recruits if there aren't enough
+func launchArmy(def config.ExpeditionDef, home map[string]int) map[string]int {
+	army := pickUnits(home, def.SoldiersNeeded)
+	if short := def.SoldiersNeeded - UnitTotal(army); short > 0 {
+		army[infantryUnit] += short
+	}
+	return army
+}
+
+// Forecast predicts how an army would fare on an expedition. An empty army
+// forecasts the one a launch would send.
+func (mm *MilitaryManager) Forecast(key string, army map[string]int, soldierCount int, militaryBonus, expeditionBonus float64) (ExpeditionForecast, error) {
+	def, ok := mm.findDef(key)
+	if !ok {
+		return ExpeditionForecast{}, fmt.Errorf("unknown expedition: %s", key)
+	}
+	if len(army) == 0 {
+		army = launchArmy(def, mm.HomeUnits(soldierCount))
+	}
+	units := config.UnitByKey()
+	for u := range army {
+		if _, ok := units[u]; !ok {
+			return ExpeditionForecast{}, fmt.Errorf("unknown unit: %s", u)
+		}
+	}
+	return ForecastExpedition(def, army, militaryBonus, expeditionBonus), nil
+}
+
+// ForecastExpedition fights every stage of an expedition on paper. Battles
+// are deterministic, so the forecast is exact while the bonuses hold;
+// campaigns assume the army advances through every stage it wins.
+func ForecastExpedition(def config.ExpeditionDef, army map[string]int, militaryBonus, expeditionBonus float64) ExpeditionForecast {
+	f := ExpeditionForecast{
+		Army:   copyUnits(army),
+		Loot:   make(map[string]float64),
+		Losses: make(map[string]int),
+	}
+	left := f.Army
+	haul := make(map[string]float64)
+	f.Won = true
+	for _, stage := range def.StageList() {
+		f.Ticks += stage.Duration
+		report := ResolveBattle(left, stage.Enemy, militaryBonus, 0)
+		for u, n := range report.AttackerLosses {
+			f.Losses[u] += n
+		}
+		left = report.AttackerLeft
+		f.Stages = append(f.Stages, StageForecast{
+			Name:   stage.Name,
+			Enemy:  copyUnits(stage.Enemy),
+			Won:    report.AttackerWon,
+			Losses: report.AttackerLosses,
+		})
+		if !report.AttackerWon {
+			// Survivors fall back with a share of the haul, as in resolveStage
+			if UnitTotal(left) > 0 {
+				for r, amount := range haul {
+					f.Loot[r] += amount * expeditionFailShare
+				}
+				for r, amount := range stage.Rewards {
+					f.Loot[r] += amount * expeditionFailShare
+				}
+			}
+			f.Won = false
+			break
+		}
+		for r, amount := range stage.Rewards {
+			haul[r] += amount * (1.0 + expeditionBonus)
+		}
+	}
+
+	if f.Won {
+		f.Loot = haul
+	}
+	f.SoldiersLost = UnitTotal(f.Losses)
+	for _, amount := range f.Loot {
+		f.LootTotal += amount
+	}
+	if sent := UnitTotal(f.Army); sent > 0 && f.Ticks > 0 {
+		f.LootPerSoldierTick = f.LootTotal / float64(sent*f.Ticks)
+	}
+	return f
 }
 
 // GetAvailableExpeditions returns expeditions available for the current age
-func (mm *MilitaryManager) GetAvailableExpeditions(currentAge string, ageOrder map[string]int) []ExpeditionDef {
-	var available []ExpeditionDef
+func (mm *MilitaryManager) GetAvailableExpeditions(currentAge string, ageOrder map[string]int) []config.ExpeditionDef {
+	var available []config.ExpeditionDef
 	for _, def := range mm.expeditions {
 		if ageOrder[def.MinAge] <= ageOrder[currentAge] {
 			available = append(available, def)
@@ -260,68 +470,460 @@ func (mm *MilitaryManager) GetAvailableExpeditions(currentAge string, ageOrder m
 	return available
 }
 
-// CalculateDefense calculates defense rating from soldiers and bonuses
-func (mm *MilitaryManager) CalculateDefense(soldierCount int, militaryBonus float64) float64 {
-	base := float64(soldierCount) * 2.0
+// DeployedSoldiers returns how many soldiers are away on expeditions
+func (mm *MilitaryManager) DeployedSoldiers() int {
+	deployed := 0
+	for _, a := range mm.active {
+		deployed += a.Soldiers
+	}
+	return deployed
+}
+
+// ActiveCount returns how many expeditions are underway
+func (mm *MilitaryManager) ActiveCount() int {
+	return len(mm.active)
+}
+
+// Roster returns every soldier by unit, home and away. Soldiers that haven't
+// been trained into another unit are infantry.
+func (mm *MilitaryManager) Roster(soldierCount int) map[string]int {
+	roster := make(map[string]int)
+	trained := 0
+	for u, n := range mm.units {
+		if n > 0 {
+			roster[u] = n
+			trained += n
+		}
+	}
+	if infantry := soldierCount - trained; infantry > 0 {
+		roster[infantryUnit] = infantry
+	}
+	return roster
+}
+
+// HomeUnits returns the soldiers not away on an expedition or in training,
+// by unit
+func (mm *MilitaryManager) HomeUnits(soldierCount int) map[string]int {
+	home := mm.Roster(soldierCount)
+	for _, a := range mm.active {
+		for u, n := range a.Units {
+			home[u] -= n
+		}
+	}
+	home[infantryUnit] -= mm.retraining()
+	for u, n := range home {
+		if n <= 0 {
+			delete(home, u)
+		}
+	}
+	return home
+}
+
+// retraining returns how many infantry are in the queue to become other units
+func (mm *MilitaryManager) retraining() int {
+	n := 0
+	for _, o := range mm.training {
+		if o.Unit != infantryUnit {
+			n += o.Count
+		}
+	}
+	return n
+}
+
+// QueuedRecruits returns how many new soldiers are in the training queue
+func (mm *MilitaryManager) QueuedRecruits() int {
+	n := 0
+	for _, o := range mm.training {
+		if o.Unit == infantryUnit {
+			n += o.Count
+		}
+	}
+	return n
+}
+
+// Reconcile trims trained units when soldiers were lost outside a battle,
+// weakest trained units first, then drops retraining orders from the back
+// of the queue if too few infantry are left at home for them
+func (mm *MilitaryManager) Reconcile(soldierCount int) {
+	excess := -soldierCount
+	for _, n := range mm.units {
+		excess += n
+	}
+	for _, def := range config.Units() {
+		if excess <= 0 {
+			break
+		}
+		n := mm.units[def.Key]
+		if n > excess {
+			n = excess
+		}
+		mm.units[def.Key] -= n
+		excess -= n
+	}
+
+	infantry := mm.Roster(soldierCount)[infantryUnit]
+	for _, a := range mm.active {
+		infantry -= a.Units[infantryUnit]
+	}
+	short := mm.retraining() - infantry
+	for i := len(mm.training) - 1; i >= 0 && short > 0; i-- {
+		o := mm.training[i]
+		if o.Unit == infantryUnit {
+			continue
+		}
+		n := o.Count
+		if n > short {
+			n = short
+		}
+		o.Count -= n
+		short -= n
+		if o.Count == 0 {
+			mm.training = append(mm.training[:i], mm.training[i+1:]...)
+		}
+	}
+}
+
+// UnitUnlocked returns whether a unit can be trained in the current age with
+// the given researched techs
+func UnitUnlocked(def config.UnitDef, currentAge string, ageOrder map[string]int, researched map[string]bool) bool {
+	if ageOrder[def.MinAge] > ageOrder[currentAge] {
+		return false
+	}
+	return def.RequiredTech == "" || researched[def.RequiredTech]
+}
+
+// QueueTraining adds units to the training queue. Infantry are new recruits;
+// any other unit retrains idle infantry at home, who leave the home army
+// until they finish. The caller checks the unit is unlocked, the population
+// cap for recruits, and pays the training cost.
+func (mm *MilitaryManager) QueueTraining(key string, count, soldierCount int) error {
+	def, ok := config.UnitByKey()[key]
+	if !ok {
+		return fmt.Errorf("unknown unit: %s", key)
+	}
+	if count <= 0 {
+		return fmt.Errorf("count must be positive")
+	}
+	if key != infantryUnit {
+		if idle := mm.HomeUnits(soldierCount)[infantryUnit]; idle < count {
+			return fmt.Errorf("training %d %s needs %d infantry at home (have %d)", count, def.Name, count, idle)
+		}
+	}
+	mm.training = append(mm.training, &TrainingOrder{
+		Unit:       key,
+		Count:      count,
+		TicksLeft:  def.TrainTicks,
+		TotalTicks: def.TrainTicks,
+	})
+	return nil
+}
+
+// TickTraining advances the order at the front of the training queue, which
+// trains up to one unit per lane at a time. Returns the units that finished;
+// retrained units join the roster here, while the caller adds finished
+// recruits to its soldiers.
+func (mm *MilitaryManager) TickTraining(lanes int) map[string]int {
+	if len(mm.training) == 0 {
+		return nil
+	}
+	o := mm.training[0]
+	o.TicksLeft--
+	if o.TicksLeft > 0 {
+		return nil
+	}
+
+	n := o.Count
+	if n > lanes {
+		n = lanes
+	}
+	if n < 1 {
+		n = 1
+	}
+	o.Count -= n
+	o.TicksLeft = o.TotalTicks
+	if o.Count == 0 {
+		mm.training = mm.training[1:]
+	}
+	if o.Unit != infantryUnit {
+		mm.units[o.Unit] += n
+	}
+	return map[string]int{o.Unit: n}
+}
+
+// TrainingTicks returns how many ticks the whole training queue needs with
+// the given number of lanes
+func (mm *MilitaryManager) TrainingTicks(lanes int) int {
+	if lanes < 1 {
+		lanes = 1
+	}
+	ticks := 0
+	for i, o := range mm.training {
+		groups := (o.Count + lanes - 1) / lanes
+		ticks += groups * o.TotalTicks
+		if i == 0 {
+			ticks -= o.TotalTicks - o.TicksLeft
+		}
+	}
+	return ticks
+}
+
+// Upkeep returns the per-tick cost of every soldier, home and away
+func (mm *MilitaryManager) Upkeep(soldierCount int) map[string]float64 {
+	upkeep := make(map[string]float64)
+	units := config.UnitByKey()
+	for u, n := range mm.Roster(soldierCount) {
+		for res, amount := range units[u].Upkeep {
+			upkeep[res] += amount * float64(n)
+		}
+	}
+	return upkeep
+}
+
+// Morale returns soldier morale, 0-100
+func (mm *MilitaryManager) Morale() float64 {
+	return mm.morale
+}
+
+// TickMorale raises morale while upkeep is paid and lowers it while it isn't.
+// Once morale falls below desertionMorale, soldiers at home desert each
+// tick, weakest first; the deserters are returned for the caller to remove.
+func (mm *MilitaryManager) TickMorale(paid bool, soldierCount int) map[string]int {
+	if paid {
+		mm.morale = math.Min(moraleMax, mm.morale+moraleRecovery)
+		return nil
+	}
+	mm.morale = math.Max(0, mm.morale-moraleDecay)
+	if mm.morale >= desertionMorale {
+		return nil
+	}
+
+	home := mm.HomeUnits(soldierCount)
+	n := int(math.Ceil(float64(UnitTotal(home)) * desertionShare))
+	deserters := make(map[string]int)
+	for _, def := range config.Units() {
+		if n <= 0 {
+			break
+		}
+		take := home[def.Key]
+		if take > n {
+			take = n
+		}
+		if take > 0 {
+			deserters[def.Key] = take
+			n -= take
+		}
+	}
+	return deserters
+}
+
+// CalculateDefense calculates the defense rating of an army with bonuses
+func (mm *MilitaryManager) CalculateDefense(army map[string]int, militaryBonus float64) float64 {
+	units := config.UnitByKey()
+	base := 0.0
+	for u, n := range army {
+		base += float64(n) * units[u].Defense
+	}
 	return base * (1.0 + militaryBonus)
 }
 
 // Snapshot returns military state for UI
-func (mm *MilitaryManager) Snapshot(currentAge string, ageOrder map[string]int, soldierCount int, militaryBonus, expeditionBonus float64) MilitaryState {
-	var activeExp *ExpeditionSnapshot
-	if mm.active != nil {
-		activeExp = &ExpeditionSnapshot{
-			Name:      mm.active.Name,
-			Soldiers:  mm.active.Soldiers,
-			TicksLeft: mm.active.TicksLeft,
+func (mm *MilitaryManager) Snapshot(currentAge string, ageOrder map[string]int, researched map[string]bool, soldierCount, slots, trainingSlots int, militaryBonus, expeditionBonus float64, raids RaidState) MilitaryState {
+	var activeExps []ExpeditionSnapshot
+	underway := make(map[string]bool)
+	for _, a := range mm.active {
+		snap := ExpeditionSnapshot{
+			Key:        a.Key,
+			Name:       a.Name,
+			Soldiers:   a.Soldiers,
+			Units:      copyUnits(a.Units),
+			TicksLeft:  a.TicksLeft,
+			TotalTicks: a.TotalTicks,
+			Stage:      a.Stage + 1,
+			Holding:    a.Holding,
+			Haul:       make(map[string]float64),
 		}
+		if def, ok := mm.findDef(a.Key); ok {
+			stages := def.StageList()
+			snap.Stages = len(stages)
+			if a.Stage < len(stages) {
+				snap.StageName = stages[a.Stage].Name
+			}
+		}
+		for r, amount := range a.Haul {
+			snap.Haul[r] = amount
+		}
+		activeExps = append(activeExps, snap)
+		underway[a.Key] = true
 	}
+	home := mm.HomeUnits(soldierCount)
+	free := UnitTotal(home)
+	freeSlot := len(mm.active) < slots
 
-	available := mm.GetAvailableExpeditions(currentAge, ageOrder)
 	var expList []ExpeditionInfo
+	available := append(mm.GetAvailableExpeditions(currentAge, ageOrder), mm.fronts...)
 	for _, def := range available {
+		var enemies []map[string]int
+		for _, stage := range def.StageList() {
+			enemies = append(enemies, copyUnits(stage.Enemy))
+		}
+		forecast := ForecastExpedition(def, launchArmy(def, home), militaryBonus, expeditionBonus)
 		expList = append(expList, ExpeditionInfo{
 			Name:           def.Name,
 			Key:            def.Key,
 			SoldiersNeeded: def.SoldiersNeeded,
-			Duration:       def.Duration,
-			Difficulty:     def.DifficultyBase,
+			Duration:       def.TotalDuration(),
+			Enemies:        enemies,
+			Stages:         len(def.StageList()),
 			Description:    def.Description,
-			CanLaunch:      soldierCount >= def.SoldiersNeeded && mm.active == nil,
+			Underway:       underway[def.Key],
+			CanLaunch:      free >= def.SoldiersNeeded && freeSlot && !underway[def.Key],
+			Forecast:       forecast,
 		})
 	}
+	rankExpeditions(expList)
 
 	loot := make(map[string]float64)
 	for k, v := range mm.totalLoot {
 		loot[k] = v
 	}
 
+	roster := mm.Roster(soldierCount)
+	var units []UnitInfo
+	for _, def := range config.Units() {
+		if ageOrder[def.MinAge] > ageOrder[currentAge] {
+			continue
+		}
+		units = append(units, UnitInfo{
+			Key:          def.Key,
+			Name:         def.Name,
+			Count:        roster[def.Key],
+			Home:         home[def.Key],
+			Attack:       def.Attack,
+			Defense:      def.Defense,
+			Counters:     def.Counters,
+			TrainCost:    def.TrainCost,
+			Upkeep:       def.Upkeep,
+			TrainTicks:   def.TrainTicks,
+			RequiredTech: def.RequiredTech,
+			Unlocked:     UnitUnlocked(def, currentAge, ageOrder, researched),
+		})
+	}
+
+	var training []TrainingSnapshot
+	names := config.UnitByKey()
+	for _, o := range mm.training {
+		training = append(training, TrainingSnapshot{
+			Unit:       o.Unit,
+			Name:       names[o.Unit].Name,
+			Count:      o.Count,
+			TicksLeft:  o.TicksLeft,
+			TotalTicks: o.TotalTicks,
+		})
+	}
+
 	return MilitaryState{
-		SoldierCount:    soldierCount,
-		DefenseRating:   mm.CalculateDefense(soldierCount, militaryBonus),
-		MilitaryBonus:   militaryBonus,
-		ExpeditionBonus: expeditionBonus,
-		ActiveExpedition: activeExp,
-		Expeditions:     expList,
-		CompletedCount:  mm.completedCount,
-		TotalLoot:       loot,
+		SoldierCount:      soldierCount,
+		AvailableSoldiers: free,
+		// Soldiers away on an expedition don't defend the settlement
+		DefenseRating:     mm.CalculateDefense(home, militaryBonus),
+		MilitaryBonus:     militaryBonus,
+		ExpeditionBonus:   expeditionBonus,
+		Units:             units,
+		Upkeep:            mm.Upkeep(soldierCount),
+		Morale:            mm.morale,
+		Training:          training,
+		TrainingSlots:     trainingSlots,
+		TrainingTicks:     mm.TrainingTicks(trainingSlots),
+		ActiveExpeditions: activeExps,
+		ExpeditionSlots:   slots,
+		Expeditions:       expList,
+		CompletedCount:    mm.completedCount,
+		TotalLoot:         loot,
+		Raids:             raids,
+	}
+}
+
+// rankExpeditions numbers the winnable expeditions that can launch now by
+// loot per soldier-tick, best first
+func rankExpeditions(exps []ExpeditionInfo) {
+	var ranked []*ExpeditionInfo
+	for i := range exps {
+		if exps[i].CanLaunch && exps[i].Forecast.Won {
+			ranked = append(ranked, &exps[i])
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Forecast.LootPerSoldierTick > ranked[j].Forecast.LootPerSoldierTick
+	})
+	for i, exp := range ranked {
+		exp.Rank = i + 1
 	}
 }
 
 // LoadState restores military state from save
-func (mm *MilitaryManager) LoadState(active *ActiveExpedition, completedCount int, totalLoot map[string]float64) {
-	mm.active = active
+func (mm *MilitaryManager) LoadState(active []ActiveExpedition, completedCount int, totalLoot map[string]float64, units map[string]int, training []TrainingOrder, morale float64) {
+	mm.active = nil
+	for _, a := range active {
+		a := a
+		if a.TotalTicks < a.TicksLeft {
+			a.TotalTicks = a.TicksLeft
+		}
+		// Saves from before unit types sent infantry only
+		if len(a.Units) == 0 {
+			a.Units = map[string]int{infantryUnit: a.Soldiers}
+		}
+		mm.active = append(mm.active, &a)
+	}
 	mm.completedCount = completedCount
 	if totalLoot != nil {
 		mm.totalLoot = totalLoot
 	}
+	mm.units = make(map[string]int)
+	for u, n := range units {
+		mm.units[u] = n
+	}
+	mm.training = nil
+	for _, o := range training {
+		o := o
+		if o.Count > 0 {
+			mm.training = append(mm.training, &o)
+		}
+	}
+	mm.morale = morale
 }
 
-// GetActiveForSave returns active expedition for saving
-func (mm *MilitaryManager) GetActiveForSave() *ActiveExpedition {
-	if mm.active == nil {
-		return nil
+// GetTrainingForSave returns the training queue for saving
+func (mm *MilitaryManager) GetTrainingForSave() []TrainingOrder {
+	out := make([]TrainingOrder, 0, len(mm.training))
+	for _, o := range mm.training {
+		out = append(out, *o)
+	}
+	return out
+}
+
+// GetUnitsForSave returns trained unit counts for saving
+func (mm *MilitaryManager) GetUnitsForSave() map[string]int {
+	return copyUnits(mm.units)
+}
+
+// GetActiveForSave returns active expeditions for saving
+func (mm *MilitaryManager) GetActiveForSave() []ActiveExpedition {
+	out := make([]ActiveExpedition, 0, len(mm.active))
+	for _, a := range mm.active {
+		cp := *a
+		cp.Units = copyUnits(a.Units)
+		out = append(out, cp)
+	}
+	return out
+}
+
+// copyUnits copies an army, dropping empty entries
+func copyUnits(army map[string]int) map[string]int {
+	out := make(map[string]int)
+	for u, n := range army {
+		if n > 0 {
+			out[u] = n
+		}
 	}
-	copy := *mm.active
-	return &copy
+	return out
 }
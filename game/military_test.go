@@ -0,0 +1,599 @@
+package game
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/user/ageforge/config"
+)
+
+func TestMilitaryManager_ConcurrentExpeditions(t *testing.T) {
+	mm := NewMilitaryManager()
+	ageOrder := raidAgeOrder()
+
+	if err := mm.LaunchExpedition("scout_ruins", nil, 10, 2, "bronze_age", ageOrder); err != nil {
+		t.Fatalf("first launch failed: %v", err)
+	}
+	if err := mm.LaunchExpedition("raid_bandits", nil, 10, 2, "bronze_age", ageOrder); err != nil {
+		t.Fatalf("second launch failed: %v", err)
+	}
+	if got := mm.ActiveCount(); got != 2 {
+		t.Errorf("active = %d, want 2", got)
+	}
+	if got := mm.DeployedSoldiers(); got != 7 {
+		t.Errorf("deployed = %d, want 7", got)
+	}
+}
+
+func TestMilitaryManager_SlotLimit(t *testing.T) {
+	mm := NewMilitaryManager()
+	ageOrder := raidAgeOrder()
+
+	if err := mm.LaunchExpedition("scout_ruins", nil, 20, 1, "iron_age", ageOrder); err != nil {
+		t.Fatalf("first launch failed: %v", err)
+	}
+	if err := mm.LaunchExpedition("trade_escort", nil, 20, 1, "iron_age", ageOrder); err == nil {
+		t.Error("launch with no free slot succeeded, want error")
+	}
+	if err := mm.LaunchExpedition("scout_ruins", nil, 20, 3, "iron_age", ageOrder); err == nil {
+		t.Error("duplicate launch succeeded, want error")
+	}
+}
+
+func TestMilitaryManager_CommittedSoldiers(t *testing.T) {
+	mm := NewMilitaryManager()
+	ageOrder := raidAgeOrder()
+
+	// 6 soldiers: scouting commits 2, leaving 4 — not enough for the bandit camp
+	if err := mm.LaunchExpedition("scout_ruins", nil, 6, 3, "bronze_age", ageOrder); err != nil {
+		t.Fatalf("launch failed: %v", err)
+	}
+	if err := mm.LaunchExpedition("raid_bandits", nil, 6, 3, "bronze_age", ageOrder); err == nil {
+		t.Error("launch with committed soldiers succeeded, want error")
+	}
+
+	state := mm.Snapshot("bronze_age", ageOrder, nil, 6, 3, 1, 0, 0, RaidState{})
+	if state.AvailableSoldiers != 4 {
+		t.Errorf("available = %d, want 4", state.AvailableSoldiers)
+	}
+	for _, exp := range state.Expeditions {
+		if exp.Key == "raid_bandits" && exp.CanLaunch {
+			t.Error("raid_bandits CanLaunch = true, want false")
+		}
+		if exp.Key == "scout_ruins" && !exp.Underway {
+			t.Error("scout_ruins Underway = false, want true")
+		}
+	}
+}
+
+func TestMilitaryManager_TickResolvesEach(t *testing.T) {
+	mm := NewMilitaryManager()
+	ageOrder := raidAgeOrder()
+	mm.LaunchExpedition("scout_ruins", nil, 10, 2, "bronze_age", ageOrder)
+	mm.LaunchExpedition("raid_bandits", nil, 10, 2, "bronze_age", ageOrder)
+
+	finished := 0
+	for i := 0; i < 15; i++ {
+		finished += len(mm.Tick(0, 0))
+		if i == 9 && mm.ActiveCount() != 1 {
+			t.Errorf("active after 10 ticks = %d, want 1", mm.ActiveCount())
+		}
+	}
+	if finished != 2 {
+		t.Errorf("finished = %d, want 2", finished)
+	}
+	if mm.ActiveCount() != 0 {
+		t.Errorf("active = %d, want 0", mm.ActiveCount())
+	}
+}
+
+func TestBuildingManager_ExpeditionSlots(t *testing.T) {
+	bm := NewBuildingManager()
+	if got := bm.ExpeditionSlots(); got != 0 {
+		t.Errorf("slots = %d, want 0", got)
+	}
+	bm.counts["barracks"] = 3
+	if got := bm.ExpeditionSlots(); got != 1 {
+		t.Errorf("slots with 3 barracks = %d, want 1", got)
+	}
+	bm.counts["castle"] = 1
+	if got := bm.ExpeditionSlots(); got != 2 {
+		t.Errorf("slots with barracks and castle = %d, want 2", got)
+	}
+}
+
+func TestEngine_SaveLoadExpeditions(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Military.active = []*ActiveExpedition{
+		{Key: "scout_ruins", Name: "Scout Nearby Ruins", Soldiers: 2, Units: map[string]int{"infantry": 2}, TicksLeft: 4, TotalTicks: 10},
+		{Key: "raid_bandits", Name: "Raid Bandit Camp", Soldiers: 5, Units: map[string]int{"archers": 5}, TicksLeft: 9, TotalTicks: 15},
+	}
+	ge.Military.units["archers"] = 5
+	if err := ge.SaveGame("test_expeditions"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_expeditions.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_expeditions"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	if got := ge2.Military.ActiveCount(); got != 2 {
+		t.Fatalf("active = %d, want 2", got)
+	}
+	if got := ge2.Military.DeployedSoldiers(); got != 7 {
+		t.Errorf("deployed = %d, want 7", got)
+	}
+	if got := ge2.Military.units["archers"]; got != 5 {
+		t.Errorf("trained archers = %d, want 5", got)
+	}
+	if got := ge2.Military.active[1].Units["archers"]; got != 5 {
+		t.Errorf("deployed archers = %d, want 5", got)
+	}
+}
+
+func TestMilitarySave_LegacySingleExpedition(t *testing.T) {
+	data := []byte(`{"active_expedition":{"Key":"scout_ruins","Name":"Scout Nearby Ruins","Soldiers":2,"TicksLeft":3},"completed_count":1}`)
+	var save MilitarySave
+	if err := json.Unmarshal(data, &save); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if save.ActiveExpedition == nil || save.ActiveExpedition.Key != "scout_ruins" {
+		t.Fatalf("ActiveExpedition = %+v, want scout_ruins", save.ActiveExpedition)
+	}
+	if len(save.ActiveExpeditions) != 0 {
+		t.Errorf("ActiveExpeditions = %d, want 0", len(save.ActiveExpeditions))
+	}
+
+	// Old expeditions carry no units: their soldiers went as infantry
+	mm := NewMilitaryManager()
+	mm.LoadState([]ActiveExpedition{*save.ActiveExpedition}, save.CompletedCount, nil, nil, nil, moraleMax)
+	if got := mm.active[0].Units["infantry"]; got != 2 {
+		t.Errorf("legacy infantry = %d, want 2", got)
+	}
+}
+
+// testCampaign is a short two-stage campaign for exercising stage logic
+func testCampaign() config.ExpeditionDef {
+	return config.ExpeditionDef{
+		Name: "Test Campaign", Key: "test_campaign",
+		MinAge: "bronze_age", SoldiersNeeded: 4,
+		Stages: []config.ExpeditionStageDef{
+			{Name: "First", Duration: 1, Enemy: map[string]int{"infantry": 1}, Rewards: map[string]float64{"gold": 10}},
+			{Name: "Second", Duration: 2, Enemy: map[string]int{"infantry": 1}, Rewards: map[string]float64{"gold": 20}},
+		},
+	}
+}
+
+func TestMilitaryManager_CampaignHoldsAndRetreats(t *testing.T) {
+	mm := NewMilitaryManager()
+	mm.expeditions = []config.ExpeditionDef{testCampaign()}
+	if err := mm.LaunchExpedition("test_campaign", nil, 10, 1, "bronze_age", raidAgeOrder()); err != nil {
+		t.Fatalf("launch failed: %v", err)
+	}
+	results := mm.Tick(0, 0)
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1", len(results))
+	}
+	if !results[0].StageWon {
+		t.Fatalf("4 infantry lost to 1 infantry: %s", results[0].Message)
+	}
+
+	if mm.ActiveCount() != 1 || !mm.active[0].Holding {
+		t.Fatalf("campaign not holding after winning a stage")
+	}
+	if got := mm.Tick(0, 0); len(got) != 0 {
+		t.Errorf("holding campaign resolved %d results, want 0", len(got))
+	}
+	res, err := mm.RetreatExpedition("test_campaign")
+	if err != nil {
+		t.Fatalf("retreat failed: %v", err)
+	}
+	if res.Rewards["gold"] != 10 {
+		t.Errorf("retreat gold = %v, want 10", res.Rewards["gold"])
+	}
+	if mm.ActiveCount() != 0 {
+		t.Errorf("active after retreat = %d, want 0", mm.ActiveCount())
+	}
+}
+
+func TestMilitaryManager_CampaignAdvance(t *testing.T) {
+	mm := NewMilitaryManager()
+	mm.expeditions = []config.ExpeditionDef{testCampaign()}
+	mm.LaunchExpedition("test_campaign", nil, 10, 1, "bronze_age", raidAgeOrder())
+
+	if err := mm.AdvanceExpedition("test_campaign"); err == nil {
+		t.Error("advance while marching succeeded, want error")
+	}
+	if _, err := mm.RetreatExpedition("test_campaign"); err == nil {
+		t.Error("retreat while marching succeeded, want error")
+	}
+
+	a := mm.active[0]
+	a.Stage, a.Holding, a.TicksLeft = 1, true, 0
+	if err := mm.AdvanceExpedition("test_campaign"); err != nil {
+		t.Fatalf("advance failed: %v", err)
+	}
+	if a.Holding || a.TicksLeft != 2 {
+		t.Errorf("after advance: holding = %v, ticks = %d, want false, 2", a.Holding, a.TicksLeft)
+	}
+}
+
+func TestEngine_CampaignRewards(t *testing.T) {
+	ge := NewGameEngine()
+	before := ge.Research.GetBonus("military_power")
+	ge.applyCampaignRewards(ExpeditionResult{Faction: "merchant_guild", Tech: "frontier_doctrine"})
+
+	if !ge.Research.IsResearched("frontier_doctrine") {
+		t.Error("frontier_doctrine not granted")
+	}
+	if got := ge.Research.GetBonus("military_power"); got <= before {
+		t.Errorf("military_power = %v, want > %v", got, before)
+	}
+	if fs, ok := ge.Diplomacy.factions["merchant_guild"]; !ok || !fs.Discovered {
+		t.Error("merchant_guild not discovered")
+	}
+}
+
+func TestResearchManager_CampaignTechNotResearchable(t *testing.T) {
+	rm := NewResearchManager()
+	if err := rm.StartResearch("frontier_doctrine", "medieval_age", raidAgeOrder(), 1e9, 0); err == nil {
+		t.Error("researching a campaign tech succeeded, want error")
+	}
+}
+
+func TestEngine_SaveLoadHoldingCampaign(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Military.active = []*ActiveExpedition{{
+		Key: "frontier_campaign", Name: "Frontier Campaign", Soldiers: 12,
+		TotalTicks: 10, Stage: 1, Holding: true, Haul: map[string]float64{"food": 40},
+	}}
+	if err := ge.SaveGame("test_campaign"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_campaign.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_campaign"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	if ge2.Military.ActiveCount() != 1 {
+		t.Fatalf("active = %d, want 1", ge2.Military.ActiveCount())
+	}
+	a := ge2.Military.active[0]
+	if a.Stage != 1 || !a.Holding || a.Haul["food"] != 40 {
+		t.Errorf("loaded stage = %d, holding = %v, haul = %v, want 1, true, food:40", a.Stage, a.Holding, a.Haul)
+	}
+}
+
+func TestMilitaryManager_TrainAndRoster(t *testing.T) {
+	mm := NewMilitaryManager()
+	if err := mm.QueueTraining("archers", 3, 5); err != nil {
+		t.Fatalf("train failed: %v", err)
+	}
+	if got := mm.HomeUnits(5); got["infantry"] != 2 {
+		t.Errorf("home while training = %v, want 2 infantry", got)
+	}
+	if err := mm.QueueTraining("archers", 3, 5); err == nil {
+		t.Error("training more than the idle infantry succeeded, want error")
+	}
+	for i := 0; i < config.UnitByKey()["archers"].TrainTicks; i++ {
+		mm.TickTraining(3)
+	}
+	roster := mm.Roster(5)
+	if roster["archers"] != 3 || roster["infantry"] != 2 {
+		t.Errorf("roster = %v, want 3 archers, 2 infantry", roster)
+	}
+	units := config.UnitByKey()
+	upkeep := mm.Upkeep(5)
+	if got := upkeep["wood"]; got != 3*units["archers"].Upkeep["wood"] {
+		t.Errorf("wood upkeep = %v, want 3 archers' worth", got)
+	}
+	if got, want := upkeep["gold"], 3*units["archers"].Upkeep["gold"]+2*units["infantry"].Upkeep["gold"]; math.Abs(got-want) > 1e-9 {
+		t.Errorf("gold upkeep = %v, want %v", got, want)
+	}
+
+	// Soldiers lost outside battle eat into infantry, then trained units
+	mm.Reconcile(2)
+	if got := mm.Roster(2); got["archers"] != 2 || got["infantry"] != 0 {
+		t.Errorf("roster after losing 3 soldiers = %v, want 2 archers", got)
+	}
+}
+
+func TestMilitaryManager_LaunchWithChosenUnits(t *testing.T) {
+	mm := NewMilitaryManager()
+	ageOrder := raidAgeOrder()
+	mm.units["archers"] = 4
+
+	if err := mm.LaunchExpedition("raid_bandits", map[string]int{"archers": 5}, 10, 2, "bronze_age", ageOrder); err == nil {
+		t.Error("sending more archers than at home succeeded, want error")
+	}
+	if err := mm.LaunchExpedition("raid_bandits", map[string]int{"archers": 4}, 10, 2, "bronze_age", ageOrder); err == nil {
+		t.Error("sending fewer than SoldiersNeeded succeeded, want error")
+	}
+	army := map[string]int{"archers": 4, "infantry": 2}
+	if err := mm.LaunchExpedition("raid_bandits", army, 10, 2, "bronze_age", ageOrder); err != nil {
+		t.Fatalf("launch failed: %v", err)
+	}
+	home := mm.HomeUnits(10)
+	if home["archers"] != 0 || home["infantry"] != 4 {
+		t.Errorf("home = %v, want 4 infantry", home)
+	}
+
+	// With no selection the strongest units at home go first
+	mm2 := NewMilitaryManager()
+	mm2.units["archers"] = 1
+	mm2.LaunchExpedition("scout_ruins", nil, 10, 1, "bronze_age", ageOrder)
+	if got := mm2.active[0].Units; got["archers"] != 1 || got["infantry"] != 1 {
+		t.Errorf("auto-picked = %v, want 1 archers, 1 infantry", got)
+	}
+}
+
+func TestMilitaryManager_ExpeditionCasualties(t *testing.T) {
+	mm := NewMilitaryManager()
+	mm.expeditions = []config.ExpeditionDef{{
+		Name: "Test", Key: "test", MinAge: "bronze_age", SoldiersNeeded: 3, Duration: 1,
+		Enemy:   map[string]int{"infantry": 3},
+		Rewards: map[string]float64{"gold": 10},
+	}}
+	mm.units["archers"] = 3
+	mm.LaunchExpedition("test", nil, 3, 1, "bronze_age", raidAgeOrder())
+
+	results := mm.Tick(0, 0)
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1", len(results))
+	}
+	res := results[0]
+	want := ResolveBattle(map[string]int{"archers": 3}, map[string]int{"infantry": 3}, 0, 0)
+	if res.SoldiersLost != UnitTotal(want.AttackerLosses) {
+		t.Errorf("lost = %d, want %d", res.SoldiersLost, UnitTotal(want.AttackerLosses))
+	}
+	if got := mm.units["archers"]; got != 3-want.AttackerLosses["archers"] {
+		t.Errorf("archers left = %d, want %d", got, 3-want.AttackerLosses["archers"])
+	}
+}
+
+func TestForecastExpedition_MatchesOutcome(t *testing.T) {
+	hard := testCampaign()
+	hard.Stages[1].Enemy = map[string]int{"infantry": 6}
+	for _, camp := range []config.ExpeditionDef{testCampaign(), hard} {
+		army := map[string]int{"infantry": 5}
+		mm := NewMilitaryManager()
+		mm.expeditions = []config.ExpeditionDef{camp}
+		f := ForecastExpedition(camp, army, 0.1, 0.5)
+		if err := mm.LaunchExpedition(camp.Key, army, 6, 1, "bronze_age", raidAgeOrder()); err != nil {
+			t.Fatalf("launch failed: %v", err)
+		}
+
+		loot := make(map[string]float64)
+		lost, ticks := 0, 0
+		for mm.ActiveCount() > 0 {
+			ticks++
+			for _, res := range mm.Tick(0.1, 0.5) {
+				lost += res.SoldiersLost
+				for r, amount := range res.Rewards {
+					loot[r] += amount
+				}
+				if res.StageWon {
+					mm.AdvanceExpedition(camp.Key)
+				}
+			}
+		}
+		if f.SoldiersLost != lost {
+			t.Errorf("%v: forecast lost %d, actual %d", camp.Stages[1].Enemy, f.SoldiersLost, lost)
+		}
+		if f.Ticks != ticks {
+			t.Errorf("%v: forecast %d ticks, actual %d", camp.Stages[1].Enemy, f.Ticks, ticks)
+		}
+		for r, amount := range loot {
+			if math.Abs(f.Loot[r]-amount) > 1e-9 {
+				t.Errorf("%v: forecast %s = %v, actual %v", camp.Stages[1].Enemy, r, f.Loot[r], amount)
+			}
+		}
+	}
+}
+
+func TestMilitaryManager_SnapshotRanksExpeditions(t *testing.T) {
+	mm := NewMilitaryManager()
+	state := mm.Snapshot("bronze_age", raidAgeOrder(), nil, 10, 2, 1, 0, 0, RaidState{})
+
+	ranks := make(map[int]float64)
+	for _, exp := range state.Expeditions {
+		if exp.Rank == 0 {
+			if exp.CanLaunch && exp.Forecast.Won {
+				t.Errorf("%s is launchable and won but unranked", exp.Key)
+			}
+			continue
+		}
+		ranks[exp.Rank] = exp.Forecast.LootPerSoldierTick
+	}
+	if len(ranks) == 0 {
+		t.Fatal("no expeditions ranked")
+	}
+	for r := 2; r <= len(ranks); r++ {
+		if ranks[r] > ranks[r-1] {
+			t.Errorf("rank %d (%v) beats rank %d (%v)", r, ranks[r], r-1, ranks[r-1])
+		}
+	}
+}
+
+func TestEngine_TrainUnits(t *testing.T) {
+	ge := NewGameEngine()
+	ge.age = "bronze_age"
+	ge.Villagers.UnlockType("soldier")
+	ge.Villagers.types["soldier"].count = 4
+	ge.Resources.UnlockResource("wood")
+	ge.Resources.Add("wood", 100)
+
+	if err := ge.TrainUnits("archers", 2); err == nil {
+		t.Error("training archers without military_tactics succeeded, want error")
+	}
+	ge.Research.Grant("military_tactics")
+	before := ge.Resources.Get("wood")
+	if err := ge.TrainUnits("archers", 2); err != nil {
+		t.Fatalf("train failed: %v", err)
+	}
+	archers := config.UnitByKey()["archers"]
+	if got := ge.Resources.Get("wood"); got != before-2*archers.TrainCost["wood"] {
+		t.Errorf("wood = %v, want training cost paid", got)
+	}
+	if got := ge.Military.units["archers"]; got != 0 {
+		t.Errorf("archers before training = %d, want 0", got)
+	}
+	// One lane trains the two archers one after the other
+	for i := 0; i < 2*archers.TrainTicks; i++ {
+		ge.processTraining()
+	}
+	if got := ge.Military.units["archers"]; got != 2 {
+		t.Errorf("archers after training = %d, want 2", got)
+	}
+	if got := ge.Resources.resources["wood"].Breakdown.SourceTotal(SourceMilitary); got >= 0 {
+		t.Errorf("wood upkeep rate = %v, want negative", got)
+	}
+	if err := ge.TrainUnits("cavalry", 1); err == nil {
+		t.Error("training cavalry in the bronze age succeeded, want error")
+	}
+}
+
+func TestMilitaryManager_TrainingQueueLanes(t *testing.T) {
+	mm := NewMilitaryManager()
+	mm.QueueTraining("infantry", 5, 0)
+	ticks := config.UnitByKey()["infantry"].TrainTicks
+
+	// Two lanes train the five recruits in three groups
+	if got := mm.TrainingTicks(2); got != 3*ticks {
+		t.Errorf("queue ticks = %d, want %d", got, 3*ticks)
+	}
+	done, elapsed := 0, 0
+	for mm.QueuedRecruits() > 0 {
+		elapsed++
+		done += mm.TickTraining(2)["infantry"]
+	}
+	if done != 5 || elapsed != 3*ticks {
+		t.Errorf("trained %d in %d ticks, want 5 in %d", done, elapsed, 3*ticks)
+	}
+	if mm.Roster(0)["archers"] != 0 {
+		t.Error("recruits joined the trained roster, want them left to the caller")
+	}
+}
+
+func TestMilitaryManager_ReconcileDropsRetraining(t *testing.T) {
+	mm := NewMilitaryManager()
+	mm.QueueTraining("archers", 2, 4)
+	mm.QueueTraining("archers", 2, 4)
+
+	// Three soldiers die: only one infantry is left to retrain
+	mm.Reconcile(1)
+	if got := mm.retraining(); got != 1 {
+		t.Errorf("retraining = %d, want 1", got)
+	}
+	if len(mm.training) != 1 {
+		t.Errorf("orders = %d, want the back order dropped", len(mm.training))
+	}
+}
+
+func TestMilitaryManager_MoraleAndDesertion(t *testing.T) {
+	mm := NewMilitaryManager()
+	mm.units["archers"] = 10
+
+	unpaidTicks := 0
+	var deserters map[string]int
+	for UnitTotal(deserters) == 0 {
+		unpaidTicks++
+		deserters = mm.TickMorale(false, 60)
+		if unpaidTicks > 100 {
+			t.Fatal("no desertions after 100 unpaid ticks")
+		}
+	}
+	if mm.Morale() >= desertionMorale {
+		t.Errorf("deserted at morale %v, want below %v", mm.Morale(), desertionMorale)
+	}
+	// 2% of 60 soldiers, weakest first
+	if deserters["infantry"] != 2 || deserters["archers"] != 0 {
+		t.Errorf("deserters = %v, want 2 infantry", deserters)
+	}
+
+	before := mm.Morale()
+	if got := mm.TickMorale(true, 60); got != nil {
+		t.Errorf("paid tick deserters = %v, want none", got)
+	}
+	if mm.Morale() <= before {
+		t.Errorf("morale %v after a paid tick, want above %v", mm.Morale(), before)
+	}
+}
+
+func TestEngine_RecruitSoldiersTrain(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Villagers.UnlockType("soldier")
+	ge.Buildings.counts["hut"] = 2
+	ge.recalculateRates()
+	popCap := ge.popCap()
+
+	if err := ge.RecruitVillager("soldier", popCap); err != nil {
+		t.Fatalf("recruit failed: %v", err)
+	}
+	if got := ge.soldierCount(); got != 0 {
+		t.Errorf("soldiers before training = %d, want 0", got)
+	}
+	// Queued recruits hold their place in the population
+	if err := ge.RecruitVillager("worker", 1); err == nil {
+		t.Error("recruiting past the queued soldiers succeeded, want error")
+	}
+
+	ticks := ge.Military.TrainingTicks(ge.trainingSlots())
+	for i := 0; i < ticks; i++ {
+		ge.processTraining()
+	}
+	if got := ge.soldierCount(); got != popCap {
+		t.Errorf("soldiers after %d ticks = %d, want %d", ticks, got, popCap)
+	}
+}
+
+func TestEngine_UnpaidSoldiersDesert(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Villagers.UnlockType("soldier")
+	ge.Villagers.types["soldier"].count = 50
+	ge.Resources.UnlockResource("gold")
+
+	for i := 0; i < 60; i++ {
+		ge.processMorale()
+	}
+	if got := ge.soldierCount(); got >= 50 {
+		t.Errorf("soldiers after 60 unpaid ticks = %d, want desertions", got)
+	}
+
+	ge.Resources.Add("gold", 100)
+	morale := ge.Military.Morale()
+	ge.processMorale()
+	if ge.Military.Morale() <= morale {
+		t.Errorf("morale %v once paid, want above %v", ge.Military.Morale(), morale)
+	}
+}
+
+func TestEngine_SaveLoadTraining(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Villagers.types["soldier"].count = 3
+	ge.Military.QueueTraining("archers", 2, 3)
+	ge.Military.QueueTraining("infantry", 4, 3)
+	ge.Military.morale = 40
+	if err := ge.SaveGame("test_training"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_training.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_training"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	if got := ge2.Military.retraining(); got != 2 {
+		t.Errorf("retraining = %d, want 2", got)
+	}
+	if got := ge2.Military.QueuedRecruits(); got != 4 {
+		t.Errorf("queued recruits = %d, want 4", got)
+	}
+	if got := ge2.Military.Morale(); got != 40 {
+		t.Errorf("morale = %v, want 40", got)
+	}
+}
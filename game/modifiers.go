@@ -0,0 +1,250 @@
+package game
+
+import (
+	"sort"
+
+	"github.com/user/ageforge/config"
+)
+
+// Every rate and bonus in the game is built from Modifiers registered by the
+// system that grants them. A resource's rate is
+//
+//	rate = additive × (1 + multiplicative) + consumption + final
+//
+// where the multiplier applies only while the additive layer is positive,
+// so inputs, drains and penalties are never inflated. Within a layer modifiers sum.
+// Other stats (military_power, tick_speed, population...) are the plain sum
+// of their modifiers.
+
+// Modifier layers, in the order they apply
+const (
+	LayerAdditive       = "additive"
+	LayerMultiplicative = "multiplicative"
+	LayerConsumption    = "consumption"
+	LayerFinal          = "final"
+)
+
+// Modifier sources
+const (
+	SourceBuilding   = "building"
+	SourceVillager   = "villager"
+	SourceTech       = "tech"
+	SourceMilestone  = "milestone"
+	SourcePrestige   = "prestige"
+	SourceEvent      = "event"
+	SourceFaction    = "faction"
+	SourceMilitary   = "military"
+	SourceSettlement = "layout"
+	SourceBiome      = "biome"
+)
+
+// flatStats are bonuses counted in units rather than as fractions
+var flatStats = map[string]bool{
+	"population":       true,
+	"expedition_slots": true,
+}
+
+// Modifier is one contribution to a stat and where it came from
+type Modifier struct {
+	Stat   string  // resource key, bonus key (e.g. "gold_rate") or storageStat(key)
+	Layer  string  // Layer* constant
+	Value  float64 // per tick, or a fraction for LayerMultiplicative
+	Source string  // Source* constant
+	Key    string  // what grants it, e.g. "farm"; stable from tick to tick
+	Name   string  // its display name, e.g. "Farm ×3"
+}
+
+// storageStat is the stat holding storage bonuses for a resource key, or
+// "all" for every resource
+func storageStat(key string) string {
+	return "storage:" + key
+}
+
+// effectModifier turns a config effect into a modifier. Returns false for
+// effects that aren't standing modifiers (instant resources, thefts, slots).
+func effectModifier(eff config.Effect, source, key, name string) (Modifier, bool) {
+	m := Modifier{Stat: eff.Target, Value: eff.Value, Source: source, Key: key, Name: name}
+	switch eff.Type {
+	case "production":
+		m.Layer = LayerAdditive
+	case "bonus", "permanent_bonus", "tick_speed":
+		m.Layer = LayerMultiplicative
+		if flatStats[eff.Target] {
+			m.Layer = LayerAdditive
+		}
+	case "storage":
+		m.Stat = storageStat(eff.Target)
+		m.Layer = LayerAdditive
+	case "capacity":
+		if eff.Target != "population" {
+			return Modifier{}, false
+		}
+		m.Layer = LayerAdditive
+	default:
+		return Modifier{}, false
+	}
+	return m, true
+}
+
+// effectModifiers converts every standing effect in effs, scaled by count
+func effectModifiers(effs []config.Effect, count float64, source, key, name string) []Modifier {
+	var mods []Modifier
+	for _, eff := range effs {
+		if m, ok := effectModifier(eff, source, key, name); ok {
+			m.Value *= count
+			mods = append(mods, m)
+		}
+	}
+	return mods
+}
+
+// ModifierStack collects the modifiers of every source
+type ModifierStack struct {
+	mods []Modifier
+}
+
+// Add registers modifiers
+func (s *ModifierStack) Add(mods ...Modifier) {
+	s.mods = append(s.mods, mods...)
+}
+
+// Replace swaps a source's modifiers for mods, keeping their place in the stack
+func (s *ModifierStack) Replace(source string, mods []Modifier) {
+	out := make([]Modifier, 0, len(s.mods)+len(mods))
+	at := -1
+	for _, m := range s.mods {
+		if m.Source != source {
+			out = append(out, m)
+		} else if at < 0 {
+			at = len(out)
+		}
+	}
+	if at < 0 {
+		at = len(out)
+	}
+	s.mods = append(out[:at], append(mods, out[at:]...)...)
+}
+
+// Of returns the modifiers on a stat, in registration order
+func (s *ModifierStack) Of(stat string) []Modifier {
+	var out []Modifier
+	for _, m := range s.mods {
+		if m.Stat == stat {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Sum returns the total of a stat's modifiers in one layer
+func (s *ModifierStack) Sum(stat, layer string) float64 {
+	total := 0.0
+	for _, m := range s.mods {
+		if m.Stat == stat && m.Layer == layer {
+			total += m.Value
+		}
+	}
+	return total
+}
+
+// Total returns the total of a stat's modifiers across all layers
+func (s *ModifierStack) Total(stat string) float64 {
+	total := 0.0
+	for _, m := range s.mods {
+		if m.Stat == stat {
+			total += m.Value
+		}
+	}
+	return total
+}
+
+// Storage returns the storage bonus for a resource: its own plus the
+// bonuses to all storage
+func (s *ModifierStack) Storage(key string) float64 {
+	return s.Total(storageStat("all")) + s.Total(storageStat(key))
+}
+
+// StorageSources lists the storage bonuses for a resource, bonuses to all
+// storage first
+func (s *ModifierStack) StorageSources(key string) []RateSource {
+	var out []RateSource
+	for _, stat := range []string{storageStat("all"), storageStat(key)} {
+		for _, m := range s.Of(stat) {
+			out = append(out, RateSource{
+				Layer: m.Layer, Source: m.Source, Key: m.Key, Name: m.Name, Value: m.Value, Amount: m.Value,
+			})
+		}
+	}
+	return out
+}
+
+// Rate computes a resource's net rate and its breakdown. Villager output is
+// raised by gather_rate before the multiplier; production_all and
+// <key>_rate make up the multiplier.
+func (s *ModifierStack) Rate(key string) (float64, RateBreakdown) {
+	var b RateBreakdown
+	villagers := 0.0
+	for _, m := range s.Of(key) {
+		if m.Layer == LayerAdditive {
+			b.Base += m.Value
+			if m.Source == SourceVillager {
+				villagers += m.Value
+			}
+		}
+		b.Sources = append(b.Sources, RateSource{
+			Layer: m.Layer, Source: m.Source, Key: m.Key, Name: m.Name, Value: m.Value, Amount: m.Value,
+		})
+	}
+	if villagers != 0 {
+		for _, m := range s.Of("gather_rate") {
+			amount := villagers * m.Value
+			b.Base += amount
+			b.Sources = append(b.Sources, RateSource{
+				Layer: LayerAdditive, Source: m.Source, Key: m.Key, Name: m.Name + " (gather)", Value: m.Value, Amount: amount,
+			})
+		}
+	}
+
+	b.Multiplier = 1
+	for _, stat := range []string{"production_all", key + "_rate"} {
+		for _, m := range s.Of(stat) {
+			b.Multiplier += m.Value
+			// A multiplier on nothing contributes nothing; leave it out
+			if b.Base <= 0 {
+				continue
+			}
+			b.Sources = append(b.Sources, RateSource{
+				Layer: LayerMultiplicative, Source: m.Source, Key: m.Key, Name: m.Name, Value: m.Value, Amount: b.Base * m.Value,
+			})
+		}
+	}
+
+	b.Consumption = s.Sum(key, LayerConsumption)
+	b.Final = s.Sum(key, LayerFinal)
+	rate := b.Base + b.Consumption + b.Final
+	if b.Base > 0 {
+		rate = b.Base*b.Multiplier + b.Consumption + b.Final
+	}
+	sort.SliceStable(b.Sources, func(i, j int) bool {
+		return layerOrder[b.Sources[i].Layer] < layerOrder[b.Sources[j].Layer]
+	})
+	return rate, b
+}
+
+var layerOrder = map[string]int{
+	LayerAdditive:       0,
+	LayerMultiplicative: 1,
+	LayerConsumption:    2,
+	LayerFinal:          3,
+}
+
+// SourceTotal returns the per-tick amount a source adds to the rate
+func (b RateBreakdown) SourceTotal(source string) float64 {
+	total := 0.0
+	for _, rs := range b.Sources {
+		if rs.Source == source {
+			total += rs.Amount
+		}
+	}
+	return total
+}
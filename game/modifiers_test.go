@@ -0,0 +1,120 @@
+package game
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestModifierStack_Rate(t *testing.T) {
+	s := &ModifierStack{}
+	s.Add(
+		Modifier{Stat: "wood", Layer: LayerAdditive, Value: 1, Source: SourceBuilding, Name: "Lumber Camp ×2"},
+		Modifier{Stat: "wood", Layer: LayerAdditive, Value: 2, Source: SourceVillager, Name: "Worker ×4"},
+		Modifier{Stat: "gather_rate", Layer: LayerMultiplicative, Value: 0.5, Source: SourceTech, Name: "Tools"},
+		Modifier{Stat: "production_all", Layer: LayerMultiplicative, Value: 0.25, Source: SourcePrestige, Name: "Prestige level 1"},
+		Modifier{Stat: "wood_rate", Layer: LayerMultiplicative, Value: 0.25, Source: SourceMilestone, Name: "Lumberjacks"},
+		Modifier{Stat: "wood", Layer: LayerFinal, Value: -1, Source: SourceEvent, Name: "Termites"},
+	)
+
+	// Gather lifts only villager output: (1 + 2 + 2×0.5) × 1.5 − 1
+	rate, b := s.Rate("wood")
+	if rate != 5 || b.Base != 4 || b.Multiplier != 1.5 || b.Final != -1 {
+		t.Fatalf("rate %v, breakdown %+v; want 5 from 4 × 1.5 − 1", rate, b)
+	}
+	sum := 0.0
+	for i, src := range b.Sources {
+		sum += src.Amount
+		if i > 0 && layerOrder[src.Layer] < layerOrder[b.Sources[i-1].Layer] {
+			t.Errorf("source %d (%s) out of layer order", i, src.Layer)
+		}
+	}
+	if sum != rate {
+		t.Errorf("sources sum to %v, want the rate %v", sum, rate)
+	}
+	if got := b.SourceTotal(SourceTech); got != 1 {
+		t.Errorf("tech contributes %v, want the gather bonus of 1", got)
+	}
+}
+
+func TestModifierStack_DrainIsNotMultiplied(t *testing.T) {
+	s := &ModifierStack{}
+	s.Add(
+		Modifier{Stat: "gold", Layer: LayerAdditive, Value: -2, Source: SourceBuilding, Name: "Mint"},
+		Modifier{Stat: "production_all", Layer: LayerMultiplicative, Value: 1, Source: SourceTech, Name: "Banking"},
+	)
+	rate, b := s.Rate("gold")
+	if rate != -2 {
+		t.Errorf("rate = %v, want −2 untouched by the multiplier", rate)
+	}
+	if got := b.SourceTotal(SourceTech); got != 0 {
+		t.Errorf("multiplier contributes %v to a negative base, want 0", got)
+	}
+}
+
+func TestEngine_RatesFromEverySource(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"farm": 4, "clocktower": 2})
+	ge.Villagers.Recruit("worker", 10, 100)
+	ge.Villagers.Assign("worker", "food", 4)
+	ge.Milestones.completed["growing_city"] = true
+	ge.recalculateRates()
+
+	// (4 farms + those by water + 4 gatherers) × (1 + 10% clocktowers + 20% milestone + biome) − 10 eating
+	food := ge.Resources.resources["food"]
+	base := 4*0.25 + float64(ge.Settlement.adjacentCount("farm"))*0.25*0.25 + 4*0.35
+	biome := 0.0
+	for _, m := range ge.Settlement.World().Modifiers() {
+		if m.Stat == "food_rate" {
+			biome += m.Value
+		}
+	}
+	want := base*(1.3+biome) - 10*0.10
+	if math.Abs(food.Rate-want) > 1e-9 {
+		t.Fatalf("food rate = %v, want %v", food.Rate, want)
+	}
+	sum := 0.0
+	for _, src := range food.Breakdown.Sources {
+		sum += src.Amount
+	}
+	if math.Abs(sum-food.Rate) > 1e-9 {
+		t.Errorf("sources sum to %v, want the rate %v", sum, food.Rate)
+	}
+	if got := food.Breakdown.SourceTotal(SourceMilestone); math.Abs(got-base*0.2) > 1e-9 {
+		t.Errorf("milestone contributes %v, want %v", got, base*0.2)
+	}
+}
+
+func TestEngine_BuildingBonusesCount(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"missile_silo": 1})
+	ge.Prestige.upgrades["military_power"] = 1
+	ge.recalculateRates()
+	if got := ge.militaryBonus(); math.Abs(got-0.35) > 1e-9 {
+		t.Errorf("military bonus = %v, want silo 0.3 + prestige 0.05", got)
+	}
+
+	ge.Prestige.upgrades["storage_bonus"] = 1
+	ge.recalculateRates()
+	wood := ge.Resources.resources["wood"]
+	if got, want := wood.Storage, ge.Resources.defs["wood"].BaseStorage+20; got != want {
+		t.Errorf("wood storage = %v, want %v", got, want)
+	}
+}
+
+func TestEngine_CachedStackMatchesRebuild(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Resources.UnlockResource("electricity")
+	ge.Buildings.LoadCounts(map[string]int{"hut": 4, "farm": 3, "smithy": 2, "data_center": 2, "maglev_station": 1, "reactor": 1})
+	ge.Villagers.Recruit("worker", 4, 20)
+	ge.Villagers.Assign("worker", "food", 2)
+	ge.recalculateRates()
+
+	// The grid ran short, so buildings were swapped in after the fact
+	if ge.Buildings.Grid().Served >= ge.Buildings.Grid().Demand {
+		t.Fatalf("grid %+v, want it short", ge.Buildings.Grid())
+	}
+	if !reflect.DeepEqual(ge.stack.mods, ge.modifiers().mods) {
+		t.Errorf("cached stack differs from a fresh one:\n%v\n%v", ge.stack.mods, ge.modifiers().mods)
+	}
+}
@@ -86,32 +86,45 @@ func (pm *PrestigeManager) BuyUpgrade(key string) error {
 	return nil
 }
 
-// GetBonuses returns all prestige bonuses (passive + upgrades) as a bonus map
-func (pm *PrestigeManager) GetBonuses() map[string]float64 {
-	bonuses := make(map[string]float64)
+// Modifiers returns prestige bonuses: the passive bonus of the prestige
+// level and the rate and flat bonuses of purchased upgrades
+func (pm *PrestigeManager) Modifiers() []Modifier {
+	var mods []Modifier
 
 	// Passive bonus: +2% production_all and +1% tick_speed per prestige level
 	if pm.level > 0 {
-		bonuses["production_all"] = float64(pm.level) * 0.02
-		bonuses["tick_speed"] = float64(pm.level) * 0.01
+		name := fmt.Sprintf("Prestige level %d", pm.level)
+		mods = append(mods,
+			Modifier{Stat: "production_all", Layer: LayerMultiplicative, Value: float64(pm.level) * 0.02, Source: SourcePrestige, Key: "level", Name: name},
+			Modifier{Stat: "tick_speed", Layer: LayerMultiplicative, Value: float64(pm.level) * 0.01, Source: SourcePrestige, Key: "level", Name: name},
+		)
 	}
 
 	// Upgrade bonuses (rate and flat bonuses, not starting resources)
-	defs := config.PrestigeUpgradeByKey()
-	for key, tier := range pm.upgrades {
+	for _, def := range config.PrestigeUpgrades() {
+		tier := pm.upgrades[def.Key]
 		if tier <= 0 {
 			continue
 		}
-		def, ok := defs[key]
-		if !ok {
-			continue
+		m := Modifier{
+			Stat: def.EffectKey, Value: def.PerTier * float64(tier),
+			Source: SourcePrestige, Key: def.Key, Name: fmt.Sprintf("%s tier %d", def.Name, tier),
 		}
-		if def.EffectType == "rate_bonus" || def.EffectType == "flat_bonus" {
-			bonuses[def.EffectKey] += def.PerTier * float64(tier)
+		switch def.EffectType {
+		case "rate_bonus":
+			m.Layer = LayerMultiplicative
+		case "flat_bonus":
+			m.Layer = LayerAdditive
+			if def.EffectKey == "all" {
+				m.Stat = storageStat("all")
+			}
+		default:
+			continue
 		}
+		mods = append(mods, m)
 	}
 
-	return bonuses
+	return mods
 }
 
 // GetStartingResources returns bonus starting resources from prestige upgrades
@@ -66,11 +66,12 @@ func TestPrestigeManager_PrestigeGrantsLevel(t *testing.T) {
 		t.Errorf("level after prestige = %v, want 1", pm.GetLevel())
 	}
 
-	bonuses := pm.GetBonuses()
-	if bonuses["production_all"] <= 0 {
+	bonuses := &ModifierStack{}
+	bonuses.Add(pm.Modifiers()...)
+	if bonuses.Total("production_all") <= 0 {
 		t.Error("production_all bonus should be > 0 after prestige")
 	}
-	if bonuses["tick_speed"] <= 0 {
+	if bonuses.Total("tick_speed") <= 0 {
 		t.Error("tick_speed bonus should be > 0 after prestige")
 	}
 }
@@ -0,0 +1,267 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/user/ageforge/config"
+)
+
+const (
+	raidStartAge     = "bronze_age" // raids begin once expeditions are available
+	raidWarningTicks = 15           // ticks between the warning and the attack
+	raidMinInterval  = 120
+	raidMaxInterval  = 200
+	raidBaseStrength = 8.0
+	// Each hostile faction adds strength and makes raids more frequent
+	raidHostileStrength = 0.25
+	raidHostileInterval = 0.15
+)
+
+// IncomingRaid is a raid that has been sighted and will attack when TicksLeft hits 0
+type IncomingRaid struct {
+	Name      string         `json:"name"`
+	Faction   string         `json:"faction,omitempty"` // faction key leading the raid, "" for bandits
+	Strength  float64        `json:"strength"`
+	Enemy     map[string]int `json:"enemy,omitempty"` // raiding army, keyed by unit
+	TicksLeft int            `json:"ticks_left"`
+}
+
+// RaidOutcome describes how a raid was resolved
+type RaidOutcome struct {
+	Won      bool
+	Severity float64 // 0 when won, up to 1 for a crushing defeat
+	Battle   BattleReport
+}
+
+// RaidManager schedules hostile raids against the settlement
+type RaidManager struct {
+	incoming   *IncomingRaid
+	nextRaid   int // tick at which the next raid is sighted, 0 = not scheduled
+	repelled   int
+	lost       int
+	lastResult string
+}
+
+// NewRaidManager creates a raid manager
+func NewRaidManager() *RaidManager {
+	return &RaidManager{}
+}
+
+// RaidStrength returns the base strength of a raid for an age, before the random spread
+func RaidStrength(stage int, hostile int) float64 {
+	if stage < 0 {
+		return 0
+	}
+	base := raidBaseStrength * math.Pow(float64(1+stage), 1.2)
+	return base * (1.0 + raidHostileStrength*float64(hostile))
+}
+
+// RaidEnemy builds a raiding army of the given strength from the two most
+// advanced unit types of an age, 60/40. Each unit is worth half its attack
+// plus defense in strength, so early raids match the old per-soldier rating.
+func RaidEnemy(strength float64, age string, ageOrder map[string]int) map[string]int {
+	var reached []config.UnitDef
+	for _, def := range config.Units() {
+		if ageOrder[def.MinAge] <= ageOrder[age] {
+			reached = append(reached, def)
+		}
+	}
+	enemy := make(map[string]int)
+	if len(reached) == 0 || strength <= 0 {
+		return enemy
+	}
+	best := reached[len(reached)-1]
+	if len(reached) == 1 {
+		enemy[best.Key] = raidUnitCount(strength, best)
+		return enemy
+	}
+	second := reached[len(reached)-2]
+	enemy[best.Key] = raidUnitCount(strength*0.6, best)
+	if n := raidUnitCount(strength*0.4, second); n > 0 {
+		enemy[second.Key] = n
+	}
+	return enemy
+}
+
+// raidUnitCount returns how many units of a type make up a share of raid strength
+func raidUnitCount(strength float64, def config.UnitDef) int {
+	n := int(math.Round(strength * 2 / (def.Attack + def.Defense)))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// raidInterval returns ticks until the next raid is sighted
+func raidInterval(hostile int) int {
+	n := raidMinInterval + rand.Intn(raidMaxInterval-raidMinInterval+1)
+	return int(float64(n) / (1.0 + raidHostileInterval*float64(hostile)))
+}
+
+// Tick advances the raid schedule. hostile lists the keys of factions hostile
+// to the player and wars those at war with it, which count twice and lead
+// every raid. Returns a newly sighted raid, or a raid that has arrived and
+// must be resolved by the caller.
+func (rm *RaidManager) Tick(tick int, age string, ageOrder map[string]int, hostile, wars []string) (sighted, arrived *IncomingRaid) {
+	pressure := len(hostile) + len(wars)
+	stage := ageOrder[age] - ageOrder[raidStartAge]
+	if stage < 0 {
+		return nil, nil
+	}
+
+	if rm.incoming != nil {
+		rm.incoming.TicksLeft--
+		if rm.incoming.TicksLeft > 0 {
+			return nil, nil
+		}
+		arrived = rm.incoming
+		rm.incoming = nil
+		rm.nextRaid = tick + raidInterval(pressure)
+		return nil, arrived
+	}
+
+	if rm.nextRaid == 0 {
+		rm.nextRaid = tick + raidInterval(pressure)
+		return nil, nil
+	}
+	if tick < rm.nextRaid {
+		return nil, nil
+	}
+
+	raid := &IncomingRaid{
+		Name:      "Bandit raiders",
+		Strength:  RaidStrength(stage, pressure) * (0.8 + 0.4*rand.Float64()),
+		TicksLeft: raidWarningTicks,
+	}
+	raid.Enemy = RaidEnemy(raid.Strength, age, ageOrder)
+	// Factions at war lead every raid; hostile ones most raids once relations sour
+	if len(wars) > 0 {
+		raid.Faction = wars[rand.Intn(len(wars))]
+	} else if len(hostile) > 0 && rand.Float64() < 0.6 {
+		sorted := append([]string(nil), hostile...)
+		sort.Strings(sorted)
+		raid.Faction = sorted[rand.Intn(len(sorted))]
+	}
+	rm.incoming = raid
+	return raid, nil
+}
+
+// Resolve fights a raid against the soldiers at home. The raiders attack;
+// the defenders get the military bonus. A lost raid's severity is the share
+// of the raiders' power still standing.
+func (rm *RaidManager) Resolve(raid *IncomingRaid, defenders map[string]int, defenseBonus float64) RaidOutcome {
+	report := ResolveBattle(raid.Enemy, defenders, 0, defenseBonus)
+	if !report.AttackerWon {
+		rm.repelled++
+		return RaidOutcome{Won: true, Battle: report}
+	}
+	rm.lost++
+	severity := 1.0
+	if power := ArmyPower(raid.Enemy); power > 0 {
+		severity = math.Min(1, ArmyPower(report.AttackerLeft)/power)
+	}
+	return RaidOutcome{Severity: severity, Battle: report}
+}
+
+// RaidLoot returns the spoils for repelling a raid of the given strength
+func RaidLoot(strength float64) map[string]float64 {
+	return map[string]float64{
+		"food": math.Round(strength * 1.5),
+		"wood": math.Round(strength),
+		"gold": math.Round(strength * 0.8),
+	}
+}
+
+// RaidTheftShare returns the share of each stockpile stolen after a lost raid
+func RaidTheftShare(severity float64) float64 {
+	return 0.1 + 0.3*severity
+}
+
+// RaidBuildingsDamaged returns how many buildings a lost raid destroys
+func RaidBuildingsDamaged(severity float64) int {
+	if severity < 0.35 {
+		return 0
+	}
+	return 1 + int(severity*3)
+}
+
+// RaidVillagersLost returns how many civilians a lost raid kills
+func RaidVillagersLost(severity float64) int {
+	if severity < 0.6 {
+		return 0
+	}
+	return 1 + int(severity*3)
+}
+
+// DisplayName returns the raid's name, naming the faction that leads it
+func (r *IncomingRaid) DisplayName() string {
+	if def, ok := config.FactionByKey()[r.Faction]; ok {
+		return fmt.Sprintf("%s raiders", def.Name)
+	}
+	return r.Name
+}
+
+// SetResult records the summary of the last resolved raid
+func (rm *RaidManager) SetResult(msg string) {
+	rm.lastResult = msg
+}
+
+// Snapshot returns raid state for UI
+func (rm *RaidManager) Snapshot(tick int) RaidState {
+	state := RaidState{
+		Repelled:   rm.repelled,
+		Lost:       rm.lost,
+		LastResult: rm.lastResult,
+	}
+	if rm.incoming != nil {
+		state.Incoming = &RaidSnapshot{
+			Name:      rm.incoming.DisplayName(),
+			Strength:  rm.incoming.Strength,
+			Enemy:     copyUnits(rm.incoming.Enemy),
+			TicksLeft: rm.incoming.TicksLeft,
+		}
+	} else if rm.nextRaid > tick {
+		state.NextRaidIn = rm.nextRaid - tick
+	}
+	return state
+}
+
+// RaidSave holds raid state for save
+type RaidSave struct {
+	Incoming   *IncomingRaid `json:"incoming,omitempty"`
+	NextRaid   int           `json:"next_raid"`
+	Repelled   int           `json:"repelled"`
+	Lost       int           `json:"lost"`
+	LastResult string        `json:"last_result,omitempty"`
+}
+
+// GetSaveData returns raid state for serialization
+func (rm *RaidManager) GetSaveData() RaidSave {
+	save := RaidSave{
+		NextRaid:   rm.nextRaid,
+		Repelled:   rm.repelled,
+		Lost:       rm.lost,
+		LastResult: rm.lastResult,
+	}
+	if rm.incoming != nil {
+		raid := *rm.incoming
+		save.Incoming = &raid
+	}
+	return save
+}
+
+// LoadState restores raid state from save. Raids saved before unit types
+// get an army built from their strength.
+func (rm *RaidManager) LoadState(save RaidSave, age string, ageOrder map[string]int) {
+	rm.incoming = save.Incoming
+	if rm.incoming != nil && len(rm.incoming.Enemy) == 0 {
+		rm.incoming.Enemy = RaidEnemy(rm.incoming.Strength, age, ageOrder)
+	}
+	rm.nextRaid = save.NextRaid
+	rm.repelled = save.Repelled
+	rm.lost = save.Lost
+	rm.lastResult = save.LastResult
+}
@@ -0,0 +1,210 @@
+package game
+
+import (
+	"os"
+	"testing"
+
+	"github.com/user/ageforge/config"
+)
+
+func raidAgeOrder() map[string]int {
+	order := make(map[string]int)
+	for _, a := range config.Ages() {
+		order[a.Key] = a.Order
+	}
+	return order
+}
+
+func TestRaidManager_NoRaidsBeforeStartAge(t *testing.T) {
+	rm := NewRaidManager()
+	ageOrder := raidAgeOrder()
+
+	for tick := 1; tick <= 1000; tick++ {
+		sighted, arrived := rm.Tick(tick, "stone_age", ageOrder, nil, nil)
+		if sighted != nil || arrived != nil {
+			t.Fatalf("raid at tick %d in stone age, want none", tick)
+		}
+	}
+}
+
+func TestRaidManager_WarningThenArrival(t *testing.T) {
+	rm := NewRaidManager()
+	ageOrder := raidAgeOrder()
+
+	var sightedAt, arrivedAt int
+	for tick := 1; tick <= 1000 && arrivedAt == 0; tick++ {
+		sighted, arrived := rm.Tick(tick, "bronze_age", ageOrder, nil, nil)
+		if sighted != nil {
+			sightedAt = tick
+		}
+		if arrived != nil {
+			arrivedAt = tick
+		}
+	}
+	if sightedAt == 0 || arrivedAt == 0 {
+		t.Fatalf("sighted = %d, arrived = %d, want both set", sightedAt, arrivedAt)
+	}
+	if got := arrivedAt - sightedAt; got != raidWarningTicks {
+		t.Errorf("warning = %d ticks, want %d", got, raidWarningTicks)
+	}
+	if sightedAt < raidMinInterval {
+		t.Errorf("first raid sighted at tick %d, want >= %d", sightedAt, raidMinInterval)
+	}
+}
+
+func TestRaidStrength_ScalesWithAgeAndHostiles(t *testing.T) {
+	if RaidStrength(-1, 0) != 0 {
+		t.Error("strength before start age should be 0")
+	}
+	if RaidStrength(0, 0) != raidBaseStrength {
+		t.Errorf("stage 0 strength = %v, want %v", RaidStrength(0, 0), raidBaseStrength)
+	}
+	if RaidStrength(5, 0) <= RaidStrength(2, 0) {
+		t.Error("later ages should raid harder")
+	}
+	if RaidStrength(2, 2) <= RaidStrength(2, 0) {
+		t.Error("hostile factions should strengthen raids")
+	}
+}
+
+func TestRaidManager_Resolve(t *testing.T) {
+	rm := NewRaidManager()
+	raid := &IncomingRaid{Name: "Test", Enemy: map[string]int{"infantry": 4}}
+
+	out := rm.Resolve(raid, map[string]int{"infantry": 10}, 0)
+	if !out.Won {
+		t.Error("10 infantry should repel 4")
+	}
+	if out.Battle.DefenderLosses == nil {
+		t.Error("battle report missing defender losses")
+	}
+	out = rm.Resolve(raid, map[string]int{"infantry": 1}, 0)
+	if out.Won {
+		t.Error("1 infantry should lose to 4")
+	}
+	if out.Severity <= 0 || out.Severity > 1 {
+		t.Errorf("severity = %v, want in (0,1]", out.Severity)
+	}
+	if out := rm.Resolve(raid, nil, 0); out.Severity != 1 {
+		t.Errorf("severity with no defenders = %v, want 1", out.Severity)
+	}
+
+	state := rm.Snapshot(0)
+	if state.Repelled != 1 || state.Lost != 2 {
+		t.Errorf("repelled/lost = %d/%d, want 1/2", state.Repelled, state.Lost)
+	}
+}
+
+func TestRaidEnemy_UsesNewestUnitsOfAge(t *testing.T) {
+	ageOrder := raidAgeOrder()
+	enemy := RaidEnemy(RaidStrength(0, 0), "bronze_age", ageOrder)
+	if enemy["archers"] == 0 || enemy["infantry"] == 0 {
+		t.Errorf("bronze raid = %v, want archers and infantry", enemy)
+	}
+	late := RaidEnemy(100, "digital_age", ageOrder)
+	if late["drones"] == 0 || late["mechanised"] == 0 || late["infantry"] != 0 {
+		t.Errorf("digital raid = %v, want drones and mechanised only", late)
+	}
+	if ArmyPower(RaidEnemy(40, "bronze_age", ageOrder)) <= ArmyPower(enemy) {
+		t.Error("stronger raids should bring bigger armies")
+	}
+}
+
+func TestRaidLosses_ScaleWithSeverity(t *testing.T) {
+	if RaidBuildingsDamaged(0.2) != 0 {
+		t.Error("light defeats should not burn buildings")
+	}
+	if RaidVillagersLost(0.5) != 0 {
+		t.Error("moderate defeats should not kill villagers")
+	}
+	if RaidBuildingsDamaged(1) <= RaidBuildingsDamaged(0.4) {
+		t.Error("heavier defeats should burn more buildings")
+	}
+	if RaidTheftShare(1) <= RaidTheftShare(0) {
+		t.Error("heavier defeats should steal more")
+	}
+}
+
+func TestVillagerManager_RemoveVillagers(t *testing.T) {
+	vm := NewVillagerManager()
+	vm.UnlockType("worker")
+	vm.Recruit("worker", 5, 100)
+	vm.Assign("worker", "food", 4)
+
+	if got := vm.RemoveVillagers("worker", 3); got != 3 {
+		t.Errorf("removed = %d, want 3", got)
+	}
+	info := vm.GetAll()["worker"]
+	if info.Count != 2 {
+		t.Errorf("count = %d, want 2", info.Count)
+	}
+	// 1 idle went first, then 2 from food
+	if info.Assignment["food"] != 2 {
+		t.Errorf("assigned to food = %d, want 2", info.Assignment["food"])
+	}
+	if got := vm.RemoveVillagers("worker", 10); got != 2 {
+		t.Errorf("removed = %d, want 2 (all remaining)", got)
+	}
+}
+
+func TestEngine_LostRaidAppliesLosses(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Resources.Add("wood", 30)
+	ge.Buildings.counts["hut"] = 3
+
+	woodBefore := ge.Resources.Get("wood")
+	ge.applyRaidLosses(1.0)
+
+	if ge.Resources.Get("wood") >= woodBefore {
+		t.Errorf("wood = %v, want less than %v after a raid", ge.Resources.Get("wood"), woodBefore)
+	}
+	if ge.Buildings.GetCount("hut") >= 3 {
+		t.Errorf("huts = %d, want some burned", ge.Buildings.GetCount("hut"))
+	}
+}
+
+func TestEngine_LostRaidDismissesHomeless(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"hut": 2})
+	ge.recalculateRates()
+	ge.Villagers.Recruit("worker", ge.popCap()-ge.Villagers.TotalPop(), ge.popCap())
+
+	// Severity 0.4 burns both huts but kills nobody
+	ge.applyRaidLosses(0.4)
+	if ge.Buildings.GetCount("hut") != 0 {
+		t.Fatalf("huts = %d, want both burned", ge.Buildings.GetCount("hut"))
+	}
+	if pop, cap := ge.Villagers.TotalPop(), ge.popCap(); pop > cap {
+		t.Errorf("pop = %d after the huts burned, want at most the cap of %d", pop, cap)
+	}
+}
+
+func TestEngine_SaveLoadRaids(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Raids.incoming = &IncomingRaid{Name: "Bandit raiders", Faction: "merchant_guild", Strength: 12, TicksLeft: 4}
+	ge.age = "bronze_age"
+	ge.Raids.repelled = 2
+	if err := ge.SaveGame("test_raids"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_raids.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_raids"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	raids := ge2.GetState().Military.Raids
+	if raids.Incoming == nil || raids.Incoming.TicksLeft != 4 {
+		t.Fatalf("incoming raid = %+v, want 4 ticks left", raids.Incoming)
+	}
+	if raids.Incoming.Name != "Merchant Guild raiders" {
+		t.Errorf("raid name = %q, want Merchant Guild raiders", raids.Incoming.Name)
+	}
+	if raids.Repelled != 2 {
+		t.Errorf("repelled = %d, want 2", raids.Repelled)
+	}
+	// The raid was saved without an army, so one is built from its strength
+	if UnitTotal(raids.Incoming.Enemy) == 0 {
+		t.Error("loaded raid has no enemy army")
+	}
+}
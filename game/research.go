@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/user/ageforge/config"
 )
@@ -27,8 +28,9 @@ func NewResearchManager() *ResearchManager {
 	}
 }
 
-// StartResearch begins researching a technology
-func (rm *ResearchManager) StartResearch(key string, currentAge string, ageOrder map[string]int, knowledge float64) error {
+// StartResearch begins researching a technology. speedBonus (research_speed)
+// shortens it by that fraction.
+func (rm *ResearchManager) StartResearch(key string, currentAge string, ageOrder map[string]int, knowledge, speedBonus float64) error {
 	def, ok := rm.defs[key]
 	if !ok {
 		return fmt.Errorf("unknown technology: %s", key)
@@ -36,6 +38,9 @@ func (rm *ResearchManager) StartResearch(key string, currentAge string, ageOrder
 	if rm.researched[key] {
 		return fmt.Errorf("%s is already researched", def.Name)
 	}
+	if def.Campaign != "" {
+		return fmt.Errorf("%s can't be researched — it is earned by completing the %s campaign", def.Name, def.Campaign)
+	}
 	if rm.currentTech != "" {
 		currentDef := rm.defs[rm.currentTech]
 		return fmt.Errorf("already researching %s (%d ticks left)", currentDef.Name, rm.ticksLeft)
@@ -59,8 +64,8 @@ func (rm *ResearchManager) StartResearch(key string, currentAge string, ageOrder
 	rm.currentTech = key
 	ticks := def.ResearchTicks
 	// Apply research speed bonus
-	if bonus, ok := rm.bonuses["research_speed"]; ok && bonus > 0 {
-		ticks = int(float64(ticks) * (1.0 - bonus))
+	if speedBonus > 0 {
+		ticks = int(float64(ticks) * (1.0 - speedBonus))
 		if ticks < 1 {
 			ticks = 1
 		}
@@ -94,6 +99,20 @@ func (rm *ResearchManager) Tick() string {
 	return ""
 }
 
+// Grant marks a tech as researched without paying for it (e.g. a campaign
+// reward). Returns false if it was already researched or doesn't exist.
+func (rm *ResearchManager) Grant(key string) bool {
+	def, ok := rm.defs[key]
+	if !ok || rm.researched[key] {
+		return false
+	}
+	rm.researched[key] = true
+	for _, eff := range def.Effects {
+		rm.bonuses[eff.Target] += eff.Value
+	}
+	return true
+}
+
 // CancelResearch cancels current research
 func (rm *ResearchManager) CancelResearch() (string, bool) {
 	if rm.currentTech == "" {
@@ -130,6 +149,18 @@ func (rm *ResearchManager) GetResearched() []string {
 	return keys
 }
 
+// Modifiers returns the modifiers granted by researched techs, in key order
+func (rm *ResearchManager) Modifiers() []Modifier {
+	keys := rm.GetResearched()
+	sort.Strings(keys)
+	var mods []Modifier
+	for _, key := range keys {
+		def := rm.defs[key]
+		mods = append(mods, effectModifiers(def.Effects, 1, SourceTech, key, def.Name)...)
+	}
+	return mods
+}
+
 // GetBonuses returns a copy of all bonuses
 func (rm *ResearchManager) GetBonuses() map[string]float64 {
 	out := make(map[string]float64)
@@ -144,7 +175,8 @@ func (rm *ResearchManager) Snapshot(currentAge string, ageOrder map[string]int)
 	techs := make(map[string]TechState)
 
 	for key, def := range rm.defs {
-		available := true
+		// Campaign techs are only ever granted
+		available := def.Campaign == ""
 		// Check age
 		if ageOrder[def.Age] > ageOrder[currentAge] {
 			available = false
@@ -165,6 +197,7 @@ func (rm *ResearchManager) Snapshot(currentAge string, ageOrder map[string]int)
 			Cost:          def.Cost,
 			Prerequisites: def.Prerequisites,
 			Description:   def.Description,
+			Campaign:      def.Campaign,
 			Researched:    rm.researched[key],
 			Available:     available && !rm.researched[key],
 			PrereqsMet:    prereqsMet,
@@ -9,7 +9,7 @@ func TestResearchManager_StartResearch(t *testing.T) {
 	ageOrder := map[string]int{"primitive_age": 0, "stone_age": 1}
 
 	// tool_making: primitive_age, cost 25, no prereqs
-	err := rm.StartResearch("tool_making", "primitive_age", ageOrder, 50)
+	err := rm.StartResearch("tool_making", "primitive_age", ageOrder, 50, 0)
 	if err != nil {
 		t.Errorf("StartResearch failed: %v", err)
 	}
@@ -20,7 +20,7 @@ func TestResearchManager_CantAfford(t *testing.T) {
 	ageOrder := map[string]int{"primitive_age": 0}
 
 	// tool_making costs 25, only have 10
-	err := rm.StartResearch("tool_making", "primitive_age", ageOrder, 10)
+	err := rm.StartResearch("tool_making", "primitive_age", ageOrder, 10, 0)
 	if err == nil {
 		t.Error("StartResearch should fail with insufficient knowledge")
 	}
@@ -31,7 +31,7 @@ func TestResearchManager_AgeGating(t *testing.T) {
 	ageOrder := map[string]int{"primitive_age": 0, "stone_age": 1}
 
 	// stoneworking requires stone_age
-	err := rm.StartResearch("stoneworking", "primitive_age", ageOrder, 1000)
+	err := rm.StartResearch("stoneworking", "primitive_age", ageOrder, 1000, 0)
 	if err == nil {
 		t.Error("StartResearch should fail when age requirement not met")
 	}
@@ -42,7 +42,7 @@ func TestResearchManager_Prerequisites(t *testing.T) {
 	ageOrder := map[string]int{"primitive_age": 0}
 
 	// fire_mastery requires tool_making
-	err := rm.StartResearch("fire_mastery", "primitive_age", ageOrder, 1000)
+	err := rm.StartResearch("fire_mastery", "primitive_age", ageOrder, 1000, 0)
 	if err == nil {
 		t.Error("StartResearch should fail when prerequisites not met")
 	}
@@ -52,7 +52,7 @@ func TestResearchManager_TickCompletion(t *testing.T) {
 	rm := NewResearchManager()
 	ageOrder := map[string]int{"primitive_age": 0}
 
-	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50)
+	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50, 0)
 
 	// Tick until complete
 	var completed string
@@ -78,7 +78,7 @@ func TestResearchManager_BonusAccumulation(t *testing.T) {
 	rm := NewResearchManager()
 	ageOrder := map[string]int{"primitive_age": 0}
 
-	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50)
+	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50, 0)
 	for rm.Tick() == "" {
 	}
 
@@ -93,7 +93,7 @@ func TestResearchManager_CancelResearch(t *testing.T) {
 	rm := NewResearchManager()
 	ageOrder := map[string]int{"primitive_age": 0}
 
-	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50)
+	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50, 0)
 	key, ok := rm.CancelResearch()
 	if !ok || key != "tool_making" {
 		t.Errorf("CancelResearch = (%v, %v), want (tool_making, true)", key, ok)
@@ -110,11 +110,11 @@ func TestResearchManager_CantResearchTwice(t *testing.T) {
 	rm := NewResearchManager()
 	ageOrder := map[string]int{"primitive_age": 0}
 
-	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50)
+	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50, 0)
 	for rm.Tick() == "" {
 	}
 
-	err := rm.StartResearch("tool_making", "primitive_age", ageOrder, 50)
+	err := rm.StartResearch("tool_making", "primitive_age", ageOrder, 50, 0)
 	if err == nil {
 		t.Error("StartResearch should fail for already-researched tech")
 	}
@@ -124,7 +124,7 @@ func TestResearchManager_SaveLoadRoundTrip(t *testing.T) {
 	rm := NewResearchManager()
 	ageOrder := map[string]int{"primitive_age": 0}
 
-	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50)
+	rm.StartResearch("tool_making", "primitive_age", ageOrder, 50, 0)
 	for rm.Tick() == "" {
 	}
 
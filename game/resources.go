@@ -167,6 +167,16 @@ func (rm *ResourceManager) LoadStorage(storage map[string]float64) {
 	}
 }
 
+// ClampToStorage trims every resource down to its storage cap, e.g. after
+// storage was lost
+func (rm *ResourceManager) ClampToStorage() {
+	for _, r := range rm.resources {
+		if r.Amount > r.Storage {
+			r.Amount = r.Storage
+		}
+	}
+}
+
 // Snapshot returns resource states for UI
 func (rm *ResourceManager) Snapshot() map[string]ResourceState {
 	out := make(map[string]ResourceState)
@@ -16,21 +16,24 @@ type GameSave struct {
 	Resources  map[string]float64      `json:"resources"`
 	Storage    map[string]float64      `json:"storage"`
 	Buildings  map[string]int          `json:"buildings"`
+	Disabled   map[string]int          `json:"disabled_buildings,omitempty"`
 	Villagers  map[string]VillagerInfo `json:"villagers"`
 	Unlocked   UnlockedState           `json:"unlocked"`
 	Stats      *GameStats              `json:"stats"`
 	// Phase 3 additions
 	Research         ResearchSave   `json:"research"`
 	Military         MilitarySave   `json:"military"`
+	Raids            RaidSave       `json:"raids"`
 	Events           EventSave      `json:"events"`
 	Milestones       []string       `json:"milestones"`
 	ChainsCompleted  []string       `json:"chains_completed,omitempty"`
 	CurrentTitle     string         `json:"current_title,omitempty"`
-	PermanentBonuses map[string]float64 `json:"permanent_bonuses"`
 	BuildQueue       []BuildQueueItem   `json:"build_queue"`
 	Prestige         PrestigeSave        `json:"prestige"`
 	Trade            TradeSave           `json:"trade"`
 	Diplomacy        DiplomacySave       `json:"diplomacy"`
+	Settlement       *SettlementSave     `json:"settlement,omitempty"` // nil in saves from before the grid
+	Timelapse        []TimelapseFrame    `json:"timelapse,omitempty"`
 	SpeedMultiplier  float64             `json:"speed_multiplier"`
 }
 
@@ -43,6 +46,12 @@ type TradeSave struct {
 	TotalExported  map[string]float64     `json:"total_exported"`
 }
 
+// SettlementSave holds the settlement layout for save
+type SettlementSave struct {
+	Seed       uint64      `json:"seed"`
+	Placements []Placement `json:"placements"`
+}
+
 // DiplomacySave holds diplomacy state for save
 type DiplomacySave struct {
 	Factions map[string]FactionStateSave `json:"factions"`
@@ -66,9 +75,14 @@ type ResearchSave struct {
 
 // MilitarySave holds military state for save
 type MilitarySave struct {
-	ActiveExpedition *ActiveExpedition  `json:"active_expedition"`
+	ActiveExpeditions []ActiveExpedition `json:"active_expeditions"`
+	// ActiveExpedition is the single expedition written by older saves
+	ActiveExpedition *ActiveExpedition  `json:"active_expedition,omitempty"`
 	CompletedCount   int                `json:"completed_count"`
 	TotalLoot        map[string]float64 `json:"total_loot"`
+	Units            map[string]int     `json:"units,omitempty"` // trained soldiers by unit
+	Training         []TrainingOrder    `json:"training,omitempty"`
+	Morale           *float64           `json:"morale,omitempty"` // nil in saves from before morale
 }
 
 // EventSave holds event state for save
@@ -125,17 +139,12 @@ func (ge *GameEngine) buildSaveSnapshot() GameSave {
 	queue := make([]BuildQueueItem, len(ge.buildQueue))
 	copy(queue, ge.buildQueue)
 
-	// Deep copy permanent bonuses
-	permBonuses := make(map[string]float64, len(ge.permanentBonuses))
-	for k, v := range ge.permanentBonuses {
-		permBonuses[k] = v
-	}
-
 	// Deep copy military loot
 	totalLoot := make(map[string]float64, len(ge.Military.totalLoot))
 	for k, v := range ge.Military.totalLoot {
 		totalLoot[k] = v
 	}
+	morale := ge.Military.Morale()
 
 	// Deep copy prestige upgrades
 	upgrades := make(map[string]int, len(ge.Prestige.upgrades))
@@ -173,6 +182,10 @@ func (ge *GameEngine) buildSaveSnapshot() GameSave {
 	agesReached := make([]string, len(ge.Stats.AgesReached))
 	copy(agesReached, ge.Stats.AgesReached)
 
+	// Frames are never modified once recorded, so copying the slice is enough
+	timelapse := make([]TimelapseFrame, len(ge.timelapse))
+	copy(timelapse, ge.timelapse)
+
 	return GameSave{
 		Timestamp: time.Now(),
 		Tick:      ge.tick,
@@ -180,6 +193,7 @@ func (ge *GameEngine) buildSaveSnapshot() GameSave {
 		Resources: ge.Resources.GetAll(),
 		Storage:   ge.Resources.GetAllStorage(),
 		Buildings: ge.Buildings.GetAll(),
+		Disabled:  ge.Buildings.GetDisabled(),
 		Villagers: ge.Villagers.GetAll(),
 		Unlocked:  ge.getUnlockedState(),
 		Stats: &GameStats{
@@ -197,10 +211,14 @@ func (ge *GameEngine) buildSaveSnapshot() GameSave {
 			TotalTicks:  ge.Research.totalTicks,
 		},
 		Military: MilitarySave{
-			ActiveExpedition: ge.Military.GetActiveForSave(),
-			CompletedCount:   ge.Military.completedCount,
-			TotalLoot:        totalLoot,
+			ActiveExpeditions: ge.Military.GetActiveForSave(),
+			CompletedCount:    ge.Military.completedCount,
+			TotalLoot:         totalLoot,
+			Units:             ge.Military.GetUnitsForSave(),
+			Training:          ge.Military.GetTrainingForSave(),
+			Morale:            &morale,
 		},
+		Raids: ge.Raids.GetSaveData(),
 		Events: EventSave{
 			LastFired:     ge.Events.GetLastFired(),
 			Active:        ge.Events.GetActiveForSave(),
@@ -211,7 +229,6 @@ func (ge *GameEngine) buildSaveSnapshot() GameSave {
 		Milestones:       ge.Milestones.GetCompleted(),
 		ChainsCompleted:  ge.Milestones.GetChainsCompleted(),
 		CurrentTitle:     ge.Milestones.GetCurrentTitle(),
-		PermanentBonuses: permBonuses,
 		Prestige: PrestigeSave{
 			Level:       ge.Prestige.level,
 			TotalEarned: ge.Prestige.totalEarned,
@@ -228,6 +245,11 @@ func (ge *GameEngine) buildSaveSnapshot() GameSave {
 		Diplomacy: DiplomacySave{
 			Factions: ge.Diplomacy.GetFactionsForSave(),
 		},
+		Settlement: &SettlementSave{
+			Seed:       ge.Settlement.Seed(),
+			Placements: ge.Settlement.Placements(),
+		},
+		Timelapse:       timelapse,
 		SpeedMultiplier: ge.speedMultiplier,
 	}
 }
@@ -257,6 +279,7 @@ func (ge *GameEngine) LoadGame(filename string) error {
 		ge.Resources.LoadStorage(save.Storage)
 	}
 	ge.Buildings.LoadCounts(save.Buildings)
+	ge.Buildings.LoadDisabled(save.Disabled)
 	ge.Villagers.LoadVillagers(save.Villagers)
 	if save.Stats != nil {
 		// Deep copy stats to avoid aliasing with the deserialized save
@@ -275,6 +298,8 @@ func (ge *GameEngine) LoadGame(filename string) error {
 		}
 	}
 	ge.buildQueue = save.BuildQueue
+	ge.rateHistory = nil
+	ge.timelapse = save.Timelapse
 
 	// Restore unlocks
 	for _, key := range save.Unlocked.Resources {
@@ -289,7 +314,16 @@ func (ge *GameEngine) LoadGame(filename string) error {
 
 	// Restore Phase 3 systems
 	ge.Research.LoadState(save.Research.Researched, save.Research.CurrentTech, save.Research.TicksLeft, save.Research.TotalTicks)
-	ge.Military.LoadState(save.Military.ActiveExpedition, save.Military.CompletedCount, save.Military.TotalLoot)
+	active := save.Military.ActiveExpeditions
+	if len(active) == 0 && save.Military.ActiveExpedition != nil {
+		active = []ActiveExpedition{*save.Military.ActiveExpedition}
+	}
+	morale := moraleMax
+	if save.Military.Morale != nil {
+		morale = *save.Military.Morale
+	}
+	ge.Military.LoadState(active, save.Military.CompletedCount, save.Military.TotalLoot, save.Military.Units, save.Military.Training, morale)
+	ge.Raids.LoadState(save.Raids, ge.age, ge.progress.GetAgeOrder())
 	ge.Events.LoadState(save.Events.LastFired, save.Events.Active, save.Events.NextEventTick, save.Events.GoodStreak, save.Events.BadStreak)
 	ge.Milestones.LoadState(save.Milestones, save.ChainsCompleted, save.CurrentTitle)
 	// Reconstruct chains and title for old saves that don't have them
@@ -298,10 +332,6 @@ func (ge *GameEngine) LoadGame(filename string) error {
 		ge.Milestones.recalculateTitle()
 	}
 
-	if save.PermanentBonuses != nil {
-		ge.permanentBonuses = save.PermanentBonuses
-	}
-
 	// Restore prestige
 	ge.Prestige.LoadState(save.Prestige.Level, save.Prestige.TotalEarned, save.Prestige.Available, save.Prestige.Upgrades)
 
@@ -310,6 +340,16 @@ func (ge *GameEngine) LoadGame(filename string) error {
 
 	// Restore diplomacy
 	ge.Diplomacy.LoadState(save.Diplomacy.Factions)
+	ge.refreshWarFronts()
+
+	// Restore the world and layout. Saves from before the grid get the
+	// default world, laid out afresh by recalculateRates.
+	if save.Settlement != nil {
+		ge.Settlement = NewSettlementManager(save.Settlement.Seed)
+		ge.Settlement.LoadState(save.Settlement.Seed, save.Settlement.Placements)
+	} else {
+		ge.Settlement = NewSettlementManager(defaultWorldSeed)
+	}
 
 	// Restore speed multiplier
 	ge.speedMultiplier = save.SpeedMultiplier
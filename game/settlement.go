@@ -0,0 +1,465 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/user/ageforge/config"
+)
+
+// The settlement is a grid of tiles around the town centre at (0,0). Every
+// building instance occupies tiles on it: wonders 2×2, everything else one.
+// Terrain comes from the World, a pure function of its seed, so only the
+// seed and the placements are saved. Players never place by hand: Sync keeps the layout
+// in step with the building counts, picking a spot for each new instance.
+
+// adjacencyPull is how many tiles further out a building will go to reach
+// terrain that grants its placement bonus
+const adjacencyPull = 5.0
+
+// preferredDistance is how far from the town centre each category settles
+var preferredDistance = map[string]float64{
+	"wonder":     0,
+	"housing":    2,
+	"storage":    3,
+	"research":   4,
+	"production": 6,
+	"military":   9,
+}
+
+// Placement is one building instance on the settlement grid
+type Placement struct {
+	Key string `json:"key"`
+	X   int    `json:"x"` // top-left tile
+	Y   int    `json:"y"`
+}
+
+type tile struct{ x, y int }
+
+// SettlementManager lays buildings out on the settlement grid
+type SettlementManager struct {
+	world      *World
+	placements []Placement // in placement order
+	placed     map[string]int
+	occupied   map[tile]int
+	crowd      map[tile]int   // housing tiles among each tile's eight neighbours
+	full       map[tile]bool  // housing tiles that can take no more housing neighbours
+	reach      int            // furthest any placed tile lies from the centre along either axis
+	adjacent   map[string]int // placed instances next to the terrain their building favours
+	defs       map[string]config.BuildingDef
+	rules      map[string]config.AdjacencyDef
+}
+
+// NewSettlementManager creates an empty settlement on the world grown from seed
+func NewSettlementManager(seed uint64) *SettlementManager {
+	return &SettlementManager{
+		world:    NewWorld(seed),
+		placed:   make(map[string]int),
+		adjacent: make(map[string]int),
+		occupied: make(map[tile]int),
+		crowd:    make(map[tile]int),
+		full:     make(map[tile]bool),
+		defs:     config.BuildingByKey(),
+		rules:    config.AdjacencyByBuilding(),
+	}
+}
+
+// Seed returns the world seed the terrain is generated from
+func (sm *SettlementManager) Seed() uint64 {
+	return sm.world.Seed()
+}
+
+// World returns the land the settlement is built on
+func (sm *SettlementManager) World() *World {
+	return sm.world
+}
+
+// Placements returns a copy of the layout, in placement order
+func (sm *SettlementManager) Placements() []Placement {
+	out := make([]Placement, len(sm.placements))
+	copy(out, sm.placements)
+	return out
+}
+
+// TerrainAt returns the terrain of a tile
+func (sm *SettlementManager) TerrainAt(x, y int) string {
+	return sm.world.TerrainAt(x, y)
+}
+
+// size returns the side length in tiles of a building's footprint
+func (sm *SettlementManager) size(key string) int {
+	if sm.defs[key].Category == "wonder" {
+		return 2
+	}
+	return 1
+}
+
+// footprint returns the tiles a building of the given key covers at (x, y)
+func (sm *SettlementManager) footprint(key string, x, y int) []tile {
+	n := sm.size(key)
+	tiles := make([]tile, 0, n*n)
+	for dy := 0; dy < n; dy++ {
+		for dx := 0; dx < n; dx++ {
+			tiles = append(tiles, tile{x + dx, y + dy})
+		}
+	}
+	return tiles
+}
+
+// Sync places every building instance that has no tile yet and removes the
+// most recently placed instances of buildings that have fewer than placed.
+// Its work grows with what changed, not with the size of the settlement.
+func (sm *SettlementManager) Sync(counts map[string]int) {
+	extra := make(map[string]int)
+	for key, n := range sm.placed {
+		if n > counts[key] {
+			extra[key] = n - counts[key]
+		}
+	}
+	// Drop the newest extras in one pass and reindex once
+	if len(extra) > 0 {
+		drop := make([]bool, len(sm.placements))
+		for i := len(sm.placements) - 1; i >= 0; i-- {
+			if key := sm.placements[i].Key; extra[key] > 0 {
+				extra[key]--
+				drop[i] = true
+			}
+		}
+		kept := sm.placements[:0]
+		for i, p := range sm.placements {
+			if !drop[i] {
+				kept = append(kept, p)
+			}
+		}
+		sm.placements = kept
+		sm.reindex()
+	}
+
+	var keys []string
+	for key, n := range counts {
+		if n > sm.placed[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		// Placing only ever takes spots away, so each further instance of
+		// a building costs at least as much as the last and the search can
+		// resume from the ring it was found in
+		from := 0
+		for sm.placed[key] < counts[key] {
+			cost, ok := sm.place(key, from)
+			if !ok {
+				break
+			}
+			from = max(0, int(math.Floor(cost-0.5)))
+		}
+	}
+}
+
+// reindex rebuilds the counts, tile occupancy and reach from the placements
+func (sm *SettlementManager) reindex() {
+	sm.placed = make(map[string]int)
+	sm.adjacent = make(map[string]int)
+	sm.occupied = make(map[tile]int, len(sm.placements))
+	sm.crowd = make(map[tile]int)
+	sm.full = make(map[tile]bool)
+	sm.reach = 0
+	for i := range sm.placements {
+		sm.occupy(i)
+	}
+}
+
+// occupy counts placement i and marks its tiles as taken
+func (sm *SettlementManager) occupy(i int) {
+	p := sm.placements[i]
+	sm.placed[p.Key]++
+	if rule, ok := sm.rules[p.Key]; ok && sm.touches(p.Key, p.X, p.Y, rule.Terrain) {
+		sm.adjacent[p.Key]++
+	}
+	housing := sm.defs[p.Key].Category == "housing"
+	for _, t := range sm.footprint(p.Key, p.X, p.Y) {
+		sm.occupied[t] = i
+		sm.reach = max(sm.reach, abs(t.x), abs(t.y))
+		if !housing {
+			continue
+		}
+		if sm.crowd[t] >= config.MaxHousingNeighbors {
+			sm.full[t] = true
+		}
+		for _, n := range neighbours(t.x, t.y) {
+			sm.crowd[n]++
+			if sm.crowd[n] >= config.MaxHousingNeighbors && sm.isHousing(n) {
+				sm.full[n] = true
+			}
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// place puts one more instance of a building on the best free spot and
+// returns its cost. It searches rings of tiles outward from the preferred
+// distance, starting at ring from, and stops once no further ring can beat
+// the best fit found.
+func (sm *SettlementManager) place(key string, from int) (float64, bool) {
+	def, ok := sm.defs[key]
+	if !ok {
+		return 0, false
+	}
+	pref := preferredDistance[def.Category]
+	size := sm.size(key)
+	half := float64(size-1) / 2
+	housing := def.Category == "housing"
+	// Every spot in ring k costs at least k, less the adjacency pull
+	rule, hasRule := sm.rules[key]
+	pull := 0.0
+	if hasRule {
+		pull = adjacencyPull
+	}
+	// Beyond the reach every tile is free, so a fit turns up by then
+	limit := math.Max(pref, float64(sm.reach+2)*math.Sqrt2) + float64(size) + 8
+
+	best, found := tile{}, false
+	bestCost := 0.0
+	for k := from; ; k++ {
+		if found && float64(k)-pull > bestCost {
+			break
+		}
+		if pref+float64(k) > limit && pref-float64(k) < 0 {
+			break
+		}
+		visit := func(x, y int) {
+			off := math.Abs(math.Hypot(float64(x)+half, float64(y)+half) - pref)
+			if int(off) != k {
+				return
+			}
+			if !sm.canPlace(size, housing, x, y) {
+				return
+			}
+			cost := off
+			if hasRule && sm.touchesAt(size, x, y, rule.Terrain) {
+				cost -= adjacencyPull
+			}
+			// Keep forests and hills clear where grass will do
+			if sm.TerrainAt(x, y) != config.TerrainGrass {
+				cost += 0.5
+			}
+			// Ties go to the topmost, then leftmost tile
+			if !found || cost < bestCost || (cost == bestCost && (y < best.y || (y == best.y && x < best.x))) {
+				best, bestCost, found = tile{x, y}, cost, true
+			}
+		}
+		annulus(pref+float64(k), pref+float64(k+1), half, visit)
+		if lo := pref - float64(k+1); pref-float64(k) > 0 {
+			annulus(math.Max(0, lo), pref-float64(k), half, visit)
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	sm.placements = append(sm.placements, Placement{Key: key, X: best.x, Y: best.y})
+	sm.occupy(len(sm.placements) - 1)
+	return bestCost, true
+}
+
+// annulus visits every tile whose centre, offset by half, lies between lo
+// and hi (inclusive) from the town centre
+func annulus(lo, hi, half float64, visit func(x, y int)) {
+	for y := int(math.Ceil(-hi - half)); y <= int(math.Floor(hi-half)); y++ {
+		cy := float64(y) + half
+		outer := math.Sqrt(math.Max(0, hi*hi-cy*cy))
+		inner := math.Sqrt(math.Max(0, lo*lo-cy*cy))
+		// The row crosses the ring twice: once left of the centre, once right
+		left, right := int(math.Ceil(-outer-half)), int(math.Floor(-inner-half))
+		for x := left; x <= right; x++ {
+			visit(x, y)
+		}
+		for x := max(right+1, int(math.Ceil(inner-half))); x <= int(math.Floor(outer-half)); x++ {
+			visit(x, y)
+		}
+	}
+}
+
+// canPlace reports whether a building of the given size fits at (x, y):
+// its tiles are free, off the water and the town centre, and housing isn't
+// packed too densely
+func (sm *SettlementManager) canPlace(size int, housing bool, x, y int) bool {
+	for ty := y; ty < y+size; ty++ {
+		for tx := x; tx < x+size; tx++ {
+			if _, taken := sm.occupied[tile{tx, ty}]; taken || (tx == 0 && ty == 0) {
+				return false
+			}
+			if sm.TerrainAt(tx, ty) == config.TerrainWater {
+				return false
+			}
+		}
+	}
+	if !housing {
+		return true
+	}
+	if sm.housingAround(x, y) > config.MaxHousingNeighbors {
+		return false
+	}
+	for _, t := range neighbours(x, y) {
+		if sm.full[t] {
+			return false
+		}
+	}
+	return true
+}
+
+// touches reports whether any tile around a building's footprint at (x, y)
+// has the given terrain
+func (sm *SettlementManager) touches(key string, x, y int, terrain string) bool {
+	return sm.touchesAt(sm.size(key), x, y, terrain)
+}
+
+// touchesAt is touches for a footprint of n×n tiles
+func (sm *SettlementManager) touchesAt(n, x, y int, terrain string) bool {
+	for ty := y - 1; ty <= y+n; ty++ {
+		for tx := x - 1; tx <= x+n; tx++ {
+			inside := tx >= x && tx < x+n && ty >= y && ty < y+n
+			if !inside && sm.TerrainAt(tx, ty) == terrain {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isHousing reports whether a tile holds a housing building
+func (sm *SettlementManager) isHousing(t tile) bool {
+	i, ok := sm.occupied[t]
+	return ok && sm.defs[sm.placements[i].Key].Category == "housing"
+}
+
+// housingAround counts the housing tiles around (x, y)
+func (sm *SettlementManager) housingAround(x, y int) int {
+	return sm.crowd[tile{x, y}]
+}
+
+// neighbours returns the eight tiles around (x, y)
+func neighbours(x, y int) [8]tile {
+	return [8]tile{
+		{x - 1, y - 1}, {x, y - 1}, {x + 1, y - 1},
+		{x - 1, y}, {x + 1, y},
+		{x - 1, y + 1}, {x, y + 1}, {x + 1, y + 1},
+	}
+}
+
+// adjacentCount returns how many placed instances of a building meet its
+// placement bonus
+func (sm *SettlementManager) adjacentCount(key string) int {
+	return sm.adjacent[key]
+}
+
+// Modifiers returns the placement bonuses of buildings sited next to the
+// terrain they favour. Switched-off instances are counted out first.
+func (sm *SettlementManager) Modifiers(bm *BuildingManager) []Modifier {
+	keys := make([]string, 0, len(sm.rules))
+	for key := range sm.rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var mods []Modifier
+	for _, key := range keys {
+		n := sm.adjacentCount(key)
+		if active := bm.active(key); active < n {
+			n = active
+		}
+		if n == 0 {
+			continue
+		}
+		rule := sm.rules[key]
+		def := sm.defs[key]
+		run := bm.Throughput(key)
+		name := fmt.Sprintf("%s ×%d by %s", def.Name, n, rule.Terrain)
+		for _, eff := range def.Effects {
+			if eff.Type != "production" {
+				continue
+			}
+			mods = append(mods, Modifier{
+				Stat: eff.Target, Layer: LayerAdditive, Value: eff.Value * float64(n) * run * rule.Bonus,
+				Source: SourceSettlement, Key: key, Name: name,
+			})
+		}
+	}
+	return mods
+}
+
+// LoadState restores a saved layout. Placements of unknown buildings or on
+// tiles already taken are dropped; Sync places them again.
+func (sm *SettlementManager) LoadState(seed uint64, placements []Placement) {
+	if seed != sm.world.Seed() {
+		sm.world = NewWorld(seed)
+	}
+	sm.placements = nil
+	sm.reindex()
+	for _, p := range placements {
+		if _, ok := sm.defs[p.Key]; !ok {
+			continue
+		}
+		fits := true
+		for _, t := range sm.footprint(p.Key, p.X, p.Y) {
+			if _, taken := sm.occupied[t]; taken {
+				fits = false
+			}
+		}
+		if !fits {
+			continue
+		}
+		sm.placements = append(sm.placements, p)
+		sm.occupy(len(sm.placements) - 1)
+	}
+}
+
+// Snapshot returns the layout and the terrain around it for UI
+func (sm *SettlementManager) Snapshot() SettlementState {
+	state := sm.layout()
+	state.Terrain = sm.terrain(state.MinX, state.MinY, state.Width, state.Height)
+	return state
+}
+
+// layout returns the snapshot of the buildings and the area around them,
+// without the terrain
+func (sm *SettlementManager) layout() SettlementState {
+	minX, minY, maxX, maxY := -8, -8, 8, 8
+	buildings := make([]PlacedBuilding, 0, len(sm.placements))
+	for _, p := range sm.placements {
+		def := sm.defs[p.Key]
+		size := sm.size(p.Key)
+		minX, minY = min(minX, p.X-3), min(minY, p.Y-3)
+		maxX, maxY = max(maxX, p.X+size+2), max(maxY, p.Y+size+2)
+		pb := PlacedBuilding{Key: p.Key, Name: def.Name, Category: def.Category, X: p.X, Y: p.Y, Size: size}
+		if rule, ok := sm.rules[p.Key]; ok {
+			pb.Adjacent = sm.touches(p.Key, p.X, p.Y, rule.Terrain)
+		}
+		buildings = append(buildings, pb)
+	}
+	state := SettlementState{
+		Seed: sm.world.Seed(),
+		MinX: minX, MinY: minY,
+		Width: maxX - minX + 1, Height: maxY - minY + 1,
+		Buildings: buildings,
+		Biomes:    sm.world.Biomes(),
+	}
+	return state
+}
+
+// terrain returns the terrain of an area, row by row
+func (sm *SettlementManager) terrain(minX, minY, width, height int) []string {
+	tiles := make([]string, 0, width*height)
+	for y := minY; y < minY+height; y++ {
+		for x := minX; x < minX+width; x++ {
+			tiles = append(tiles, sm.TerrainAt(x, y))
+		}
+	}
+	return tiles
+}
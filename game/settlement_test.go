@@ -0,0 +1,184 @@
+package game
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/user/ageforge/config"
+)
+
+func TestSettlementManager_SyncFollowsCounts(t *testing.T) {
+	sm := NewSettlementManager(defaultWorldSeed)
+	sm.Sync(map[string]int{"hut": 6, "farm": 4, "sacred_grove": 1})
+
+	seen := make(map[tile]bool)
+	placed := make(map[string]int)
+	for _, p := range sm.Placements() {
+		placed[p.Key]++
+		for _, tl := range sm.footprint(p.Key, p.X, p.Y) {
+			if seen[tl] {
+				t.Fatalf("%s overlaps another building at %v", p.Key, tl)
+			}
+			seen[tl] = true
+			if sm.TerrainAt(tl.x, tl.y) == config.TerrainWater {
+				t.Errorf("%s built on water at %v", p.Key, tl)
+			}
+		}
+	}
+	if placed["hut"] != 6 || placed["farm"] != 4 || placed["sacred_grove"] != 1 {
+		t.Fatalf("placed %v, want 6 huts, 4 farms and the grove", placed)
+	}
+	if seen[tile{0, 0}] {
+		t.Error("a building took the town centre")
+	}
+
+	// Losing buildings clears their newest tiles and keeps the rest in place
+	before := sm.Placements()
+	sm.Sync(map[string]int{"hut": 4, "farm": 4, "sacred_grove": 1})
+	after := sm.Placements()
+	if len(after) != 9 {
+		t.Fatalf("%d placements after losing 2 huts, want 9", len(after))
+	}
+	var kept []Placement
+	huts := 0
+	for _, p := range before {
+		if p.Key == "hut" {
+			huts++
+			if huts > 4 {
+				continue
+			}
+		}
+		kept = append(kept, p)
+	}
+	for i := range kept {
+		if after[i] != kept[i] {
+			t.Errorf("placement %d = %+v, want %+v", i, after[i], kept[i])
+		}
+	}
+}
+
+func TestSettlementManager_HousingDensity(t *testing.T) {
+	sm := NewSettlementManager(defaultWorldSeed)
+	sm.Sync(map[string]int{"hut": 40})
+	for _, p := range sm.Placements() {
+		if n := sm.housingAround(p.X, p.Y); n > config.MaxHousingNeighbors {
+			t.Errorf("hut at (%d,%d) has %d housing neighbours, max %d", p.X, p.Y, n, config.MaxHousingNeighbors)
+		}
+	}
+}
+
+func TestSettlementManager_PlacesThousandsQuickly(t *testing.T) {
+	counts := map[string]int{
+		"hut": 800, "house": 400, "farm": 600, "woodcutter_camp": 300,
+		"stone_pit": 300, "mine": 200, "library": 200, "barracks": 200,
+	}
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+
+	// Loading a save with no layout places everything at once, under the
+	// engine lock
+	sm := NewSettlementManager(defaultWorldSeed)
+	start := time.Now()
+	sm.Sync(counts)
+	if n := len(sm.Placements()); n != total {
+		t.Fatalf("placed %d buildings, want %d", n, total)
+	}
+	if took := time.Since(start); took > 2*time.Second {
+		t.Errorf("placing %d buildings took %v, want under 2s", total, took)
+	}
+
+	counts["hut"], counts["farm"] = 400, 300
+	start = time.Now()
+	sm.Sync(counts)
+	if took := time.Since(start); took > 200*time.Millisecond {
+		t.Errorf("removing 700 buildings took %v, want under 200ms", took)
+	}
+}
+
+func TestSettlementManager_AdjacencyBonus(t *testing.T) {
+	sm := NewSettlementManager(defaultWorldSeed)
+	sm.Sync(map[string]int{"farm": 3})
+	n := sm.adjacentCount("farm")
+	if n == 0 {
+		t.Fatal("no farm was placed by water")
+	}
+
+	bm := NewBuildingManager()
+	bm.LoadCounts(map[string]int{"farm": 3})
+	want := 0.25 * float64(n) * config.AdjacencyByBuilding()["farm"].Bonus
+	stack := &ModifierStack{}
+	stack.Add(sm.Modifiers(bm)...)
+	if got := stack.Total("food"); math.Abs(got-want) > 1e-9 {
+		t.Errorf("layout bonus = %v food, want %v", got, want)
+	}
+
+	// Switched-off farms lose their placement bonus too
+	if err := bm.SetDisabled("farm", 3); err != nil {
+		t.Fatalf("SetDisabled failed: %v", err)
+	}
+	if mods := sm.Modifiers(bm); len(mods) != 0 {
+		t.Errorf("switched-off farms still get %v", mods)
+	}
+}
+
+func TestSettlementManager_AdjacentCountsKept(t *testing.T) {
+	sm := NewSettlementManager(defaultWorldSeed)
+	rules := config.AdjacencyByBuilding()
+	recount := func(key string) int {
+		n := 0
+		for _, p := range sm.Placements() {
+			if p.Key == key && sm.touches(key, p.X, p.Y, rules[key].Terrain) {
+				n++
+			}
+		}
+		return n
+	}
+	check := func(when string) {
+		for key := range rules {
+			if got, want := sm.adjacentCount(key), recount(key); got != want {
+				t.Errorf("%s: %s adjacent count = %d, want %d", when, key, got, want)
+			}
+		}
+	}
+
+	sm.Sync(map[string]int{"farm": 40, "woodcutter_camp": 30, "stone_pit": 30, "hut": 60})
+	check("after placing")
+	sm.Sync(map[string]int{"farm": 12, "woodcutter_camp": 30, "stone_pit": 5, "hut": 20})
+	check("after removing")
+	sm.LoadState(sm.Seed(), sm.Placements())
+	check("after loading")
+}
+
+func TestEngine_SaveLoadSettlement(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"hut": 5, "farm": 2})
+	ge.recalculateRates()
+	want := ge.Settlement.Placements()
+	if err := ge.SaveGame("test_settlement"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_settlement.json")
+
+	ge2 := NewGameEngine()
+	ge2.Buildings.LoadCounts(map[string]int{"farm": 1})
+	ge2.recalculateRates()
+	if err := ge2.LoadGame("test_settlement"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	if ge2.Settlement.Seed() != ge.Settlement.Seed() {
+		t.Errorf("world seed %d after load, want %d", ge2.Settlement.Seed(), ge.Settlement.Seed())
+	}
+	got := ge2.Settlement.Placements()
+	if len(got) != len(want) {
+		t.Fatalf("%d placements after load, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("placement %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
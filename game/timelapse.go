@@ -0,0 +1,112 @@
+package game
+
+// The timelapse is a record of the settlement over a run: the building
+// counts on every age advance and every TimelapseInterval ticks. Frames
+// hold counts only; TimelapseLayouts replays them on the game's world to
+// lay each one out again.
+
+// TimelapseInterval is how many ticks apart periodic frames are taken
+const TimelapseInterval = 50
+
+// MaxTimelapseFrames caps the record; past it, every other periodic frame
+// is dropped so the whole run still fits
+const MaxTimelapseFrames = 240
+
+// TimelapseFrame is the settlement at one point of the run
+type TimelapseFrame struct {
+	Tick      int            `json:"tick"`
+	Age       string         `json:"age"`
+	Buildings map[string]int `json:"buildings"` // nonzero counts only
+}
+
+// sameAs reports whether two frames show the same settlement
+func (f TimelapseFrame) sameAs(o TimelapseFrame) bool {
+	if f.Age != o.Age || len(f.Buildings) != len(o.Buildings) {
+		return false
+	}
+	for key, n := range f.Buildings {
+		if o.Buildings[key] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// timelapseFrame captures the settlement now (must be called with lock held)
+func (ge *GameEngine) timelapseFrame() TimelapseFrame {
+	counts := make(map[string]int)
+	for key, n := range ge.Buildings.counts {
+		if n > 0 {
+			counts[key] = n
+		}
+	}
+	return TimelapseFrame{Tick: ge.tick, Age: ge.age, Buildings: counts}
+}
+
+// recordTimelapse adds a frame unless nothing changed since the last one
+// (must be called with lock held)
+func (ge *GameEngine) recordTimelapse() {
+	frame := ge.timelapseFrame()
+	if n := len(ge.timelapse); n > 0 && ge.timelapse[n-1].sameAs(frame) {
+		return
+	}
+	ge.timelapse = append(ge.timelapse, frame)
+	if len(ge.timelapse) > MaxTimelapseFrames {
+		ge.timelapse = thinTimelapse(ge.timelapse)
+	}
+}
+
+// thinTimelapse drops every other frame, keeping the first frame of each
+// age and the latest frame
+func thinTimelapse(frames []TimelapseFrame) []TimelapseFrame {
+	out := make([]TimelapseFrame, 0, len(frames)/2+1)
+	skip := false
+	for i, f := range frames {
+		newAge := i == 0 || frames[i-1].Age != f.Age
+		if newAge || i == len(frames)-1 || !skip {
+			out = append(out, f)
+		}
+		skip = !skip
+	}
+	return out
+}
+
+// Timelapse returns the recorded frames of this run, oldest first, ending
+// with the settlement as it is now
+func (ge *GameEngine) Timelapse() []TimelapseFrame {
+	ge.mu.RLock()
+	defer ge.mu.RUnlock()
+
+	out := make([]TimelapseFrame, len(ge.timelapse), len(ge.timelapse)+1)
+	copy(out, ge.timelapse)
+	if now := ge.timelapseFrame(); len(out) == 0 || !out[len(out)-1].sameAs(now) {
+		out = append(out, now)
+	}
+	return out
+}
+
+// TimelapseLayouts lays out each frame's buildings on the world grown from
+// seed. One layout is carried from frame to frame, placing and removing
+// only what changed, the way Sync did during the run. Every layout covers
+// the same tiles so frames line up.
+func TimelapseLayouts(seed uint64, frames []TimelapseFrame) []SettlementState {
+	sm := NewSettlementManager(seed)
+	layouts := make([]SettlementState, len(frames))
+	minX, minY, maxX, maxY := 0, 0, 0, 0
+	for i, f := range frames {
+		sm.Sync(f.Buildings)
+		layouts[i] = sm.layout()
+		s := layouts[i]
+		minX, minY = min(minX, s.MinX), min(minY, s.MinY)
+		maxX, maxY = max(maxX, s.MinX+s.Width-1), max(maxY, s.MinY+s.Height-1)
+	}
+
+	width, height := maxX-minX+1, maxY-minY+1
+	terrain := sm.terrain(minX, minY, width, height)
+	for i := range layouts {
+		layouts[i].MinX, layouts[i].MinY = minX, minY
+		layouts[i].Width, layouts[i].Height = width, height
+		layouts[i].Terrain = terrain
+	}
+	return layouts
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEngine_TimelapseRecordsChanges(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"hut": 2})
+	ge.tick = TimelapseInterval
+	ge.recordTimelapse()
+	ge.tick = 2 * TimelapseInterval
+	ge.recordTimelapse() // nothing changed
+	ge.Buildings.LoadCounts(map[string]int{"hut": 3, "farm": 1})
+	ge.tick = 3 * TimelapseInterval
+	ge.recordTimelapse()
+	ge.advanceAge("stone_age")
+
+	frames := ge.Timelapse()
+	if len(frames) != 3 {
+		t.Fatalf("%d frames, want 3: %+v", len(frames), frames)
+	}
+	if frames[0].Tick != TimelapseInterval || frames[0].Buildings["hut"] != 2 {
+		t.Errorf("first frame = %+v, want 2 huts at tick %d", frames[0], TimelapseInterval)
+	}
+	if frames[1].Buildings["farm"] != 1 {
+		t.Errorf("second frame = %+v, want the farm", frames[1])
+	}
+	if frames[2].Age != "stone_age" {
+		t.Errorf("last frame age = %s, want stone_age", frames[2].Age)
+	}
+
+	// The settlement as it is now ends the record
+	ge.Buildings.LoadCounts(map[string]int{"hut": 5})
+	frames = ge.Timelapse()
+	if last := frames[len(frames)-1]; len(frames) != 4 || last.Buildings["hut"] != 5 {
+		t.Errorf("current frame missing: %+v", frames)
+	}
+}
+
+func TestEngine_TimelapseThinsKeepingAges(t *testing.T) {
+	ge := NewGameEngine()
+	for i := 1; i <= MaxTimelapseFrames+1; i++ {
+		if i == 100 {
+			ge.age = "stone_age"
+		}
+		ge.tick = i * TimelapseInterval
+		ge.Buildings.LoadCounts(map[string]int{"hut": i})
+		ge.recordTimelapse()
+	}
+	if n := len(ge.timelapse); n > MaxTimelapseFrames/2+2 {
+		t.Fatalf("%d frames after thinning, want at most %d", n, MaxTimelapseFrames/2+2)
+	}
+	ages := 0
+	for i, f := range ge.timelapse {
+		if f.Age == "stone_age" && (i == 0 || ge.timelapse[i-1].Age != f.Age) {
+			ages++
+			if f.Buildings["hut"] != 100 {
+				t.Errorf("first stone age frame has %d huts, want 100", f.Buildings["hut"])
+			}
+		}
+	}
+	if ages != 1 {
+		t.Errorf("found %d stone age starts, want 1", ages)
+	}
+	if last := ge.timelapse[len(ge.timelapse)-1]; last.Buildings["hut"] != MaxTimelapseFrames+1 {
+		t.Errorf("latest frame dropped: %+v", last)
+	}
+}
+
+func TestTimelapseLayouts(t *testing.T) {
+	frames := []TimelapseFrame{
+		{Tick: 50, Age: "primitive_age", Buildings: map[string]int{"hut": 1}},
+		{Tick: 100, Age: "stone_age", Buildings: map[string]int{"hut": 4, "farm": 2}},
+		{Tick: 150, Age: "stone_age", Buildings: map[string]int{"hut": 2, "farm": 30}},
+	}
+	layouts := TimelapseLayouts(defaultWorldSeed, frames)
+	if len(layouts) != len(frames) {
+		t.Fatalf("%d layouts, want %d", len(layouts), len(frames))
+	}
+	for i, l := range layouts {
+		if l.MinX != layouts[0].MinX || l.Width != layouts[0].Width || l.Height != layouts[0].Height {
+			t.Errorf("layout %d covers a different area", i)
+		}
+		if len(l.Terrain) != l.Width*l.Height {
+			t.Errorf("layout %d has %d terrain tiles, want %d", i, len(l.Terrain), l.Width*l.Height)
+		}
+		counts := make(map[string]int)
+		for _, b := range l.Buildings {
+			counts[b.Key]++
+		}
+		for key, n := range frames[i].Buildings {
+			if counts[key] != n {
+				t.Errorf("layout %d has %d %s, want %d", i, counts[key], key, n)
+			}
+		}
+	}
+}
+
+func TestEngine_SaveLoadTimelapse(t *testing.T) {
+	ge := NewGameEngine()
+	ge.Buildings.LoadCounts(map[string]int{"hut": 2})
+	ge.tick = TimelapseInterval
+	ge.recordTimelapse()
+	if err := ge.SaveGame("test_timelapse"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_timelapse.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_timelapse"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	if len(ge2.timelapse) != 1 || !ge2.timelapse[0].sameAs(ge.timelapse[0]) || ge2.timelapse[0].Tick != TimelapseInterval {
+		t.Errorf("timelapse after load = %+v, want %+v", ge2.timelapse, ge.timelapse)
+	}
+}
@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/user/ageforge/config"
 )
@@ -26,6 +27,8 @@ type ActiveRoute struct {
 	Key        string
 	TicksLeft  int
 	CyclesDone int
+	Suspended  bool // the partner faction is a rival or under embargo
+	Stalled    bool // the last cycle couldn't pay its exports
 }
 
 // NewTradeManager creates a new trade manager
@@ -40,20 +43,31 @@ func NewTradeManager() *TradeManager {
 	}
 }
 
-// GetExchangeRate returns the current rate for a resource pair, accounting for supply pressure
-func (tm *TradeManager) GetExchangeRate(from, to string) float64 {
+// GetExchangeRate returns the current rate for a resource pair, accounting for
+// faction prices and supply pressure
+func (tm *TradeManager) GetExchangeRate(from, to string, diplomacy *DiplomacyManager) float64 {
 	rates := config.ExchangeRateByKey()
-	key := from + ":" + to
-	def, ok := rates[key]
+	def, ok := rates[from+":"+to]
 	if !ok {
 		return 0
 	}
-	pressure := tm.supplyPressure[key]
-	return def.BaseRate * (1.0 - pressure*0.3)
+	return tm.currentRate(def, diplomacy)
+}
+
+// currentRate scales a pair's base rate by faction supply and demand, then
+// lowers it by the player's own supply pressure (floored at half)
+func (tm *TradeManager) currentRate(def config.ExchangeRateDef, diplomacy *DiplomacyManager) float64 {
+	base := def.BaseRate * diplomacy.MarketMultiplier(def.From, def.To)
+	pressure := tm.supplyPressure[def.From+":"+def.To]
+	rate := base * (1.0 - pressure*0.3)
+	if rate < base*0.5 {
+		rate = base * 0.5 // floor at 50% of base
+	}
+	return rate
 }
 
 // Exchange performs an instant resource exchange
-func (tm *TradeManager) Exchange(from, to string, amount float64, resources *ResourceManager, buildings *BuildingManager, tick int) (float64, error) {
+func (tm *TradeManager) Exchange(from, to string, amount float64, resources *ResourceManager, buildings *BuildingManager, diplomacy *DiplomacyManager, tick int) (float64, error) {
 	rates := config.ExchangeRateByKey()
 	key := from + ":" + to
 	def, ok := rates[key]
@@ -71,17 +85,13 @@ func (tm *TradeManager) Exchange(from, to string, amount float64, resources *Res
 		return 0, fmt.Errorf("not enough %s (have: %.0f, need: %.0f)", from, resources.Get(from), amount)
 	}
 
-	// Calculate received amount with supply pressure
-	pressure := tm.supplyPressure[key]
-	rate := def.BaseRate * (1.0 - pressure*0.3)
-	if rate < def.BaseRate*0.5 {
-		rate = def.BaseRate * 0.5 // floor at 50% of base
-	}
-	got := amount * rate
+	// Calculate received amount with faction prices and supply pressure
+	got := amount * tm.currentRate(def, diplomacy)
 
 	// Execute trade
 	resources.Remove(from, amount)
 	resources.Add(to, got)
+	diplomacy.MarketTrade([]string{from}, []string{to})
 
 	// Update supply pressure (selling more pushes rate down)
 	// More markets reduce pressure impact
@@ -100,7 +110,7 @@ func (tm *TradeManager) Exchange(from, to string, amount float64, resources *Res
 }
 
 // StartRoute activates a trade route
-func (tm *TradeManager) StartRoute(key string, buildings *BuildingManager, age string, ageOrder map[string]int) error {
+func (tm *TradeManager) StartRoute(key string, buildings *BuildingManager, diplomacy *DiplomacyManager, age string, ageOrder map[string]int) error {
 	routes := config.TradeRouteByKey()
 	def, ok := routes[key]
 	if !ok {
@@ -117,6 +127,12 @@ func (tm *TradeManager) StartRoute(key string, buildings *BuildingManager, age s
 		return fmt.Errorf("%s requires %d %s(s) (have: %d)", def.Name, def.MinCount, def.RequiredBld, buildings.GetCount(def.RequiredBld))
 	}
 
+	// Check the partner faction will trade
+	if _, open := diplomacy.RouteStanding(def.Partner); def.Partner != "" && !open {
+		partner := config.FactionByKey()[def.Partner]
+		return fmt.Errorf("%s trades with %s, who won't deal with you while undiscovered, a rival or under embargo", def.Name, partner.Name)
+	}
+
 	// Check not already active
 	if _, active := tm.activeRoutes[key]; active {
 		return fmt.Errorf("%s is already active", def.Name)
@@ -158,34 +174,58 @@ func (tm *TradeManager) Tick(resources *ResourceManager, buildings *BuildingMana
 			continue
 		}
 
+		// Routes wait out a rivalry or embargo with their partner
+		yield, open := routeYield(def, diplomacy)
+		if !open {
+			if !route.Suspended {
+				route.Suspended = true
+				partner := config.FactionByKey()[def.Partner]
+				messages = append(messages, fmt.Sprintf("Trade route %s suspended: %s won't trade with you", def.Name, partner.Name))
+			}
+			continue
+		}
+		if route.Suspended {
+			route.Suspended = false
+			partner := config.FactionByKey()[def.Partner]
+			messages = append(messages, fmt.Sprintf("Trade route %s resumed with %s", def.Name, partner.Name))
+		}
+
 		route.TicksLeft--
 		if route.TicksLeft <= 0 {
 			// Check if we can afford the exports
-			canAfford := true
+			short := ""
 			for res, amount := range def.Export {
 				if resources.Get(res) < amount {
-					canAfford = false
+					short = res
 					break
 				}
 			}
+			if short != "" && !route.Stalled {
+				messages = append(messages, fmt.Sprintf("Trade route %s stalled: not enough %s to export", def.Name, short))
+			}
+			route.Stalled = short != ""
 
-			if canAfford {
+			if short == "" {
 				// Consume exports
 				for res, amount := range def.Export {
 					resources.Remove(res, amount)
 					tm.totalExported[res] += amount
 				}
 
-				// Add imports (with diplomacy bonus)
+				// Add imports (with market and partner yield and diplomacy bonus)
 				for res, amount := range def.Import {
 					bonus := 0.0
 					if diplomacy != nil {
 						bonus = diplomacy.GetTradeBonus(res)
 					}
-					actual := amount * (1.0 + bonus)
+					actual := amount * yield * (1.0 + bonus)
 					resources.Add(res, actual)
 					tm.totalImported[res] += actual
 				}
+				diplomacy.MarketTrade(mapKeys(def.Export), mapKeys(def.Import))
+				if def.Partner != "" {
+					diplomacy.RecordRouteCycle(def.Partner)
+				}
 
 				route.CyclesDone++
 			}
@@ -213,8 +253,54 @@ func (tm *TradeManager) Tick(resources *ResourceManager, buildings *BuildingMana
 	return messages
 }
 
+// RouteFlows returns the active routes that export or import a resource, by
+// name, with what a cycle moves at the current yield
+func (tm *TradeManager) RouteFlows(res string, diplomacy *DiplomacyManager) []RouteFlow {
+	var flows []RouteFlow
+	for key, route := range tm.activeRoutes {
+		def, ok := config.TradeRouteByKey()[key]
+		if !ok {
+			continue
+		}
+		yield, _ := routeYield(def, diplomacy)
+		amount := def.Import[res]*yield*(1.0+diplomacy.GetTradeBonus(res)) - def.Export[res]
+		if amount == 0 {
+			continue
+		}
+		flows = append(flows, RouteFlow{
+			Name:        def.Name,
+			PerCycle:    amount,
+			TicksPerRun: def.TicksPerRun,
+			Suspended:   route.Suspended,
+			Stalled:     route.Stalled,
+		})
+	}
+	sort.Slice(flows, func(i, j int) bool { return flows[i].Name < flows[j].Name })
+	return flows
+}
+
+// routeYield returns the multiplier on a route's imports from market prices
+// and relations with its partner, and whether the partner trades at all
+func routeYield(def config.TradeRouteDef, diplomacy *DiplomacyManager) (float64, bool) {
+	yield := diplomacy.RouteYield(def.Export, def.Import)
+	if def.Partner == "" {
+		return yield, true
+	}
+	standing, open := diplomacy.RouteStanding(def.Partner)
+	return yield * standing, open
+}
+
+// mapKeys returns the keys of a resource map
+func mapKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 // Snapshot returns the trade state for UI consumption
-func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings *BuildingManager) TradeState {
+func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings *BuildingManager, diplomacy *DiplomacyManager) TradeState {
 	rates := config.ExchangeRateByKey()
 	allRoutes := config.TradeRouteByKey()
 
@@ -224,14 +310,13 @@ func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings
 		if ageOrder[def.MinAge] > ageOrder[age] {
 			continue
 		}
-		pressure := tm.supplyPressure[key]
-		currentRate := def.BaseRate * (1.0 - pressure*0.3)
 		exchangeRates[key] = ExchangeRateInfo{
 			From:     def.From,
 			To:       def.To,
-			Rate:     currentRate,
+			Rate:     tm.currentRate(def, diplomacy),
 			BaseRate: def.BaseRate,
-			Pressure: pressure,
+			Pressure: tm.supplyPressure[key],
+			Market:   diplomacy.MarketMultiplier(def.From, def.To),
 		}
 	}
 
@@ -239,6 +324,7 @@ func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings
 	var activeRoutes []ActiveRouteInfo
 	for key, route := range tm.activeRoutes {
 		def := allRoutes[key]
+		yield, _ := routeYield(def, diplomacy)
 		activeRoutes = append(activeRoutes, ActiveRouteInfo{
 			Name:       def.Name,
 			Key:        key,
@@ -246,6 +332,10 @@ func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings
 			CyclesDone: route.CyclesDone,
 			Export:     def.Export,
 			Import:     def.Import,
+			Yield:      yield,
+			Partner:    def.Partner,
+			Suspended:  route.Suspended,
+			Stalled:    route.Stalled,
 		})
 	}
 
@@ -258,7 +348,8 @@ func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings
 		if _, active := tm.activeRoutes[def.Key]; active {
 			continue
 		}
-		canStart := buildings.GetCount(def.RequiredBld) >= def.MinCount
+		yield, open := routeYield(def, diplomacy)
+		canStart := buildings.GetCount(def.RequiredBld) >= def.MinCount && open
 		availableRoutes = append(availableRoutes, TradeRouteInfo{
 			Name:        def.Name,
 			Key:         def.Key,
@@ -267,6 +358,9 @@ func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings
 			CanStart:    canStart,
 			RequiredBld: def.RequiredBld,
 			MinCount:    def.MinCount,
+			Yield:       yield,
+			Partner:     def.Partner,
+			PartnerOpen: open,
 			Description: def.Description,
 		})
 	}
@@ -281,8 +375,22 @@ func (tm *TradeManager) Snapshot(age string, ageOrder map[string]int, buildings
 		totalImported[k] = v
 	}
 
+	// Market prices of everything the discovered factions trade
+	prices := make(map[string]float64)
+	if diplomacy != nil {
+		for _, def := range config.BaseFactions() {
+			if fs, ok := diplomacy.factions[def.Key]; !ok || !fs.Discovered {
+				continue
+			}
+			for res := range economyGoods(def) {
+				prices[res] = diplomacy.PriceIndex(res)
+			}
+		}
+	}
+
 	return TradeState{
 		ExchangeRates:   exchangeRates,
+		Prices:          prices,
 		ActiveRoutes:    activeRoutes,
 		AvailableRoutes: availableRoutes,
 		TotalExchanged:  totalExchanged,
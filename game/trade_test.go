@@ -0,0 +1,143 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+// routeSetup returns managers with a port, stocked gold and room for the
+// spice trade's imports
+func routeSetup() (*ResourceManager, *BuildingManager) {
+	rm := NewResourceManager()
+	for _, res := range []string{"gold", "food", "culture"} {
+		rm.UnlockResource(res)
+		rm.AddStorage(res, 100000)
+	}
+	rm.Add("gold", 10000)
+	bm := NewBuildingManager()
+	bm.LoadCounts(map[string]int{"port": 1})
+	return rm, bm
+}
+
+// runSpiceCycle ticks a route until its first cycle completes
+func runSpiceCycle(tm *TradeManager, rm *ResourceManager, bm *BuildingManager, dm *DiplomacyManager) {
+	for i := 0; i < 18; i++ {
+		tm.Tick(rm, bm, dm)
+	}
+}
+
+func TestTradeManager_RouteNeedsOpenPartner(t *testing.T) {
+	_, bm := routeSetup()
+	tm := NewTradeManager()
+	dm := NewDiplomacyManager()
+
+	if err := tm.StartRoute("spice_trade", bm, dm, "colonial_age", raidAgeOrder()); err == nil {
+		t.Fatal("started a route with an undiscovered partner")
+	}
+	dm.Discover("merchant_guild")
+	dm.factions["merchant_guild"].Status = "embargo"
+	if err := tm.StartRoute("spice_trade", bm, dm, "colonial_age", raidAgeOrder()); err == nil {
+		t.Fatal("started a route with an embargoed partner")
+	}
+	dm.factions["merchant_guild"].Status = "neutral"
+	if err := tm.StartRoute("spice_trade", bm, dm, "colonial_age", raidAgeOrder()); err != nil {
+		t.Fatalf("StartRoute failed: %v", err)
+	}
+}
+
+func TestTradeManager_RouteSuspendsDuringEmbargo(t *testing.T) {
+	rm, bm := routeSetup()
+	tm := NewTradeManager()
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	if err := tm.StartRoute("spice_trade", bm, dm, "colonial_age", raidAgeOrder()); err != nil {
+		t.Fatalf("StartRoute failed: %v", err)
+	}
+
+	dm.factions["merchant_guild"].Status = "embargo"
+	if msgs := tm.Tick(rm, bm, dm); len(msgs) != 1 {
+		t.Fatalf("messages = %v, want the route suspended", msgs)
+	}
+	runSpiceCycle(tm, rm, bm, dm)
+	route := tm.activeRoutes["spice_trade"]
+	if !route.Suspended || route.TicksLeft != 18 || rm.Get("food") != 0 {
+		t.Errorf("suspended route advanced: %+v, food %v", route, rm.Get("food"))
+	}
+
+	dm.factions["merchant_guild"].Status = "neutral"
+	if msgs := tm.Tick(rm, bm, dm); len(msgs) != 1 {
+		t.Fatalf("messages = %v, want the route resumed", msgs)
+	}
+	if route.Suspended || route.TicksLeft != 17 {
+		t.Errorf("resumed route = %+v, want 17 ticks left", route)
+	}
+}
+
+func TestTradeManager_PartnerOpinionScalesImports(t *testing.T) {
+	imports := func(opinion int) float64 {
+		rm, bm := routeSetup()
+		tm := NewTradeManager()
+		dm := NewDiplomacyManager()
+		dm.Discover("merchant_guild")
+		dm.factions["merchant_guild"].Opinion = opinion
+		if err := tm.StartRoute("spice_trade", bm, dm, "colonial_age", raidAgeOrder()); err != nil {
+			t.Fatalf("StartRoute failed: %v", err)
+		}
+		runSpiceCycle(tm, rm, bm, dm)
+		return rm.Get("food")
+	}
+
+	neutral, friendly := imports(0), imports(100)
+	if neutral <= 0 {
+		t.Fatal("route imported no food")
+	}
+	if got := friendly / neutral; math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("food at 100 opinion / at 0 = %v, want 1.5", got)
+	}
+}
+
+func TestTradeManager_RouteCycleBuildsPartnerOpinion(t *testing.T) {
+	rm, bm := routeSetup()
+	tm := NewTradeManager()
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	dm.Discover("artisan_league")
+	if err := tm.StartRoute("spice_trade", bm, dm, "colonial_age", raidAgeOrder()); err != nil {
+		t.Fatalf("StartRoute failed: %v", err)
+	}
+
+	runSpiceCycle(tm, rm, bm, dm)
+	guild := dm.factions["merchant_guild"]
+	if guild.TradeCount != 1 || guild.Opinion != routeCycleOpinion {
+		t.Errorf("guild trades = %d, opinion = %d; want 1 and %d", guild.TradeCount, guild.Opinion, routeCycleOpinion)
+	}
+	if league := dm.factions["artisan_league"]; league.TradeCount != 0 || league.Opinion != 0 {
+		t.Errorf("league trades = %d, opinion = %d; want untouched", league.TradeCount, league.Opinion)
+	}
+}
+
+func TestTradeManager_RouteStallsWithoutExports(t *testing.T) {
+	rm, bm := routeSetup()
+	tm := NewTradeManager()
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	if err := tm.StartRoute("spice_trade", bm, dm, "colonial_age", raidAgeOrder()); err != nil {
+		t.Fatalf("StartRoute failed: %v", err)
+	}
+	if flows := tm.RouteFlows("gold", dm); len(flows) != 1 || flows[0].PerCycle != -100 {
+		t.Fatalf("gold flows = %+v, want the spice trade's 100 gold export", flows)
+	}
+
+	rm.Remove("gold", rm.Get("gold"))
+	runSpiceCycle(tm, rm, bm, dm)
+	route := tm.activeRoutes["spice_trade"]
+	if !route.Stalled || route.CyclesDone != 0 || rm.Get("food") != 0 {
+		t.Errorf("route = %+v, food %v; want stalled with nothing imported", route, rm.Get("food"))
+	}
+
+	rm.Add("gold", 1000)
+	runSpiceCycle(tm, rm, bm, dm)
+	if route.Stalled || route.CyclesDone != 1 {
+		t.Errorf("route = %+v, want it running again", route)
+	}
+}
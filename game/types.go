@@ -22,6 +22,8 @@ type GameState struct {
 	Prestige       PrestigeState
 	Trade          TradeState
 	Diplomacy      DiplomacyState
+	Power          PowerState
+	Settlement     SettlementState
 	Log            []LogEntry
 	Stats          StatsSnapshot
 	SaveExists     bool
@@ -37,15 +39,53 @@ type BuildQueueSnapshot struct {
 	TotalTicks int
 }
 
-// RateBreakdown shows the components that make up a resource's net rate
+// RateBreakdown shows how a resource's net rate was built from the modifier
+// stack: rate = Base × Multiplier + Consumption + Final (the multiplier only
+// while Base is positive). The Amounts of Sources sum to the rate.
 type RateBreakdown struct {
-	BuildingRate float64
-	VillagerRate float64
-	ResearchRate float64
-	EventRate    float64
-	TradeRate    float64
-	FoodDrain    float64
-	BonusRate    float64
+	Base        float64      // additive layer, gather bonuses included
+	Multiplier  float64      // 1 + multiplicative layer
+	Consumption float64      // consumption layer: inputs burned by buildings
+	Final       float64      // final layer: drains, upkeep, events
+	Sources     []RateSource // every contribution that applies, additive layer first
+}
+
+// RateSource is one modifier's contribution to a resource rate
+type RateSource struct {
+	Layer  string
+	Source string
+	Key    string
+	Name   string
+	Value  float64 // the modifier: per tick, or a fraction for multipliers
+	Amount float64 // what it adds to the rate per tick
+}
+
+// ResourceExplanation is the full derivation of a resource's rate and
+// storage cap, and how it changed since an earlier tick
+type ResourceExplanation struct {
+	Key            string
+	Name           string
+	Amount         float64
+	Rate           float64
+	Breakdown      RateBreakdown
+	Storage        float64
+	BaseStorage    float64
+	StorageSources []RateSource // storage bonuses, all-storage first
+	Routes         []RouteFlow  // active trade routes moving the resource
+
+	Since      int // ticks back compared, 0 without history
+	WasAmount  float64
+	WasRate    float64
+	WasStorage float64
+	Changes    []RateChange // sources whose contribution changed, largest first
+}
+
+// RateChange is how one source's contribution to a rate changed
+type RateChange struct {
+	Source string
+	Name   string
+	Was    float64
+	Now    float64
 }
 
 // ResourceState represents a single resource's current state
@@ -68,6 +108,59 @@ type BuildingState struct {
 	// Cost for next building
 	NextCost map[string]float64
 	CanBuild bool
+	// Instances switched off; they grant no effects and pay no upkeep
+	Disabled int
+	// Recipe inputs, power draw and upkeep per building per tick, and the
+	// share of full output they allowed this tick
+	Inputs      map[string]float64
+	PowerDemand float64
+	PowerPrio   int
+	Upkeep      map[string]float64
+	Throughput  float64
+}
+
+// PowerState is the electric grid this tick: generation shared among the
+// buildings that draw power, with the rest charging the stockpile
+type PowerState struct {
+	Supply float64 // electricity generated
+	Demand float64 // what powered buildings want at full output
+	Served float64 // what they got
+}
+
+// SettlementState is the settlement layout and the terrain around it
+type SettlementState struct {
+	Seed          uint64           // the world's seed
+	MinX, MinY    int              // tile at the top-left of Terrain
+	Width, Height int              // tiles covered by Terrain
+	Terrain       []string         // row-major, Width × Height
+	Buildings     []PlacedBuilding // in placement order
+	Biomes        []BiomeShare     // the land around the town, largest share first
+}
+
+// BiomeShare is one terrain's share of the land around the town
+type BiomeShare struct {
+	Terrain string
+	Name    string
+	Share   float64
+}
+
+// TerrainAt returns the terrain of a tile, or "" outside the snapshot
+func (s SettlementState) TerrainAt(x, y int) string {
+	x, y = x-s.MinX, y-s.MinY
+	if x < 0 || y < 0 || x >= s.Width || y >= s.Height {
+		return ""
+	}
+	return s.Terrain[y*s.Width+x]
+}
+
+// PlacedBuilding is one building instance on the settlement grid
+type PlacedBuilding struct {
+	Key      string
+	Name     string
+	Category string
+	X, Y     int  // top-left tile; the town centre is (0,0)
+	Size     int  // tiles per side
+	Adjacent bool // sited next to the terrain its placement bonus needs
 }
 
 // VillagerState represents all villager info
@@ -133,6 +226,7 @@ type TechState struct {
 	Cost          float64
 	Prerequisites []string
 	Description   string
+	Campaign      string // expedition key that grants this tech, "" if researchable
 	Researched    bool
 	Available     bool // meets age + prereqs and not yet researched
 	PrereqsMet    bool
@@ -142,32 +236,116 @@ type TechState struct {
 
 // MilitaryState represents military system state for UI
 type MilitaryState struct {
-	SoldierCount     int
-	DefenseRating    float64
-	MilitaryBonus    float64
-	ExpeditionBonus  float64
-	ActiveExpedition *ExpeditionSnapshot
-	Expeditions      []ExpeditionInfo
-	CompletedCount   int
-	TotalLoot        map[string]float64
+	SoldierCount      int
+	AvailableSoldiers int // soldiers not committed to an expedition
+	DefenseRating     float64
+	MilitaryBonus     float64
+	ExpeditionBonus   float64
+	Units             []UnitInfo         // unit types reached this age
+	Upkeep            map[string]float64 // per tick, for every soldier
+	Morale            float64            // 0-100; falls while upkeep goes unpaid
+	Training          []TrainingSnapshot // training queue, front first
+	TrainingSlots     int                // units that train at once
+	TrainingTicks     int                // until the whole queue is done
+	ActiveExpeditions []ExpeditionSnapshot
+	ExpeditionSlots   int
+	Expeditions       []ExpeditionInfo
+	CompletedCount    int
+	TotalLoot         map[string]float64
+	Raids             RaidState
 }
 
-// ExpeditionSnapshot represents an active expedition for UI
-type ExpeditionSnapshot struct {
+// RaidState represents raid history and any incoming raid for UI
+type RaidState struct {
+	Incoming   *RaidSnapshot
+	NextRaidIn int // ticks until the next raid is sighted, 0 if unknown
+	Repelled   int
+	Lost       int
+	LastResult string
+}
+
+// RaidSnapshot represents an incoming raid for UI
+type RaidSnapshot struct {
 	Name      string
-	Soldiers  int
+	Strength  float64
+	Enemy     map[string]int
 	TicksLeft int
 }
 
+// UnitInfo represents a military unit type for UI
+type UnitInfo struct {
+	Key          string
+	Name         string
+	Count        int // home and away
+	Home         int
+	Attack       float64
+	Defense      float64
+	Counters     []string
+	TrainCost    map[string]float64
+	Upkeep       map[string]float64
+	TrainTicks   int
+	RequiredTech string
+	Unlocked     bool // age and tech allow training
+}
+
+// TrainingSnapshot represents one order in the training queue for UI
+type TrainingSnapshot struct {
+	Unit       string
+	Name       string
+	Count      int
+	TicksLeft  int
+	TotalTicks int
+}
+
+// ExpeditionSnapshot represents an active expedition for UI
+type ExpeditionSnapshot struct {
+	Key        string
+	Name       string
+	Soldiers   int
+	Units      map[string]int
+	TicksLeft  int
+	TotalTicks int
+	Stage      int // 1-based; the stage to fight next when holding
+	Stages     int
+	StageName  string
+	Holding    bool // waiting for the player to advance or retreat
+	Haul       map[string]float64
+}
+
 // ExpeditionInfo represents an available expedition for UI
 type ExpeditionInfo struct {
 	Name           string
 	Key            string
 	SoldiersNeeded int
-	Duration       int
-	Difficulty     float64
+	Duration       int              // total ticks across all stages
+	Enemies        []map[string]int // enemy army per stage
+	Stages         int
 	Description    string
+	Underway       bool
 	CanLaunch      bool
+	Forecast       ExpeditionForecast // with the army a launch would send now
+	Rank           int                // by loot per soldier-tick among launchable wins, 0 if unranked
+}
+
+// ExpeditionForecast is the predicted outcome of sending an army on an expedition
+type ExpeditionForecast struct {
+	Army               map[string]int
+	Won                bool // every stage is won
+	Stages             []StageForecast
+	Ticks              int // until the army is home, counting only stages fought
+	Loot               map[string]float64
+	LootTotal          float64
+	Losses             map[string]int
+	SoldiersLost       int
+	LootPerSoldierTick float64
+}
+
+// StageForecast is the predicted battle of one expedition stage
+type StageForecast struct {
+	Name   string
+	Enemy  map[string]int
+	Won    bool
+	Losses map[string]int
 }
 
 // === Milestone Types ===
@@ -268,6 +446,7 @@ type ActiveEventState struct {
 // TradeState represents the trade system state for UI
 type TradeState struct {
 	ExchangeRates   map[string]ExchangeRateInfo
+	Prices          map[string]float64 // market price index of faction-traded resources, 1 is normal
 	ActiveRoutes    []ActiveRouteInfo
 	AvailableRoutes []TradeRouteInfo
 	TotalExchanged  map[string]float64
@@ -281,6 +460,7 @@ type ExchangeRateInfo struct {
 	Rate     float64
 	BaseRate float64
 	Pressure float64
+	Market   float64 // faction supply and demand multiplier on BaseRate
 }
 
 // ActiveRouteInfo represents an active trade route for UI
@@ -291,6 +471,19 @@ type ActiveRouteInfo struct {
 	CyclesDone int
 	Export     map[string]float64
 	Import     map[string]float64
+	Yield      float64 // market and partner multiplier on imports
+	Partner    string  // faction key, "" for local routes
+	Suspended  bool    // waiting out a rivalry or embargo with the partner
+	Stalled    bool    // the last cycle couldn't pay its exports
+}
+
+// RouteFlow is what an active trade route moves of one resource
+type RouteFlow struct {
+	Name        string
+	PerCycle    float64 // imports at the current yield less exports
+	TicksPerRun int
+	Suspended   bool
+	Stalled     bool
 }
 
 // TradeRouteInfo represents an available trade route for UI
@@ -302,6 +495,9 @@ type TradeRouteInfo struct {
 	CanStart    bool
 	RequiredBld string
 	MinCount    int
+	Yield       float64 // market and partner multiplier on imports
+	Partner     string  // faction key, "" for local routes
+	PartnerOpen bool    // the partner is discovered and neither a rival nor under embargo
 	Description string
 }
 
@@ -313,12 +509,56 @@ type DiplomacyState struct {
 }
 
 // FactionInfo represents an NPC faction for UI
+
 type FactionInfo struct {
-	Name       string
-	Discovered bool
-	Opinion    int
-	Status     string
-	Specialty  string
-	TradeBonus float64
-	TradeCount int
+	Name         string
+	Discovered   bool
+	Opinion      int
+	Status       string
+	Specialty    string
+	TradeBonus   float64
+	TradeCount   int
+	Wants        string             // resource asked for in supply requests
+	Request      FactionRequestInfo // Key is "" when there is no open request
+	Proposal     TreatyInfo         // Key is "" when nothing is on the table
+	Treaty       TreatyInfo         // Key is "" when no treaty is in force
+	Economy      []FactionGoodInfo  // sorted by resource
+	Disruption   float64            // 0-1, how cut off the faction is from the market
+	Incidents    int                // border incidents since opinion fell to -50
+	WarIncidents int                // incidents that bring war
+	WarScore     int                // at war: battles won minus raids lost
+	SurrenderAt  int                // at war: war score at which the faction sues for peace
+	PeaceTribute float64            // at war: gold asked for peace
+}
+
+// FactionGoodInfo represents one resource in a faction's economy for UI
+type FactionGoodInfo struct {
+	Resource string
+	Stock    float64
+	Capacity float64
+	Produces float64 // per tick
+	Consumes float64 // per tick
+}
+
+// FactionRequestInfo represents a faction's open request for UI
+type FactionRequestInfo struct {
+	Key            string
+	Name           string
+	Resources      map[string]float64 // delivery demanded, nil for military aid
+	Expedition     string             // expedition to win for military aid
+	ExpeditionName string
+	TicksLeft      int
+	Reward         int
+	Penalty        int
+}
+
+// TreatyInfo represents a treaty proposed or in force for UI
+type TreatyInfo struct {
+	Key          string
+	Name         string
+	Duration     int
+	TicksLeft    int // until a proposal is withdrawn or a treaty runs its term
+	Benefits     []string
+	Obligations  []string
+	BreakPenalty int
 }
@@ -1,5 +1,10 @@
 package game
 
+import (
+	"fmt"
+	"sort"
+)
+
 // VillagerTypeDef defines a villager type's properties
 type VillagerTypeDef struct {
 	Name     string
@@ -196,6 +201,42 @@ func (vm *VillagerManager) GetProductionRates() map[string]float64 {
 	return rates
 }
 
+// Modifiers returns villager output on each assigned resource and the food
+// each type eats, in key order
+func (vm *VillagerManager) Modifiers() []Modifier {
+	keys := make([]string, 0, len(vm.types))
+	for key := range vm.types {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var mods []Modifier
+	for _, key := range keys {
+		rt := vm.types[key]
+		def := vm.definitions[key]
+		resources := make([]string, 0, len(rt.assignment))
+		for res, count := range rt.assignment {
+			if count > 0 {
+				resources = append(resources, res)
+			}
+		}
+		sort.Strings(resources)
+		for _, res := range resources {
+			count := rt.assignment[res]
+			mods = append(mods, Modifier{
+				Stat: res, Layer: LayerAdditive, Value: def.GatherRate * float64(count),
+				Source: SourceVillager, Key: key, Name: fmt.Sprintf("%s ×%d", def.Name, count),
+			})
+		}
+		if rt.count > 0 && def.FoodCost > 0 {
+			mods = append(mods, Modifier{
+				Stat: "food", Layer: LayerFinal, Value: -def.FoodCost * float64(rt.count),
+				Source: SourceVillager, Key: key, Name: fmt.Sprintf("%s ×%d eating", def.Name, rt.count),
+			})
+		}
+	}
+	return mods
+}
+
 // GetAll returns serializable villager info (for save)
 func (vm *VillagerManager) GetAll() map[string]VillagerInfo {
 	out := make(map[string]VillagerInfo)
@@ -236,6 +277,68 @@ func (vm *VillagerManager) RemoveSoldiers(count int) {
 	}
 }
 
+// RemoveVillagers removes villagers of a type, idle ones first, then from
+// their assignments in resource key order. Returns how many were removed.
+func (vm *VillagerManager) RemoveVillagers(vType string, count int) int {
+	rt, ok := vm.types[vType]
+	if !ok {
+		return 0
+	}
+	if count > rt.count {
+		count = rt.count
+	}
+	fromAssigned := count - vm.IdleCount(vType)
+	resources := make([]string, 0, len(rt.assignment))
+	for res := range rt.assignment {
+		resources = append(resources, res)
+	}
+	sort.Strings(resources)
+	for _, res := range resources {
+		if fromAssigned <= 0 {
+			break
+		}
+		n := rt.assignment[res]
+		take := n
+		if take > fromAssigned {
+			take = fromAssigned
+		}
+		rt.assignment[res] -= take
+		fromAssigned -= take
+	}
+	rt.count -= count
+	return count
+}
+
+// Dismiss sends away up to count villagers: idle ones of every type first,
+// then gatherers. Soldiers are never dismissed, as they belong to units.
+// Returns how many of each type left.
+func (vm *VillagerManager) Dismiss(count int) map[string]int {
+	keys := make([]string, 0, len(vm.types))
+	for key := range vm.types {
+		if key != "soldier" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	dismissed := make(map[string]int)
+	for _, idleOnly := range []bool{true, false} {
+		for _, key := range keys {
+			n := count
+			if idle := vm.IdleCount(key); idleOnly && n > idle {
+				n = idle
+			}
+			if n <= 0 {
+				continue
+			}
+			n = vm.RemoveVillagers(key, n)
+			dismissed[key] += n
+			count -= n
+		}
+	}
+	return dismissed
+}
+
 // Snapshot returns villager state for UI
 func (vm *VillagerManager) Snapshot(popCap int) VillagerState {
 	state := VillagerState{
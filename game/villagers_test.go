@@ -130,6 +130,26 @@ func TestVillagerManager_RemoveSoldiers(t *testing.T) {
 	}
 }
 
+func TestVillagerManager_RemoveVillagersInKeyOrder(t *testing.T) {
+	// Idle villagers go first, then gatherers by resource key, every time
+	for i := 0; i < 20; i++ {
+		vm := NewVillagerManager()
+		vm.UnlockType("worker")
+		vm.Recruit("worker", 10, 20)
+		vm.Assign("worker", "wood", 3)
+		vm.Assign("worker", "food", 3)
+		vm.Assign("worker", "stone", 3)
+
+		if got := vm.RemoveVillagers("worker", 5); got != 5 {
+			t.Fatalf("removed = %d, want 5", got)
+		}
+		assign := vm.GetAll()["worker"].Assignment
+		if assign["food"] != 0 || assign["stone"] != 2 || assign["wood"] != 3 {
+			t.Fatalf("assignment after removal = %v, want food 0, stone 2, wood 3", assign)
+		}
+	}
+}
+
 func TestVillagerManager_SaveLoadRoundTrip(t *testing.T) {
 	vm := NewVillagerManager()
 	vm.UnlockType("worker")
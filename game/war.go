@@ -0,0 +1,224 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/user/ageforge/config"
+)
+
+// Faction conflict: a faction whose opinion falls to incidentOpinion stages
+// border incidents, and after warIncidents of them declares war. At war it
+// raids more often, won't trade, and can be fought on its war front. Peace
+// is bought with tribute, or forced by winning enough battles.
+
+const (
+	incidentOpinion  = -50 // at or below this opinion a faction stages border incidents
+	incidentInterval = 50  // ticks between a faction's incidents
+	incidentPenalty  = 5   // opinion lost per incident
+	warIncidents     = 3   // incidents before a faction declares war
+	allyJoinOpinion  = 60  // an ally this fond of the player joins its wars
+	surrenderScore   = 3   // war score at which a faction sues for peace
+	peaceTributeBase = 50  // gold per age per point of war score short of surrender
+	peaceOpinion     = -30 // opinion once peace is made
+
+	warFrontPrefix   = "war_" // expedition key prefix of a war front
+	warFrontTicks    = 30
+	warFrontStrength = 1.5 // a war front army against a raid of the same age
+)
+
+// tickConflict stages border incidents for factions whose opinion has fallen
+// far enough and declares war once they pile up. A treaty that promises no
+// raids keeps the border quiet.
+func (dm *DiplomacyManager) tickConflict(tick int) []string {
+	var messages []string
+	for i, def := range config.BaseFactions() {
+		fs, ok := dm.factions[def.Key]
+		if !ok || !fs.Discovered || fs.Status == "war" {
+			continue
+		}
+		if (tick+i*agendaStagger)%incidentInterval != 0 {
+			continue
+		}
+		if fs.Treaty != "" && config.TreatyByKey()[fs.Treaty].NoRaids {
+			continue
+		}
+		if fs.Opinion > incidentOpinion {
+			if fs.Incidents > 0 {
+				fs.Incidents = 0
+				messages = append(messages, fmt.Sprintf("Tensions on the border with %s have eased", def.Name))
+			}
+			continue
+		}
+		fs.Incidents++
+		fs.adjustOpinion(-incidentPenalty)
+		if fs.Incidents >= warIncidents {
+			messages = append(messages, dm.declareWar(def, fs)...)
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("Border incident with %s (%d of %d before war, -%d opinion)",
+			def.Name, fs.Incidents, warIncidents, incidentPenalty))
+	}
+	return messages
+}
+
+// declareWar puts a faction at war with the player. Allies fond enough of
+// the player join on its side; the faction's own allies join against it
+// unless they like the player.
+func (dm *DiplomacyManager) declareWar(def config.FactionDef, fs *FactionState) []string {
+	enterWar(fs)
+	messages := []string{fmt.Sprintf("⚔ %s declares war on you! Fight them on the war front or make peace with tribute.", def.Name)}
+	for _, other := range config.BaseFactions() {
+		ofs, ok := dm.factions[other.Key]
+		if !ok || !ofs.Discovered || other.Key == def.Key || ofs.Status == "war" {
+			continue
+		}
+		switch {
+		case ofs.Status == "allied" && ofs.Opinion >= allyJoinOpinion:
+			fs.WarScore++
+			messages = append(messages, fmt.Sprintf("%s joins the war on your side against %s", other.Name, def.Name))
+		case containsKey(def.Allies, other.Key) && ofs.Status != "allied" && ofs.Opinion <= 0:
+			if ofs.Treaty != "" && config.TreatyByKey()[ofs.Treaty].NoRaids {
+				continue
+			}
+			enterWar(ofs)
+			messages = append(messages, fmt.Sprintf("⚔ %s joins %s's war against you!", other.Name, def.Name))
+		}
+	}
+	return messages
+}
+
+// enterWar sets a faction at war, tearing up any treaty and agenda
+func enterWar(fs *FactionState) {
+	fs.Status = "war"
+	fs.Incidents = 0
+	fs.WarScore = 0
+	fs.Treaty = ""
+	fs.TreatyExpires = 0
+	fs.Proposal = ""
+	fs.Request = nil
+}
+
+// containsKey reports whether keys holds key
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// AtWar returns the keys of factions at war with the player, sorted
+func (dm *DiplomacyManager) AtWar() []string {
+	var keys []string
+	for key, fs := range dm.factions {
+		if fs.Discovered && fs.Status == "war" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RecordWarVictory credits a won battle on a faction's war front. Enough
+// victories and the faction sues for peace.
+func (dm *DiplomacyManager) RecordWarVictory(factionKey string, tick int) string {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil || fs.Status != "war" {
+		return ""
+	}
+	fs.WarScore++
+	if fs.WarScore >= surrenderScore {
+		return fmt.Sprintf("%s sues for peace! %s", def.Name, makePeace(def, fs, tick))
+	}
+	return fmt.Sprintf("Victory against %s (war score %d of %d)", def.Name, fs.WarScore, surrenderScore)
+}
+
+// RecordRaidLost charges a raid lost to a faction at war against the
+// player's war score
+func (dm *DiplomacyManager) RecordRaidLost(factionKey string) string {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil || fs.Status != "war" {
+		return ""
+	}
+	fs.WarScore--
+	return fmt.Sprintf("%s gains ground in the war (war score %d)", def.Name, fs.WarScore)
+}
+
+// PeaceTribute returns the gold a faction at war asks for peace: more in
+// later ages, less the more battles the player has won
+func PeaceTribute(warScore int, age string, ageOrder map[string]int) float64 {
+	short := surrenderScore - warScore
+	if short < 1 {
+		return 0
+	}
+	return float64(peaceTributeBase * (ageOrder[age] + 1) * short)
+}
+
+// MakePeace pays a faction at war its tribute and ends the war. Returns the
+// gold spent.
+func (dm *DiplomacyManager) MakePeace(factionKey string, gold float64, age string, ageOrder map[string]int, tick int) (float64, string, error) {
+	def, fs, err := dm.lookup(factionKey)
+	if err != nil {
+		return 0, "", err
+	}
+	if fs.Status != "war" {
+		return 0, "", fmt.Errorf("not at war with %s", def.Name)
+	}
+	tribute := PeaceTribute(fs.WarScore, age, ageOrder)
+	if gold < tribute {
+		return 0, "", fmt.Errorf("%s wants %.0f gold for peace (have: %.0f) — win battles on the war front to lower it", def.Name, tribute, gold)
+	}
+	return tribute, fmt.Sprintf("Paid %s %.0f gold in tribute. %s", def.Name, tribute, makePeace(def, fs, tick)), nil
+}
+
+// makePeace ends a war and binds the faction to a non-aggression pact for
+// its full term
+func makePeace(def config.FactionDef, fs *FactionState, tick int) string {
+	pact := config.TreatyByKey()["non_aggression"]
+	fs.Status = "neutral"
+	fs.Opinion = peaceOpinion
+	fs.WarScore = 0
+	fs.Incidents = 0
+	fs.Treaty = pact.Key
+	fs.TreatyExpires = tick + pact.Duration
+	return fmt.Sprintf("Peace with %s: a %d-tick %s holds the border.", def.Name, pact.Duration, pact.Name)
+}
+
+// WarFront builds the expedition that fights a faction at war, its army and
+// spoils scaled to the current age like a raid's
+func WarFront(factionKey, age string, ageOrder map[string]int) config.ExpeditionDef {
+	def := config.FactionByKey()[factionKey]
+	stage := ageOrder[age] - ageOrder[raidStartAge]
+	if stage < 0 {
+		stage = 0
+	}
+	strength := RaidStrength(stage, 0) * warFrontStrength
+	enemy := RaidEnemy(strength, age, ageOrder)
+	rewards := RaidLoot(strength)
+	if def.Specialty != "" {
+		rewards[def.Specialty] += math.Round(strength)
+	}
+	return config.ExpeditionDef{
+		Name:           "War on " + def.Name,
+		Key:            warFrontPrefix + factionKey,
+		MinAge:         age,
+		SoldiersNeeded: UnitTotal(enemy),
+		Duration:       warFrontTicks,
+		Enemy:          enemy,
+		Rewards:        rewards,
+		Description:    fmt.Sprintf("Strike at the %s. %d victories force them to sue for peace.", def.Name, surrenderScore),
+	}
+}
+
+// warFrontFaction returns the faction an expedition key fights, if it is a
+// war front
+func warFrontFaction(key string) (string, bool) {
+	if !strings.HasPrefix(key, warFrontPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(key, warFrontPrefix), true
+}
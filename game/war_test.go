@@ -0,0 +1,227 @@
+package game
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDiplomacyManager_IncidentsEscalateToWar(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	fs.Opinion = -60
+
+	// The guild is first in BaseFactions, so its border flares every 50 ticks
+	for i := 1; i < warIncidents; i++ {
+		if msgs := dm.tickConflict(i * incidentInterval); len(msgs) != 1 || fs.Incidents != i {
+			t.Fatalf("incident %d: incidents = %d, messages %v", i, fs.Incidents, msgs)
+		}
+	}
+	if msgs := dm.tickConflict(incidentInterval + 1); len(msgs) != 0 {
+		t.Errorf("incident off schedule: %v", msgs)
+	}
+	dm.tickConflict(warIncidents * incidentInterval)
+	if fs.Status != "war" {
+		t.Fatalf("status = %q after %d incidents, want war", fs.Status, warIncidents)
+	}
+	if fs.Opinion != -60-warIncidents*incidentPenalty {
+		t.Errorf("opinion = %d, want %d", fs.Opinion, -60-warIncidents*incidentPenalty)
+	}
+	if _, open := dm.RouteStanding("merchant_guild"); open {
+		t.Error("the guild still trades at war")
+	}
+	if wars := dm.AtWar(); len(wars) != 1 || wars[0] != "merchant_guild" {
+		t.Errorf("at war = %v, want merchant_guild", wars)
+	}
+	if _, err := dm.SetStatus("merchant_guild", "neutral", 0); err == nil {
+		t.Error("set neutral at war, want peace made first")
+	}
+}
+
+func TestDiplomacyManager_TensionsEase(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	fs.Opinion = -50
+
+	dm.tickConflict(incidentInterval)
+	fs.Opinion = -40
+	if msgs := dm.tickConflict(2 * incidentInterval); len(msgs) != 1 || fs.Incidents != 0 {
+		t.Errorf("incidents = %d, messages %v; want eased", fs.Incidents, msgs)
+	}
+
+	// A non-aggression pact keeps the border quiet however low opinion goes
+	fs.Opinion = -90
+	fs.Treaty = "non_aggression"
+	fs.TreatyExpires = 1000
+	dm.tickConflict(3 * incidentInterval)
+	if fs.Incidents != 0 {
+		t.Errorf("incidents = %d under a non-aggression pact, want 0", fs.Incidents)
+	}
+}
+
+func TestDiplomacyManager_AlliesJoinWar(t *testing.T) {
+	dm := NewDiplomacyManager()
+	for _, key := range []string{"merchant_guild", "artisan_league", "tech_consortium"} {
+		dm.Discover(key)
+	}
+	dm.factions["tech_consortium"].Status = "allied"
+	dm.factions["tech_consortium"].Opinion = allyJoinOpinion
+	fs := dm.factions["merchant_guild"]
+	fs.Opinion = -80
+	fs.Incidents = warIncidents - 1
+
+	dm.tickConflict(incidentInterval)
+	if fs.Status != "war" || fs.WarScore != 1 {
+		t.Fatalf("guild status %q, war score %d; want war with the consortium's +1", fs.Status, fs.WarScore)
+	}
+	if got := dm.factions["artisan_league"].Status; got != "war" {
+		t.Errorf("league status = %q, want war alongside its ally", got)
+	}
+	if got := dm.factions["tech_consortium"].Status; got != "allied" {
+		t.Errorf("consortium status = %q, want still allied", got)
+	}
+}
+
+func TestDiplomacyManager_FriendlyAllyStaysOut(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	dm.Discover("artisan_league")
+	dm.factions["artisan_league"].Opinion = 10
+	fs := dm.factions["merchant_guild"]
+	fs.Opinion = -80
+	fs.Incidents = warIncidents - 1
+
+	dm.tickConflict(incidentInterval)
+	if got := dm.factions["artisan_league"].Status; got != "neutral" {
+		t.Errorf("league status = %q, want neutral while it likes the player", got)
+	}
+}
+
+func TestDiplomacyManager_WarEndsInSurrender(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	enterWar(fs)
+
+	if msg := dm.RecordRaidLost("merchant_guild"); msg == "" || fs.WarScore != -1 {
+		t.Errorf("war score = %d after a lost raid, want -1", fs.WarScore)
+	}
+	for i := 0; i < surrenderScore; i++ {
+		dm.RecordWarVictory("merchant_guild", 100)
+	}
+	if fs.Status != "war" {
+		t.Fatalf("surrendered at war score %d", fs.WarScore)
+	}
+	dm.RecordWarVictory("merchant_guild", 100)
+	if fs.Status != "neutral" || fs.Opinion != peaceOpinion {
+		t.Errorf("status %q, opinion %d; want neutral and %d", fs.Status, fs.Opinion, peaceOpinion)
+	}
+	if fs.Treaty != "non_aggression" || fs.TreatyExpires != 400 {
+		t.Errorf("treaty = %q until %d, want non_aggression until 400", fs.Treaty, fs.TreatyExpires)
+	}
+	if msg := dm.RecordWarVictory("merchant_guild", 100); msg != "" {
+		t.Errorf("victory after peace recorded: %q", msg)
+	}
+}
+
+func TestDiplomacyManager_PeaceTribute(t *testing.T) {
+	dm := NewDiplomacyManager()
+	dm.Discover("merchant_guild")
+	fs := dm.factions["merchant_guild"]
+	enterWar(fs)
+	fs.WarScore = 1
+
+	// Colonial is age 7: 50 × 8 ages × 2 points short
+	if _, _, err := dm.MakePeace("merchant_guild", 799, "colonial_age", raidAgeOrder(), 0); err == nil {
+		t.Fatal("made peace without enough gold")
+	}
+	cost, _, err := dm.MakePeace("merchant_guild", 1000, "colonial_age", raidAgeOrder(), 0)
+	if err != nil {
+		t.Fatalf("MakePeace failed: %v", err)
+	}
+	if cost != 800 {
+		t.Errorf("tribute = %v, want 800", cost)
+	}
+	if _, _, err := dm.MakePeace("merchant_guild", 1000, "colonial_age", raidAgeOrder(), 0); err == nil {
+		t.Error("made peace twice")
+	}
+}
+
+func TestRaidManager_WarLeadsRaids(t *testing.T) {
+	rm := NewRaidManager()
+	ageOrder := raidAgeOrder()
+	for tick := 1; tick <= 1000; tick++ {
+		sighted, _ := rm.Tick(tick, "bronze_age", ageOrder, []string{"merchant_guild"}, []string{"merchant_guild"})
+		if sighted != nil {
+			if sighted.Faction != "merchant_guild" {
+				t.Errorf("raid led by %q, want the faction at war", sighted.Faction)
+			}
+			return
+		}
+	}
+	t.Fatal("no raid sighted in 1000 ticks")
+}
+
+func TestEngine_WarFront(t *testing.T) {
+	ge := NewGameEngine()
+	ge.age = "colonial_age"
+	ge.Diplomacy.Discover("merchant_guild")
+	enterWar(ge.Diplomacy.factions["merchant_guild"])
+	ge.refreshWarFronts()
+
+	def, ok := ge.Military.findDef("war_merchant_guild")
+	if !ok {
+		t.Fatal("no war front against the guild")
+	}
+	if UnitTotal(def.Enemy) == 0 || def.Rewards["gold"] <= 0 {
+		t.Errorf("war front enemy %v, rewards %v", def.Enemy, def.Rewards)
+	}
+	found := false
+	for _, exp := range ge.GetState().Military.Expeditions {
+		found = found || exp.Key == def.Key
+	}
+	if !found {
+		t.Error("war front missing from the military snapshot")
+	}
+
+	// Peace closes the front
+	ge.Resources.UnlockResource("gold")
+	ge.Resources.AddStorage("gold", 10000)
+	ge.Resources.Add("gold", 5000)
+	if err := ge.MakePeace("merchant_guild"); err != nil {
+		t.Fatalf("MakePeace failed: %v", err)
+	}
+	if _, ok := ge.Military.findDef("war_merchant_guild"); ok {
+		t.Error("war front still open after peace")
+	}
+}
+
+func TestEngine_SaveLoadWar(t *testing.T) {
+	ge := NewGameEngine()
+	ge.age = "colonial_age"
+	ge.Diplomacy.Discover("merchant_guild")
+	ge.Diplomacy.Discover("artisan_league")
+	enterWar(ge.Diplomacy.factions["merchant_guild"])
+	ge.Diplomacy.factions["merchant_guild"].WarScore = 2
+	ge.Diplomacy.factions["artisan_league"].Incidents = 1
+	if err := ge.SaveGame("test_war"); err != nil {
+		t.Fatalf("SaveGame failed: %v", err)
+	}
+	defer os.Remove("data/saves/test_war.json")
+
+	ge2 := NewGameEngine()
+	if err := ge2.LoadGame("test_war"); err != nil {
+		t.Fatalf("LoadGame failed: %v", err)
+	}
+	guild := ge2.Diplomacy.factions["merchant_guild"]
+	if guild.Status != "war" || guild.WarScore != 2 {
+		t.Errorf("guild status %q, war score %d; want war and 2", guild.Status, guild.WarScore)
+	}
+	if got := ge2.Diplomacy.factions["artisan_league"].Incidents; got != 1 {
+		t.Errorf("league incidents = %d, want 1", got)
+	}
+	if _, ok := ge2.Military.findDef("war_merchant_guild"); !ok {
+		t.Error("war front not restored on load")
+	}
+}
@@ -0,0 +1,265 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/user/ageforge/config"
+)
+
+// A World is the land a game is played on. Everything about it — where
+// the river runs, how much forest, mountain and desert there is — grows
+// from one seed, so it is the same every time the game is loaded. The mix
+// of land around the town is its biome: each terrain's share of it scales
+// that biome's production bonuses and event weights.
+
+// defaultWorldSeed is the world of saves made before worlds were seeded
+const defaultWorldSeed uint64 = 0x5eed0a6e
+
+// townSquare is the radius of open ground kept around the town centre
+const townSquare = 2
+
+// mappedRadius is how far from the town centre, in tiles, terrain is
+// generated up front; placement looks terrain up thousands of times, and
+// settlements rarely spread beyond it
+const mappedRadius = 64
+
+// World is the terrain of a game, generated from its seed
+type World struct {
+	seed uint64
+
+	riverAt    int     // tiles from the town centre to the river
+	riverWidth int     // tiles
+	riverAmp   float64 // how far it meanders, in tiles
+	riverBend  float64 // tiles per radian of meander
+	riverPhase float64
+	sideways   bool // the river runs east–west
+
+	// Noise levels above which land is hill, desert or forest; the lower
+	// the level, the more of it there is
+	hillLevel, desertLevel, forestLevel float64
+
+	mix    map[string]float64 // terrain -> share of the land within config.BiomeRadius
+	events map[string]float64 // event key -> weight factor
+
+	// Terrain of every tile within mappedRadius, row by row, as indexes
+	// into terrains; read-only once generated
+	terrains []string
+	mapped   []uint8
+}
+
+// newWorldSeed picks the seed of a new game's world
+func newWorldSeed() uint64 {
+	return rand.Uint64()
+}
+
+// NewWorld generates the world grown from a seed
+func NewWorld(seed uint64) *World {
+	param := func(i int) float64 { return tileNoise(seed^0x3a7c11, i, 0) }
+	w := &World{
+		seed:        seed,
+		riverAt:     5 + int(param(0)*6),
+		riverWidth:  1 + int(param(1)*3),
+		riverAmp:    1.5 + param(2)*3,
+		riverBend:   4 + param(3)*4,
+		riverPhase:  param(4) * 2 * math.Pi,
+		sideways:    param(5) < 0.5,
+		hillLevel:   0.60 + param(6)*0.15,
+		desertLevel: 0.55 + param(7)*0.35,
+		forestLevel: 0.55 + param(8)*0.17,
+	}
+	if param(9) < 0.5 {
+		w.riverAt = -w.riverAt
+	}
+	w.terrains = config.Terrains()
+	index := make(map[string]uint8, len(w.terrains))
+	for i, terrain := range w.terrains {
+		index[terrain] = uint8(i)
+	}
+	side := 2*mappedRadius + 1
+	w.mapped = make([]uint8, 0, side*side)
+	for y := -mappedRadius; y <= mappedRadius; y++ {
+		for x := -mappedRadius; x <= mappedRadius; x++ {
+			w.mapped = append(w.mapped, index[w.generate(x, y)])
+		}
+	}
+	w.survey()
+	return w
+}
+
+// Seed returns the seed the world grew from
+func (w *World) Seed() uint64 {
+	return w.seed
+}
+
+// TerrainAt returns the terrain of a tile
+func (w *World) TerrainAt(x, y int) string {
+	if x < -mappedRadius || x > mappedRadius || y < -mappedRadius || y > mappedRadius {
+		return w.generate(x, y)
+	}
+	side := 2*mappedRadius + 1
+	return w.terrains[w.mapped[(y+mappedRadius)*side+x+mappedRadius]]
+}
+
+// generate works out the terrain of a tile: a river meandering past the
+// town, patches of hills, desert and forest, and grass elsewhere
+func (w *World) generate(x, y int) string {
+	if x*x+y*y <= townSquare*townSquare {
+		return config.TerrainGrass
+	}
+	along, across := y, x
+	if w.sideways {
+		along, across = x, y
+	}
+	river := w.riverAt + int(math.Round(w.riverAmp*math.Sin(float64(along)/w.riverBend+w.riverPhase)))
+	if across >= river && across < river+w.riverWidth {
+		return config.TerrainWater
+	}
+	if smoothNoise(w.seed^0x41115, x, y, 7) > w.hillLevel {
+		return config.TerrainHill
+	}
+	if smoothNoise(w.seed^0xd5e27, x, y, 11) > w.desertLevel {
+		return config.TerrainDesert
+	}
+	if smoothNoise(w.seed^0xf0e57, x, y, 5) > w.forestLevel {
+		return config.TerrainForest
+	}
+	return config.TerrainGrass
+}
+
+// survey measures the biome mix and the event weights it gives
+func (w *World) survey() {
+	r := config.BiomeRadius
+	counts := make(map[string]int)
+	total := 0
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				counts[w.TerrainAt(x, y)]++
+				total++
+			}
+		}
+	}
+	w.mix = make(map[string]float64, len(counts))
+	for terrain, n := range counts {
+		w.mix[terrain] = float64(n) / float64(total)
+	}
+	w.events = biomeEventWeights(w.mix)
+}
+
+// biomeEventWeights returns the weight factor a biome mix gives each event
+// it affects. Events needing a terrain the world lacks get 0.
+func biomeEventWeights(mix map[string]float64) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, b := range config.Biomes() {
+		for key, v := range b.Events {
+			if _, ok := weights[key]; !ok {
+				weights[key] = 1
+			}
+			weights[key] += v * mix[b.Terrain]
+		}
+	}
+	for _, b := range config.Biomes() {
+		for _, key := range b.Only {
+			if mix[b.Terrain] == 0 {
+				weights[key] = 0
+			} else if _, ok := weights[key]; !ok {
+				weights[key] = 1
+			}
+		}
+	}
+	for key, v := range weights {
+		weights[key] = math.Max(0, v)
+	}
+	return weights
+}
+
+// Share returns a terrain's share of the land around the town
+func (w *World) Share(terrain string) float64 {
+	return w.mix[terrain]
+}
+
+// EventWeight returns the factor the world applies to an event's weight:
+// 1 unless its biome makes the event likelier, rarer or impossible
+func (w *World) EventWeight(key string) float64 {
+	if v, ok := w.events[key]; ok {
+		return v
+	}
+	return 1
+}
+
+// Modifiers returns the production bonuses of the world's biomes, each
+// scaled by its terrain's share of the land
+func (w *World) Modifiers() []Modifier {
+	var mods []Modifier
+	for _, b := range config.Biomes() {
+		share := w.mix[b.Terrain]
+		if share == 0 {
+			continue
+		}
+		name := fmt.Sprintf("%s %.0f%%", b.Name, share*100)
+		resources := mapKeys(b.Bonuses)
+		sort.Strings(resources)
+		for _, res := range resources {
+			mods = append(mods, Modifier{
+				Stat: res + "_rate", Layer: LayerMultiplicative, Value: b.Bonuses[res] * share,
+				Source: SourceBiome, Key: b.Terrain, Name: name,
+			})
+		}
+	}
+	return mods
+}
+
+// Biomes returns the biome mix, largest share first
+func (w *World) Biomes() []BiomeShare {
+	var out []BiomeShare
+	for _, b := range config.Biomes() {
+		if share := w.mix[b.Terrain]; share > 0 {
+			out = append(out, BiomeShare{Terrain: b.Terrain, Name: b.Name, Share: share})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Share > out[j].Share })
+	return out
+}
+
+// smoothNoise interpolates lattice noise placed every cell tiles, giving
+// values in [0,1) that drift smoothly from tile to tile
+func smoothNoise(seed uint64, x, y, cell int) float64 {
+	cx, cy := floorDiv(x, cell), floorDiv(y, cell)
+	fx := float64(x-cx*cell) / float64(cell)
+	fy := float64(y-cy*cell) / float64(cell)
+	fx, fy = fx*fx*(3-2*fx), fy*fy*(3-2*fy)
+	top := lerp(tileNoise(seed, cx, cy), tileNoise(seed, cx+1, cy), fx)
+	bottom := lerp(tileNoise(seed, cx, cy+1), tileNoise(seed, cx+1, cy+1), fx)
+	return lerp(top, bottom, fy)
+}
+
+// tileNoise hashes a seed and a lattice point to a value in [0,1)
+func tileNoise(seed uint64, x, y int) float64 {
+	h := seed ^ uint64(int64(x))*0x9e3779b97f4a7c15
+	h = mix64(h) ^ uint64(int64(y))*0xc2b2ae3d27d4eb4f
+	return float64(mix64(h)>>11) / (1 << 53)
+}
+
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
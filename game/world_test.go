@@ -0,0 +1,86 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	"github.com/user/ageforge/config"
+)
+
+func TestWorld_SeedDecidesTheLand(t *testing.T) {
+	a, b := NewWorld(42), NewWorld(42)
+	other := NewWorld(43)
+	differs := false
+	for y := -20; y <= 20; y++ {
+		for x := -20; x <= 20; x++ {
+			if a.TerrainAt(x, y) != b.TerrainAt(x, y) {
+				t.Fatalf("tile (%d,%d) is %s and %s in two worlds of one seed", x, y, a.TerrainAt(x, y), b.TerrainAt(x, y))
+			}
+			if a.TerrainAt(x, y) != other.TerrainAt(x, y) {
+				differs = true
+			}
+		}
+	}
+	if !differs {
+		t.Error("worlds of different seeds are identical")
+	}
+
+	total := 0.0
+	for _, share := range a.mix {
+		total += share
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("biome shares sum to %v, want 1", total)
+	}
+	if a.Share(config.TerrainWater) == 0 {
+		t.Error("no river near the town")
+	}
+}
+
+func TestWorld_BiomeBonusesScaleWithShare(t *testing.T) {
+	w := NewWorld(defaultWorldSeed)
+	biomes := config.BiomeByTerrain()
+	for _, m := range w.Modifiers() {
+		res := m.Stat[:len(m.Stat)-len("_rate")]
+		want := biomes[m.Key].Bonuses[res] * w.Share(m.Key)
+		if m.Layer != LayerMultiplicative || m.Source != SourceBiome || math.Abs(m.Value-want) > 1e-9 {
+			t.Errorf("%+v, want a %v biome multiplier", m, want)
+		}
+	}
+}
+
+func TestWorld_BiomeEventWeights(t *testing.T) {
+	plains := biomeEventWeights(map[string]float64{config.TerrainGrass: 1})
+	if plains["pirate_attack"] != 0 || plains["mine_collapse"] != 0 {
+		t.Errorf("pirates %v, mine collapses %v; want neither without a river or mountains",
+			plains["pirate_attack"], plains["mine_collapse"])
+	}
+	if plains["bountiful_harvest"] != 2 {
+		t.Errorf("harvest weight ×%v on plains, want ×2", plains["bountiful_harvest"])
+	}
+
+	// Half river, half desert: −4 and +4 drought at full coverage cancel out
+	mixed := biomeEventWeights(map[string]float64{config.TerrainWater: 0.5, config.TerrainDesert: 0.5})
+	if mixed["drought"] != 1 || mixed["pirate_attack"] != 1 || mixed["storm"] != 2.5 {
+		t.Errorf("drought ×%v, pirates ×%v, storms ×%v; want ×1, ×1, ×2.5",
+			mixed["drought"], mixed["pirate_attack"], mixed["storm"])
+	}
+}
+
+func TestEventManager_WorldWeights(t *testing.T) {
+	ageOrder := map[string]int{"primitive_age": 0}
+	onlyStorms := func(key string) float64 {
+		if key == "storm" {
+			return 1
+		}
+		return 0
+	}
+	for i := 0; i < 20; i++ {
+		em := NewEventManager()
+		em.nextEventTick = 0
+		triggered, _ := em.Tick(1000, "primitive_age", ageOrder, onlyStorms)
+		if len(triggered) != 1 || triggered[0].Key != "storm" {
+			t.Fatalf("triggered %v, want only storms to fire", triggered)
+		}
+	}
+}
@@ -11,6 +11,16 @@ import (
 )
 
 func main() {
+	// Offline subcommands run without the TUI
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "balance":
+			os.Exit(runBalance(os.Args[2:]))
+		case "wiki":
+			os.Exit(runWiki(os.Args[2:]))
+		}
+	}
+
 	// Create game engine
 	engine := game.NewGameEngine()
 
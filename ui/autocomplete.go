@@ -8,14 +8,6 @@ import (
 	"github.com/user/ageforge/game"
 )
 
-// commands is the full list of command names for autocomplete
-var commands = []string{
-	"gather", "build", "recruit", "assign", "unassign",
-	"research", "expedition", "prestige",
-	"trade", "diplomacy", "upgrade",
-	"rates", "status", "speed", "save", "saves", "load", "help", "quit",
-}
-
 // NewAutoCompleter returns an autocomplete function for the command input field.
 // It uses live game state to provide context-aware suggestions.
 func NewAutoCompleter(engine *game.GameEngine) func(string) []string {
@@ -31,7 +23,7 @@ func NewAutoCompleter(engine *game.GameEngine) func(string) []string {
 
 		if len(parts) == 1 && !hasTrailingSpace {
 			// Partial command name
-			return filterPrefix(commands, parts[0], "")
+			return filterPrefix(commandNames(), parts[0], "")
 		}
 
 		cmd := strings.ToLower(parts[0])
@@ -62,119 +54,125 @@ func NewAutoCompleter(engine *game.GameEngine) func(string) []string {
 // completed contains fully-typed argument words, partial is what's being typed,
 // and prefix is the string to prepend to each suggestion.
 func suggestArg(cmd string, completed []string, partial string, prefix string, engine *game.GameEngine) []string {
+	spec, ok := lookupCommand(cmd)
+	if !ok {
+		return nil
+	}
 	state := engine.GetState()
+	if spec.complete != nil {
+		return filterPrefix(spec.complete(completed, state, engine), partial, prefix)
+	}
+	if len(completed) >= len(spec.args) {
+		return nil
+	}
+	return filterPrefix(argCandidates(spec.args[len(completed)], state, engine), partial, prefix)
+}
 
-	switch cmd {
-	case "gather", "g":
-		return filterPrefix(unlockedResourceKeys(state), partial, prefix)
-
-	case "build", "b":
-		if len(completed) == 0 {
-			return filterPrefix(unlockedBuildingKeys(state), partial, prefix)
-		}
-		if len(completed) == 1 {
-			return filterPrefix([]string{"max"}, partial, prefix)
-		}
-
-	case "recruit", "r":
-		if len(completed) == 0 {
-			return filterPrefix(unlockedVillagerTypes(state), partial, prefix)
-		}
-		if len(completed) == 1 {
-			return filterPrefix([]string{"max"}, partial, prefix)
-		}
+// argCandidates lists the values an argument accepts in the current state,
+// followed by its literal choices
+func argCandidates(arg commandArg, state game.GameState, engine *game.GameEngine) []string {
+	var keys []string
+	switch arg.kind {
+	case argResource:
+		keys = unlockedResourceKeys(state)
+	case argBuilding:
+		keys = unlockedBuildingKeys(state)
+	case argVillager:
+		keys = unlockedVillagerTypes(state)
+	case argTech:
+		keys = availableTechKeys(state)
+	case argExpedition:
+		keys = availableExpeditionKeys(state)
+	case argUnit:
+		keys = trainableUnitKeys(state)
+	case argFaction:
+		keys = discoveredFactionKeys(state)
+	case argPrestigeUpgrade:
+		keys = prestigeUpgradeKeys(state)
+	case argUpgrade:
+		keys = upgradeableBuildingKeys(engine)
+	case argSave:
+		keys = saveNames()
+	case argSpeed:
+		keys = availableSpeedOptions(engine)
+	case argCommand:
+		keys = commandNames()
+	}
+	return append(keys, arg.choices...)
+}
 
-	case "assign", "a":
-		if len(completed) == 0 {
-			return filterPrefix(unlockedVillagerTypes(state), partial, prefix)
-		}
-		if len(completed) == 1 {
-			return filterPrefix(unlockedResourceKeys(state), partial, prefix)
-		}
-		return filterPrefix([]string{"all"}, partial, prefix)
+// completeUnassign only offers resources the villager type is assigned to
+func completeUnassign(completed []string, state game.GameState, _ *game.GameEngine) []string {
+	switch len(completed) {
+	case 0:
+		return unlockedVillagerTypes(state)
+	case 1:
+		return assignedResources(state, strings.ToLower(completed[0]))
+	}
+	return []string{"all"}
+}
 
-	case "unassign", "u":
-		if len(completed) == 0 {
-			return filterPrefix(unlockedVillagerTypes(state), partial, prefix)
-		}
+// completeTrade handles both exchanges and the "trade route" subcommands
+func completeTrade(completed []string, state game.GameState, _ *game.GameEngine) []string {
+	if len(completed) == 0 {
+		return append(unlockedResourceKeys(state), "list", "route")
+	}
+	if strings.ToLower(completed[0]) == "route" {
 		if len(completed) == 1 {
-			vType := strings.ToLower(completed[0])
-			return filterPrefix(assignedResources(state, vType), partial, prefix)
-		}
-		return filterPrefix([]string{"all"}, partial, prefix)
-
-	case "research", "res":
-		keys := availableTechKeys(state)
-		keys = append(keys, "list", "cancel")
-		return filterPrefix(keys, partial, prefix)
-
-	case "expedition", "exp":
-		keys := availableExpeditionKeys(state)
-		keys = append(keys, "list")
-		return filterPrefix(keys, partial, prefix)
-
-	case "prestige":
-		if len(completed) == 0 {
-			return filterPrefix([]string{"confirm", "shop", "buy"}, partial, prefix)
-		}
-		if strings.ToLower(completed[0]) == "buy" {
-			return filterPrefix(prestigeUpgradeKeys(state), partial, prefix)
-		}
-		if strings.ToLower(completed[0]) == "confirm" {
-			return filterPrefix([]string{"yes"}, partial, prefix)
+			return []string{"list", "start", "stop"}
 		}
-
-	case "trade", "t":
-		if len(completed) == 0 {
-			// First arg: "list", "route", or resource name for exchange
-			keys := unlockedResourceKeys(state)
-			keys = append(keys, "list", "route")
-			return filterPrefix(keys, partial, prefix)
-		}
-		if strings.ToLower(completed[0]) == "route" {
-			if len(completed) == 1 {
-				return filterPrefix([]string{"list", "start", "stop"}, partial, prefix)
-			}
-			if len(completed) == 2 {
-				sub := strings.ToLower(completed[1])
-				if sub == "start" {
-					return filterPrefix(availableTradeRouteKeys(state), partial, prefix)
-				}
-				if sub == "stop" {
-					return filterPrefix(activeTradeRouteKeys(state), partial, prefix)
-				}
+		if len(completed) == 2 {
+			switch strings.ToLower(completed[1]) {
+			case "start":
+				return availableTradeRouteKeys(state)
+			case "stop":
+				return activeTradeRouteKeys(state)
 			}
 		}
-		if len(completed) == 1 {
-			// Second arg for exchange: target resource
-			return filterPrefix(unlockedResourceKeys(state), partial, prefix)
-		}
+		return nil
+	}
+	if len(completed) == 1 {
+		return unlockedResourceKeys(state)
+	}
+	return nil
+}
 
-	case "diplomacy", "dip":
-		if len(completed) == 0 {
-			return filterPrefix([]string{"ally", "rival", "embargo", "gift", "neutral"}, partial, prefix)
+// completeExpedition offers holding campaigns after "advance" and "retreat",
+// and unit:count pairs for the units at home after an expedition key
+func completeExpedition(completed []string, state game.GameState, _ *game.GameEngine) []string {
+	if len(completed) == 0 {
+		return append(availableExpeditionKeys(state), "list", "info", "best", "advance", "retreat")
+	}
+	switch strings.ToLower(completed[0]) {
+	case "advance", "retreat":
+		if len(completed) == 1 {
+			return holdingExpeditionKeys(state)
 		}
+		return nil
+	case "info":
 		if len(completed) == 1 {
-			return filterPrefix(discoveredFactionKeys(state), partial, prefix)
+			return availableExpeditionKeys(state)
 		}
+		return homeUnitCounts(state, completed[2:])
+	case "list", "best":
+		return nil
+	}
+	return homeUnitCounts(state, completed[1:])
+}
 
-	case "upgrade":
-		if len(completed) == 0 {
-			keys := upgradeableBuildingKeys(engine)
-			keys = append(keys, "all")
-			return filterPrefix(keys, partial, prefix)
+// completePrestige completes upgrade keys after "buy" and "yes" after "confirm"
+func completePrestige(completed []string, state game.GameState, _ *game.GameEngine) []string {
+	if len(completed) == 0 {
+		return []string{"confirm", "shop", "buy"}
+	}
+	if len(completed) == 1 {
+		switch strings.ToLower(completed[0]) {
+		case "buy":
+			return prestigeUpgradeKeys(state)
+		case "confirm":
+			return []string{"yes"}
 		}
-
-	case "speed":
-		return filterPrefix(availableSpeedOptions(engine), partial, prefix)
-
-	case "save":
-		return filterPrefix(saveNames(), partial, prefix)
-
-	case "load":
-		return filterPrefix(saveNames(), partial, prefix)
 	}
-
 	return nil
 }
 
@@ -262,6 +260,45 @@ func availableExpeditionKeys(state game.GameState) []string {
 	return keys
 }
 
+func trainableUnitKeys(state game.GameState) []string {
+	var keys []string
+	for _, u := range state.Military.Units {
+		if u.Unlocked && len(u.TrainCost) > 0 {
+			keys = append(keys, u.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// homeUnitCounts offers unit:count for each unit at home not already picked
+func homeUnitCounts(state game.GameState, picked []string) []string {
+	used := make(map[string]bool)
+	for _, p := range picked {
+		unit, _, _ := strings.Cut(strings.ToLower(p), ":")
+		used[unit] = true
+	}
+	var out []string
+	for _, u := range state.Military.Units {
+		if u.Home > 0 && !used[u.Key] {
+			out = append(out, fmt.Sprintf("%s:%d", u.Key, u.Home))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func holdingExpeditionKeys(state game.GameState) []string {
+	var keys []string
+	for _, exp := range state.Military.ActiveExpeditions {
+		if exp.Holding {
+			keys = append(keys, exp.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func prestigeUpgradeKeys(state game.GameState) []string {
 	var keys []string
 	for key, u := range state.Prestige.Upgrades {
@@ -0,0 +1,593 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/ageforge/config"
+	"github.com/user/ageforge/game"
+)
+
+// argKind is the kind of value a command argument accepts. It drives
+// completion: each kind knows how to list its candidates from live state.
+type argKind int
+
+const (
+	argWord            argKind = iota // only the fixed choices
+	argResource                       // unlocked resource key
+	argBuilding                       // unlocked building key
+	argVillager                       // unlocked villager type
+	argTech                           // technology available to research
+	argExpedition                     // expedition key
+	argUnit                           // military unit that can be trained
+	argFaction                        // discovered faction key
+	argPrestigeUpgrade                // prestige upgrade that isn't maxed
+	argUpgrade                        // building with an available upgrade
+	argSave                           // save file name
+	argSpeed                          // unlocked speed multiplier
+	argCommand                        // command name
+	argCount                          // positive integer, or one of the choices (all/max)
+	argAmount                         // positive number
+)
+
+// commandArg describes one positional argument of a command
+type commandArg struct {
+	name    string
+	desc    string
+	kind    argKind
+	choices []string // literal words accepted in this position (list, all, max, ...)
+}
+
+// commandSpec declares a command: how it's invoked, documented and completed
+type commandSpec struct {
+	name     string
+	aliases  []string
+	usage    []string // one line per form, e.g. "build <building> [count|max]"
+	summary  string
+	args     []commandArg
+	examples []string
+	handler  func(args []string, engine *game.GameEngine) CommandResult
+	// complete overrides schema-driven completion for commands whose later
+	// arguments depend on earlier ones. It returns unfiltered candidates for
+	// the argument after completed.
+	complete func(completed []string, state game.GameState, engine *game.GameEngine) []string
+	// context returns live, state-dependent hints shown by "help <name>"
+	context func(state game.GameState) []string
+}
+
+// commandRegistry lists every command in the order shown by "help". It is
+// filled in init because the help handler reads the registry itself.
+var commandRegistry []commandSpec
+
+func init() {
+	commandRegistry = []commandSpec{
+		{
+			name:    "gather",
+			aliases: []string{"g"},
+			usage:   []string{"gather <food|wood|stone> [amount]"},
+			summary: "Hand-gather resources (max 5 per command)",
+			args: []commandArg{
+				{name: "resource", desc: "resource to gather", kind: argWord, choices: []string{"food", "wood", "stone"}},
+				{name: "amount", desc: "how much to gather, 1-5 (default 3)", kind: argAmount},
+			},
+			examples: []string{"gather food", "g wood 5"},
+			handler:  cmdGather,
+		},
+		{
+			name:    "build",
+			aliases: []string{"b"},
+			usage:   []string{"build", "build <building> [count|max]"},
+			summary: "Build structure(s), or list buildings with no argument",
+			args: []commandArg{
+				{name: "building", desc: "building key, e.g. hut or farm", kind: argBuilding},
+				{name: "count", desc: "how many to build (default 1), or max for as many as you can afford", kind: argCount, choices: []string{"max"}},
+			},
+			examples: []string{"build farm", "build hut 3", "b lumber_mill max"},
+			handler:  cmdBuild,
+			context:  helpBuildContext,
+		},
+		{
+			name:    "recruit",
+			aliases: []string{"r"},
+			usage:   []string{"recruit <type> [count|max]"},
+			summary: "Recruit villagers (costs food)",
+			args: []commandArg{
+				{name: "type", desc: "villager type, e.g. worker or scholar; soldiers join the training queue as infantry", kind: argVillager},
+				{name: "count", desc: "how many to recruit (default 1), or max for as many as food and housing allow", kind: argCount, choices: []string{"max"}},
+			},
+			examples: []string{"recruit worker", "r worker 5", "recruit scholar max"},
+			handler:  cmdRecruit,
+		},
+		{
+			name:    "assign",
+			aliases: []string{"a"},
+			usage:   []string{"assign <type> <resource> [count|all]"},
+			summary: "Assign idle villagers to gather a resource",
+			args: []commandArg{
+				{name: "type", desc: "villager type", kind: argVillager},
+				{name: "resource", desc: "resource to gather", kind: argResource},
+				{name: "count", desc: "how many to assign (default 1), or all idle villagers of that type", kind: argCount, choices: []string{"all"}},
+			},
+			examples: []string{"assign worker food 3", "a worker wood all"},
+			handler:  cmdAssign,
+		},
+		{
+			name:    "unassign",
+			aliases: []string{"u"},
+			usage:   []string{"unassign <type> <resource> [count|all]"},
+			summary: "Return assigned villagers to idle",
+			args: []commandArg{
+				{name: "type", desc: "villager type", kind: argVillager},
+				{name: "resource", desc: "resource they are gathering", kind: argResource},
+				{name: "count", desc: "how many to unassign (default 1), or all of them", kind: argCount, choices: []string{"all"}},
+			},
+			examples: []string{"unassign worker food 2", "u worker stone all"},
+			handler:  cmdUnassign,
+			complete: completeUnassign,
+		},
+		{
+			name:    "research",
+			aliases: []string{"res"},
+			usage:   []string{"research <tech_key>", "research list", "research cancel"},
+			summary: "Research a technology, list available techs, or cancel",
+			args: []commandArg{
+				{name: "tech_key", desc: "technology key (spaces become underscores), list, or cancel", kind: argTech, choices: []string{"list", "cancel"}},
+			},
+			examples: []string{"research agriculture", "res list", "research cancel"},
+			handler:  cmdResearch,
+			context:  helpResearchContext,
+		},
+		{
+			name:    "expedition",
+			aliases: []string{"exp"},
+			usage: []string{
+				"expedition <key> [unit:count ...]", "expedition list",
+				"expedition info <key> [unit:count ...]", "expedition best",
+				"expedition advance <key>", "expedition retreat <key>",
+			},
+			summary: "Launch and forecast expeditions, and lead campaigns between stages",
+			args: []commandArg{
+				{name: "key", desc: "expedition key; list shows expeditions, info forecasts one, best ranks them, advance/retreat orders a holding campaign", kind: argExpedition, choices: []string{"list", "info", "best", "advance", "retreat"}},
+				{name: "unit:count", desc: "units to send, e.g. archers:3 (default: the strongest soldiers at home, as many as needed); info accepts units you don't have yet"},
+			},
+			examples: []string{"expedition scout_ruins", "exp raid_bandits infantry:3 archers:2", "exp info trade_escort cavalry:4", "exp best", "exp advance frontier_campaign"},
+			handler:  cmdExpedition,
+			complete: completeExpedition,
+			context:  helpExpeditionContext,
+		},
+		{
+			name:    "train",
+			usage:   []string{"train", "train <unit> [count|max]"},
+			summary: "Queue idle infantry to train as other unit types, or list units with no argument",
+			args: []commandArg{
+				{name: "unit", desc: "unit key, e.g. archers or cavalry", kind: argUnit},
+				{name: "count", desc: "how many to train (default 1), or max for as many as you can afford; they leave the home army until trained", kind: argCount, choices: []string{"max"}},
+			},
+			examples: []string{"train", "train archers 3", "train cavalry max"},
+			handler:  cmdTrain,
+			context:  helpTrainContext,
+		},
+		{
+			name:    "trade",
+			aliases: []string{"t"},
+			usage: []string{
+				"trade <from> <to> <amount>", "trade list",
+				"trade route list", "trade route start <key>", "trade route stop <key>",
+			},
+			summary: "Exchange resources at the market and manage trade routes",
+			args: []commandArg{
+				{name: "from", desc: "resource to give; list shows rates, route manages trade routes", kind: argResource, choices: []string{"list", "route"}},
+				{name: "to", desc: "resource to receive", kind: argResource},
+				{name: "amount", desc: "how much of the from resource to give", kind: argAmount},
+			},
+			examples: []string{"trade wood gold 100", "t list", "trade route start local_barter"},
+			handler:  cmdTrade,
+			complete: completeTrade,
+			context:  helpTradeContext,
+		},
+		{
+			name:    "diplomacy",
+			aliases: []string{"dip"},
+			usage: []string{
+				"diplomacy", "diplomacy ally <faction>", "diplomacy rival <faction>",
+				"diplomacy embargo <faction>", "diplomacy gift <faction>", "diplomacy neutral <faction>",
+				"diplomacy accept <faction>", "diplomacy decline <faction>", "diplomacy break <faction>",
+				"diplomacy fulfill <faction>", "diplomacy refuse <faction>", "diplomacy peace <faction>",
+			},
+			summary: "Show faction status, change relations, answer treaties and requests, and make peace",
+			args: []commandArg{
+				{name: "action", desc: "ally (costs gold), rival, embargo, gift (200 gold, +11 to +19 opinion) or neutral; accept/decline a proposed treaty, break one in force; fulfill/refuse a request; peace pays tribute to end a war", kind: argWord,
+					choices: []string{"ally", "rival", "embargo", "gift", "neutral", "accept", "decline", "break", "fulfill", "refuse", "peace"}},
+				{name: "faction", desc: "faction key", kind: argFaction},
+			},
+			examples: []string{"diplomacy", "dip gift merchant_guild", "dip accept merchant_guild", "dip fulfill artisan_league"},
+			handler:  cmdDiplomacy,
+		},
+		{
+			name:    "prestige",
+			usage:   []string{"prestige", "prestige confirm yes", "prestige shop", "prestige buy <key>"},
+			summary: "View prestige status, reset for bonuses, or buy upgrades",
+			args: []commandArg{
+				{name: "action", desc: "confirm yes to reset, shop to list upgrades, buy to purchase one", kind: argWord,
+					choices: []string{"confirm", "shop", "buy"}},
+				{name: "key", desc: "prestige upgrade key (for buy)", kind: argPrestigeUpgrade},
+			},
+			examples: []string{"prestige", "prestige buy gather_boost"},
+			handler:  cmdPrestige,
+			complete: completePrestige,
+		},
+		{
+			name:    "upgrade",
+			usage:   []string{"upgrade", "upgrade <building>", "upgrade all"},
+			summary: "List building upgrades, or upgrade a building type",
+			args: []commandArg{
+				{name: "building", desc: "building to upgrade (all of that type), or all for everything affordable", kind: argUpgrade,
+					choices: []string{"all"}},
+			},
+			examples: []string{"upgrade", "upgrade hut", "upgrade all"},
+			handler:  cmdUpgrade,
+		},
+		{
+			name:    "demolish",
+			usage:   []string{"demolish <building> [count|all]"},
+			summary: fmt.Sprintf("Tear down buildings for a %.0f%% refund of what the last ones cost", config.DemolishRefund*100),
+			args: []commandArg{
+				{name: "building", desc: "building key; switched-off ones go first, wonders can't be demolished", kind: argBuilding},
+				{name: "count", desc: "how many to demolish (default 1), or all of them", kind: argCount, choices: []string{"all"}},
+			},
+			examples: []string{"demolish hut", "demolish hut 10", "demolish stash all"},
+			handler:  cmdDemolish,
+		},
+		{
+			name:    "toggle",
+			usage:   []string{"toggle <building> [n]"},
+			summary: "Switch buildings off to save their upkeep and inputs, or back on",
+			args: []commandArg{
+				{name: "building", desc: "building key", kind: argBuilding},
+				{name: "n", desc: "how many to leave switched off, 0 for none (default: all off, or all back on if any are off)", kind: argCount},
+			},
+			examples: []string{"toggle smithy", "toggle power_grid 2", "toggle power_grid 0"},
+			handler:  cmdToggle,
+			context:  helpToggleContext,
+		},
+		{
+			name:    "rates",
+			usage:   []string{"rates"},
+			summary: "Show resource rate breakdown",
+			handler: func(_ []string, engine *game.GameEngine) CommandResult { return cmdRates(engine) },
+		},
+		{
+			name:    "why",
+			usage:   []string{"why <resource> [ticks]"},
+			summary: "Explain a resource's rate and storage cap, and what changed",
+			args: []commandArg{
+				{name: "resource", desc: "resource to explain", kind: argResource},
+				{name: "ticks", desc: fmt.Sprintf("how many ticks back to compare, up to %d (default %d)", game.MaxWhyTicks, game.DefaultWhyTicks), kind: argCount},
+			},
+			examples: []string{"why gold", "why food 50"},
+			handler:  cmdWhy,
+		},
+		{
+			name:    "status",
+			aliases: []string{"s"},
+			usage:   []string{"status"},
+			summary: "Show detailed status",
+			handler: func(_ []string, engine *game.GameEngine) CommandResult { return cmdStatus(engine) },
+		},
+		{
+			name:     "speed",
+			usage:    []string{"speed [multiplier]"},
+			summary:  "Set game speed (unlocks per wonder built)",
+			args:     []commandArg{{name: "multiplier", desc: "1.0, 1.5, 2.0, ... up to your unlocked maximum", kind: argSpeed}},
+			examples: []string{"speed 1.5"},
+			handler:  cmdSpeed,
+		},
+		{
+			name:    "dump",
+			aliases: []string{"exportlogs"},
+			usage:   []string{"dump"},
+			summary: "Export logs to file for debugging",
+			handler: cmdDump,
+		},
+		{
+			name:    "map",
+			usage:   []string{"map export [--gif|--png-seq]"},
+			summary: "Export a timelapse of your settlement across the ages",
+			args: []commandArg{
+				{name: "action", desc: "export renders every recorded frame of this run", kind: argWord, choices: []string{"export"}},
+				{name: "format", desc: "--gif for one animated GIF (default), --png-seq for a numbered PNG per frame", kind: argWord, choices: []string{"--gif", "--png-seq"}},
+			},
+			examples: []string{"map export", "map export --png-seq"},
+			handler:  cmdMap,
+		},
+		{
+			name:     "save",
+			usage:    []string{"save [name]", "save list"},
+			summary:  "Save game (default: autosave)",
+			args:     []commandArg{{name: "name", desc: "save slot name", kind: argSave}},
+			examples: []string{"save", "save before_war"},
+			handler: func(args []string, engine *game.GameEngine) CommandResult {
+				if len(args) > 0 && args[0] == "list" {
+					return cmdSaveList()
+				}
+				return cmdSave(args, engine)
+			},
+		},
+		{
+			name:     "load",
+			usage:    []string{"load [name]"},
+			summary:  "Load game (default: autosave)",
+			args:     []commandArg{{name: "name", desc: "save slot name", kind: argSave}},
+			examples: []string{"load before_war"},
+			handler:  cmdLoad,
+		},
+		{
+			name:    "saves",
+			usage:   []string{"saves"},
+			summary: "List all save files",
+			handler: func(_ []string, _ *game.GameEngine) CommandResult { return cmdSaveList() },
+		},
+		{
+			name:     "help",
+			aliases:  []string{"h", "?"},
+			usage:    []string{"help [command]"},
+			summary:  "Show all commands, or details for one",
+			args:     []commandArg{{name: "command", desc: "command name or alias", kind: argCommand}},
+			examples: []string{"help build", "? trade"},
+			handler:  cmdHelp,
+		},
+		{
+			name:    "quit",
+			usage:   []string{"quit"},
+			summary: "Save and exit the game",
+			handler: func(_ []string, _ *game.GameEngine) CommandResult { return CommandResult{Type: "quit"} },
+		},
+	}
+}
+
+// lookupCommand finds a command by name or alias
+func lookupCommand(name string) (commandSpec, bool) {
+	name = strings.ToLower(name)
+	for _, c := range commandRegistry {
+		if c.name == name {
+			return c, true
+		}
+		for _, a := range c.aliases {
+			if a == name {
+				return c, true
+			}
+		}
+	}
+	return commandSpec{}, false
+}
+
+// commandNames returns every registered command name, without aliases
+func commandNames() []string {
+	names := make([]string, 0, len(commandRegistry))
+	for _, c := range commandRegistry {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func cmdHelp(args []string, engine *game.GameEngine) CommandResult {
+	if len(args) == 0 {
+		return cmdHelpOverview()
+	}
+	spec, ok := lookupCommand(args[0])
+	if !ok {
+		return CommandResult{
+			Message: fmt.Sprintf("Unknown command: %s. Type 'help' for commands.", args[0]),
+			Type:    "error",
+		}
+	}
+
+	var lines []string
+	title := fmt.Sprintf("[gold]%s[-] - %s", spec.name, spec.summary)
+	if len(spec.aliases) > 0 {
+		title += fmt.Sprintf(" [gray](alias: %s)[-]", strings.Join(spec.aliases, ", "))
+	}
+	lines = append(lines, title)
+
+	lines = append(lines, "[gold]Usage:[-]")
+	for _, u := range spec.usage {
+		lines = append(lines, "  [cyan]"+u+"[-]")
+	}
+	if len(spec.args) > 0 {
+		lines = append(lines, "[gold]Arguments:[-]")
+		for _, a := range spec.args {
+			lines = append(lines, fmt.Sprintf("  %-12s %s", a.name, a.desc))
+		}
+	}
+	if len(spec.examples) > 0 {
+		lines = append(lines, "[gold]Examples:[-]")
+		for _, e := range spec.examples {
+			lines = append(lines, "  "+e)
+		}
+	}
+	if spec.context != nil {
+		lines = append(lines, spec.context(engine.GetState())...)
+	}
+	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
+}
+
+func cmdHelpOverview() CommandResult {
+	var lines []string
+	var shortcuts []string
+	lines = append(lines, "[gold]Commands:[-]")
+	width := 0
+	for _, c := range commandRegistry {
+		width = max(width, len(c.usage[0]))
+	}
+	for _, c := range commandRegistry {
+		for i, u := range c.usage {
+			desc := c.summary
+			if i > 0 {
+				desc = ""
+			}
+			// Pad the plain text before adding color tags so columns line up
+			pad := width + 1 - len(u)
+			name, rest, _ := strings.Cut(u, " ")
+			line := "  [cyan]" + name + "[-]"
+			if rest != "" {
+				line += " " + rest
+			}
+			if desc != "" {
+				line += strings.Repeat(" ", pad) + "- " + desc
+			}
+			lines = append(lines, line)
+		}
+		for _, a := range c.aliases {
+			shortcuts = append(shortcuts, a+"="+c.name)
+		}
+	}
+	lines = append(lines, "")
+	lines = append(lines, "[gold]Shortcuts:[-] "+strings.Join(shortcuts, ", "))
+	lines = append(lines, "Type [cyan]help <command>[-] for usage, examples and live hints.")
+	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
+}
+
+// helpBuildContext lists unlocked buildings you can afford right now
+func helpBuildContext(state game.GameState) []string {
+	var keys []string
+	for key, b := range state.Buildings {
+		if b.Unlocked && b.CanBuild {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := []string{"[gold]Affordable now:[-]"}
+	if len(keys) == 0 {
+		return append(lines, "  [gray]Nothing affordable yet - gather more resources[-]")
+	}
+	for _, key := range keys {
+		b := state.Buildings[key]
+		lines = append(lines, fmt.Sprintf("  [green]✓[-] [cyan]%s[-] (%d built) - %s",
+			key, b.Count, FormatCost(b.NextCost)))
+	}
+	return lines
+}
+
+// helpToggleContext lists buildings with instances switched off
+func helpToggleContext(state game.GameState) []string {
+	var keys []string
+	for key, b := range state.Buildings {
+		if b.Disabled > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := []string{"[gold]Switched off:[-]"}
+	if len(keys) == 0 {
+		return append(lines, "  [gray]Every building is running[-]")
+	}
+	for _, key := range keys {
+		b := state.Buildings[key]
+		lines = append(lines, fmt.Sprintf("  [cyan]%s[-] %d of %d off", key, b.Disabled, b.Count))
+	}
+	return lines
+}
+
+// helpResearchContext lists techs that can be researched now
+func helpResearchContext(state game.GameState) []string {
+	lines := []string{"[gold]Available now:[-]"}
+	if state.Research.CurrentTech != "" {
+		lines = append(lines, fmt.Sprintf("  [yellow]Researching %s (%d ticks left)[-]",
+			state.Research.CurrentTechName, state.Research.TicksLeft))
+	}
+	keys := availableTechKeys(state)
+	if len(keys) == 0 {
+		return append(lines, "  [gray]No technologies available to research[-]")
+	}
+	knowledge := 0.0
+	if rs, ok := state.Resources["knowledge"]; ok {
+		knowledge = rs.Amount
+	}
+	for _, key := range keys {
+		t := state.Research.Techs[key]
+		mark := "[red]✗[-]"
+		if knowledge >= t.Cost {
+			mark = "[green]✓[-]"
+		}
+		lines = append(lines, fmt.Sprintf("  %s [cyan]%s[-] - %s (%.0f knowledge)", mark, key, t.Name, t.Cost))
+	}
+	return lines
+}
+
+// helpTrainContext lists units that can be trained now
+func helpTrainContext(state game.GameState) []string {
+	lines := []string{"[gold]Trainable now:[-]"}
+	units := make(map[string]game.UnitInfo, len(state.Military.Units))
+	for _, u := range state.Military.Units {
+		units[u.Key] = u
+	}
+	home := units["infantry"].Home
+	lines = append(lines, fmt.Sprintf("  [yellow]Infantry at home: %d  Training lanes: %d  Queue: %d ticks[-]",
+		home, state.Military.TrainingSlots, state.Military.TrainingTicks))
+	keys := trainableUnitKeys(state)
+	if len(keys) == 0 {
+		return append(lines, "  [gray]No units available to train[-]")
+	}
+	for _, key := range keys {
+		u := units[key]
+		lines = append(lines, fmt.Sprintf("  [cyan]%s[-] - %s (%s each, %d ticks)", u.Key, u.Name, FormatCost(u.TrainCost), u.TrainTicks))
+	}
+	return lines
+}
+
+// helpExpeditionContext ranks the expeditions that can be launched and won now
+func helpExpeditionContext(state game.GameState) []string {
+	lines := []string{"[gold]Best expeditions now:[-]"}
+	top := make([]string, 3)
+	for _, exp := range state.Military.Expeditions {
+		if exp.Rank > 0 && exp.Rank <= len(top) {
+			top[exp.Rank-1] = fmt.Sprintf("  %d. [cyan]%s[-] - %.2f loot per soldier-tick, %d lost",
+				exp.Rank, exp.Key, exp.Forecast.LootPerSoldierTick, exp.Forecast.SoldiersLost)
+		}
+	}
+	if top[0] == "" {
+		return append(lines, "  [gray]No expedition you can launch now would be won[-]")
+	}
+	for _, line := range top {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// helpTradeContext lists market pairs and routes that can be started now
+func helpTradeContext(state game.GameState) []string {
+	var lines []string
+	if len(state.Trade.ExchangeRates) > 0 {
+		var pairs []string
+		for _, info := range state.Trade.ExchangeRates {
+			pairs = append(pairs, fmt.Sprintf("%s→%s %.2f", info.From, info.To, info.Rate))
+		}
+		sort.Strings(pairs)
+		lines = append(lines, "[gold]Exchange rates:[-]", "  "+strings.Join(pairs, ", "))
+	} else {
+		lines = append(lines, "[gold]Exchange rates:[-]", "  [gray]Build a market to exchange resources[-]")
+	}
+
+	lines = append(lines, "[gold]Reachable routes:[-]")
+	found := false
+	for _, route := range state.Trade.AvailableRoutes {
+		if !route.CanStart {
+			continue
+		}
+		found = true
+		lines = append(lines, fmt.Sprintf("  [green]✓[-] [cyan]%s[-] - %s: %s → %s",
+			route.Key, route.Name, FormatCost(route.Export), FormatCost(route.Import)))
+	}
+	if !found {
+		lines = append(lines, "  [gray]No routes can be started right now[-]")
+	}
+	for _, route := range state.Trade.ActiveRoutes {
+		lines = append(lines, fmt.Sprintf("  [yellow]active[-] [cyan]%s[-] (%d ticks left)", route.Key, route.TicksLeft))
+	}
+	return lines
+}
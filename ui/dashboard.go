@@ -181,15 +181,7 @@ func (d *Dashboard) build() {
 			if text == "" {
 				return
 			}
-			if strings.ToLower(strings.TrimSpace(text)) == "quit" {
-				d.engine.SaveGame("autosave")
-				d.app.Stop()
-				return
-			}
-			result := HandleCommand(text, d.engine)
-			if result.Message != "" && result.Type != "success" {
-				d.engine.AddLog(result.Type, result.Message)
-			}
+			d.runCommand(text)
 		}
 	})
 
@@ -215,6 +207,12 @@ func (d *Dashboard) build() {
 
 	// Global key handling
 	d.root.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Wiki search prompt captures every key while open
+		if d.activeTab == 5 && d.wikiTab.Searching() {
+			d.wikiTab.HandleSearchKey(event)
+			return nil
+		}
+
 		switch event.Key() {
 		case tcell.KeyEsc:
 			d.engine.SaveGame("autosave")
@@ -266,6 +264,38 @@ func (d *Dashboard) build() {
 			}
 		}
 
+		// When map tab is active, arrows move the inspect cursor and b/u act
+		// on the building under it, while the command line is empty
+		if d.activeTab == 6 && d.inputField.GetText() == "" {
+			switch event.Key() {
+			case tcell.KeyUp:
+				d.mapTab.MoveCursor(0, -1)
+				return nil
+			case tcell.KeyDown:
+				d.mapTab.MoveCursor(0, 1)
+				return nil
+			case tcell.KeyLeft:
+				d.mapTab.MoveCursor(-1, 0)
+				return nil
+			case tcell.KeyRight:
+				d.mapTab.MoveCursor(1, 0)
+				return nil
+			}
+			if d.mapTab.Inspecting() && event.Key() == tcell.KeyRune {
+				key := d.mapTab.Selected()
+				switch {
+				case event.Rune() == 'b' && key != "":
+					d.runCommand("build " + key)
+					return nil
+				case event.Rune() == 'u' && key != "":
+					d.runCommand("upgrade " + key)
+					return nil
+				}
+				// Any other key starts typing a command
+				d.mapTab.StopInspecting()
+			}
+		}
+
 		// When wiki tab is active, intercept navigation keys
 		if d.activeTab == 5 {
 			switch event.Key() {
@@ -282,6 +312,34 @@ func (d *Dashboard) build() {
 				d.wikiTab.ScrollDown()
 				return nil
 			}
+			// Search and link keys only apply while the command line is empty
+			if d.inputField.GetText() == "" {
+				switch event.Key() {
+				case tcell.KeyLeft:
+					if d.wikiTab.HasLinks() {
+						d.wikiTab.PrevLink()
+						return nil
+					}
+				case tcell.KeyRight:
+					if d.wikiTab.HasLinks() {
+						d.wikiTab.NextLink()
+						return nil
+					}
+				case tcell.KeyEnter:
+					if d.wikiTab.HasLinks() {
+						d.wikiTab.FollowLink()
+						return nil
+					}
+				case tcell.KeyBackspace, tcell.KeyBackspace2:
+					if d.wikiTab.Back() {
+						return nil
+					}
+				}
+				if event.Rune() == '/' {
+					d.wikiTab.StartSearch()
+					return nil
+				}
+			}
 			// Number keys for quick nav
 			if event.Rune() >= '1' && event.Rune() <= '9' {
 				idx := int(event.Rune() - '1')
@@ -298,6 +356,19 @@ func (d *Dashboard) build() {
 	})
 }
 
+// runCommand runs a command line, logging anything but success
+func (d *Dashboard) runCommand(text string) {
+	result := HandleCommand(text, d.engine)
+	if result.Type == "quit" {
+		d.engine.SaveGame("autosave")
+		d.app.Stop()
+		return
+	}
+	if result.Message != "" && result.Type != "success" {
+		d.engine.AddLog(result.Type, result.Message)
+	}
+}
+
 func (d *Dashboard) switchTab(index int) {
 	d.activeTab = index
 	d.tabPages.SwitchToPage(d.tabNames[index])
@@ -422,10 +493,19 @@ func (d *Dashboard) refreshStatus(state game.GameState) {
 	if state.Milestones.CurrentTitle != "" {
 		titleStr = fmt.Sprintf("  [yellow]\"%s\"[-]", state.Milestones.CurrentTitle)
 	}
+	powerStr := ""
+	if p := state.Power; p.Demand > 0 {
+		color := "green"
+		if p.Served < p.Demand {
+			color = "red"
+		}
+		powerStr = fmt.Sprintf("  |  [%s]Power: %s/%s %s[-]",
+			color, FormatNumber(p.Demand), FormatNumber(p.Supply), ProgressBar(p.Demand, p.Supply, 6))
+	}
 	d.statusTV.SetText(fmt.Sprintf(
-		"[gold]%s[-]%s%s  Tick: %d%s%s  |  Pop: %d/%d  |  [gray]F1-F9=Tabs  ESC=Menu[-]",
+		"[gold]%s[-]%s%s  Tick: %d%s%s  |  Pop: %d/%d%s  |  [gray]F1-F9=Tabs  ESC=Menu[-]",
 		state.AgeName, prestigeStr, titleStr, state.Tick, nextAgeStr, speedStr,
-		state.Villagers.TotalPop, state.Villagers.MaxPop,
+		state.Villagers.TotalPop, state.Villagers.MaxPop, powerStr,
 	))
 }
 
@@ -3,17 +3,20 @@ package ui
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/user/ageforge/config"
 	"github.com/user/ageforge/game"
 )
 
 // CommandResult represents the result of a command execution
 type CommandResult struct {
 	Message string
-	Type    string // "info", "success", "error"
+	Type    string // "info", "success", "warning", "error", or "quit" to exit the game
 }
 
 // HandleCommand parses and executes a command string
@@ -24,99 +27,14 @@ func HandleCommand(input string, engine *game.GameEngine) CommandResult {
 	}
 
 	cmd := strings.ToLower(parts[0])
-	args := parts[1:]
-
-	switch cmd {
-	case "help", "h", "?":
-		return cmdHelp(args)
-	case "gather", "g":
-		return cmdGather(args, engine)
-	case "build", "b":
-		return cmdBuild(args, engine)
-	case "recruit", "r":
-		return cmdRecruit(args, engine)
-	case "assign", "a":
-		return cmdAssign(args, engine)
-	case "unassign", "u":
-		return cmdUnassign(args, engine)
-	case "status", "s":
-		return cmdStatus(engine)
-	case "research", "res":
-		return cmdResearch(args, engine)
-	case "expedition", "exp":
-		return cmdExpedition(args, engine)
-	case "trade", "t":
-		return cmdTrade(args, engine)
-	case "diplomacy", "dip":
-		return cmdDiplomacy(args, engine)
-	case "prestige":
-		return cmdPrestige(args, engine)
-	case "rates":
-		return cmdRates(engine)
-	case "speed":
-		return cmdSpeed(args, engine)
-	case "upgrade":
-		return cmdUpgrade(args, engine)
-	case "dump", "exportlogs":
-		return cmdDump(args, engine)
-	case "saves":
-		return cmdSaveList()
-	case "save":
-		if len(args) > 0 && args[0] == "list" {
-			return cmdSaveList()
-		}
-		return cmdSave(args, engine)
-	case "load":
-		return cmdLoad(args, engine)
-	default:
+	spec, ok := lookupCommand(cmd)
+	if !ok {
 		return CommandResult{
 			Message: fmt.Sprintf("Unknown command: %s. Type 'help' for commands.", cmd),
 			Type:    "error",
 		}
 	}
-}
-
-func cmdHelp(args []string) CommandResult {
-	help := `[gold]Commands:[-]
-  [cyan]gather[-] <food|wood|stone> [n] - Hand-gather resources (max 5)
-  [cyan]build[-] <building> [count|max] - Build structure(s) (default: 1)
-  [cyan]recruit[-] <type> [count|max]  - Recruit villagers (default: 1)
-  [cyan]assign[-] <type> <resource> [n|all]- Assign villagers to gather
-  [cyan]unassign[-] <type> <resource> [n|all]- Unassign villagers
-  [cyan]research[-] <tech_key>         - Research a technology
-  [cyan]research[-] cancel             - Cancel current research
-  [cyan]research[-] list               - List available techs
-  [cyan]expedition[-] <key>            - Launch a military expedition
-  [cyan]expedition[-] list             - List available expeditions
-  [cyan]trade[-] <from> <to> <amount>  - Exchange resources
-  [cyan]trade[-] list                  - Show exchange rates
-  [cyan]trade[-] route list            - List trade routes
-  [cyan]trade[-] route start <key>     - Start a trade route
-  [cyan]trade[-] route stop <key>      - Stop a trade route
-  [cyan]diplomacy[-]                   - Show faction status
-  [cyan]diplomacy[-] ally <faction>    - Ally with faction (costs gold)
-  [cyan]diplomacy[-] rival <faction>   - Declare rivalry
-  [cyan]diplomacy[-] embargo <faction> - Embargo faction
-  [cyan]diplomacy[-] gift <faction>    - Send gift (+15 opinion)
-  [cyan]diplomacy[-] neutral <faction> - Reset to neutral
-  [cyan]prestige[-]                    - View prestige status
-  [cyan]prestige[-] confirm yes        - Reset game with prestige bonus
-  [cyan]prestige[-] shop               - View prestige upgrades
-  [cyan]prestige[-] buy <key>          - Buy a prestige upgrade
-  [cyan]rates[-]                       - Show resource rate breakdown
-  [cyan]status[-]                      - Show detailed status
-  [cyan]upgrade[-]                     - List available building upgrades
-  [cyan]upgrade[-] <building>          - Upgrade all of that building type
-  [cyan]upgrade[-] all                 - Upgrade everything affordable
-  [cyan]dump[-]                        - Export logs to file for debugging
-  [cyan]save[-] [name]                 - Save game (default: autosave)
-  [cyan]load[-] [name]                 - Load game (default: autosave)
-  [cyan]saves[-]                       - List all save files
-  [cyan]speed[-] [1.0|1.5|2.0|...]     - Set game speed (unlocks per wonder built)
-  [cyan]help[-]                        - Show this help
-
-[gold]Shortcuts:[-] g=gather, b=build, r=recruit, a=assign, u=unassign, s=status, res=research, exp=expedition, t=trade, dip=diplomacy`
-	return CommandResult{Message: help, Type: "info"}
+	return spec.handler(parts[1:], engine)
 }
 
 func cmdUpgrade(args []string, engine *game.GameEngine) CommandResult {
@@ -166,6 +84,51 @@ func cmdUpgrade(args []string, engine *game.GameEngine) CommandResult {
 	}
 }
 
+// exporting is set while a timelapse export runs in the background
+var exporting atomic.Bool
+
+func cmdMap(args []string, engine *game.GameEngine) CommandResult {
+	if len(args) == 0 || strings.ToLower(args[0]) != "export" {
+		return CommandResult{Message: "Usage: map export [--gif|--png-seq]", Type: "error"}
+	}
+	format := "gif"
+	if len(args) >= 2 {
+		switch strings.ToLower(args[1]) {
+		case "--gif":
+		case "--png-seq":
+			format = "png"
+		default:
+			return CommandResult{Message: fmt.Sprintf("Unknown option: %s (use --gif or --png-seq)", args[1]), Type: "error"}
+		}
+	}
+
+	if !exporting.CompareAndSwap(false, true) {
+		return CommandResult{Message: "A timelapse export is already running", Type: "error"}
+	}
+	frames := engine.Timelapse()
+	seed := engine.GetState().Settlement.Seed
+	ts := time.Now().Format("2006-01-02_150405")
+	path := fmt.Sprintf("data/timelapse/timelapse_%s", ts)
+	if format == "gif" {
+		path += ".gif"
+	}
+
+	// Rendering takes a while on long runs, so keep it off the UI goroutine
+	// and report back through the log
+	go func() {
+		defer exporting.Store(false)
+		if _, err := ExportTimelapse(frames, seed, format, path); err != nil {
+			engine.AddLog("error", fmt.Sprintf("Failed to export timelapse: %v", err))
+			return
+		}
+		engine.AddLog("info", fmt.Sprintf("Timelapse exported to %s (%d frames)", path, len(frames)))
+	}()
+	return CommandResult{
+		Message: fmt.Sprintf("Rendering timelapse of %d frames to %s...", len(frames), path),
+		Type:    "info",
+	}
+}
+
 func cmdDump(args []string, engine *game.GameEngine) CommandResult {
 	state := engine.GetState()
 	logs := engine.GetLogs()
@@ -313,6 +276,59 @@ func cmdBuild(args []string, engine *game.GameEngine) CommandResult {
 	}
 }
 
+func cmdDemolish(args []string, engine *game.GameEngine) CommandResult {
+	if len(args) < 1 {
+		return CommandResult{Message: "Usage: demolish <building> [count|all]", Type: "error"}
+	}
+	key := strings.ToLower(args[0])
+	count := 1
+	if len(args) >= 2 {
+		if strings.ToLower(args[1]) == "all" {
+			count = engine.GetState().Buildings[key].Count
+		} else if n, err := strconv.Atoi(args[1]); err == nil && n > 0 {
+			count = n
+		} else {
+			return CommandResult{Message: fmt.Sprintf("Invalid count: %s", args[1]), Type: "error"}
+		}
+	}
+	n, err := engine.Demolish(key, count)
+	if err != nil {
+		return CommandResult{Message: err.Error(), Type: "error"}
+	}
+	return CommandResult{Message: fmt.Sprintf("Demolished %d %s", n, key), Type: "success"}
+}
+
+func cmdToggle(args []string, engine *game.GameEngine) CommandResult {
+	if len(args) < 1 {
+		return CommandResult{Message: "Usage: toggle <building> [n]", Type: "error"}
+	}
+	key := strings.ToLower(args[0])
+	b, ok := engine.GetState().Buildings[key]
+	if !ok {
+		return CommandResult{Message: fmt.Sprintf("Unknown building: %s", key), Type: "error"}
+	}
+
+	// With no count, flip the whole type: all off, or all back on if any are off
+	off := b.Count
+	if b.Disabled > 0 {
+		off = 0
+	}
+	if len(args) >= 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return CommandResult{Message: fmt.Sprintf("Invalid count: %s", args[1]), Type: "error"}
+		}
+		off = n
+	}
+	if err := engine.SetDisabled(key, off); err != nil {
+		return CommandResult{Message: err.Error(), Type: "error"}
+	}
+	return CommandResult{
+		Message: fmt.Sprintf("%s: %d of %d running", b.Name, b.Count-off, b.Count),
+		Type:    "success",
+	}
+}
+
 func cmdRecruit(args []string, engine *game.GameEngine) CommandResult {
 	if len(args) < 1 {
 		return CommandResult{Message: "Usage: recruit <worker|scholar> [count|max]", Type: "error"}
@@ -326,7 +342,7 @@ func cmdRecruit(args []string, engine *game.GameEngine) CommandResult {
 			return CommandResult{Message: err.Error(), Type: "error"}
 		}
 		return CommandResult{
-			Message: fmt.Sprintf("Recruited %d %s(s)!", recruited, vType),
+			Message: recruitMessage(vType, recruited),
 			Type:    "success",
 		}
 	}
@@ -341,11 +357,19 @@ func cmdRecruit(args []string, engine *game.GameEngine) CommandResult {
 		return CommandResult{Message: err.Error(), Type: "error"}
 	}
 	return CommandResult{
-		Message: fmt.Sprintf("Recruited %d %s(s)!", count, vType),
+		Message: recruitMessage(vType, count),
 		Type:    "success",
 	}
 }
 
+// recruitMessage confirms a recruitment; soldiers join the training queue
+func recruitMessage(vType string, count int) string {
+	if vType == "soldier" {
+		return fmt.Sprintf("Queued %d soldier(s) for training!", count)
+	}
+	return fmt.Sprintf("Recruited %d %s(s)!", count, vType)
+}
+
 func cmdAssign(args []string, engine *game.GameEngine) CommandResult {
 	if len(args) < 2 {
 		return CommandResult{Message: "Usage: assign <type> <resource> [count|all]", Type: "error"}
@@ -468,38 +492,51 @@ func cmdLoad(args []string, engine *game.GameEngine) CommandResult {
 	return CommandResult{Message: fmt.Sprintf("Game loaded from '%s'", name), Type: "info"}
 }
 
+// rateGroups lists the sources shown by the rates command, in order
+var rateGroups = []struct{ source, label string }{
+	{game.SourceBuilding, "Buildings"},
+	{game.SourceVillager, "Villagers"},
+	{game.SourceTech, "Research"},
+	{game.SourceMilestone, "Milestones"},
+	{game.SourcePrestige, "Prestige"},
+	{game.SourceEvent, "Events"},
+	{game.SourceFaction, "Factions"},
+	{game.SourceSettlement, "Layout"},
+	{game.SourceBiome, "Biome"},
+	{"consumed", "Consumed"},
+	{"drain", "Drain"},
+	{"upkeep", "Upkeep"},
+}
+
 func cmdRates(engine *game.GameEngine) CommandResult {
 	state := engine.GetState()
 	var lines []string
 	lines = append(lines, "[gold]Resource Rate Breakdown:[-]")
 
 	for _, rs := range state.Resources {
-		if !rs.Unlocked || (rs.Rate == 0 && rs.Breakdown == (game.RateBreakdown{})) {
+		if !rs.Unlocked || (rs.Rate == 0 && len(rs.Breakdown.Sources) == 0) {
 			continue
 		}
 		lines = append(lines, fmt.Sprintf("  [cyan]%s[-]:  %s/tick", rs.Name, FormatRate(rs.Rate)))
-		b := rs.Breakdown
-		var parts []string
-		if b.BuildingRate != 0 {
-			parts = append(parts, fmt.Sprintf("Buildings: %+.2f", b.BuildingRate))
-		}
-		if b.VillagerRate != 0 {
-			parts = append(parts, fmt.Sprintf("Villagers: %+.2f", b.VillagerRate))
-		}
-		if b.ResearchRate != 0 {
-			parts = append(parts, fmt.Sprintf("Research: %+.2f", b.ResearchRate))
-		}
-		if b.EventRate != 0 {
-			parts = append(parts, fmt.Sprintf("Events: %+.2f", b.EventRate))
-		}
-		if b.TradeRate != 0 {
-			parts = append(parts, fmt.Sprintf("Trade: %+.2f", b.TradeRate))
-		}
-		if b.BonusRate != 0 {
-			parts = append(parts, fmt.Sprintf("Bonuses: %+.2f", b.BonusRate))
+		// Sum each source's contribution; recipe inputs show as consumed,
+		// villagers eating as drain and unit and building upkeep as upkeep
+		totals := make(map[string]float64)
+		for _, src := range rs.Breakdown.Sources {
+			label := src.Source
+			if src.Layer == game.LayerConsumption {
+				label = "consumed"
+			} else if src.Source == game.SourceVillager && src.Layer == game.LayerFinal {
+				label = "drain"
+			} else if src.Layer == game.LayerFinal && (src.Source == game.SourceMilitary || src.Source == game.SourceBuilding) {
+				label = "upkeep"
+			}
+			totals[label] += src.Amount
 		}
-		if b.FoodDrain != 0 {
-			parts = append(parts, fmt.Sprintf("Drain: %+.2f", b.FoodDrain))
+		var parts []string
+		for _, group := range rateGroups {
+			if v := totals[group.source]; v != 0 {
+				parts = append(parts, fmt.Sprintf("%s: %+.2f", group.label, v))
+			}
 		}
 		if len(parts) > 0 {
 			lines = append(lines, fmt.Sprintf("    %s", strings.Join(parts, "  ")))
@@ -512,6 +549,104 @@ func cmdRates(engine *game.GameEngine) CommandResult {
 	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
 }
 
+func cmdWhy(args []string, engine *game.GameEngine) CommandResult {
+	if len(args) == 0 {
+		return CommandResult{Message: "Usage: why <resource> [ticks]", Type: "error"}
+	}
+	ticks := game.DefaultWhyTicks
+	if len(args) >= 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return CommandResult{Message: fmt.Sprintf("Invalid tick count: %s", args[1]), Type: "error"}
+		}
+		ticks = n
+	}
+	exp, err := engine.ExplainResource(strings.ToLower(args[0]), ticks)
+	if err != nil {
+		return CommandResult{Message: err.Error(), Type: "error"}
+	}
+
+	b := exp.Breakdown
+	lines := []string{fmt.Sprintf("[gold]Why %s:[-] %s/tick", exp.Name, FormatRate(exp.Rate))}
+	lines = append(lines, fmt.Sprintf("  [cyan]Base[-] %+.2f/tick", b.Base))
+	lines = append(lines, whySources(b.Sources, game.LayerAdditive)...)
+	if b.Multiplier != 1 {
+		note := ""
+		if b.Base <= 0 {
+			note = " [gray](not applied: base isn't positive)[-]"
+		}
+		lines = append(lines, fmt.Sprintf("  [cyan]× %.2f multiplier[-]%s", b.Multiplier, note))
+		lines = append(lines, whySources(b.Sources, game.LayerMultiplicative)...)
+	}
+	if consumed := whySources(b.Sources, game.LayerConsumption); len(consumed) > 0 {
+		lines = append(lines, fmt.Sprintf("  [cyan]Consumed[-] %+.2f/tick", b.Consumption))
+		lines = append(lines, consumed...)
+	}
+	if final := whySources(b.Sources, game.LayerFinal); len(final) > 0 {
+		lines = append(lines, fmt.Sprintf("  [cyan]Then[-] %+.2f/tick", b.Final))
+		lines = append(lines, final...)
+	}
+	lines = append(lines, fmt.Sprintf("  = %s/tick", FormatRate(exp.Rate)))
+
+	lines = append(lines, fmt.Sprintf("\n  [cyan]Storage cap[-] %s (holding %s)", FormatNumber(exp.Storage), FormatNumber(exp.Amount)))
+	lines = append(lines, fmt.Sprintf("    %-10s %-30s %s", "base", "", FormatNumber(exp.BaseStorage)))
+	for _, src := range exp.StorageSources {
+		lines = append(lines, fmt.Sprintf("    %-10s %-30s +%s", src.Source, src.Name, FormatNumber(src.Amount)))
+	}
+
+	if len(exp.Routes) > 0 {
+		lines = append(lines, "\n  [cyan]Trade routes[-] [gray](paid per cycle, not in the rate)[-]")
+		for _, route := range exp.Routes {
+			status := ""
+			if route.Suspended {
+				status = " [red]suspended[-]"
+			} else if route.Stalled {
+				status = " [yellow]stalled[-]"
+			}
+			lines = append(lines, fmt.Sprintf("    %-30s %+.1f every %d ticks (≈ %+.2f/tick)%s",
+				route.Name, route.PerCycle, route.TicksPerRun, route.PerCycle/float64(route.TicksPerRun), status))
+		}
+	}
+
+	if exp.Since == 0 {
+		lines = append(lines, "\n  [gray]No history yet to compare against[-]")
+		return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
+	}
+	lines = append(lines, fmt.Sprintf("\n  [cyan]Since %d ticks ago[-]: rate %+.2f → %+.2f/tick, amount %s → %s, cap %s → %s",
+		exp.Since, exp.WasRate, exp.Rate, FormatNumber(exp.WasAmount), FormatNumber(exp.Amount),
+		FormatNumber(exp.WasStorage), FormatNumber(exp.Storage)))
+	if len(exp.Changes) == 0 {
+		lines = append(lines, "    [gray]No source changed[-]")
+	}
+	for _, c := range exp.Changes {
+		was, now := fmt.Sprintf("%+.2f", c.Was), fmt.Sprintf("%+.2f", c.Now)
+		if c.Was == 0 {
+			was = "new"
+		}
+		if c.Now == 0 {
+			now = "gone"
+		}
+		lines = append(lines, fmt.Sprintf("    %-10s %-30s %s → %s", c.Source, c.Name, was, now))
+	}
+	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
+}
+
+// whySources formats the sources in one layer of a rate breakdown
+func whySources(sources []game.RateSource, layer string) []string {
+	var lines []string
+	for _, src := range sources {
+		if src.Layer != layer {
+			continue
+		}
+		if layer == game.LayerMultiplicative {
+			lines = append(lines, fmt.Sprintf("    %-10s %-30s %+.0f%% → %+.2f", src.Source, src.Name, src.Value*100, src.Amount))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("    %-10s %-30s %+.2f", src.Source, src.Name, src.Amount))
+	}
+	return lines
+}
+
 func cmdSpeed(args []string, engine *game.GameEngine) CommandResult {
 	if len(args) == 0 {
 		mult := engine.GetSpeedMultiplier()
@@ -612,13 +747,38 @@ func cmdExpedition(args []string, engine *game.GameEngine) CommandResult {
 		return cmdExpeditionList(engine)
 	}
 	subcmd := strings.ToLower(args[0])
-	if subcmd == "list" {
+	switch subcmd {
+	case "list":
 		return cmdExpeditionList(engine)
+	case "best":
+		return cmdExpeditionBest(engine)
+	case "info":
+		if len(args) < 2 {
+			return CommandResult{Message: "Usage: expedition info <key> [unit:count ...]", Type: "error"}
+		}
+		return cmdExpeditionInfo(args[1:], engine)
+	case "advance", "retreat":
+		if len(args) < 2 {
+			return CommandResult{Message: fmt.Sprintf("Usage: expedition %s <key>", subcmd), Type: "error"}
+		}
+		key := strings.Join(args[1:], "_")
+		if subcmd == "advance" {
+			if err := engine.AdvanceExpedition(key); err != nil {
+				return CommandResult{Message: err.Error(), Type: "error"}
+			}
+			return CommandResult{Message: fmt.Sprintf("Campaign advancing: %s", key), Type: "success"}
+		}
+		if err := engine.RetreatExpedition(key); err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{Message: fmt.Sprintf("Campaign retreating: %s", key), Type: "success"}
 	}
 
-	// Launch expedition
-	expKey := strings.Join(args, "_")
-	if err := engine.LaunchExpedition(expKey); err != nil {
+	expKey, army, err := parseExpeditionArgs(args)
+	if err != nil {
+		return CommandResult{Message: err.Error(), Type: "error"}
+	}
+	if err := engine.LaunchExpedition(expKey, army); err != nil {
 		return CommandResult{Message: err.Error(), Type: "error"}
 	}
 	return CommandResult{
@@ -627,6 +787,164 @@ func cmdExpedition(args []string, engine *game.GameEngine) CommandResult {
 	}
 }
 
+// parseExpeditionArgs splits expedition arguments into the key, formed by
+// the plain words, and the army given by unit:count pairs
+func parseExpeditionArgs(args []string) (string, map[string]int, error) {
+	var keyParts []string
+	var army map[string]int
+	for _, arg := range args {
+		unit, countStr, ok := strings.Cut(strings.ToLower(arg), ":")
+		if !ok {
+			keyParts = append(keyParts, arg)
+			continue
+		}
+		n, err := strconv.Atoi(countStr)
+		if err != nil || n <= 0 {
+			return "", nil, fmt.Errorf("bad unit count %q — use unit:count, e.g. archers:3", arg)
+		}
+		if army == nil {
+			army = make(map[string]int)
+		}
+		army[unit] += n
+	}
+	return strings.Join(keyParts, "_"), army, nil
+}
+
+func cmdExpeditionInfo(args []string, engine *game.GameEngine) CommandResult {
+	key, army, err := parseExpeditionArgs(args)
+	if err != nil {
+		return CommandResult{Message: err.Error(), Type: "error"}
+	}
+	f, err := engine.ForecastExpedition(key, army)
+	if err != nil {
+		return CommandResult{Message: err.Error(), Type: "error"}
+	}
+	def := config.ExpeditionByKey()[key]
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("[gold]%s[-] [gray](%s)[-]", def.Name, key))
+	lines = append(lines, fmt.Sprintf("  [gray]%s[-]", def.Description))
+	lines = append(lines, fmt.Sprintf("  Army: %s", game.FormatUnits(f.Army)))
+	if sent := game.UnitTotal(f.Army); sent < def.SoldiersNeeded {
+		lines = append(lines, fmt.Sprintf("  [red]Too small to launch: needs at least %d soldiers[-]", def.SoldiersNeeded))
+	}
+	for i, st := range f.Stages {
+		outcome := "[green]won[-]"
+		if !st.Won {
+			outcome = "[red]lost[-]"
+		}
+		losses := "no losses"
+		if n := game.UnitTotal(st.Losses); n > 0 {
+			losses = "lose " + game.FormatUnits(st.Losses)
+		}
+		lines = append(lines, fmt.Sprintf("  %d. %s vs %s — %s, %s", i+1, st.Name, game.FormatUnits(st.Enemy), outcome, losses))
+	}
+
+	if f.Won {
+		lines = append(lines, fmt.Sprintf("  [green]Victory[-] in %d ticks", f.Ticks))
+	} else {
+		lines = append(lines, fmt.Sprintf("  [red]Defeat[-] after %d ticks", f.Ticks))
+	}
+	loot := "nothing"
+	if f.LootTotal > 0 {
+		loot = FormatCost(f.Loot)
+	}
+	lines = append(lines, fmt.Sprintf("  Loot: %s", loot))
+	lines = append(lines, fmt.Sprintf("  Casualties: %d of %d  Loot per soldier-tick: %.2f",
+		f.SoldiersLost, game.UnitTotal(f.Army), f.LootPerSoldierTick))
+	lines = append(lines, "  [gray]Battles are deterministic: this is the outcome while your bonuses hold.[-]")
+
+	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
+}
+
+func cmdExpeditionBest(engine *game.GameEngine) CommandResult {
+	state := engine.GetState()
+	exps := make([]game.ExpeditionInfo, 0, len(state.Military.Expeditions))
+	for _, exp := range state.Military.Expeditions {
+		if exp.Rank > 0 {
+			exps = append(exps, exp)
+		}
+	}
+	sort.Slice(exps, func(i, j int) bool { return exps[i].Rank < exps[j].Rank })
+
+	var lines []string
+	lines = append(lines, "[gold]Best Expeditions (loot per soldier-tick):[-]")
+	for _, exp := range exps {
+		f := exp.Forecast
+		lines = append(lines, fmt.Sprintf("  %d. [cyan]%s[-] - %.2f/soldier-tick (%.0f loot, %d ticks, %d lost)",
+			exp.Rank, exp.Key, f.LootPerSoldierTick, f.LootTotal, f.Ticks, f.SoldiersLost))
+	}
+	if len(exps) == 0 {
+		lines = append(lines, "  [gray]No expedition you can launch now would be won[-]")
+	}
+	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
+}
+
+func cmdTrain(args []string, engine *game.GameEngine) CommandResult {
+	if len(args) < 1 {
+		return cmdUnitList(engine)
+	}
+	unit := strings.ToLower(args[0])
+
+	if len(args) >= 2 && strings.ToLower(args[1]) == "max" {
+		trained, err := engine.TrainMax(unit)
+		if err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{
+			Message: fmt.Sprintf("Queued %d %s for training!", trained, unit),
+			Type:    "success",
+		}
+	}
+
+	count := 1
+	if len(args) >= 2 {
+		if n, err := strconv.Atoi(args[1]); err == nil && n > 0 {
+			count = n
+		}
+	}
+	if err := engine.TrainUnits(unit, count); err != nil {
+		return CommandResult{Message: err.Error(), Type: "error"}
+	}
+	return CommandResult{
+		Message: fmt.Sprintf("Queued %d %s for training!", count, unit),
+		Type:    "success",
+	}
+}
+
+func cmdUnitList(engine *game.GameEngine) CommandResult {
+	state := engine.GetState()
+	var lines []string
+	lines = append(lines, "[gold]Military Units:[-]")
+
+	for _, u := range state.Military.Units {
+		status := "[green]✓[-]"
+		if !u.Unlocked {
+			status = "[red]✗[-]"
+		}
+		cost := "recruit soldiers"
+		if len(u.TrainCost) > 0 {
+			cost = FormatCost(u.TrainCost)
+		}
+		lines = append(lines, fmt.Sprintf("  %s [cyan]%s[-] - %s  %d (%d home)  ATK %.1f DEF %.1f  [gray]%s[-]",
+			status, u.Key, u.Name, u.Count, u.Home, u.Attack, u.Defense, cost))
+	}
+	if len(state.Military.Units) == 0 {
+		lines = append(lines, "  [gray]No units available yet[-]")
+	}
+
+	mil := state.Military
+	lines = append(lines, fmt.Sprintf("\n[yellow]Training: %d lane(s), %d ticks queued  Morale: %.0f%%[-]",
+		mil.TrainingSlots, mil.TrainingTicks, mil.Morale))
+	for _, o := range mil.Training {
+		lines = append(lines, fmt.Sprintf("  %d %s (%d ticks to the next group)", o.Count, o.Name, o.TicksLeft))
+	}
+	if len(mil.Upkeep) > 0 {
+		lines = append(lines, fmt.Sprintf("  Upkeep: %s /tick", FormatCost(mil.Upkeep)))
+	}
+	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
+}
+
 func cmdPrestige(args []string, engine *game.GameEngine) CommandResult {
 	if len(args) == 0 {
 		return cmdPrestigeStatus(engine)
@@ -739,13 +1057,29 @@ func cmdExpeditionList(engine *game.GameEngine) CommandResult {
 		if exp.CanLaunch {
 			canStr = "[green]✓[-]"
 		}
-		lines = append(lines, fmt.Sprintf("  %s [cyan]%s[-] - %s (%d soldiers, %d ticks)",
-			canStr, exp.Key, exp.Name, exp.SoldiersNeeded, exp.Duration))
+		stages := ""
+		if exp.Stages > 1 {
+			stages = fmt.Sprintf(", %d stages", exp.Stages)
+		}
+		forecast := "[red]defeat[-]"
+		if exp.Forecast.Won {
+			forecast = fmt.Sprintf("[green]victory[-], %d lost", exp.Forecast.SoldiersLost)
+		}
+		lines = append(lines, fmt.Sprintf("  %s [cyan]%s[-] - %s (%d soldiers, %d ticks%s) vs %s: %s",
+			canStr, exp.Key, exp.Name, exp.SoldiersNeeded, exp.Duration, stages, game.FormatUnits(exp.Enemies[0]), forecast))
 	}
 
-	if state.Military.ActiveExpedition != nil {
-		lines = append(lines, fmt.Sprintf("\n[yellow]Active: %s (%d ticks left)[-]",
-			state.Military.ActiveExpedition.Name, state.Military.ActiveExpedition.TicksLeft))
+	mil := state.Military
+	lines = append(lines, fmt.Sprintf("\n[yellow]Slots: %d/%d  Available soldiers: %d[-]",
+		len(mil.ActiveExpeditions), mil.ExpeditionSlots, mil.AvailableSoldiers))
+	for _, exp := range mil.ActiveExpeditions {
+		if exp.Holding {
+			lines = append(lines, fmt.Sprintf("[yellow]Holding: %s (%s, stage %d/%d next) — expedition advance|retreat %s[-]",
+				exp.Name, game.FormatUnits(exp.Units), exp.Stage, exp.Stages, exp.Key))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("[yellow]Active: %s (%s, %d ticks left)[-]",
+			exp.Name, game.FormatUnits(exp.Units), exp.TicksLeft))
 	}
 
 	if len(state.Military.Expeditions) == 0 {
@@ -845,8 +1179,14 @@ func cmdTradeRouteList(engine *game.GameEngine) CommandResult {
 	if len(trade.ActiveRoutes) > 0 {
 		lines = append(lines, "\n[green]Active:[-]")
 		for _, route := range trade.ActiveRoutes {
-			lines = append(lines, fmt.Sprintf("  [cyan]%s[-] (%s) - %d ticks left, %d cycles done",
-				route.Name, route.Key, route.TicksLeft, route.CyclesDone))
+			progress := fmt.Sprintf("%d ticks left", route.TicksLeft)
+			if route.Suspended {
+				progress = "[red]suspended[-]"
+			} else if route.Stalled {
+				progress += ", [yellow]stalled[-]"
+			}
+			lines = append(lines, fmt.Sprintf("  [cyan]%s[-] (%s)%s - %s, %d cycles done, yield ×%.2f",
+				route.Name, route.Key, routePartnerLabel(route.Partner), progress, route.CyclesDone, route.Yield))
 		}
 	}
 
@@ -857,7 +1197,7 @@ func cmdTradeRouteList(engine *game.GameEngine) CommandResult {
 			if route.CanStart {
 				status = "[green]✓[-]"
 			}
-			lines = append(lines, fmt.Sprintf("  %s [cyan]%s[-] - %s", status, route.Key, route.Name))
+			lines = append(lines, fmt.Sprintf("  %s [cyan]%s[-] - %s%s", status, route.Key, route.Name, routePartnerLabel(route.Partner)))
 			lines = append(lines, fmt.Sprintf("    %s", route.Description))
 		}
 	}
@@ -914,7 +1254,7 @@ func cmdDiplomacy(args []string, engine *game.GameEngine) CommandResult {
 		if err := engine.SendGift(factionKey); err != nil {
 			return CommandResult{Message: err.Error(), Type: "error"}
 		}
-		return CommandResult{Message: fmt.Sprintf("Sent gift to %s (+15 opinion)", factionKey), Type: "success"}
+		return CommandResult{Message: fmt.Sprintf("Sent gift to %s", factionKey), Type: "success"}
 
 	case "neutral":
 		if len(args) < 2 {
@@ -926,8 +1266,68 @@ func cmdDiplomacy(args []string, engine *game.GameEngine) CommandResult {
 		}
 		return CommandResult{Message: fmt.Sprintf("Reset %s to neutral", factionKey), Type: "info"}
 
+	case "accept":
+		if len(args) < 2 {
+			return CommandResult{Message: "Usage: diplomacy accept <faction_key>", Type: "error"}
+		}
+		factionKey := strings.Join(args[1:], "_")
+		if err := engine.AcceptTreaty(factionKey); err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{Message: fmt.Sprintf("Signed treaty with %s", factionKey), Type: "success"}
+
+	case "decline":
+		if len(args) < 2 {
+			return CommandResult{Message: "Usage: diplomacy decline <faction_key>", Type: "error"}
+		}
+		factionKey := strings.Join(args[1:], "_")
+		if err := engine.DeclineTreaty(factionKey); err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{Message: fmt.Sprintf("Declined %s's proposal", factionKey), Type: "info"}
+
+	case "break":
+		if len(args) < 2 {
+			return CommandResult{Message: "Usage: diplomacy break <faction_key>", Type: "error"}
+		}
+		factionKey := strings.Join(args[1:], "_")
+		if err := engine.BreakTreaty(factionKey); err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{Message: fmt.Sprintf("Broke treaty with %s!", factionKey), Type: "warning"}
+
+	case "fulfill":
+		if len(args) < 2 {
+			return CommandResult{Message: "Usage: diplomacy fulfill <faction_key>", Type: "error"}
+		}
+		factionKey := strings.Join(args[1:], "_")
+		if err := engine.FulfillRequest(factionKey); err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{Message: fmt.Sprintf("Met %s's request", factionKey), Type: "success"}
+
+	case "refuse":
+		if len(args) < 2 {
+			return CommandResult{Message: "Usage: diplomacy refuse <faction_key>", Type: "error"}
+		}
+		factionKey := strings.Join(args[1:], "_")
+		if err := engine.RefuseRequest(factionKey); err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{Message: fmt.Sprintf("Refused %s's request", factionKey), Type: "warning"}
+
+	case "peace":
+		if len(args) < 2 {
+			return CommandResult{Message: "Usage: diplomacy peace <faction_key>", Type: "error"}
+		}
+		factionKey := strings.Join(args[1:], "_")
+		if err := engine.MakePeace(factionKey); err != nil {
+			return CommandResult{Message: err.Error(), Type: "error"}
+		}
+		return CommandResult{Message: fmt.Sprintf("Made peace with %s", factionKey), Type: "success"}
+
 	default:
-		return CommandResult{Message: "Usage: diplomacy [ally|rival|embargo|gift|neutral] <faction_key>", Type: "error"}
+		return CommandResult{Message: "Usage: diplomacy [ally|rival|embargo|gift|neutral|accept|decline|break|fulfill|refuse|peace] <faction_key>", Type: "error"}
 	}
 }
 
@@ -953,6 +1353,9 @@ func cmdDiplomacyStatus(engine *game.GameEngine) CommandResult {
 		}
 		lines = append(lines, fmt.Sprintf("  [cyan]%s[-] (%s) [%s]  Opinion: %d%s  Trades: %d",
 			f.Name, key, f.Status, f.Opinion, bonusStr, f.TradeCount))
+		for _, line := range factionAgendaLines(f) {
+			lines = append(lines, "    "+line)
+		}
 	}
 
 	return CommandResult{Message: strings.Join(lines, "\n"), Type: "info"}
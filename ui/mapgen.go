@@ -28,6 +28,7 @@ type MapGenConfig struct {
 	DetailLevel   int // 0=mini, 1=full
 	Buildings     map[string]game.BuildingState
 	AgeKey        string
+	Settlement    game.SettlementState // real layout; empty lays buildings out radially
 }
 
 func eraFromAge(ageKey string) int {
@@ -362,11 +363,50 @@ func getBuildingVisual(key, category string) BuildingVisual {
 	}
 }
 
+// HitMap records which building group each part of a rendered map shows
+type HitMap struct {
+	Center  image.Point // the town centre
+	Step    int         // pixels per settlement tile
+	regions []hitRegion // in draw order, so later regions are on top
+}
+
+type hitRegion struct {
+	key  string
+	rect image.Rectangle
+}
+
+// At returns the key of the building drawn at a pixel, or ""
+func (hm HitMap) At(p image.Point) string {
+	for i := len(hm.regions) - 1; i >= 0; i-- {
+		if p.In(hm.regions[i].rect) {
+			return hm.regions[i].key
+		}
+	}
+	return ""
+}
+
+// Regions returns the pixel regions of every instance of a building
+func (hm HitMap) Regions(key string) []image.Rectangle {
+	var rects []image.Rectangle
+	for _, r := range hm.regions {
+		if r.key == key {
+			rects = append(rects, r.rect)
+		}
+	}
+	return rects
+}
+
 // GenerateMapImage creates a procedural pixel map as an image.RGBA
 func GenerateMapImage(cfg MapGenConfig) *image.RGBA {
+	img, _ := RenderMap(cfg)
+	return img
+}
+
+// RenderMap draws the map and records where each building landed on it
+func RenderMap(cfg MapGenConfig) (*image.RGBA, HitMap) {
 	w, h := cfg.Width, cfg.Height
 	if w < 4 || h < 4 {
-		return image.NewRGBA(image.Rect(0, 0, 1, 1))
+		return image.NewRGBA(image.Rect(0, 0, 1, 1)), HitMap{}
 	}
 
 	era := eraFromAge(cfg.AgeKey)
@@ -375,6 +415,13 @@ func GenerateMapImage(cfg MapGenConfig) *image.RGBA {
 	seed := hashKey(cfg.AgeKey)
 	cx, cy := w/2, h/2
 	dl := cfg.DetailLevel
+	layout := len(cfg.Settlement.Terrain) > 0
+	var grid tileLayout
+	if layout {
+		grid = newTileLayout(cfg.Settlement, w, h)
+		cx, cy = grid.pixel(0.5, 0.5)
+		seed = cfg.Settlement.Seed // the world's own texture, whatever the age
+	}
 
 	// ═══════════════════════════════════════════
 	// 1. BASE TERRAIN
@@ -383,7 +430,7 @@ func GenerateMapImage(cfg MapGenConfig) *image.RGBA {
 		for x := 0; x < w; x++ {
 			n := noise2D(x, y, seed)
 			elev := noise2D(x/4, y/4, seed+200)
-			if elev > 0.65 {
+			if elev > 0.65 && !layout {
 				hillT := (elev - 0.65) / 0.35
 				base := lerp(pal.Ground, pal.GroundAlt, n)
 				img.SetRGBA(x, y, lerp(base, lerp(pal.Hill, pal.HillLight, n), hillT))
@@ -441,13 +488,14 @@ func GenerateMapImage(cfg MapGenConfig) *image.RGBA {
 	}
 
 	// ═══════════════════════════════════════════
-	// 3. RIVER
+	// 3. RIVER (or the settlement's own terrain)
 	// ═══════════════════════════════════════════
 	riverBaseX := float64(w) * 0.28
 	riverW := 3 + dl*3
 	bankW := 1 + dl
-	// No river in space/cosmic
-	if era < 7 {
+	if layout {
+		drawSettlementTerrain(img, w, h, pal, seed, cfg.Settlement, grid)
+	} else if era < 7 { // no river in space/cosmic
 		for y := 0; y < h; y++ {
 			rx := riverBaseX + math.Sin(float64(y)*0.06)*float64(w)*0.10 + math.Sin(float64(y)*0.15)*float64(w)*0.03
 			for dx := -bankW; dx < riverW+bankW; dx++ {
@@ -471,12 +519,19 @@ func GenerateMapImage(cfg MapGenConfig) *image.RGBA {
 	// ═══════════════════════════════════════════
 	// 4. VEGETATION (era-specific)
 	// ═══════════════════════════════════════════
-	drawVegetation(img, w, h, era, dl, pal, seed)
+	if !layout {
+		drawVegetation(img, w, h, era, dl, pal, seed)
+	}
 
 	// ═══════════════════════════════════════════
 	// 5. COLLECT BUILDING PLACEMENTS
 	// ═══════════════════════════════════════════
-	placements := placeBuildingsRadial(cfg.Buildings, cx, cy, w, h, era, dl)
+	var placements []bldInfo
+	if layout {
+		placements = placeBuildingsOnGrid(cfg.Settlement, grid)
+	} else {
+		placements = placeBuildingsRadial(cfg.Buildings, cx, cy, w, h, era, dl)
+	}
 
 	// Sort: furthest first so close buildings draw on top
 	sort.Slice(placements, func(i, j int) bool {
@@ -505,7 +560,15 @@ func GenerateMapImage(cfg MapGenConfig) *image.RGBA {
 	// ═══════════════════════════════════════════
 	drawDecorations(img, w, h, era, dl, pal, seed, placements)
 
-	return img
+	hits := HitMap{Center: image.Pt(cx, cy), Step: 2 + dl*2}
+	if layout {
+		hits.Step = max(1, int(math.Round(grid.ts)))
+	}
+	for _, b := range placements {
+		r := max(1, b.size/2)
+		hits.regions = append(hits.regions, hitRegion{b.key, image.Rect(b.x-r, b.y-r, b.x+r+1, b.y+r+1)})
+	}
+	return img, hits
 }
 
 // ─── Vegetation ──────────────────────────────────────────
@@ -627,6 +690,72 @@ func placeBuildingsRadial(buildings map[string]game.BuildingState, cx, cy, w, h,
 	return placements
 }
 
+// ─── Settlement layout ───────────────────────────────────
+
+// tileLayout maps settlement tiles to pixels, fitting the snapshot to the
+// image: ts pixels per tile, with tile (0,0)'s corner at (ox, oy)
+type tileLayout struct {
+	ts, ox, oy float64
+}
+
+func newTileLayout(s game.SettlementState, w, h int) tileLayout {
+	ts := math.Min(float64(w)/float64(s.Width), float64(h)/float64(s.Height))
+	return tileLayout{
+		ts: ts,
+		ox: float64(w)/2 - (float64(s.MinX)+float64(s.Width)/2)*ts,
+		oy: float64(h)/2 - (float64(s.MinY)+float64(s.Height)/2)*ts,
+	}
+}
+
+// pixel returns the pixel at a point in tile coordinates
+func (l tileLayout) pixel(x, y float64) (int, int) {
+	return int(l.ox + x*l.ts), int(l.oy + y*l.ts)
+}
+
+// tile returns the tile under a pixel
+func (l tileLayout) tile(px, py int) (int, int) {
+	return int(math.Floor((float64(px) - l.ox) / l.ts)), int(math.Floor((float64(py) - l.oy) / l.ts))
+}
+
+// sandColor tints desert tiles; the era palettes have no sand of their own
+var sandColor = c(214, 190, 130)
+
+// drawSettlementTerrain paints the water, forest, hill and desert tiles of
+// the settlement over the base ground
+func drawSettlementTerrain(img *image.RGBA, w, h int, pal TerrainPalette, seed uint64, s game.SettlementState, l tileLayout) {
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			tx, ty := l.tile(x, y)
+			n := noise2D(x, y, seed+7)
+			existing := img.RGBAAt(x, y)
+			switch s.TerrainAt(tx, ty) {
+			case config.TerrainWater:
+				img.SetRGBA(x, y, lerp(lerp(pal.WaterDeep, pal.WaterLight, n), pal.Water, 0.4))
+			case config.TerrainForest:
+				if n > 0.15 {
+					img.SetRGBA(x, y, lerp(existing, lerp(pal.TreeDark, pal.TreeLight, n), 0.85))
+				}
+			case config.TerrainHill:
+				img.SetRGBA(x, y, lerp(existing, lerp(pal.Hill, pal.HillLight, n), 0.75))
+			case config.TerrainDesert:
+				img.SetRGBA(x, y, lerp(existing, lerp(sandColor, pal.GroundAlt, n*0.3), 0.7))
+			}
+		}
+	}
+}
+
+// placeBuildingsOnGrid turns the settlement's placements into pixel positions
+func placeBuildingsOnGrid(s game.SettlementState, l tileLayout) []bldInfo {
+	placements := make([]bldInfo, 0, len(s.Buildings))
+	for _, b := range s.Buildings {
+		half := float64(b.Size) / 2
+		bx, by := l.pixel(float64(b.X)+half, float64(b.Y)+half)
+		size := max(2, int(l.ts*float64(b.Size))-1)
+		placements = append(placements, bldInfo{b.Key, b.Category, bx, by, size})
+	}
+	return placements
+}
+
 // ─── Surroundings ────────────────────────────────────────
 func drawSurroundings(img *image.RGBA, w, h, era, dl int, pal TerrainPalette, seed uint64, placements []bldInfo) {
 	for _, b := range placements {
@@ -27,9 +27,9 @@ func (m *MiniMap) Primitive() tview.Primitive {
 	return m.image
 }
 
-// UpdateState regenerates the map when buildings or age change
+// UpdateState regenerates the map when buildings, age or world change
 func (m *MiniMap) UpdateState(state game.GameState) {
-	h := hashKey(state.Age)
+	h := hashKey(state.Age) ^ state.Settlement.Seed
 	for k, bs := range state.Buildings {
 		if bs.Count > 0 {
 			h ^= hashKey(k) * uint64(bs.Count)
@@ -54,6 +54,7 @@ func (m *MiniMap) UpdateState(state game.GameState) {
 		DetailLevel: 0,
 		Buildings:   state.Buildings,
 		AgeKey:      state.Age,
+		Settlement:  state.Settlement,
 	})
 
 	m.image.SetImage(img)
@@ -95,14 +95,41 @@ func (t *EconomyTab) refreshBuildings(state game.GameState) {
 	}
 	sort.Strings(keys)
 
+	// Buildings whose inputs or power ran short this tick
+	var starved []string
+	for _, key := range keys {
+		if bs := state.Buildings[key]; bs.Count > bs.Disabled && bs.Throughput < 1 {
+			starved = append(starved, fmt.Sprintf("%s %.0f%%", bs.Name, bs.Throughput*100))
+		}
+	}
+	if len(starved) > 0 {
+		fmt.Fprintf(&sb, " [red]Starved:[-] %s\n\n", strings.Join(starved, ", "))
+	}
+
 	for _, key := range keys {
 		bs := state.Buildings[key]
 		icon := "[green]✓[-]"
 		if !bs.CanBuild {
 			icon = "[red]✗[-]"
 		}
-		fmt.Fprintf(&sb, " %s [cyan]%s[-] [gray]x%d[-]\n", icon, bs.Name, bs.Count)
+		off := ""
+		if bs.Disabled > 0 {
+			off = fmt.Sprintf(" [yellow](%d off)[-]", bs.Disabled)
+		}
+		fmt.Fprintf(&sb, " %s [cyan]%s[-] [gray]x%d[-]%s\n", icon, bs.Name, bs.Count, off)
 		fmt.Fprintf(&sb, "   Cost: %s\n", FormatCost(bs.NextCost))
+		if len(bs.Inputs) > 0 {
+			fmt.Fprintf(&sb, "   Inputs: %s/tick each\n", formatInputs(bs.Inputs))
+		}
+		if len(bs.Upkeep) > 0 {
+			fmt.Fprintf(&sb, "   Upkeep: %s/tick each\n", formatInputs(bs.Upkeep))
+		}
+		if bs.PowerDemand > 0 {
+			fmt.Fprintf(&sb, "   Power: %g/tick each, priority %d\n", bs.PowerDemand, bs.PowerPrio)
+		}
+		if bs.Count > bs.Disabled && bs.Throughput < 1 {
+			fmt.Fprintf(&sb, "   [red]Running at %.0f%%[-]\n", bs.Throughput*100)
+		}
 		fmt.Fprintf(&sb, "   [gray]%s[-]\n", bs.Description)
 	}
 
@@ -121,6 +148,20 @@ func (t *EconomyTab) refreshBuildings(state game.GameState) {
 	t.buildingTV.SetText(sb.String())
 }
 
+// formatInputs lists recipe inputs or upkeep, keeping the small per-tick amounts exact
+func formatInputs(inputs map[string]float64) string {
+	keys := make([]string, 0, len(inputs))
+	for k := range inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s:%g", k, inputs[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
 func (t *EconomyTab) refreshVillagers(state game.GameState) {
 	var sb strings.Builder
 	v := state.Villagers
@@ -2,19 +2,36 @@ package ui
 
 import (
 	"fmt"
+	"image"
+	"image/color"
+	"strings"
 
 	"github.com/rivo/tview"
 
+	"github.com/user/ageforge/config"
 	"github.com/user/ageforge/game"
 )
 
-// MapTab displays a full-screen procedural pixel settlement map
+var (
+	cursorColor    = color.RGBA{255, 255, 255, 255}
+	highlightColor = color.RGBA{255, 215, 0, 255}
+)
+
+// MapTab displays a full-screen procedural pixel settlement map with a
+// cursor for inspecting the buildings on it
 type MapTab struct {
 	root     *tview.Flex
 	image    *tview.Image
 	titleTV  *tview.TextView
+	infoTV   *tview.TextView
 	lastHash uint64
 	lastAge  string
+
+	base       *image.RGBA // the map without the cursor drawn on it
+	hits       HitMap
+	cursor     image.Point
+	inspecting bool
+	state      game.GameState
 }
 
 // NewMapTab creates a new full-screen map tab
@@ -27,9 +44,14 @@ func NewMapTab() *MapTab {
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 
+	t.infoTV = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+
 	t.root = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(t.titleTV, 1, 0, false).
-		AddItem(t.image, 0, 1, false)
+		AddItem(t.image, 0, 1, false).
+		AddItem(t.infoTV, 3, 0, false)
 
 	return t
 }
@@ -41,7 +63,10 @@ func (t *MapTab) Root() tview.Primitive {
 
 // Refresh updates the map with current game state
 func (t *MapTab) Refresh(state game.GameState) {
-	h := hashKey(state.Age)
+	t.state = state
+	t.updateInfo()
+
+	h := hashKey(state.Age) ^ state.Settlement.Seed
 	for k, bs := range state.Buildings {
 		if bs.Count > 0 {
 			h ^= hashKey(k) * uint64(bs.Count)
@@ -65,18 +90,177 @@ func (t *MapTab) Refresh(state game.GameState) {
 		totalBuildings += bs.Count
 	}
 
-	img := GenerateMapImage(MapGenConfig{
+	img, hits := RenderMap(MapGenConfig{
 		Width:       pixW,
 		Height:      pixH,
 		DetailLevel: 1,
 		Buildings:   state.Buildings,
 		AgeKey:      state.Age,
+		Settlement:  state.Settlement,
 	})
 
-	t.image.SetImage(img)
+	t.base, t.hits = img, hits
+	if !t.inspecting || !t.cursor.In(img.Bounds()) {
+		t.cursor = hits.Center
+	}
+	t.draw()
 	label := settlementLabel(totalBuildings)
-	t.titleTV.SetText(fmt.Sprintf("[gold]── %s ──[-]", label))
+	title := fmt.Sprintf("[gold]── %s ──[-]", label)
+	if biomes := biomeSummary(state.Settlement.Biomes, 3); biomes != "" {
+		title += "  [gray]" + biomes + "[-]"
+	}
+	t.titleTV.SetText(title)
 
 	t.lastHash = h
 	t.lastAge = state.Age
 }
+
+// Inspecting reports whether the cursor is shown
+func (t *MapTab) Inspecting() bool {
+	return t.inspecting
+}
+
+// MoveCursor moves the cursor a tile at a time, showing it first if hidden
+func (t *MapTab) MoveCursor(dx, dy int) {
+	if t.base == nil {
+		return
+	}
+	if !t.inspecting {
+		t.inspecting = true
+		t.cursor = t.hits.Center
+	} else {
+		step := max(2, t.hits.Step)
+		b := t.base.Bounds()
+		t.cursor.X = min(max(t.cursor.X+dx*step, b.Min.X), b.Max.X-1)
+		t.cursor.Y = min(max(t.cursor.Y+dy*step, b.Min.Y), b.Max.Y-1)
+	}
+	t.draw()
+	t.updateInfo()
+}
+
+// StopInspecting hides the cursor
+func (t *MapTab) StopInspecting() {
+	if !t.inspecting {
+		return
+	}
+	t.inspecting = false
+	t.draw()
+	t.updateInfo()
+}
+
+// Selected returns the key of the building under the cursor, or ""
+func (t *MapTab) Selected() string {
+	if !t.inspecting {
+		return ""
+	}
+	return t.hits.At(t.cursor)
+}
+
+// draw shows the map, outlining every instance of the building under the
+// cursor and marking the cursor with a crosshair
+func (t *MapTab) draw() {
+	if t.base == nil {
+		return
+	}
+	if !t.inspecting {
+		t.image.SetImage(t.base)
+		return
+	}
+	img := image.NewRGBA(t.base.Bounds())
+	copy(img.Pix, t.base.Pix)
+	if key := t.hits.At(t.cursor); key != "" {
+		for _, r := range t.hits.Regions(key) {
+			outlineRect(img, r.Inset(-2), highlightColor)
+		}
+	}
+	// Lines two pixels thick so they survive the half-block downscale
+	for d := 3; d <= 6; d++ {
+		for w := 0; w < 2; w++ {
+			c := t.cursor
+			img.SetRGBA(c.X-d, c.Y+w, cursorColor)
+			img.SetRGBA(c.X+d, c.Y+w, cursorColor)
+			img.SetRGBA(c.X+w, c.Y-d, cursorColor)
+			img.SetRGBA(c.X+w, c.Y+d, cursorColor)
+		}
+	}
+	t.image.SetImage(img)
+}
+
+// outlineRect draws a two-pixel border just inside r
+func outlineRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		for w := 0; w < 2; w++ {
+			img.SetRGBA(x, r.Min.Y+w, c)
+			img.SetRGBA(x, r.Max.Y-1-w, c)
+		}
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for w := 0; w < 2; w++ {
+			img.SetRGBA(r.Min.X+w, y, c)
+			img.SetRGBA(r.Max.X-1-w, y, c)
+		}
+	}
+}
+
+// updateInfo describes the building under the cursor: how many there are,
+// what they do and where they sit on their upgrade chain
+func (t *MapTab) updateInfo() {
+	if !t.inspecting {
+		t.infoTV.SetText("[gray]←↑↓→ inspect buildings · b build · u upgrade[-]")
+		return
+	}
+	key := t.Selected()
+	if key == "" {
+		t.infoTV.SetText("[gray]Open ground — move the cursor onto a building · any other key to stop[-]")
+		return
+	}
+	def := config.BuildingByKey()[key]
+	bs := t.state.Buildings[key]
+
+	line := fmt.Sprintf("[gold]%s[-] ×%d", def.Name, bs.Count)
+	if bs.Disabled > 0 {
+		line += fmt.Sprintf(" [red](%d off)[-]", bs.Disabled)
+	}
+	line += fmt.Sprintf(" [gray]%s[-]", def.Category)
+	if bs.Unlocked {
+		line += "  next: " + FormatCost(bs.NextCost)
+	}
+
+	var effects []string
+	for _, eff := range def.Effects {
+		effects = append(effects, formatEffect(eff))
+	}
+	if len(effects) == 0 {
+		effects = append(effects, "[gray]no effects[-]")
+	}
+
+	var path []string
+	for _, u := range config.BuildingUpgrades() {
+		if u.To == key {
+			path = append(path, "from "+config.BuildingByKey()[u.From].Name)
+		}
+	}
+	if u, ok := config.UpgradesFromKey()[key]; ok {
+		path = append(path, fmt.Sprintf("to %s in %s (%.0f%% of its cost)",
+			config.BuildingByKey()[u.To].Name, config.AgeByKey()[u.MinAge].Name, u.CostScale*100))
+	}
+	upgrades := "[gray]no upgrades[-]"
+	if len(path) > 0 {
+		upgrades = "Upgrades " + strings.Join(path, " · ")
+	}
+
+	t.infoTV.SetText(line + "\n" + strings.Join(effects, " ") + "\n" +
+		upgrades + "  [gray]b build · u upgrade[-]")
+}
+
+// biomeSummary lists the largest shares of the land around the town
+func biomeSummary(biomes []game.BiomeShare, n int) string {
+	var parts []string
+	for i, b := range biomes {
+		if i == n {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", b.Name, b.Share*100))
+	}
+	return strings.Join(parts, " · ")
+}
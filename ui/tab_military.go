@@ -14,7 +14,9 @@ import (
 type MilitaryTab struct {
 	root       *tview.Flex
 	overviewTV *tview.TextView
+	unitsTV    *tview.TextView
 	expedTV    *tview.TextView
+	raidTV     *tview.TextView
 	lootTV     *tview.TextView
 }
 
@@ -27,19 +29,31 @@ func NewMilitaryTab() *MilitaryTab {
 		SetScrollable(true)
 	t.overviewTV.SetBorder(true).SetTitle(" Military Overview ").SetTitleColor(ColorTitle)
 
+	t.unitsTV = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	t.unitsTV.SetBorder(true).SetTitle(" Units ").SetTitleColor(ColorTitle)
+
 	t.expedTV = tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true)
 	t.expedTV.SetBorder(true).SetTitle(" Expeditions ").SetTitleColor(ColorTitle)
 
+	t.raidTV = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	t.raidTV.SetBorder(true).SetTitle(" Raids ").SetTitleColor(ColorTitle)
+
 	t.lootTV = tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true)
 	t.lootTV.SetBorder(true).SetTitle(" Loot History ").SetTitleColor(ColorTitle)
 
-	// Left: overview + loot, Right: expeditions
+	// Left: overview + units + raids + loot, Right: expeditions
 	leftPanel := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(t.overviewTV, 10, 0, false).
+		AddItem(t.unitsTV, 13, 0, false).
+		AddItem(t.raidTV, 8, 0, false).
 		AddItem(t.lootTV, 0, 1, false)
 
 	t.root = tview.NewFlex().SetDirection(tview.FlexColumn).
@@ -57,7 +71,9 @@ func (t *MilitaryTab) Root() tview.Primitive {
 // Refresh updates the military tab with current state
 func (t *MilitaryTab) Refresh(state game.GameState) {
 	t.refreshOverview(state)
+	t.refreshUnits(state)
 	t.refreshExpeditions(state)
+	t.refreshRaids(state)
 	t.refreshLoot(state)
 }
 
@@ -67,6 +83,15 @@ func (t *MilitaryTab) refreshOverview(state game.GameState) {
 
 	fmt.Fprintf(&sb, " [gold]Soldiers:[-]  %d\n", mil.SoldierCount)
 	fmt.Fprintf(&sb, " [gold]Defense:[-]   %.1f\n", mil.DefenseRating)
+	if mil.SoldierCount > 0 {
+		moraleColor := "green"
+		if mil.Morale < 25 {
+			moraleColor = "red"
+		} else if mil.Morale < 100 {
+			moraleColor = "yellow"
+		}
+		fmt.Fprintf(&sb, " [gold]Morale:[-]    [%s]%.0f%%[-]\n", moraleColor, mil.Morale)
+	}
 
 	if mil.MilitaryBonus > 0 {
 		fmt.Fprintf(&sb, " [green]Military Bonus: +%.0f%%[-]\n", mil.MilitaryBonus*100)
@@ -77,14 +102,23 @@ func (t *MilitaryTab) refreshOverview(state game.GameState) {
 
 	sb.WriteString("\n")
 
-	if mil.ActiveExpedition != nil {
-		exp := mil.ActiveExpedition
-		fmt.Fprintf(&sb, " [yellow]Active:[-] %s\n", exp.Name)
-		fmt.Fprintf(&sb, " [yellow]Soldiers deployed:[-] %d\n", exp.Soldiers)
-		bar := ProgressBar(float64(exp.TicksLeft), float64(exp.TicksLeft+1), 20) // approximate
+	fmt.Fprintf(&sb, " [yellow]Expeditions:[-] %d/%d slots  [yellow]Available soldiers:[-] %d\n",
+		len(mil.ActiveExpeditions), mil.ExpeditionSlots, mil.AvailableSoldiers)
+	if len(mil.ActiveExpeditions) == 0 {
+		sb.WriteString(" [gray]No active expeditions[-]\n")
+	}
+	for _, exp := range mil.ActiveExpeditions {
+		fmt.Fprintf(&sb, " [cyan]%s[-] [gray](%s)[-]\n", exp.Name, game.FormatUnits(exp.Units))
+		if exp.Holding {
+			fmt.Fprintf(&sb, " [yellow]Holding before stage %d/%d:[-] %s\n", exp.Stage, exp.Stages, exp.StageName)
+			fmt.Fprintf(&sb, " [green]expedition advance %s[-] | [green]expedition retreat %s[-]\n", exp.Key, exp.Key)
+			continue
+		}
+		if exp.Stages > 1 {
+			fmt.Fprintf(&sb, " [gray]Stage %d/%d: %s[-]\n", exp.Stage, exp.Stages, exp.StageName)
+		}
+		bar := ProgressBar(float64(exp.TotalTicks-exp.TicksLeft), float64(exp.TotalTicks), 20)
 		fmt.Fprintf(&sb, " %s %d ticks remaining\n", bar, exp.TicksLeft)
-	} else {
-		sb.WriteString(" [gray]No active expedition[-]\n")
 	}
 
 	fmt.Fprintf(&sb, "\n [gray]Completed: %d expeditions[-]", mil.CompletedCount)
@@ -92,10 +126,61 @@ func (t *MilitaryTab) refreshOverview(state game.GameState) {
 	t.overviewTV.SetText(sb.String())
 }
 
+func (t *MilitaryTab) refreshUnits(state game.GameState) {
+	var sb strings.Builder
+	mil := state.Military
+
+	if len(mil.Units) == 0 {
+		sb.WriteString(" [gray]No units yet[-]\n")
+	}
+	for _, u := range mil.Units {
+		name := fmt.Sprintf("[cyan]%-20s[-]", u.Name)
+		if !u.Unlocked {
+			name = fmt.Sprintf("[gray]%-20s[-]", u.Name)
+		}
+		fmt.Fprintf(&sb, " %s %3d [gray](%d home)[-]  ATK %-4.1f DEF %-4.1f\n", name, u.Count, u.Home, u.Attack, u.Defense)
+	}
+	if len(mil.Upkeep) > 0 {
+		fmt.Fprintf(&sb, " [yellow]Upkeep:[-] %s /tick\n", FormatCost(mil.Upkeep))
+	}
+	fmt.Fprintf(&sb, " [yellow]Training:[-] %d lane(s)", mil.TrainingSlots)
+	if len(mil.Training) == 0 {
+		sb.WriteString(" [gray]— queue empty[-]\n")
+	} else {
+		fmt.Fprintf(&sb, ", %d ticks queued\n", mil.TrainingTicks)
+	}
+	for _, o := range mil.Training {
+		bar := ProgressBar(float64(o.TotalTicks-o.TicksLeft), float64(o.TotalTicks), 10)
+		fmt.Fprintf(&sb, "  %d %s %s\n", o.Count, o.Name, bar)
+	}
+	sb.WriteString(" [gray]Commands: train <unit> <count|max>, recruit soldier <count>[-]")
+
+	t.unitsTV.SetText(sb.String())
+}
+
 func (t *MilitaryTab) refreshExpeditions(state game.GameState) {
 	var sb strings.Builder
 	mil := state.Military
 
+	// Ranked view: best loot per tick of soldier time among winnable launches
+	var best []game.ExpeditionInfo
+	for _, exp := range mil.Expeditions {
+		if exp.Rank > 0 {
+			best = append(best, exp)
+		}
+	}
+	sort.Slice(best, func(i, j int) bool { return best[i].Rank < best[j].Rank })
+	if len(best) > 3 {
+		best = best[:3]
+	}
+	if len(best) > 0 {
+		sb.WriteString(" [gold]Best per soldier-tick:[-]\n")
+		for _, exp := range best {
+			fmt.Fprintf(&sb, "  %d. [cyan]%s[-] %.2f\n", exp.Rank, exp.Name, exp.Forecast.LootPerSoldierTick)
+		}
+		sb.WriteString("\n")
+	}
+
 	if len(mil.Expeditions) == 0 {
 		sb.WriteString(" [gray]No expeditions available yet[-]\n")
 		sb.WriteString(" [gray]Reach Bronze Age and recruit soldiers[-]\n")
@@ -109,34 +194,75 @@ func (t *MilitaryTab) refreshExpeditions(state game.GameState) {
 				statusIcon = "[red]▸[-]"
 			}
 
-			diffColor := "green"
-			if exp.Difficulty > 0.5 {
-				diffColor = "red"
-			} else if exp.Difficulty > 0.3 {
-				diffColor = "yellow"
+			// Battles are deterministic, so the forecast with the army a
+			// launch would send is exact
+			f := exp.Forecast
+			enemyColor := "green"
+			outcome := fmt.Sprintf("[green]victory[-], %d lost", f.SoldiersLost)
+			if !f.Won {
+				enemyColor = "red"
+				outcome = fmt.Sprintf("[red]defeat at stage %d[-], %d lost", len(f.Stages), f.SoldiersLost)
+			} else if f.SoldiersLost*2 > game.UnitTotal(f.Army) {
+				enemyColor = "yellow"
 			}
 
-			fmt.Fprintf(&sb, " %s [cyan]%s[-]\n", statusIcon, exp.Name)
+			if exp.Stages > 1 {
+				fmt.Fprintf(&sb, " %s [cyan]%s[-] [gold](%d-stage campaign)[-]\n", statusIcon, exp.Name, exp.Stages)
+			} else {
+				fmt.Fprintf(&sb, " %s [cyan]%s[-]\n", statusIcon, exp.Name)
+			}
 			fmt.Fprintf(&sb, "   [gray]%s[-]\n", exp.Description)
-			fmt.Fprintf(&sb, "   Soldiers: %d  Duration: %d ticks  Difficulty: [%s]%.0f%%[-]\n",
-				exp.SoldiersNeeded, exp.Duration, diffColor, exp.Difficulty*100)
+			fmt.Fprintf(&sb, "   Soldiers: %d  Duration: %d ticks\n", exp.SoldiersNeeded, exp.Duration)
+			fmt.Fprintf(&sb, "   Enemy: [%s]%s[-]\n", enemyColor, game.FormatUnits(exp.Enemies[0]))
+			fmt.Fprintf(&sb, "   Forecast: %s, %.0f loot (%.2f/soldier-tick)\n", outcome, f.LootTotal, f.LootPerSoldierTick)
 
 			if exp.CanLaunch {
 				fmt.Fprintf(&sb, "   [green]expedition %s[-]\n", exp.Key)
-			} else if mil.ActiveExpedition != nil {
+			} else if exp.Underway {
 				sb.WriteString("   [gray]expedition in progress[-]\n")
+			} else if len(mil.ActiveExpeditions) >= mil.ExpeditionSlots {
+				sb.WriteString("   [gray]no free expedition slot[-]\n")
 			} else {
-				fmt.Fprintf(&sb, "   [red]need %d soldiers[-]\n", exp.SoldiersNeeded)
+				fmt.Fprintf(&sb, "   [red]need %d soldiers (%d available)[-]\n", exp.SoldiersNeeded, mil.AvailableSoldiers)
 			}
 			sb.WriteString("\n")
 		}
 	}
 
-	sb.WriteString(" [gray]Commands: expedition <key>[-]\n")
+	sb.WriteString(" [gray]Commands: expedition <key> <unit:count>..., expedition info <key>, expedition best[-]\n")
 
 	t.expedTV.SetText(sb.String())
 }
 
+func (t *MilitaryTab) refreshRaids(state game.GameState) {
+	var sb strings.Builder
+	mil := state.Military
+	raids := mil.Raids
+
+	if raid := raids.Incoming; raid != nil {
+		color := "green"
+		if raid.Strength > mil.DefenseRating {
+			color = "red"
+		}
+		fmt.Fprintf(&sb, " [red]⚔ %s incoming![-]\n", raid.Name)
+		fmt.Fprintf(&sb, " Strength: [%s]%.0f[-] vs defense %.0f\n", color, raid.Strength, mil.DefenseRating)
+		fmt.Fprintf(&sb, " Raiders: %s\n", game.FormatUnits(raid.Enemy))
+		fmt.Fprintf(&sb, " Arrives in [yellow]%d[-] ticks\n", raid.TicksLeft)
+	} else if raids.NextRaidIn > 0 {
+		fmt.Fprintf(&sb, " [gray]No raiders sighted (next in ~%d ticks)[-]\n", raids.NextRaidIn)
+	} else {
+		sb.WriteString(" [gray]No raiders sighted[-]\n")
+		sb.WriteString(" [gray]Raids begin in the Bronze Age[-]\n")
+	}
+
+	fmt.Fprintf(&sb, "\n [green]Repelled: %d[-]  [red]Lost: %d[-]\n", raids.Repelled, raids.Lost)
+	if raids.LastResult != "" {
+		fmt.Fprintf(&sb, " [gray]Last: %s[-]", raids.LastResult)
+	}
+
+	t.raidTV.SetText(sb.String())
+}
+
 func (t *MilitaryTab) refreshLoot(state game.GameState) {
 	var sb strings.Builder
 	mil := state.Military
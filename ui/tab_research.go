@@ -125,7 +125,9 @@ func (t *ResearchTab) refreshTree(state game.GameState) {
 			}
 
 			costStr := ""
-			if !ts.Researched {
+			if !ts.Researched && ts.Campaign != "" {
+				costStr = " [gray](campaign reward)[-]"
+			} else if !ts.Researched {
 				costStr = fmt.Sprintf(" [gray](%.0f knowledge)[-]", ts.Cost)
 			}
 
@@ -191,7 +193,9 @@ func (t *ResearchTab) refreshBonuses(state game.GameState) {
 		for _, key := range keys {
 			value := state.Research.Bonuses[key]
 			name := formatBonusName(key)
-			if value > 0 {
+			if key == "expedition_slots" {
+				fmt.Fprintf(&sb, " [green]+%.0f[-] %s\n", value, name)
+			} else if value > 0 {
 				fmt.Fprintf(&sb, " [green]+%.0f%%[-] %s\n", value*100, name)
 			} else {
 				fmt.Fprintf(&sb, " [red]%.0f%%[-] %s\n", value*100, name)
@@ -213,6 +217,8 @@ func formatBonusName(key string) string {
 		return "Military Power"
 	case "expedition_reward":
 		return "Expedition Rewards"
+	case "expedition_slots":
+		return "Expedition Slots"
 	case "research_speed":
 		return "Research Speed"
 	case "build_cost":
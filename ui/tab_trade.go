@@ -7,6 +7,7 @@ import (
 
 	"github.com/rivo/tview"
 
+	"github.com/user/ageforge/config"
 	"github.com/user/ageforge/game"
 )
 
@@ -95,8 +96,23 @@ func (t *TradeTab) refreshExchange(state game.GameState) {
 				rateColor = "yellow"
 			}
 
-			fmt.Fprintf(&sb, " %s → %s: [%s]%.2f[-]%s\n",
-				info.From, info.To, rateColor, info.Rate, pressureStr)
+			marketStr := ""
+			if info.Market > 1.01 {
+				marketStr = fmt.Sprintf(" [green]mkt ×%.2f[-]", info.Market)
+			} else if info.Market < 0.99 {
+				marketStr = fmt.Sprintf(" [red]mkt ×%.2f[-]", info.Market)
+			}
+
+			fmt.Fprintf(&sb, " %s → %s: [%s]%.2f[-]%s%s\n",
+				info.From, info.To, rateColor, info.Rate, pressureStr, marketStr)
+		}
+	}
+
+	// Faction market prices
+	if len(trade.Prices) > 0 {
+		sb.WriteString("\n [gold]Market Prices:[-] [gray](faction supply and demand)[-]\n")
+		for _, line := range marketPriceLines(trade.Prices) {
+			fmt.Fprintf(&sb, "   %s\n", line)
 		}
 	}
 
@@ -122,9 +138,22 @@ func (t *TradeTab) refreshRoutes(state game.GameState) {
 	if len(trade.ActiveRoutes) > 0 {
 		sb.WriteString(" [gold]Active Routes:[-]\n\n")
 		for _, route := range trade.ActiveRoutes {
-			fmt.Fprintf(&sb, " [green]▸[-] [cyan]%s[-]\n", route.Name)
+			icon := "[green]▸[-]"
+			if route.Suspended {
+				icon = "[red]‖[-]"
+			} else if route.Stalled {
+				icon = "[yellow]‖[-]"
+			}
+			fmt.Fprintf(&sb, " %s [cyan]%s[-]%s\n", icon, route.Name, routePartnerLabel(route.Partner))
 			fmt.Fprintf(&sb, "   Export: %s\n", formatResMap(route.Export))
-			fmt.Fprintf(&sb, "   Import: %s\n", formatResMap(route.Import))
+			fmt.Fprintf(&sb, "   Import: %s %s\n", formatResMap(route.Import), formatYield(route.Yield))
+			if route.Suspended {
+				sb.WriteString("   [red]Suspended until relations mend[-]\n\n")
+				continue
+			}
+			if route.Stalled {
+				sb.WriteString("   [yellow]Stalled: last cycle couldn't pay its exports[-]\n")
+			}
 			bar := ProgressBar(float64(route.TicksLeft), float64(route.TicksLeft+1), 15)
 			fmt.Fprintf(&sb, "   %s %d ticks  [gray](%d cycles)[-]\n\n", bar, route.TicksLeft, route.CyclesDone)
 		}
@@ -138,11 +167,13 @@ func (t *TradeTab) refreshRoutes(state game.GameState) {
 			if route.CanStart {
 				statusIcon = "[green]✓[-]"
 			}
-			fmt.Fprintf(&sb, " %s [cyan]%s[-]\n", statusIcon, route.Name)
+			fmt.Fprintf(&sb, " %s [cyan]%s[-]%s\n", statusIcon, route.Name, routePartnerLabel(route.Partner))
 			fmt.Fprintf(&sb, "   [gray]%s[-]\n", route.Description)
-			fmt.Fprintf(&sb, "   Export: %s → Import: %s\n", formatResMap(route.Export), formatResMap(route.Import))
+			fmt.Fprintf(&sb, "   Export: %s → Import: %s %s\n", formatResMap(route.Export), formatResMap(route.Import), formatYield(route.Yield))
 			if route.CanStart {
 				fmt.Fprintf(&sb, "   [green]trade route start %s[-]\n", route.Key)
+			} else if !route.PartnerOpen {
+				sb.WriteString("   [red]partner won't trade: undiscovered, rival or embargo[-]\n")
 			} else {
 				fmt.Fprintf(&sb, "   [red]need %d %s[-]\n", route.MinCount, route.RequiredBld)
 			}
@@ -188,7 +219,7 @@ func (t *TradeTab) refreshDiplomacy(state game.GameState) {
 				statusColor = "green"
 			case "friendly":
 				statusColor = "cyan"
-			case "rival":
+			case "rival", "war":
 				statusColor = "red"
 			case "embargo":
 				statusColor = "yellow"
@@ -211,14 +242,133 @@ func (t *TradeTab) refreshDiplomacy(state game.GameState) {
 			fmt.Fprintf(&sb, " %-20s [%s][%s][-]  Op: [%s]%d[-]%s  [gray](%d trades)[-]\n",
 				faction.Name, statusColor, faction.Status, opinionColor, faction.Opinion,
 				bonusStr, faction.TradeCount)
+			fmt.Fprintf(&sb, "   %s\n", factionEconomyLine(faction))
+			for _, line := range factionAgendaLines(faction) {
+				fmt.Fprintf(&sb, "   %s\n", line)
+			}
 		}
 	}
 
 	sb.WriteString("\n [gray]Commands: diplomacy ally/rival/embargo/gift/neutral <faction>[-]\n")
+	sb.WriteString(" [gray]Agenda:   diplomacy accept/decline/break/fulfill/refuse <faction>[-]\n")
+	sb.WriteString(" [gray]War:      diplomacy peace <faction>, or win its war front expedition[-]\n")
 
 	t.diplomacyTV.SetText(sb.String())
 }
 
+// routePartnerLabel names the faction a route trades with
+func routePartnerLabel(partner string) string {
+	if partner == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [gray]with %s[-]", config.FactionByKey()[partner].Name)
+}
+
+// marketPriceLines lists price indexes, three per line, scarcest first
+func marketPriceLines(prices map[string]float64) []string {
+	keys := make([]string, 0, len(prices))
+	for k := range prices {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if prices[keys[i]] != prices[keys[j]] {
+			return prices[keys[i]] > prices[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	var lines, row []string
+	for _, k := range keys {
+		color := "white"
+		if prices[k] > 1.05 {
+			color = "green"
+		} else if prices[k] < 0.95 {
+			color = "red"
+		}
+		row = append(row, fmt.Sprintf("%s [%s]%.2f[-]", k, color, prices[k]))
+		if len(row) == 3 {
+			lines = append(lines, strings.Join(row, "  "))
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		lines = append(lines, strings.Join(row, "  "))
+	}
+	return lines
+}
+
+// formatYield shows a route's market yield when it differs from normal
+func formatYield(yield float64) string {
+	switch {
+	case yield > 1.01:
+		return fmt.Sprintf("[green](×%.2f)[-]", yield)
+	case yield < 0.99:
+		return fmt.Sprintf("[red](×%.2f)[-]", yield)
+	}
+	return ""
+}
+
+// factionEconomyLine shows how full a faction's stockpiles are
+func factionEconomyLine(f game.FactionInfo) string {
+	var parts []string
+	for _, g := range f.Economy {
+		fill := 0.0
+		if g.Capacity > 0 {
+			fill = g.Stock / g.Capacity
+		}
+		color := "white"
+		if fill < 0.25 {
+			color = "red"
+		} else if fill > 0.75 {
+			color = "green"
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s]%.0f%%[-]", g.Resource, color, fill*100))
+	}
+	line := "[gray]Stock:[-] " + strings.Join(parts, ", ")
+	if f.Disruption > 0 {
+		line += fmt.Sprintf("  [yellow]disrupted %.0f%%[-]", f.Disruption*100)
+	}
+	return line
+}
+
+// factionAgendaLines describes a faction's war or border incidents, treaty,
+// proposal and open request
+func factionAgendaLines(f game.FactionInfo) []string {
+	var lines []string
+	if f.Status == "war" {
+		lines = append(lines, fmt.Sprintf("[red]At war:[-] war score %d, they sue for peace at %d — peace now costs %.0f gold",
+			f.WarScore, f.SurrenderAt, f.PeaceTribute))
+	} else if f.Incidents > 0 {
+		lines = append(lines, fmt.Sprintf("[red]Border incidents:[-] %d of %d before war — raise opinion above -50 to ease tensions",
+			f.Incidents, f.WarIncidents))
+	}
+	if f.Treaty.Key != "" {
+		lines = append(lines, fmt.Sprintf("[green]Treaty:[-] %s (%dt left) — %s",
+			f.Treaty.Name, f.Treaty.TicksLeft, treatyTermsText(f.Treaty)))
+	}
+	if f.Proposal.Key != "" {
+		lines = append(lines, fmt.Sprintf("[cyan]Proposes:[-] %s for %dt — %s [gray](%dt to answer)[-]",
+			f.Proposal.Name, f.Proposal.Duration, treatyTermsText(f.Proposal), f.Proposal.TicksLeft))
+	}
+	if r := f.Request; r.Key != "" {
+		what := formatResMap(r.Resources)
+		if r.Expedition != "" {
+			what = fmt.Sprintf("win %s (expedition %s)", r.ExpeditionName, r.Expedition)
+		}
+		lines = append(lines, fmt.Sprintf("[yellow]Requests:[-] %s — %s [gray](%dt left, +%d/-%d opinion)[-]",
+			r.Name, what, r.TicksLeft, r.Reward, r.Penalty))
+	}
+	return lines
+}
+
+// treatyTermsText joins a treaty's benefits and obligations for display
+func treatyTermsText(t game.TreatyInfo) string {
+	text := strings.Join(t.Benefits, ", ")
+	if len(t.Obligations) > 0 {
+		text += "; you owe " + strings.Join(t.Obligations, ", ")
+	}
+	return text
+}
+
 // formatResMap formats a resource map for display
 func formatResMap(m map[string]float64) string {
 	if len(m) == 0 {
@@ -19,31 +19,50 @@ type WikiTab struct {
 	pages       []wikiPage
 	current     int
 	lastRendered int
+	lastState   game.GameState
+
+	// Search and cross-link navigation (see wiki_search.go)
+	index         []wikiIndexEntry
+	searching     bool
+	query         string
+	results       []wikiIndexEntry
+	selected      int
+	focus         string // key of the highlighted entry
+	focusLinks    []string
+	linkIdx       int
+	history       []string
+	pendingScroll bool
 }
 
 type wikiPage struct {
-	title   string
-	render  func(state game.GameState) string
+	key    string // file name stem for static exports
+	title  string
+	render func(w WikiWriter, state game.GameState)
+}
+
+// wikiPages returns every wiki page in navigation order
+func wikiPages() []wikiPage {
+	return []wikiPage{
+		{key: "overview", title: "Overview", render: wikiOverview},
+		{key: "getting-started", title: "Getting Started", render: wikiGettingStarted},
+		{key: "resources", title: "Resources", render: wikiResources},
+		{key: "buildings", title: "Buildings", render: wikiBuildings},
+		{key: "villagers", title: "Villagers", render: wikiVillagers},
+		{key: "research", title: "Research", render: wikiResearch},
+		{key: "military", title: "Military", render: wikiMilitary},
+		{key: "factions", title: "Factions", render: wikiFactions},
+		{key: "ages", title: "Ages", render: wikiAges},
+		{key: "events", title: "Events", render: wikiEvents},
+		{key: "prestige", title: "Prestige", render: wikiPrestige},
+		{key: "commands", title: "Commands", render: wikiCommands},
+		{key: "strategy", title: "Tips & Strategy", render: wikiStrategy},
+	}
 }
 
 // NewWikiTab creates the wiki tab
 func NewWikiTab() *WikiTab {
 	t := &WikiTab{lastRendered: -1}
-
-	t.pages = []wikiPage{
-		{title: "Overview", render: wikiOverview},
-		{title: "Getting Started", render: wikiGettingStarted},
-		{title: "Resources", render: wikiResources},
-		{title: "Buildings", render: wikiBuildings},
-		{title: "Villagers", render: wikiVillagers},
-		{title: "Research", render: wikiResearch},
-		{title: "Military", render: wikiMilitary},
-		{title: "Ages", render: wikiAges},
-		{title: "Events", render: wikiEvents},
-		{title: "Prestige", render: wikiPrestige},
-		{title: "Commands", render: wikiCommands},
-		{title: "Tips & Strategy", render: wikiStrategy},
-	}
+	t.pages = wikiPages()
 
 	t.nav = tview.NewTextView().
 		SetDynamicColors(true)
@@ -51,6 +70,7 @@ func NewWikiTab() *WikiTab {
 
 	t.content = tview.NewTextView().
 		SetDynamicColors(true).
+		SetRegions(true).
 		SetScrollable(true).
 		SetWordWrap(true)
 	t.content.SetBorder(true)
@@ -66,6 +86,7 @@ func NewWikiTab() *WikiTab {
 func (t *WikiTab) PrevPage() {
 	if t.current > 0 {
 		t.current--
+		t.clearFocus()
 		t.content.ScrollToBeginning()
 	}
 }
@@ -74,6 +95,7 @@ func (t *WikiTab) PrevPage() {
 func (t *WikiTab) NextPage() {
 	if t.current < len(t.pages)-1 {
 		t.current++
+		t.clearFocus()
 		t.content.ScrollToBeginning()
 	}
 }
@@ -82,6 +104,7 @@ func (t *WikiTab) NextPage() {
 func (t *WikiTab) GoToPage(idx int) {
 	if idx >= 0 && idx < len(t.pages) {
 		t.current = idx
+		t.clearFocus()
 		t.content.ScrollToBeginning()
 	}
 }
@@ -105,6 +128,8 @@ func (t *WikiTab) Root() tview.Primitive {
 
 // Refresh updates the wiki with live game data
 func (t *WikiTab) Refresh(state game.GameState) {
+	t.lastState = state
+
 	// Update nav
 	var nav strings.Builder
 	for i, p := range t.pages {
@@ -116,182 +141,292 @@ func (t *WikiTab) Refresh(state game.GameState) {
 	}
 	nav.WriteString("\n [gray]↑↓ to navigate[-]")
 	nav.WriteString("\n [gray]PgUp/PgDn to scroll[-]")
-	t.nav.SetText(nav.String())
+	nav.WriteString("\n [gray]/ to search[-]")
+	nav.WriteString(t.linksNav())
+	if t.searching {
+		t.nav.SetText(t.searchNav())
+	} else {
+		t.nav.SetText(nav.String())
+	}
 
 	// Update content — only reset scroll when page changes
 	pageChanged := t.current != t.lastRendered
 	t.content.SetTitle(fmt.Sprintf(" %s ", t.pages[t.current].title))
-	t.content.SetText(t.pages[t.current].render(state))
+	w := newTviewWikiWriter()
+	t.pages[t.current].render(w, state)
+	t.content.SetText(w.String())
 	if pageChanged {
 		t.content.ScrollToBeginning()
 		t.lastRendered = t.current
 	}
+
+	// Highlight the focused entry; scroll to it only right after a jump so
+	// PgUp/PgDn still work afterwards
+	if t.focus != "" {
+		if t.pendingScroll {
+			t.content.Highlight("e:" + t.focus).ScrollToHighlight()
+			t.pendingScroll = false
+		} else if t.linkIdx < len(t.focusLinks) {
+			t.content.Highlight("e:"+t.focus, "l:"+t.focusLinks[t.linkIdx])
+		}
+	}
 }
 
 // ===== Wiki Pages =====
 
-func wikiOverview(_ game.GameState) string {
-	return `[gold]AgeForge — A CLI Idle Empire Builder[-]
-
-AgeForge is an idle/clicker game where you build a civilization
-from nothing. Starting in the Primitive Age with just your
-bare hands, you gather resources, build structures, recruit
-villagers, and advance through 22 ages — from primitive
-survival to galactic transcendence.
-
-[gold]Core Loop[-]
-  1. [cyan]Gather[-] resources manually
-  2. [cyan]Build[-] structures for housing and production
-  3. [cyan]Recruit[-] villagers and assign them to tasks
-  4. [cyan]Research[-] technologies for permanent bonuses
-  5. [cyan]Send expeditions[-] for loot and resources
-  6. [cyan]Advance[-] to the next age when requirements are met
-
-[gold]Key Concepts[-]
-  • [yellow]Resources[-] are capped by storage. Build storage to hold more.
-  • [yellow]Villagers[-] eat food every tick. Balance food workers vs others.
-  • [yellow]Buildings[-] cost more each time (scaling costs).
-  • [yellow]Ages[-] require both resources AND buildings to advance.
-  • [yellow]Wonders[-] are unique buildings that take many ticks to build.
-  • [yellow]Research[-] costs knowledge and unlocks permanent bonuses.
-  • [yellow]Events[-] happen randomly — some help, some hurt.
-  • [yellow]Milestones[-] reward permanent bonuses for achievements.
-  • [yellow]Prestige[-] lets you reset at Medieval+ for permanent bonuses.
-
-[gold]Game Speed[-]
-  The game ticks every 2 seconds. Production and consumption
-  happen each tick. This is designed as a long-term idle game —
-  later ages take weeks or months to reach.
-
-  Your game auto-saves when you press ESC. Use 'save' and
-  'load' commands for manual saves.`
+func wikiOverview(w WikiWriter, _ game.GameState) {
+	w.Title("AgeForge — A CLI Idle Empire Builder")
+	w.Para(
+		"AgeForge is an idle/clicker game where you build a civilization",
+		"from nothing. Starting in the Primitive Age with just your",
+		"bare hands, you gather resources, build structures, recruit",
+		"villagers, and advance through 22 ages — from primitive",
+		"survival to galactic transcendence.",
+	)
+
+	w.Heading("Core Loop")
+	w.Step(1, w.Cmd("Gather")+" resources manually")
+	w.Step(2, w.Cmd("Build")+" structures for housing and production")
+	w.Step(3, w.Cmd("Recruit")+" villagers and assign them to tasks")
+	w.Step(4, w.Cmd("Research")+" technologies for permanent bonuses")
+	w.Step(5, w.Cmd("Send expeditions")+" for loot and resources")
+	w.Step(6, w.Cmd("Advance")+" to the next age when requirements are met")
+
+	w.Heading("Key Concepts")
+	w.Bullet(w.Em("Resources") + " are capped by storage. Build storage to hold more.")
+	w.Bullet(w.Em("Villagers") + " eat food every tick. Balance food workers vs others.")
+	w.Bullet(w.Em("Buildings") + " cost more each time (scaling costs).")
+	w.Bullet(w.Em("Ages") + " require both resources AND buildings to advance.")
+	w.Bullet(w.Em("Wonders") + " are unique buildings that take many ticks to build.")
+	w.Bullet(w.Em("Research") + " costs knowledge and unlocks permanent bonuses.")
+	w.Bullet(w.Em("Events") + " happen randomly — some help, some hurt.")
+	w.Bullet(w.Em("Milestones") + " reward permanent bonuses for achievements.")
+	w.Bullet(w.Em("Prestige") + " lets you reset at Medieval+ for permanent bonuses.")
+
+	w.Heading("Game Speed")
+	w.Para(
+		"The game ticks every 2 seconds. Production and consumption",
+		"happen each tick. This is designed as a long-term idle game —",
+		"later ages take weeks or months to reach.",
+	)
+	w.Para(
+		"Your game auto-saves when you press ESC. Use 'save' and",
+		"'load' commands for manual saves.",
+	)
 }
 
-func wikiGettingStarted(_ game.GameState) string {
-	return `[gold]Getting Started[-]
-
-You begin in the [yellow]Primitive Age[-] with 15 food, 12 wood,
-and nothing else. Here's your first steps:
-
-[gold]Step 1: Gather Wood[-]
-  Type: [cyan]gather wood[-]
-  You get 3 wood per gather. You need more for your first stash.
-
-[gold]Step 2: Build a Stash[-]
-  Type: [cyan]build stash[-]
-  Stashes provide +100 storage. You need more storage before
-  you can hold enough resources for the Stone Age (500 food).
-
-[gold]Step 3: Build a Hut[-]
-  Type: [cyan]build hut[-]
-  Huts provide +2 population capacity. You need housing
-  before you can recruit villagers.
-
-[gold]Step 4: Build an Altar[-]
-  Type: [cyan]build altar[-]
-  Altars slowly generate knowledge (+0.01/tick). You need
-  200 knowledge and 5 altars for the Stone Age.
-
-[gold]Step 5: Recruit Workers & Shamans[-]
-  Type: [cyan]recruit worker[-] or [cyan]recruit shaman[-]
-  Workers gather food/wood (0.35/tick). Shamans gather
-  knowledge (0.08/tick). You need both to advance.
-
-[gold]Step 6: Assign Villagers[-]
-  Type: [cyan]assign worker food[-] or [cyan]assign shaman knowledge[-]
-  Each worker eats 0.10 food/tick, shamans eat 0.2/tick.
-  1 food worker can sustain 1 shaman with food to spare.
-
-[gold]Step 7: Keep Building[-]
-  Build more huts, stashes, and altars. Recruit and assign
-  villagers. Watch the age progress bar — when all
-  requirements turn [green]green[-], you'll advance!
-
-[gold]The Food Balance[-]
-  This is the most important early game concept:
-  • Each worker eats [yellow]0.10 food/tick[-]
-  • Each worker gathers [yellow]0.35 resource/tick[-]
-  • So 1 food worker produces net +0.25 for others
-  • 1 food worker covers ~1 shaman (0.20 food) or 2.5 workers
-
-[gold]Storage Matters[-]
-  Base storage is only [yellow]50[-] per resource. Resources stop
-  accumulating when storage is full! Build Stashes
-  (Primitive Age), Storage Pits (Stone Age), and Warehouses
-  (Bronze Age) to increase caps.`
+func wikiGettingStarted(w WikiWriter, _ game.GameState) {
+	w.Title("Getting Started")
+	w.Para(
+		"You begin in the "+w.Link("primitive_age", "Primitive Age")+" with 15 food, 12 wood,",
+		"and nothing else. Here's your first steps:",
+	)
+
+	w.Heading("Step 1: Gather Wood")
+	w.Para(
+		"Type: "+w.Cmd("gather wood"),
+		"You get 3 wood per gather. You need more for your first stash.",
+	)
+
+	w.Heading("Step 2: Build a Stash")
+	w.Para(
+		"Type: "+w.Cmd("build stash"),
+		w.Link("stash", "Stashes")+" provide +100 storage. You need more storage before",
+		"you can hold enough resources for the Stone Age (500 food).",
+	)
+
+	w.Heading("Step 3: Build a Hut")
+	w.Para(
+		"Type: "+w.Cmd("build hut"),
+		w.Link("hut", "Huts")+" provide +2 population capacity. You need housing",
+		"before you can recruit villagers.",
+	)
+
+	w.Heading("Step 4: Build an Altar")
+	w.Para(
+		"Type: "+w.Cmd("build altar"),
+		w.Link("altar", "Altars")+" slowly generate knowledge (+0.01/tick). You need",
+		"200 knowledge and 5 altars for the Stone Age.",
+	)
+
+	w.Heading("Step 5: Recruit Workers & Shamans")
+	w.Para(
+		"Type: "+w.Cmd("recruit worker")+" or "+w.Cmd("recruit shaman"),
+		w.Link("worker", "Workers")+" gather food/wood (0.35/tick). "+w.Link("shaman", "Shamans")+" gather",
+		"knowledge (0.08/tick). You need both to advance.",
+	)
+
+	w.Heading("Step 6: Assign Villagers")
+	w.Para(
+		"Type: "+w.Cmd("assign worker food")+" or "+w.Cmd("assign shaman knowledge"),
+		"Each worker eats 0.10 food/tick, shamans eat 0.2/tick.",
+		"1 food worker can sustain 1 shaman with food to spare.",
+	)
+
+	w.Heading("Step 7: Keep Building")
+	w.Para(
+		"Build more huts, stashes, and altars. Recruit and assign",
+		"villagers. Watch the age progress bar — when all",
+		"requirements turn "+w.Good("green")+", you'll advance!",
+	)
+
+	w.Heading("The Food Balance")
+	w.Para("This is the most important early game concept:")
+	w.Bullet("Each worker eats " + w.Em("0.10 food/tick"))
+	w.Bullet("Each worker gathers " + w.Em("0.35 resource/tick"))
+	w.Bullet("So 1 food worker produces net +0.25 for others")
+	w.Bullet("1 food worker covers ~1 shaman (0.20 food) or 2.5 workers")
+
+	w.Heading("Storage Matters")
+	w.Para(
+		"Base storage is only "+w.Em("50")+" per resource. Resources stop",
+		"accumulating when storage is full! Build "+w.Link("stash", "Stashes"),
+		"(Primitive Age), "+w.Link("storage_pit", "Storage Pits")+" (Stone Age), and "+w.Link("warehouse", "Warehouses"),
+		"(Bronze Age) to increase caps.",
+	)
 }
 
-func wikiResources(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Resources[-]\n\n")
-	sb.WriteString("Resources are the core currency of the game. Each has\n")
-	sb.WriteString("a storage cap — once full, production is wasted.\n\n")
+func wikiResources(w WikiWriter, state game.GameState) {
+	w.Title("Resources")
+	w.Para(
+		"Resources are the core currency of the game. Each has",
+		"a storage cap — once full, production is wasted.",
+	)
 
-	defs := config.BaseResources()
-	for _, def := range defs {
+	ages := config.AgeByKey()
+	for _, def := range config.BaseResources() {
 		rs, exists := state.Resources[def.Key]
-		sb.WriteString(fmt.Sprintf("[cyan]%s[-] [gray](%s)[-]\n", def.Name, def.Key))
-		sb.WriteString(fmt.Sprintf("  %s\n", def.Description))
-		sb.WriteString(fmt.Sprintf("  Unlocks in: [yellow]%s[-]\n", def.Age))
+		w.Entry(def.Name, def.Key)
+		w.Detail(def.Description)
+		w.Detail("Unlocks in: " + w.Link(def.Age, ages[def.Age].Name))
 		if exists && rs.Unlocked {
-			sb.WriteString(fmt.Sprintf("  [orange]Current: %.0f / %.0f  Rate: %s[-]\n",
-				rs.Amount, rs.Storage, FormatRate(rs.Rate)))
+			w.Detail(w.Live(fmt.Sprintf("Current: %.0f / %.0f  Rate: ", rs.Amount, rs.Storage)) +
+				wikiRate(w, rs.Rate))
 		} else {
-			sb.WriteString("  [gray]Not yet unlocked[-]\n")
+			w.Detail(w.Dim("Not yet unlocked"))
 		}
-		sb.WriteString("\n")
 	}
 
-	sb.WriteString("[gold]Storage[-]\n")
-	sb.WriteString("  Base storage starts at 10-50 per resource.\n")
-	sb.WriteString("  Build storage buildings to increase caps:\n")
-	sb.WriteString("  • [cyan]Stash[-] (Primitive Age): +30 all resources\n")
-	sb.WriteString("  • [cyan]Storage Pit[-] (Stone Age): +50 all resources\n")
-	sb.WriteString("  • [cyan]Warehouse[-] (Bronze Age): +150 all resources\n")
-	sb.WriteString("  • [cyan]Granary[-] (Iron Age): +200 food only\n")
-	sb.WriteString("  Each age has a dedicated storage building with\n")
-	sb.WriteString("  increasing capacity to handle scaling costs.\n")
-
-	return sb.String()
+	w.Heading("Storage")
+	w.Para(
+		"Base storage starts at 10-50 per resource.",
+		"Build storage buildings to increase caps:",
+	)
+	w.Bullet(w.Link("stash", "Stash") + " (Primitive Age): +30 all resources")
+	w.Bullet(w.Link("storage_pit", "Storage Pit") + " (Stone Age): +50 all resources")
+	w.Bullet(w.Link("warehouse", "Warehouse") + " (Bronze Age): +150 all resources")
+	w.Bullet(w.Link("granary", "Granary") + " (Iron Age): +200 food only")
+	w.Para(
+		"Each age has a dedicated storage building with",
+		"increasing capacity to handle scaling costs.",
+	)
+
+	w.Heading("How Rates Add Up")
+	w.Para(
+		"Buildings, villagers and tech production make a base rate.",
+		"Bonuses from techs, wonders, milestones, prestige and allies",
+		"add up into one multiplier, applied while the base is positive.",
+		"Food eaten, unit upkeep and events come last, unscaled.",
+	)
+	w.Bullet(w.Cmd("rates") + " — every resource's rate, summed by source")
+	w.Bullet(w.Cmd("why gold") + " — each building, villager, tech, milestone, prestige, event and faction behind a rate and storage cap, and what changed in the last 10 ticks (" + w.Cmd("why gold 50") + " looks further back)")
 }
 
-func wikiBuildings(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Buildings[-]\n\n")
-	sb.WriteString("Buildings provide production, housing, storage, and more.\n")
-	sb.WriteString("Each building costs more than the last (scaling costs).\n\n")
+func wikiBuildings(w WikiWriter, state game.GameState) {
+	w.Title("Buildings")
+	w.Para(
+		"Buildings provide production, housing, storage, and more.",
+		"Each building costs more than the last (scaling costs).",
+	)
+
+	w.Heading("Power Grid")
+	w.Para(
+		"From the Electric Age, modern buildings draw power. Each tick the",
+		"electricity your generators make is shared among them, highest",
+		"priority first; a building short of power produces that much less.",
+		"Only spare power reaches the stockpile, and a stockpile can't power anything.",
+	)
+	if p := state.Power; p.Demand > 0 || p.Supply > 0 {
+		w.Para(w.Live(fmt.Sprintf("Generating %s/tick  |  Demand %s/tick  |  Served %s/tick",
+			FormatNumber(p.Supply), FormatNumber(p.Demand), FormatNumber(p.Served))))
+	}
+
+	w.Heading("World & Biomes")
+	w.Para(
+		"Every game is played on its own world, with a river, forests,",
+		"mountains and desert laid out from a seed kept in your save.",
+		fmt.Sprintf("The mix of land within %d tiles of the town is your biome;", config.BiomeRadius),
+		"each kind of land's share scales its bonuses and the events it brings.",
+	)
+	shares := make(map[string]float64)
+	for _, b := range state.Settlement.Biomes {
+		shares[b.Terrain] = b.Share
+	}
+	for _, b := range config.Biomes() {
+		w.Entry(b.Name, "biome_"+b.Terrain, w.Live(fmt.Sprintf("%.0f%% of your land", shares[b.Terrain]*100)))
+		w.Detail(b.Description)
+		w.Detail("Production at full coverage: " + wikiPercents(w, b.Bonuses))
+		events := wikiPercents(w, b.Events)
+		for _, key := range b.Only {
+			events += "  " + w.Link(key, key) + " only here"
+		}
+		if events = strings.TrimSpace(events); events != "" {
+			w.Detail("Event chance at full coverage: " + events)
+		}
+	}
+
+	w.Heading("Settlement Layout")
+	w.Para(
+		"Every building takes a spot on the settlement grid around the town",
+		"centre, wonders four tiles and the rest one. Spots are picked for you:",
+		"homes cluster near the centre, workshops further out, barracks at the edge.",
+		"Nothing is built on water.",
+	)
+	w.Bullet(fmt.Sprintf("Housing can have at most %d housing neighbours", config.MaxHousingNeighbors))
+	buildingByKey := config.BuildingByKey()
+	for _, r := range config.AdjacencyRules() {
+		name := buildingByKey[r.Building].Name
+		w.Bullet(fmt.Sprintf("%s: +%.0f%% next to %s — %s", w.Link(r.Building, name), r.Bonus*100, r.Terrain, r.Description))
+	}
 
 	// Group by age
 	ages := config.AgeOrder()
 	buildingDefs := config.BaseBuildings()
+	rules := config.AdjacencyByBuilding()
+	sited := make(map[string]int)
+	for _, pb := range state.Settlement.Buildings {
+		if pb.Adjacent {
+			sited[pb.Key]++
+		}
+	}
 	byAge := make(map[string][]config.BuildingDef)
 	for _, b := range buildingDefs {
 		byAge[b.RequiredAge] = append(byAge[b.RequiredAge], b)
 	}
 
 	ageNames := config.AgeByKey()
+	techs := config.TechByKey()
 
 	for _, ageKey := range ages {
 		buildings, ok := byAge[ageKey]
 		if !ok {
 			continue
 		}
-		ageDef := ageNames[ageKey]
-		sb.WriteString(fmt.Sprintf("[gold]── %s ──[-]\n", ageDef.Name))
+		w.Section(ageNames[ageKey].Name)
 
 		for _, b := range buildings {
 			bs, exists := state.Buildings[b.Key]
-			sb.WriteString(fmt.Sprintf("\n [cyan]%s[-] [gray](%s)[-]", b.Name, b.Key))
+			var extra []string
 			if b.MaxCount > 0 {
-				sb.WriteString(fmt.Sprintf(" [yellow]Max: %d[-]", b.MaxCount))
+				extra = append(extra, w.Em(fmt.Sprintf("Max: %d", b.MaxCount)))
 			}
 			if b.Category == "wonder" {
-				sb.WriteString(fmt.Sprintf(" [yellow]Wonder — %d ticks to build[-]", b.BuildTicks))
+				extra = append(extra, w.Em(fmt.Sprintf("Wonder — %d ticks to build", b.BuildTicks)))
 			}
-			sb.WriteString("\n")
-			sb.WriteString(fmt.Sprintf("   %s\n", b.Description))
-			sb.WriteString(fmt.Sprintf("   Category: [yellow]%s[-]  Scale: %.0f%%\n",
-				b.Category, (b.CostScale-1)*100))
+			w.Entry(b.Name, b.Key, extra...)
+			w.Detail(b.Description)
+			w.Detail(fmt.Sprintf("Category: %s  Scale: %.0f%%",
+				w.Em(b.Category), (b.CostScale-1)*100))
 
 			// Base cost
 			costKeys := make([]string, 0, len(b.BaseCost))
@@ -301,52 +436,76 @@ func wikiBuildings(state game.GameState) string {
 			sort.Strings(costKeys)
 			costParts := make([]string, 0)
 			for _, k := range costKeys {
-				costParts = append(costParts, fmt.Sprintf("%s:%.0f", k, b.BaseCost[k]))
+				costParts = append(costParts, fmt.Sprintf("%s:%.0f", w.Link(k, k), b.BaseCost[k]))
+			}
+			w.Detail("Base cost: " + strings.Join(costParts, " "))
+
+			if b.RequiredTech != "" {
+				w.Detail("Requires tech: " + w.Link(b.RequiredTech, techs[b.RequiredTech].Name))
 			}
-			sb.WriteString(fmt.Sprintf("   Base cost: %s\n", strings.Join(costParts, " ")))
 
 			// Effects
 			for _, eff := range b.Effects {
-				sb.WriteString(fmt.Sprintf("   Effect: [yellow]%s %s +%.1f[-]\n",
-					eff.Type, eff.Target, eff.Value))
+				text := fmt.Sprintf("%s %s +%.1f", eff.Type, eff.Target, eff.Value)
+				if eff.Type == "capacity" && eff.Target == "expedition_slots" {
+					text += " once per building type"
+				}
+				w.Detail("Effect: " + w.Em(text))
+			}
+			if len(b.Inputs) > 0 {
+				w.Detail("Consumes: " + w.Em(formatInputs(b.Inputs)+" per tick each") + " (runs slower when short)")
+			}
+			if len(b.Upkeep) > 0 {
+				w.Detail("Upkeep: " + w.Em(formatInputs(b.Upkeep)+" per tick each") + " (not paid while switched off)")
+			}
+			if r, ok := rules[b.Key]; ok {
+				w.Detail("Placement: " + w.Em(fmt.Sprintf("+%.0f%% production next to %s", r.Bonus*100, r.Terrain)))
+			}
+			if b.PowerDemand > 0 {
+				w.Detail("Power: " + w.Em(fmt.Sprintf("%g/tick each, priority %d", b.PowerDemand, b.PowerPrio)) + " (slows when the grid is short)")
 			}
 
 			// Live data
 			if exists && bs.Unlocked {
-				sb.WriteString(fmt.Sprintf("   [orange]Built: %d  Next cost: %s[-]\n",
-					bs.Count, FormatCost(bs.NextCost)))
+				w.Detail(w.Live(fmt.Sprintf("Built: %d (%d off)  Next cost: %s",
+					bs.Count, bs.Disabled, FormatCost(bs.NextCost))))
+				if r, ok := rules[b.Key]; ok && bs.Count > 0 {
+					w.Detail(w.Live(fmt.Sprintf("By %s: %d of %d", r.Terrain, sited[b.Key], bs.Count)))
+				}
 			}
 		}
-		sb.WriteString("\n")
 	}
-
-	return sb.String()
 }
 
-func wikiVillagers(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Villagers[-]\n\n")
-	sb.WriteString("Villagers are your workforce. They consume food each tick\n")
-	sb.WriteString("and can be assigned to gather resources.\n\n")
+func wikiVillagers(w WikiWriter, state game.GameState) {
+	w.Title("Villagers")
+	w.Para(
+		"Villagers are your workforce. They consume food each tick",
+		"and can be assigned to gather resources.",
+	)
 
 	v := state.Villagers
-	sb.WriteString(fmt.Sprintf("[orange]Population: %d / %d  |  Idle: %d  |  Food drain: %.1f/tick[-]\n\n",
-		v.TotalPop, v.MaxPop, v.TotalIdle, v.FoodDrain))
+	w.Para(w.Live(fmt.Sprintf("Population: %d / %d  |  Idle: %d  |  Food drain: %.1f/tick",
+		v.TotalPop, v.MaxPop, v.TotalIdle, v.FoodDrain)))
 
 	types := game.DefaultVillagerTypes()
 	for _, def := range types {
-		sb.WriteString(fmt.Sprintf("[cyan]%s[-] [gray](%s)[-]\n", def.Name, def.Key))
-		sb.WriteString(fmt.Sprintf("  Food cost: [yellow]%.2f/tick[-]\n", def.FoodCost))
-		sb.WriteString(fmt.Sprintf("  Gather rate: [yellow]%.1f/tick[-] per assigned villager\n", def.GatherRate))
+		w.Entry(def.Name, def.Key)
+		w.Detail("Food cost: " + w.Em(fmt.Sprintf("%.2f/tick", def.FoodCost)))
+		w.Detail("Gather rate: " + w.Em(fmt.Sprintf("%.1f/tick", def.GatherRate)) + " per assigned villager")
 		if len(def.CanGather) > 0 {
-			sb.WriteString(fmt.Sprintf("  Can gather: [yellow]%s[-]\n", strings.Join(def.CanGather, ", ")))
+			links := make([]string, len(def.CanGather))
+			for i, res := range def.CanGather {
+				links[i] = w.Link(res, res)
+			}
+			w.Detail("Can gather: " + strings.Join(links, ", "))
 		} else {
-			sb.WriteString("  [gray]Cannot gather resources (military unit)[-]\n")
+			w.Detail(w.Dim("Cannot gather resources (military unit)"))
 		}
 
 		// Live data
 		if vt, ok := v.Types[def.Key]; ok && vt.Unlocked {
-			sb.WriteString(fmt.Sprintf("  [orange]Count: %d  Idle: %d[-]\n", vt.Count, vt.IdleCount))
+			w.Detail(w.Live(fmt.Sprintf("Count: %d  Idle: %d", vt.Count, vt.IdleCount)))
 			aKeys := make([]string, 0, len(vt.Assignments))
 			for k := range vt.Assignments {
 				aKeys = append(aKeys, k)
@@ -354,44 +513,49 @@ func wikiVillagers(state game.GameState) string {
 			sort.Strings(aKeys)
 			for _, res := range aKeys {
 				if vt.Assignments[res] > 0 {
-					sb.WriteString(fmt.Sprintf("  [orange]  → %s: %d (producing %.1f/tick)[-]\n",
-						res, vt.Assignments[res], float64(vt.Assignments[res])*def.GatherRate))
+					w.Detail(w.Live(fmt.Sprintf("  → %s: %d (producing %.1f/tick)",
+						res, vt.Assignments[res], float64(vt.Assignments[res])*def.GatherRate)))
 				}
 			}
 		} else {
-			sb.WriteString("  [gray]Not yet unlocked[-]\n")
+			w.Detail(w.Dim("Not yet unlocked"))
 		}
-		sb.WriteString("\n")
 	}
 
-	sb.WriteString("[gold]Food Economy[-]\n")
-	sb.WriteString("  Each worker eats 0.10 food/tick but gathers 0.35/tick.\n")
-	sb.WriteString("  So 1 worker on food produces net +0.25 for others.\n")
-	sb.WriteString("  That covers 1 shaman (0.20) or 2.5 other workers (0.10 each).\n\n")
-	sb.WriteString("  Workers on food: covers this many others:\n")
-	sb.WriteString("    1 food worker  → 1 shaman + 1 worker\n")
-	sb.WriteString("    2 food workers → 2 shamans + 2 workers\n")
-	sb.WriteString("    4 food workers → ~5 shamans or ~10 workers\n")
-
-	return sb.String()
+	w.Heading("Food Economy")
+	w.Para(
+		"Each worker eats 0.10 food/tick but gathers 0.35/tick.",
+		"So 1 worker on food produces net +0.25 for others.",
+		"That covers 1 shaman (0.20) or 2.5 other workers (0.10 each).",
+	)
+	w.Pre(
+		"Workers on food: covers this many others:",
+		"  1 food worker  → 1 shaman + 1 worker",
+		"  2 food workers → 2 shamans + 2 workers",
+		"  4 food workers → ~5 shamans or ~10 workers",
+	)
 }
 
-func wikiAges(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Ages[-]\n\n")
-	sb.WriteString("Advancing through ages unlocks new buildings, resources,\n")
-	sb.WriteString("and villager types. Requirements include both resources\n")
-	sb.WriteString("(which are consumed) and buildings (which must exist).\n\n")
+func wikiAges(w WikiWriter, state game.GameState) {
+	w.Title("Ages")
+	w.Para(
+		"Advancing through ages unlocks new buildings, resources,",
+		"and villager types. Requirements include both resources",
+		"(which are consumed) and buildings (which must exist).",
+	)
 
-	sb.WriteString(fmt.Sprintf("[orange]Current Age: %s[-]\n\n", state.AgeName))
+	w.Para(w.Live("Current Age: " + state.AgeName))
+
+	linkAll := func(keys []string) string {
+		links := make([]string, len(keys))
+		for i, k := range keys {
+			links[i] = w.Link(k, k)
+		}
+		return strings.Join(links, ", ")
+	}
 
 	ages := config.Ages()
 	for _, age := range ages {
-		// Highlight current age
-		marker := "  "
-		if age.Key == state.Age {
-			marker = "[orange]▸[-]"
-		}
 		// Check if reached
 		reached := false
 		for _, a := range state.Stats.AgesReached {
@@ -400,17 +564,22 @@ func wikiAges(state game.GameState) string {
 				break
 			}
 		}
-		icon := "[gray]○[-]"
+		icon := w.Dim("○")
 		if reached {
-			icon = "[green]●[-]"
+			icon = w.Good("●")
+		}
+		extra := []string{icon}
+		// Highlight current age
+		if age.Key == state.Age {
+			extra = append(extra, w.Live("◂ current"))
 		}
 
-		sb.WriteString(fmt.Sprintf("%s %s [cyan]%s[-]\n", marker, icon, age.Name))
-		sb.WriteString(fmt.Sprintf("    %s\n", age.Description))
+		w.Entry(age.Name, age.Key, extra...)
+		w.Detail(age.Description)
 
 		// Requirements
 		if len(age.ResourceReqs) > 0 || len(age.BuildingReqs) > 0 {
-			sb.WriteString("    [gold]Requirements:[-]\n")
+			w.Detail(w.Em("Requirements:"))
 			rKeys := make([]string, 0, len(age.ResourceReqs))
 			for k := range age.ResourceReqs {
 				rKeys = append(rKeys, k)
@@ -422,11 +591,11 @@ func wikiAges(state game.GameState) string {
 				if rs, ok := state.Resources[k]; ok {
 					current = rs.Amount
 				}
-				color := "red"
+				progress := w.Bad(fmt.Sprintf("%.0f / %.0f", current, req))
 				if current >= req {
-					color = "green"
+					progress = w.Good(fmt.Sprintf("%.0f / %.0f", current, req))
 				}
-				sb.WriteString(fmt.Sprintf("      [%s]%s: %.0f / %.0f[-]\n", color, k, current, req))
+				w.Detail(fmt.Sprintf("%s: %s", w.Link(k, k), progress))
 			}
 			bKeys := make([]string, 0, len(age.BuildingReqs))
 			for k := range age.BuildingReqs {
@@ -439,206 +608,585 @@ func wikiAges(state game.GameState) string {
 				if bs, ok := state.Buildings[k]; ok {
 					current = bs.Count
 				}
-				color := "red"
+				progress := w.Bad(fmt.Sprintf("%d / %d", current, req))
 				if current >= req {
-					color = "green"
+					progress = w.Good(fmt.Sprintf("%d / %d", current, req))
 				}
-				sb.WriteString(fmt.Sprintf("      [%s]%s: %d / %d[-]\n", color, k, current, req))
+				w.Detail(fmt.Sprintf("%s: %s", w.Link(k, k), progress))
 			}
 		}
 
 		// Unlocks
 		if len(age.UnlockBuildings) > 0 {
-			sb.WriteString(fmt.Sprintf("    Unlocks buildings: [yellow]%s[-]\n",
-				strings.Join(age.UnlockBuildings, ", ")))
+			w.Detail("Unlocks buildings: " + linkAll(age.UnlockBuildings))
 		}
 		if len(age.UnlockResources) > 0 {
-			sb.WriteString(fmt.Sprintf("    Unlocks resources: [yellow]%s[-]\n",
-				strings.Join(age.UnlockResources, ", ")))
+			w.Detail("Unlocks resources: " + linkAll(age.UnlockResources))
 		}
 		if len(age.UnlockVillagers) > 0 {
-			sb.WriteString(fmt.Sprintf("    Unlocks villagers: [yellow]%s[-]\n",
-				strings.Join(age.UnlockVillagers, ", ")))
+			w.Detail("Unlocks villagers: " + linkAll(age.UnlockVillagers))
 		}
-		sb.WriteString("\n")
 	}
-
-	return sb.String()
 }
 
-func wikiResearch(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Research & Technology[-]\n\n")
-	sb.WriteString("The tech tree allows you to research technologies that\n")
-	sb.WriteString("provide permanent bonuses to your civilization.\n\n")
+func wikiResearch(w WikiWriter, state game.GameState) {
+	w.Title("Research & Technology")
+	w.Para(
+		"The tech tree allows you to research technologies that",
+		"provide permanent bonuses to your civilization.",
+	)
 
-	sb.WriteString("[gold]How It Works[-]\n")
-	sb.WriteString("  • Spend [yellow]knowledge[-] to start researching a tech\n")
-	sb.WriteString("  • Research takes several ticks to complete\n")
-	sb.WriteString("  • Only one tech can be researched at a time\n")
-	sb.WriteString("  • Many techs have [yellow]prerequisites[-] that must be\n")
-	sb.WriteString("    researched first\n")
-	sb.WriteString("  • Each tech is locked to a minimum [yellow]age[-]\n\n")
+	w.Heading("How It Works")
+	w.Bullet("Spend " + w.Link("knowledge", "knowledge") + " to start researching a tech")
+	w.Bullet("Research takes several ticks to complete")
+	w.Bullet("Only one tech can be researched at a time")
+	w.Bullet("Many techs have " + w.Em("prerequisites") + " that must be researched first")
+	w.Bullet("Each tech is locked to a minimum " + w.Em("age"))
 
 	// Current research status
 	if state.Research.CurrentTech != "" {
-		fmt.Fprintf(&sb, "[orange]Currently Researching: %s (%d/%d ticks)[-]\n\n",
+		w.Para(w.Live(fmt.Sprintf("Currently Researching: %s (%d/%d ticks)",
 			state.Research.CurrentTechName,
 			state.Research.TotalTicks-state.Research.TicksLeft,
-			state.Research.TotalTicks)
+			state.Research.TotalTicks)))
 	}
-	fmt.Fprintf(&sb, "[orange]Total Researched: %d techs[-]\n\n", state.Research.TotalResearched)
+	w.Para(w.Live(fmt.Sprintf("Total Researched: %d techs", state.Research.TotalResearched)))
 
 	// List all techs by age
 	techsByAge := config.TechsByAge()
 	ageOrder := config.AgeOrder()
 	ages := config.AgeByKey()
+	allTechs := config.TechByKey()
 
 	for _, ageKey := range ageOrder {
 		ageTechs, ok := techsByAge[ageKey]
 		if !ok {
 			continue
 		}
-		ageDef := ages[ageKey]
 
 		sort.Slice(ageTechs, func(i, j int) bool {
 			return ageTechs[i].Name < ageTechs[j].Name
 		})
 
-		sb.WriteString(fmt.Sprintf("[gold]── %s ──[-]\n", ageDef.Name))
+		w.Section(ages[ageKey].Name)
 		for _, tech := range ageTechs {
 			ts, ok := state.Research.Techs[tech.Key]
-			icon := "[gray]•[-]"
+			icon := w.Dim("•")
 			if ok && ts.Researched {
-				icon = "[green]✓[-]"
+				icon = w.Good("✓")
 			} else if ok && ts.Available {
-				icon = "[cyan]○[-]"
+				icon = w.Cmd("○")
 			}
 
-			fmt.Fprintf(&sb, " %s [cyan]%s[-] [gray](%s)[-] — %.0f knowledge\n",
-				icon, tech.Name, tech.Key, tech.Cost)
-			fmt.Fprintf(&sb, "   %s\n", tech.Description)
+			if tech.Campaign != "" {
+				w.Entry(tech.Name, tech.Key, icon, "— earned by "+w.Link(tech.Campaign, expeditionName(tech.Campaign)))
+			} else {
+				w.Entry(tech.Name, tech.Key, icon, fmt.Sprintf("— %.0f knowledge", tech.Cost))
+			}
+			w.Detail(tech.Description)
 			if len(tech.Prerequisites) > 0 {
-				allTechs := config.TechByKey()
-				var prereqNames []string
+				var prereqs []string
 				for _, p := range tech.Prerequisites {
 					if pd, ok := allTechs[p]; ok {
-						prereqNames = append(prereqNames, pd.Name)
+						prereqs = append(prereqs, w.Link(p, pd.Name))
 					}
 				}
-				fmt.Fprintf(&sb, "   [gray]Requires: %s[-]\n", strings.Join(prereqNames, ", "))
+				w.Detail(w.Dim("Requires: ") + strings.Join(prereqs, ", "))
 			}
-			sb.WriteString("\n")
 		}
 	}
 
-	sb.WriteString("[gold]Commands[-]\n")
-	sb.WriteString("  [cyan]research[-] <tech_key>  — Start researching\n")
-	sb.WriteString("  [cyan]research cancel[-]      — Cancel current research\n")
-	sb.WriteString("  [cyan]research list[-]        — Show available techs\n")
-
-	return sb.String()
+	w.Heading("Commands")
+	w.Pre(
+		w.Cmd("research")+" <tech_key>  — Start researching",
+		w.Cmd("research cancel")+"      — Cancel current research",
+		w.Cmd("research list")+"        — Show available techs",
+	)
 }
 
-func wikiMilitary(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Military System[-]\n\n")
-	sb.WriteString("Recruit soldiers and send them on expeditions to earn\n")
-	sb.WriteString("loot and resources. Military becomes available in the\n")
-	sb.WriteString("Bronze Age.\n\n")
+func wikiMilitary(w WikiWriter, state game.GameState) {
+	w.Title("Military System")
+	w.Para(
+		"Recruit soldiers and send them on expeditions to earn",
+		"loot and resources. Military becomes available in the",
+		"Bronze Age.",
+	)
+
+	w.Heading("How It Works")
+	w.Bullet("Build " + w.Link("barracks", "Barracks") + " to unlock soldiers")
+	w.Bullet(w.Cmd("recruit soldier") + " to queue new infantry for training")
+	w.Bullet("Soldiers eat food and draw gold upkeep, but don't gather resources")
+	w.Bullet("Send soldiers on " + w.Em("expeditions") + " for loot")
+	w.Bullet("Military bonuses from research strengthen every attack")
+	w.Bullet("Soldiers at home defend against " + w.Em("raids"))
 
-	sb.WriteString("[gold]How It Works[-]\n")
-	sb.WriteString("  • Build [yellow]Barracks[-] to unlock soldiers\n")
-	sb.WriteString("  • [cyan]recruit soldier[-] to add military units\n")
-	sb.WriteString("  • Soldiers eat food but don't gather resources\n")
-	sb.WriteString("  • Send soldiers on [yellow]expeditions[-] for loot\n")
-	sb.WriteString("  • Military bonuses from research improve success\n\n")
+	ages := config.AgeByKey()
+	techs := config.TechByKey()
+
+	w.Heading("Units")
+	w.Para(
+		"Every recruit is "+w.Link("infantry", "Infantry")+". "+w.Cmd("train")+" turns idle",
+		"infantry at home into other units once the age and research",
+		"allow, for a one-off cost. Every unit also costs upkeep",
+		"every tick on top of the soldier's food.",
+	)
+	unitNames := config.UnitByKey()
+	for _, u := range config.Units() {
+		var counters []string
+		for _, c := range u.Counters {
+			counters = append(counters, w.Link(c, unitNames[c].Name))
+		}
+		req := w.Link(u.MinAge, ages[u.MinAge].Name)
+		if tech, ok := techs[u.RequiredTech]; ok {
+			req += " + " + w.Link(tech.Key, tech.Name)
+		}
+		w.Entry(u.Name, u.Key, w.Dim("requires ")+req)
+		w.Detail(u.Description)
+		w.Detail(fmt.Sprintf("Attack: %.1f  Defense: %.1f  Counters: %s", u.Attack, u.Defense, strings.Join(counters, ", ")))
+		train := fmt.Sprintf("%d ticks", u.TrainTicks)
+		if len(u.TrainCost) > 0 {
+			train = wikiRewards(w, u.TrainCost) + fmt.Sprintf(", %d ticks", u.TrainTicks)
+		}
+		w.Detail("Train: " + train + "  Upkeep: " + wikiUpkeep(w, u.Upkeep))
+	}
+
+	w.Heading("Training")
+	w.Para(
+		"Recruits and trainees wait in a training queue. The order",
+		"at the front trains one unit per lane at a time; you start",
+		"with one lane, and each "+w.Link("barracks", "Barracks")+" adds one, each",
+		w.Link("castle", "Castle")+" two and each "+w.Link("bunker", "Bunker")+" three. Queued",
+		"recruits already count against the population cap, and",
+		"infantry being retrained leave the home army until done.",
+	)
+
+	w.Heading("Morale")
+	w.Para(
+		"Soldiers expect their upkeep. While any upkeep resource is",
+		"at zero, morale falls 2% a tick; once paid it recovers 1%",
+		"a tick. Below 25% morale, 2% of the soldiers at home",
+		"desert every tick, weakest units first.",
+	)
+
+	w.Heading("Battles")
+	w.Para(
+		"Expeditions and raids are fought as battles of up to 8",
+		"rounds. Each round both sides strike at once: a unit's",
+		"attack is spread over the enemy by headcount, and deals",
+		"half again as much damage to a unit type it counters. A",
+		"unit falls for each full Defense worth of damage its type",
+		"takes. The attacker must wipe out the defenders to win;",
+		"battles are deterministic, so the same armies always give",
+		"the same result and casualty report.",
+	)
+	w.Para(
+		"That makes expeditions plannable: "+w.Cmd("expedition info")+" fights",
+		"every stage on paper with your current bonuses and shows",
+		"the outcome, casualties and loot, and "+w.Cmd("expedition best"),
+		"ranks the expeditions you can launch and win by loot per",
+		"tick of soldier time.",
+	)
+
+	w.Heading("Expedition Slots")
+	w.Para(
+		"You start with one expedition slot. "+w.Link("barracks", "Barracks")+",",
+		w.Link("castle", "Castle")+" and "+w.Link("bunker", "Bunker")+" each add one slot once built,",
+		"however many you build, as do "+w.Link("cartography", "Cartography")+" and "+w.Link("orbital_mechanics", "Orbital Mechanics")+".",
+		"Several expeditions can run at once, but each needs its",
+		"own soldiers and the same expedition cannot run twice.",
+	)
+
+	w.Heading("Campaigns")
+	w.Para(
+		"Campaigns are expeditions fought in several stages, each",
+		"against its own enemy. After winning a stage your troops hold",
+		"camp: "+w.Cmd("expedition advance")+" fights the next stage, while",
+		w.Cmd("expedition retreat")+" brings home the loot won so far.",
+		"Losing a stage recovers only part of the haul. Winning",
+		"every stage can discover a faction early or grant a",
+		"unique technology that can't be researched.",
+	)
 
 	mil := state.Military
-	fmt.Fprintf(&sb, "[orange]Soldiers: %d  |  Defense: %.1f[-]\n", mil.SoldierCount, mil.DefenseRating)
+	status := []string{w.Live(fmt.Sprintf("Soldiers: %d  |  Defense: %.1f  |  Morale: %.0f%%", mil.SoldierCount, mil.DefenseRating, mil.Morale))}
+	if len(mil.Training) > 0 {
+		status = append(status, w.Live(fmt.Sprintf("Training: %d order(s), %d ticks on %d lane(s)", len(mil.Training), mil.TrainingTicks, mil.TrainingSlots)))
+	}
 	if mil.MilitaryBonus > 0 {
-		fmt.Fprintf(&sb, "[orange]Military Bonus: +%.0f%%[-]\n", mil.MilitaryBonus*100)
+		status = append(status, w.Live(fmt.Sprintf("Military Bonus: +%.0f%%", mil.MilitaryBonus*100)))
 	}
-	if mil.ActiveExpedition != nil {
-		fmt.Fprintf(&sb, "[orange]Active Expedition: %s (%d ticks left)[-]\n",
-			mil.ActiveExpedition.Name, mil.ActiveExpedition.TicksLeft)
+	status = append(status, w.Live(fmt.Sprintf("Expedition Slots: %d/%d  |  Available Soldiers: %d",
+		len(mil.ActiveExpeditions), mil.ExpeditionSlots, mil.AvailableSoldiers)))
+	for _, exp := range mil.ActiveExpeditions {
+		switch {
+		case exp.Holding:
+			status = append(status, w.Live(fmt.Sprintf("Active Expedition: %s (holding before stage %d/%d)", exp.Name, exp.Stage, exp.Stages)))
+		case exp.Stages > 1:
+			status = append(status, w.Live(fmt.Sprintf("Active Expedition: %s (stage %d/%d, %d ticks left)", exp.Name, exp.Stage, exp.Stages, exp.TicksLeft)))
+		default:
+			status = append(status, w.Live(fmt.Sprintf("Active Expedition: %s (%d ticks left)", exp.Name, exp.TicksLeft)))
+		}
+	}
+	status = append(status, w.Live(fmt.Sprintf("Completed Expeditions: %d", mil.CompletedCount)))
+	w.Para(status...)
+
+	w.Heading("Raids")
+	w.Para(
+		"From the "+w.Link("bronze_age", "Bronze Age")+" on, raiders attack every few minutes.",
+		"They are sighted 15 ticks ahead, giving you time to recruit",
+		"more soldiers. Raids grow stronger each age and with",
+		"every hostile faction (rival, embargo, war, or opinion",
+		"-50 or below). Hostile factions also raid more often;",
+		"a faction at war counts twice and leads every raid.",
+	)
+	w.Bullet("Raiders bring the two newest unit types of your age")
+	w.Bullet("Defense = sum of Defense of units at home × military bonus")
+	w.Bullet("Soldiers away on an expedition don't defend")
+	w.Bullet(w.Good("Win") + ": loot of food, wood and gold")
+	w.Bullet(w.Bad("Lose") + ": stockpiles stolen; heavy defeats burn buildings")
+	w.Bullet("Defenders fall in the battle; a rout also kills villagers, but wonders are spared")
+	raids := mil.Raids
+	raidStatus := []string{w.Live(fmt.Sprintf("Raids repelled: %d  |  lost: %d", raids.Repelled, raids.Lost))}
+	if raids.Incoming != nil {
+		raidStatus = append(raidStatus, w.Live(fmt.Sprintf("Incoming: %s, %s (%d ticks)",
+			raids.Incoming.Name, game.FormatUnits(raids.Incoming.Enemy), raids.Incoming.TicksLeft)))
 	}
-	fmt.Fprintf(&sb, "[orange]Completed Expeditions: %d[-]\n\n", mil.CompletedCount)
+	w.Para(raidStatus...)
 
-	sb.WriteString("[gold]Available Expeditions[-]\n\n")
+	w.Heading("Expeditions")
+	available := make(map[string]bool)
 	for _, exp := range mil.Expeditions {
-		diffColor := "green"
-		if exp.Difficulty > 0.5 {
-			diffColor = "red"
-		} else if exp.Difficulty > 0.3 {
-			diffColor = "yellow"
+		available[exp.Key] = true
+	}
+	factions := config.FactionByKey()
+	for _, exp := range config.Expeditions() {
+		if available[exp.Key] {
+			w.Entry(exp.Name, exp.Key)
+		} else {
+			w.Entry(exp.Name, exp.Key, w.Dim("requires ")+w.Link(exp.MinAge, ages[exp.MinAge].Name))
 		}
+		w.Detail(exp.Description)
+		w.Detail(fmt.Sprintf("Soldiers: %d  Duration: %d ticks", exp.SoldiersNeeded, exp.TotalDuration()))
 
-		fmt.Fprintf(&sb, " [cyan]%s[-] [gray](%s)[-]\n", exp.Name, exp.Key)
-		fmt.Fprintf(&sb, "   %s\n", exp.Description)
-		fmt.Fprintf(&sb, "   Soldiers: %d  Duration: %d ticks  Difficulty: [%s]%.0f%%[-]\n\n",
-			exp.SoldiersNeeded, exp.Duration, diffColor, exp.Difficulty*100)
+		if !exp.IsCampaign() {
+			w.Detail("Enemy: " + wikiArmy(w, exp.Enemy))
+			w.Detail("Rewards: " + wikiRewards(w, exp.Rewards))
+			continue
+		}
+		for i, stage := range exp.Stages {
+			w.Detail(fmt.Sprintf("Stage %d: %s (%d ticks) vs %s — %s",
+				i+1, stage.Name, stage.Duration, wikiArmy(w, stage.Enemy), wikiRewards(w, stage.Rewards)))
+		}
+		var unlocks []string
+		if f, ok := factions[exp.DiscoverFaction]; ok {
+			unlocks = append(unlocks, "discovers "+w.Link(f.Key, f.Name))
+		}
+		if tech, ok := techs[exp.UnlockTech]; ok {
+			unlocks = append(unlocks, "grants "+w.Link(tech.Key, tech.Name))
+		}
+		if len(unlocks) > 0 {
+			w.Detail(w.Good("On completion: ") + strings.Join(unlocks, ", "))
+		}
 	}
 
-	if len(mil.Expeditions) == 0 {
-		sb.WriteString("  [gray]Reach Bronze Age to unlock expeditions[-]\n\n")
+	w.Heading("Commands")
+	w.Pre(
+		w.Cmd("expedition")+" <key>          — Launch with the strongest soldiers at home",
+		w.Cmd("expedition")+" <key> <unit:n> — Launch with chosen units, e.g. archers:3",
+		w.Cmd("train")+" <unit> [count|max]  — Train idle infantry into a unit",
+		w.Cmd("expedition advance")+" <key>  — Send a holding campaign onward",
+		w.Cmd("expedition retreat")+" <key>  — Bring a holding campaign home",
+		w.Cmd("expedition list")+"           — Show available expeditions",
+		w.Cmd("expedition info")+" <key>     — Forecast an expedition, optionally with unit:n",
+		w.Cmd("expedition best")+"           — Rank expeditions by loot per soldier-tick",
+	)
+}
+
+// expeditionName returns an expedition's display name, or the key if unknown
+func expeditionName(key string) string {
+	if def, ok := config.ExpeditionByKey()[key]; ok {
+		return def.Name
 	}
+	return key
+}
 
-	sb.WriteString("[gold]Commands[-]\n")
-	sb.WriteString("  [cyan]expedition[-] <key>   — Launch an expedition\n")
-	sb.WriteString("  [cyan]expedition list[-]    — Show available expeditions\n")
+// wikiRewards formats a reward map as sorted, linked resource amounts
+func wikiRewards(w WikiWriter, rewards map[string]float64) string {
+	keys := make([]string, 0, len(rewards))
+	for k := range rewards {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s:%.0f", w.Link(k, k), rewards[k])
+	}
+	return strings.Join(parts, " ")
+}
 
-	return sb.String()
+// wikiRate formats a rate with its sign through the writer's own spans,
+// like FormatRate does with tview tags
+func wikiRate(w WikiWriter, rate float64) string {
+	switch {
+	case rate > 0:
+		return w.Good("+" + FormatNumber(rate))
+	case rate < 0:
+		return w.Bad(FormatNumber(rate))
+	}
+	return w.Dim("+0.0")
 }
 
-func wikiEvents(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Random Events & Milestones[-]\n\n")
+// wikiPercents formats fractions by key as sorted, linked percentages
+func wikiPercents(w WikiWriter, values map[string]float64) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s %+.0f%%", w.Link(k, k), values[k]*100)
+	}
+	return strings.Join(parts, "  ")
+}
 
-	sb.WriteString("[gold]Random Events[-]\n")
-	sb.WriteString("Events trigger randomly during gameplay. Some are\n")
-	sb.WriteString("beneficial (bonus resources), some are harmful (lost\n")
-	sb.WriteString("production), and some are mixed.\n\n")
+// wikiArmy formats an army as linked unit counts in unit order
+func wikiArmy(w WikiWriter, army map[string]int) string {
+	var parts []string
+	for _, u := range config.Units() {
+		if n := army[u.Key]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, w.Link(u.Key, u.Name)))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// wikiUpkeep formats per-tick costs as sorted, linked resource rates
+func wikiUpkeep(w WikiWriter, upkeep map[string]float64) string {
+	keys := make([]string, 0, len(upkeep))
+	for k := range upkeep {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s:%g/tick", w.Link(k, k), upkeep[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+func wikiFactions(w WikiWriter, state game.GameState) {
+	w.Title("Factions & Diplomacy")
+	w.Para(
+		"NPC factions appear as your civilization advances. Each",
+		"specializes in one resource and rewards good relations",
+		"with better trade terms.",
+	)
+
+	w.Heading("How It Works")
+	w.Bullet("Factions are " + w.Em("discovered") + " when you reach their age")
+	w.Bullet(w.Em("Opinion") + " ranges from -100 to 100 and drifts back toward 0")
+	w.Bullet(w.Cmd("diplomacy gift") + " costs 200 gold for +11 to +19 opinion, more when the faction is short of gold")
+	w.Bullet("Allying requires 50 opinion and 500 gold")
+	w.Bullet("Rivals, embargoed factions, factions at war and those at -50 opinion send " + w.Em("raids") + " (see Military)")
+	w.Bullet("Allied factions boost imports of their specialty")
+	w.Bullet("Colonial and later " + w.Em("trade routes") + " trade with a partner faction: imports scale from ×0.5 at -100 opinion to ×1.5 at 100, and each cycle adds +1 opinion")
+	w.Bullet("A rivalry, embargo or war " + w.Em("suspends") + " every route with that faction until relations mend")
+	w.Bullet("Rivals, embargoes and enemies at war lose opinion over time")
+
+	ages := config.AgeByKey()
+	for _, def := range config.BaseFactions() {
+		w.Entry(def.Name, def.Key)
+		w.Detail(def.Description)
+		w.Detail(fmt.Sprintf("Specialty: %s  Trade bonus: %s  Wants: %s  Appears: %s",
+			w.Link(def.Specialty, def.Specialty),
+			w.Em(fmt.Sprintf("+%.0f%%", def.TradeBonus*100)),
+			w.Link(def.Wants, def.Wants),
+			w.Link(def.MinAge, ages[def.MinAge].Name)))
+		w.Detail(fmt.Sprintf("Produces: %s  Consumes: %s",
+			wikiRewards(w, def.Produces), wikiRewards(w, def.Consumes)))
+		var routes []string
+		for _, route := range config.BaseTradeRoutes() {
+			if route.Partner == def.Key {
+				routes = append(routes, route.Name)
+			}
+		}
+		if len(routes) > 0 {
+			w.Detail("Routes: " + strings.Join(routes, ", "))
+		}
+		var allies []string
+		for _, ally := range def.Allies {
+			allies = append(allies, config.FactionByKey()[ally].Name)
+		}
+		if len(allies) > 0 {
+			w.Detail("Allies in war: " + strings.Join(allies, ", "))
+		}
+
+		// Live data
+		if f, ok := state.Diplomacy.Factions[def.Key]; ok && f.Discovered {
+			w.Detail(w.Live(fmt.Sprintf("Status: %s  Opinion: %+d  Trades: %d",
+				f.Status, f.Opinion, f.TradeCount)))
+			if f.Treaty.Key != "" {
+				w.Detail(w.Live(fmt.Sprintf("Treaty: %s (%d ticks left)", f.Treaty.Name, f.Treaty.TicksLeft)))
+			}
+			if f.Proposal.Key != "" {
+				w.Detail(w.Live(fmt.Sprintf("Proposes: %s (%d ticks to answer)", f.Proposal.Name, f.Proposal.TicksLeft)))
+			}
+			if f.Request.Key != "" {
+				w.Detail(w.Live(fmt.Sprintf("Requests: %s (%d ticks left)", f.Request.Name, f.Request.TicksLeft)))
+			}
+			if f.Status == "war" {
+				w.Detail(w.Live(fmt.Sprintf("At war: score %d of %d, peace costs %.0f gold", f.WarScore, f.SurrenderAt, f.PeaceTribute)))
+			} else if f.Incidents > 0 {
+				w.Detail(w.Live(fmt.Sprintf("Border incidents: %d of %d before war", f.Incidents, f.WarIncidents)))
+			}
+		} else {
+			w.Detail(w.Dim("Not yet discovered"))
+		}
+	}
+
+	w.Heading("Economies")
+	w.Para(
+		"Each discovered faction produces and consumes a few",
+		"resources every tick into stockpiles that hold 200",
+		"ticks of its flow. How full the stockpiles are sets",
+		"market prices: empty means scarce, full means cheap.",
+	)
+	w.Bullet(w.Em("Exchange rates") + " scale by the price of what you sell over what you buy")
+	w.Bullet(w.Em("Trade routes") + " yield more imports when their exports are scarce")
+	w.Bullet("Your trades and route cycles fill stockpiles of what you sell and drain what you buy")
+	w.Bullet("Rivals, embargoed factions and enemies at war drift out of the market over 50 ticks, so their goods grow scarce")
+	if len(state.Trade.Prices) > 0 {
+		keys := make([]string, 0, len(state.Trade.Prices))
+		for k := range state.Trade.Prices {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = fmt.Sprintf("%s %.2f", k, state.Trade.Prices[k])
+		}
+		w.Detail(w.Live("Prices now: " + strings.Join(parts, ", ")))
+	}
 
-	sb.WriteString("  • Events respect [yellow]age requirements[-]\n")
-	sb.WriteString("  • Each event has a [yellow]cooldown[-] between occurrences\n")
-	sb.WriteString("  • Timed events show in the [yellow]Active Events[-] panel\n")
-	sb.WriteString("  • All events are logged in the game log\n\n")
+	w.Heading("Agendas")
+	w.Para(
+		"Every 150 ticks each faction speaks up, alternating",
+		"between proposing a treaty and making a request.",
+		"Factions under embargo, or still waiting on an answer,",
+		"stay quiet.",
+	)
+	w.Bullet(w.Em("Requests") + " have a deadline; meeting one raises opinion, refusing or ignoring it lowers it")
+	w.Bullet("Military aid is met by winning the named expedition before the deadline")
+	w.Bullet(w.Em("Treaties") + " last a fixed term and pay opinion when signed and again when they run out")
+	w.Bullet("Doing anything a treaty forbids " + w.Em("breaks") + " it and costs opinion")
+
+	w.Heading("Requests")
+	for _, def := range config.FactionRequests() {
+		w.Entry(def.Name, def.Key)
+		w.Detail(def.Description)
+		amount := "—"
+		if def.Kind != "military_aid" {
+			amount = fmt.Sprintf("%.0f%% of stock (min %.0f)", def.Share*100, def.MinAmount)
+		}
+		w.Detail(fmt.Sprintf("Asks: %s  Deadline: %d ticks  Opinion: %s / %s",
+			amount, def.Deadline,
+			w.Em(fmt.Sprintf("+%d", def.Reward)), w.Em(fmt.Sprintf("-%d", def.Penalty))))
+	}
+
+	w.Heading("Treaties")
+	for _, def := range config.Treaties() {
+		w.Entry(def.Name, def.Key)
+		w.Detail(def.Description)
+		w.Detail(fmt.Sprintf("Term: %d ticks  Offered at opinion %d to %d  Broken: %s",
+			def.Duration, def.MinOpinion, def.MaxOpinion, w.Em(fmt.Sprintf("-%d", def.BreakPenalty))))
+	}
+
+	w.Heading("Border Incidents & War")
+	w.Para(
+		"A faction whose opinion falls to -50 or below stages a",
+		"border incident every 50 ticks, costing 5 more opinion.",
+		"The third incident is a declaration of war. Lifting its",
+		"opinion above -50 first eases tensions and clears the",
+		"count; a non-aggression pact keeps the border quiet.",
+	)
+	w.Bullet("At war a faction " + w.Em("raids") + " you more often and leads every raid")
+	w.Bullet("It won't trade: its routes are suspended and it drifts out of the market")
+	w.Bullet("A " + w.Em("War on <faction>") + " expedition opens, scaled to your age; each victory adds 1 to the war score")
+	w.Bullet("Each raid it wins against you takes 1 off the war score")
+	w.Bullet("At war score 3 the faction " + w.Em("sues for peace") + " on its own")
+	w.Bullet(w.Cmd("diplomacy peace") + " buys peace sooner: 50 gold × ages reached × points the score is short of 3")
+	w.Bullet("Peace resets opinion to -30 and binds both sides to a full-term non-aggression pact")
+	w.Bullet("When war breaks out, your allies at 60+ opinion join your side (+1 war score each); the enemy's allies join against you unless their opinion of you is above 0")
+
+	w.Heading("Commands")
+	w.Pre(
+		w.Cmd("diplomacy")+" gift <faction>      — Send a gift",
+		w.Cmd("diplomacy")+" ally <faction>      — Propose alliance",
+		w.Cmd("diplomacy")+" rival <faction>     — Declare rivalry",
+		w.Cmd("diplomacy")+" embargo <faction>   — Block trade",
+		w.Cmd("diplomacy")+" neutral <faction>   — Return to neutral",
+		w.Cmd("diplomacy")+" accept <faction>    — Sign a proposed treaty",
+		w.Cmd("diplomacy")+" decline <faction>   — Turn a proposal down",
+		w.Cmd("diplomacy")+" break <faction>     — Walk out of a treaty",
+		w.Cmd("diplomacy")+" fulfill <faction>   — Pay an open request",
+		w.Cmd("diplomacy")+" refuse <faction>    — Refuse an open request",
+		w.Cmd("diplomacy")+" peace <faction>     — Pay tribute to end a war",
+	)
+}
+
+func wikiEvents(w WikiWriter, state game.GameState) {
+	w.Title("Random Events & Milestones")
+
+	w.Heading("Random Events")
+	w.Para(
+		"Events trigger randomly during gameplay. Some are",
+		"beneficial (bonus resources), some are harmful (lost",
+		"production), and some are mixed.",
+	)
+	w.Bullet("Events respect " + w.Em("age requirements"))
+	w.Bullet("Each event has a " + w.Em("cooldown") + " between occurrences")
+	w.Bullet("Your world's " + w.Em("biome") + " makes some events likelier, rarer or impossible")
+	w.Bullet("Timed events show in the " + w.Em("Active Events") + " panel")
+	w.Bullet("All events are logged in the game log")
 
 	// Show active events
 	if len(state.ActiveEvents) > 0 {
-		sb.WriteString("[orange]Active Events:[-]\n")
+		w.Heading(w.Live("Active Events:"))
 		for _, evt := range state.ActiveEvents {
-			fmt.Fprintf(&sb, "  [yellow]⚡ %s[-] (%d ticks left)\n", evt.Name, evt.TicksLeft)
+			w.Bullet(fmt.Sprintf("%s (%d ticks left)", w.Em("⚡ "+evt.Name), evt.TicksLeft))
 		}
-		sb.WriteString("\n")
 	}
 
 	// List event types
-	events := config.RandomEvents()
-	sb.WriteString("[gold]Event Types[-]\n\n")
-	for _, evt := range events {
-		color := "cyan"
+	w.Heading("Event Types")
+	for _, evt := range config.RandomEvents() {
 		isNegative := false
 		for _, eff := range evt.Effects {
 			if eff.Type == "steal_resource" || eff.Value < 0 {
 				isNegative = true
 			}
 		}
+		extra := []string{w.Dim("from ") + w.Link(evt.MinAge, evt.MinAge+"+")}
 		if isNegative {
-			color = "red"
+			extra = append(extra, w.Bad("harmful"))
 		}
-		fmt.Fprintf(&sb, " [%s]%s[-] [gray](%s+)[-]\n", color, evt.Name, evt.MinAge)
-		fmt.Fprintf(&sb, "   %s\n\n", evt.Description)
+		w.Entry(evt.Name, evt.Key, extra...)
+		w.Detail(evt.Description)
 	}
 
 	// Milestones
-	sb.WriteString("\n[gold]Milestones[-]\n\n")
-	sb.WriteString("Milestones are permanent achievements that reward\n")
-	sb.WriteString("bonuses when conditions are met.\n\n")
+	w.Heading("Milestones")
+	w.Para(
+		"Milestones are permanent achievements that reward",
+		"bonuses when conditions are met.",
+	)
 
 	ms := state.Milestones
-	fmt.Fprintf(&sb, "[orange]Progress: %d / %d[-]\n\n", ms.CompletedCount, ms.TotalCount)
+	w.Para(w.Live(fmt.Sprintf("Progress: %d / %d", ms.CompletedCount, ms.TotalCount)))
 
 	mKeys := make([]string, 0, len(ms.Milestones))
 	for k := range ms.Milestones {
@@ -648,66 +1196,66 @@ func wikiEvents(state game.GameState) string {
 	for _, key := range mKeys {
 		m := ms.Milestones[key]
 		if m.Completed {
-			fmt.Fprintf(&sb, " [green]✓ %s[-]\n", m.Name)
+			w.Entry(m.Name, key, w.Good("✓"))
 		} else {
-			fmt.Fprintf(&sb, " [gray]○ %s[-]\n", m.Name)
+			w.Entry(m.Name, key, w.Dim("○"))
 		}
-		fmt.Fprintf(&sb, "   %s\n\n", m.Description)
+		w.Detail(m.Description)
 	}
-
-	return sb.String()
 }
 
-func wikiPrestige(state game.GameState) string {
-	var sb strings.Builder
-	sb.WriteString("[gold]Prestige System[-]\n\n")
-	sb.WriteString("Prestige is the endgame loop. Once you reach the\n")
-	sb.WriteString("[yellow]Medieval Age[-] or later, you can prestige to reset\n")
-	sb.WriteString("your game and earn permanent bonuses.\n\n")
-
-	sb.WriteString("[gold]How It Works[-]\n")
-	sb.WriteString("  1. Play until you reach [yellow]Medieval Age[-] or beyond\n")
-	sb.WriteString("  2. Type [cyan]prestige confirm[-] to reset\n")
-	sb.WriteString("  3. Earn [yellow]prestige points[-] based on progress\n")
-	sb.WriteString("  4. Spend points in the [yellow]prestige shop[-]\n")
-	sb.WriteString("  5. Start over with permanent bonuses!\n\n")
-
-	sb.WriteString("[gold]What Gets Reset[-]\n")
-	sb.WriteString("  [red]Wiped:[-] Resources, buildings, villagers, research,\n")
-	sb.WriteString("  military, events, milestones, build queue, age\n")
-	sb.WriteString("  [green]Kept:[-] Prestige level, points, purchased upgrades\n\n")
-
-	sb.WriteString("[gold]Point Calculation[-]\n")
-	sb.WriteString("  • Base: 1 point per age beyond Primitive\n")
-	sb.WriteString("    (Medieval = 5, Modern = 12)\n")
-	sb.WriteString("  • Bonus: +1 per 10 milestones completed\n")
-	sb.WriteString("  • Bonus: +1 per 15 techs researched\n")
-	sb.WriteString("  • Bonus: +1 per 50 buildings built\n")
-	sb.WriteString("  • Diminishing returns at higher prestige levels\n\n")
-
-	sb.WriteString("[gold]Passive Bonuses[-]\n")
-	sb.WriteString("  Each prestige level gives:\n")
-	sb.WriteString("  • [green]+2% production[-] to all resources\n")
-	sb.WriteString("  • [green]+1% tick speed[-] (game runs faster!)\n")
-	sb.WriteString("  These stack and apply automatically.\n\n")
+func wikiPrestige(w WikiWriter, state game.GameState) {
+	w.Title("Prestige System")
+	w.Para(
+		"Prestige is the endgame loop. Once you reach the",
+		w.Link("medieval_age", "Medieval Age")+" or later, you can prestige to reset",
+		"your game and earn permanent bonuses.",
+	)
+
+	w.Heading("How It Works")
+	w.Step(1, "Play until you reach "+w.Em("Medieval Age")+" or beyond")
+	w.Step(2, "Type "+w.Cmd("prestige confirm")+" to reset")
+	w.Step(3, "Earn "+w.Em("prestige points")+" based on progress")
+	w.Step(4, "Spend points in the "+w.Em("prestige shop"))
+	w.Step(5, "Start over with permanent bonuses!")
+
+	w.Heading("What Gets Reset")
+	w.Para(
+		w.Bad("Wiped:")+" Resources, buildings, villagers, research,",
+		"military, events, milestones, build queue, age",
+		w.Good("Kept:")+" Prestige level, points, purchased upgrades",
+	)
+
+	w.Heading("Point Calculation")
+	w.Bullet("Base: 1 point per age beyond Primitive (Medieval = 5, Modern = 12)")
+	w.Bullet("Bonus: +1 per 10 milestones completed")
+	w.Bullet("Bonus: +1 per 15 techs researched")
+	w.Bullet("Bonus: +1 per 50 buildings built")
+	w.Bullet("Diminishing returns at higher prestige levels")
+
+	w.Heading("Passive Bonuses")
+	w.Para("Each prestige level gives:")
+	w.Bullet(w.Good("+2% production") + " to all resources")
+	w.Bullet(w.Good("+1% tick speed") + " (game runs faster!)")
+	w.Para("These stack and apply automatically.")
 
 	// Live status
 	p := state.Prestige
-	sb.WriteString("[gold]Your Prestige Status[-]\n")
-	fmt.Fprintf(&sb, "  Level: [cyan]%d[-]\n", p.Level)
-	fmt.Fprintf(&sb, "  Points: [cyan]%d[-] available / [cyan]%d[-] total earned\n", p.Available, p.TotalEarned)
+	w.Heading("Your Prestige Status")
+	w.Bullet(fmt.Sprintf("Level: %s", w.Cmd(fmt.Sprintf("%d", p.Level))))
+	w.Bullet(fmt.Sprintf("Points: %s available / %s total earned",
+		w.Cmd(fmt.Sprintf("%d", p.Available)), w.Cmd(fmt.Sprintf("%d", p.TotalEarned))))
 	if p.PassiveBonus > 0 {
-		fmt.Fprintf(&sb, "  Passive: [green]+%.0f%%[-] production\n", p.PassiveBonus*100)
+		w.Bullet(fmt.Sprintf("Passive: %s production", w.Good(fmt.Sprintf("+%.0f%%", p.PassiveBonus*100))))
 	}
 	if p.CanPrestige {
-		fmt.Fprintf(&sb, "  [green]You can prestige now for %d points![-]\n", p.PendingPoints)
+		w.Bullet(w.Good(fmt.Sprintf("You can prestige now for %d points!", p.PendingPoints)))
 	} else {
-		fmt.Fprintf(&sb, "  [yellow]Reach Medieval Age to prestige[-]\n")
+		w.Bullet(w.Warn("Reach Medieval Age to prestige"))
 	}
-	sb.WriteString("\n")
 
 	// Shop listing
-	sb.WriteString("[gold]Prestige Shop[-]\n\n")
+	w.Heading("Prestige Shop")
 	for _, key := range []string{
 		"gather_boost", "storage_bonus", "research_speed", "military_power",
 		"starting_food", "starting_wood", "population_cap", "expedition_loot",
@@ -717,242 +1265,168 @@ func wikiPrestige(state game.GameState) string {
 		if !ok {
 			continue
 		}
-		icon := "[gray]○[-]"
+		icon := w.Dim("○")
 		if u.Tier >= u.MaxTier {
-			icon = "[green]★[-]"
+			icon = w.Good("★")
 		} else if u.Tier > 0 {
-			icon = "[cyan]◆[-]"
+			icon = w.Cmd("◆")
 		}
-		costStr := "[gray]MAXED[-]"
+		costStr := w.Dim("MAXED")
 		if u.NextCost > 0 {
 			costStr = fmt.Sprintf("%d pts", u.NextCost)
 		}
-		fmt.Fprintf(&sb, " %s [cyan]%s[-] (%d/%d)\n", icon, u.Name, u.Tier, u.MaxTier)
-		fmt.Fprintf(&sb, "   %s\n", u.Description)
+		w.Entry(u.Name, key, icon, fmt.Sprintf("(%d/%d)", u.Tier, u.MaxTier))
+		w.Detail(u.Description)
 		if u.Tier > 0 {
-			fmt.Fprintf(&sb, "   Current: [green]%s[-]\n", u.Effect)
+			w.Detail("Current: " + w.Good(u.Effect))
 		}
-		fmt.Fprintf(&sb, "   Next tier: %s\n\n", costStr)
+		w.Detail("Next tier: " + costStr)
 	}
 
-	sb.WriteString("[gold]Commands[-]\n")
-	sb.WriteString("  [cyan]prestige[-]              — View prestige status\n")
-	sb.WriteString("  [cyan]prestige confirm[-]      — Reset with prestige bonus\n")
-	sb.WriteString("  [cyan]prestige shop[-]         — View upgrade shop\n")
-	sb.WriteString("  [cyan]prestige buy[-] <key>    — Purchase an upgrade tier\n")
-
-	return sb.String()
+	w.Heading("Commands")
+	w.Pre(
+		w.Cmd("prestige")+"              — View prestige status",
+		w.Cmd("prestige confirm")+"      — Reset with prestige bonus",
+		w.Cmd("prestige shop")+"         — View upgrade shop",
+		w.Cmd("prestige buy")+" <key>    — Purchase an upgrade tier",
+	)
 }
 
-func wikiCommands(_ game.GameState) string {
-	return `[gold]Commands[-]
-
-All commands can be typed in the input bar at the bottom.
-Most have single-letter shortcuts.
-
-[gold]── Resource Gathering ──[-]
-
-  [cyan]gather[-] <food|wood|stone> [amount]
-  Shortcut: [cyan]g[-]
-  Hand-gather food, wood, or stone. Default 3, max 5.
-  Example: [yellow]gather food[-], [yellow]g wood 5[-], [yellow]g stone[-]
-
-[gold]── Building ──[-]
-
-  [cyan]build[-] <building_key>
-  Shortcut: [cyan]b[-]
-  Construct a building. Costs scale with each built.
-  Type [yellow]build[-] with no args to see available buildings.
-  Example: [yellow]build hut[-] or [yellow]b gathering_camp[-]
-
-  Buildings with build time (wonders) are queued and
-  complete after the required number of ticks.
-
-[gold]── Villagers ──[-]
-
-  [cyan]recruit[-] <type> [count]
-  Shortcut: [cyan]r[-]
-  Recruit villagers. Requires available population cap.
-  Example: [yellow]recruit worker 3[-] or [yellow]r scholar[-]
-
-  [cyan]assign[-] <type> <resource> [count]
-  Shortcut: [cyan]a[-]
-  Assign idle villagers to gather a resource.
-  Example: [yellow]assign worker food 2[-] or [yellow]a worker wood[-]
-
-  [cyan]unassign[-] <type> <resource> [count]
-  Shortcut: [cyan]u[-]
-  Remove villagers from a gathering assignment.
-  Example: [yellow]unassign worker stone 1[-]
-
-[gold]── Research ──[-]
-
-  [cyan]research[-] <tech_key>
-  Shortcut: [cyan]res[-]
-  Start researching a technology. Costs knowledge.
-  Example: [yellow]research tool_making[-] or [yellow]res fire_mastery[-]
-
-  [cyan]research cancel[-]
-  Cancel current research (no refund).
-
-  [cyan]research list[-]
-  Show available technologies.
-
-[gold]── Military ──[-]
-
-  [cyan]expedition[-] <key>
-  Shortcut: [cyan]exp[-]
-  Launch a military expedition. Requires soldiers.
-  Example: [yellow]expedition scout_ruins[-] or [yellow]exp raid_bandits[-]
-
-  [cyan]expedition list[-]
-  Show available expeditions.
-
-[gold]── Prestige ──[-]
-
-  [cyan]prestige[-]
-  View your prestige level, points, and bonuses.
-
-  [cyan]prestige confirm[-]
-  Reset the game and earn prestige points.
-  Requires Medieval Age or later.
-
-  [cyan]prestige shop[-]
-  View available prestige upgrades and costs.
-
-  [cyan]prestige buy[-] <upgrade_key>
-  Purchase the next tier of a prestige upgrade.
-  Example: [yellow]prestige buy gather_boost[-]
-
-[gold]── Information ──[-]
-
-  [cyan]status[-]        Detailed civilization overview
-  Shortcut: [cyan]s[-]
-
-  [cyan]help[-]          Command quick reference
-  Shortcut: [cyan]h[-] or [cyan]?[-]
-
-[gold]── Save/Load ──[-]
-
-  [cyan]save[-] [name]    Save game (default: autosave)
-  [cyan]load[-] [name]    Load game (default: autosave)
-
-  Game auto-saves when you press ESC to return to menu.
-  Saves are stored in data/saves/ as JSON files.
-
-[gold]── Debug ──[-]
-
-  [cyan]dump[-]
-  Export all logs and engine state to a file for debugging.
-  Creates a timestamped file in data/logs/.
-  Example: [yellow]dump[-]
-
-[gold]── Other ──[-]
-
-  [cyan]quit[-]          Save and exit the game
-
-[gold]── Navigation ──[-]
+func wikiCommands(w WikiWriter, _ game.GameState) {
+	w.Title("Commands")
+	w.Para(
+		"All commands can be typed in the input bar at the bottom.",
+		"Most have short aliases. Type "+w.Cmd("help <command>")+" in game for live hints.",
+	)
+
+	for _, c := range commandRegistry {
+		w.Section(c.name)
+		var lines []string
+		for _, u := range c.usage {
+			lines = append(lines, w.Cmd(u))
+		}
+		if len(c.aliases) > 0 {
+			var aliases []string
+			for _, a := range c.aliases {
+				aliases = append(aliases, w.Cmd(a))
+			}
+			lines = append(lines, "Shortcut: "+strings.Join(aliases, " or "))
+		}
+		lines = append(lines, c.summary+".")
+		w.Para(lines...)
+		for _, a := range c.args {
+			w.Bullet(w.Dim(a.name) + " — " + a.desc)
+		}
+		if len(c.examples) > 0 {
+			var examples []string
+			for _, e := range c.examples {
+				examples = append(examples, w.Em(e))
+			}
+			w.Para("Example: " + strings.Join(examples, ", "))
+		}
+	}
 
-  F1-F5 / Tab    Switch between dashboard tabs
-  Shift+Tab      Previous tab
-  ↑↓ / 1-9       Navigate wiki pages (in Wiki tab)
-  PgUp/PgDn      Scroll wiki content
-  ESC            Auto-save and return to main menu`
+	w.Section("Notes")
+	w.Para(
+		"Buildings with build time (wonders) are queued and",
+		"complete after the required number of ticks.",
+	)
+	w.Para(
+		"Game auto-saves when you press ESC to return to menu.",
+		"Saves are stored in data/saves/ as JSON files.",
+		"Log dumps are written to data/logs/.",
+	)
+
+	w.Section("Navigation")
+	w.Pre(
+		"F1-F9 / Tab    Switch between dashboard tabs",
+		"Shift+Tab      Previous tab",
+		"↑↓ / 1-9       Navigate wiki pages (in Wiki tab)",
+		"PgUp/PgDn      Scroll wiki content",
+		"/              Search the wiki (buildings, techs, ages, ...)",
+		"←→ / Enter     Select and follow links from a found entry",
+		"ESC            Auto-save and return to main menu",
+	)
 }
 
-func wikiStrategy(_ game.GameState) string {
-	return `[gold]Tips & Strategy[-]
-
-[gold]── Early Game (Primitive Age) ──[-]
-
-  • Gather wood first — you need 5 for your first stash
-  • Build stashes early! Base storage is 50 but you need
-    1500 food for the Stone Age
-  • Build altars to start generating knowledge — you
-    need 200 knowledge and 5 altars for Stone Age
-  • Recruit shamans and assign them to knowledge
-  • Build huts, recruit workers, keep 1/3 on food
-  • Don't recruit faster than you can feed
-
-[gold]── Stone Age ──[-]
-
-  • Build Storage Pits early — caps fill fast
-  • Stone Pits are slow (0.1/tick) so build several
-  • Firepits generate knowledge for Bronze Age
-  • You need 1250 food, 750 stone, 250 knowledge for Bronze
-  • That means LOTS of storage buildings first
-
-[gold]── Bronze Age ──[-]
-
-  • This is the big unlock — farms, mines, markets, etc.
-  • Warehouses (+150 storage) are critical for scaling
-  • Scholars are now available — assign to knowledge
-  • Iron and gold open up new building options
-  • Start saving for Iron Age requirements early
-
-[gold]── Research ──[-]
-
-  • Start researching [yellow]Tool Making[-] as soon as you have
-    scholars generating knowledge
-  • Research bonuses stack — prioritize production multipliers
-  • [yellow]Agriculture[-] is huge — +0.5 food/tick permanently
-  • Keep a steady knowledge income for continuous research
-
-[gold]── Military ──[-]
-
-  • Soldiers eat food but don't gather — balance carefully
-  • Start with easier expeditions (Scout Ruins) to build loot
-  • Military bonuses from research improve expedition success
-  • Failed expeditions still give partial loot
-
-[gold]── Prestige ──[-]
-
-  • Don't prestige too early — push past Medieval for more points
-  • [yellow]Starting Food/Wood[-] upgrades help early game the most
-  • [yellow]Gather Boost[-] and [yellow]Research Speed[-] compound over time
-  • The passive +2% production and +1% tick speed per level adds up
-  • [yellow]Temporal Mastery[-] upgrade gives +5% tick speed per tier
-  • Each prestige is faster than the last thanks to bonuses
-
-[gold]── General Tips ──[-]
-
-  • [yellow]Storage is the real gate[-] — you can't hold age
-    requirements without building storage infrastructure
-  • [yellow]Building costs scale[-] — your 10th hut costs much
-    more than your 1st. Plan purchases carefully.
-  • [yellow]Wonders are worth it[-] — they take many ticks but
-    provide powerful bonuses
-  • [yellow]Diversify production[-] — don't put all workers on
-    one resource
-  • [yellow]Watch for events[-] — random events can help or
-    hurt. Active events show in the Stats tab.
-  • [yellow]Chase milestones[-] — they give permanent bonuses
-    that compound over time
-  • [yellow]Check the Ages wiki[-] — requirements turn green
-    as you meet them, plan ahead
-  • [yellow]The game is idle[-] — leave it running and check
-    back. Resources accumulate over time.
-  • [yellow]Save often[-] — type 'save' before closing
-
-[gold]── Production Math ──[-]
-
-  Worker gather rate:  0.35 / tick (every 2 sec)
-  Worker food cost:    0.10 / tick  (net food: +0.25)
-  Shaman gather rate:  0.20 / tick (knowledge only)
-  Shaman food cost:    0.20 / tick
-  Scholar gather rate:  0.25 / tick
-  Scholar food cost:   0.20 / tick
-  Merchant gather rate: 0.30 / tick (gold, crypto)
-  Merchant food cost:  0.20 / tick
-  Engineer gather rate: 0.35 / tick (oil, electricity, data)
-  Engineer food cost:  0.25 / tick
-  Hacker gather rate:  0.40 / tick (data, crypto)
-  Hacker food cost:    0.30 / tick
-  Astronaut gather rate: 0.50 / tick (titanium, dark matter, plasma)
-  Astronaut food cost: 0.40 / tick
-
-  Per hour (1800 ticks):
-    1 worker gathering:  630 resources
-    1 worker food cost:  180 food
-    1 building at 0.1/tick: 180 resources`
+func wikiStrategy(w WikiWriter, _ game.GameState) {
+	w.Title("Tips & Strategy")
+
+	w.Section("Early Game (Primitive Age)")
+	w.Bullet("Gather wood first — you need 5 for your first stash")
+	w.Bullet("Build stashes early! Base storage is 50 but you need 1500 food for the Stone Age")
+	w.Bullet("Build altars to start generating knowledge — you need 200 knowledge and 5 altars for Stone Age")
+	w.Bullet("Recruit shamans and assign them to knowledge")
+	w.Bullet("Build huts, recruit workers, keep 1/3 on food")
+	w.Bullet("Don't recruit faster than you can feed")
+
+	w.Section("Stone Age")
+	w.Bullet("Build " + w.Link("storage_pit", "Storage Pits") + " early — caps fill fast")
+	w.Bullet(w.Link("stone_pit", "Stone Pits") + " are slow (0.1/tick) so build several")
+	w.Bullet(w.Link("firepit", "Firepits") + " generate knowledge for Bronze Age")
+	w.Bullet("You need 1250 food, 750 stone, 250 knowledge for Bronze")
+	w.Bullet("That means LOTS of storage buildings first")
+
+	w.Section("Bronze Age")
+	w.Bullet("This is the big unlock — farms, mines, markets, etc.")
+	w.Bullet(w.Link("warehouse", "Warehouses") + " (+150 storage) are critical for scaling")
+	w.Bullet(w.Link("scholar", "Scholars") + " are now available — assign to knowledge")
+	w.Bullet("Iron and gold open up new building options")
+	w.Bullet("Start saving for Iron Age requirements early")
+
+	w.Section("Research")
+	w.Bullet("Start researching " + w.Link("tool_making", "Tool Making") + " as soon as you have scholars generating knowledge")
+	w.Bullet("Research bonuses stack — prioritize production multipliers")
+	w.Bullet(w.Link("agriculture", "Agriculture") + " is huge — +0.5 food/tick permanently")
+	w.Bullet("Keep a steady knowledge income for continuous research")
+
+	w.Section("Military")
+	w.Bullet("Soldiers eat food and gold but don't gather — balance carefully")
+	w.Bullet("Build " + w.Link("barracks", "Barracks") + " before a big recruitment drive to train faster")
+	w.Bullet("Start with easier expeditions (Scout Ruins) to build loot")
+	w.Bullet("Military bonuses from research make battles cheaper to win")
+	w.Bullet("Check " + w.Cmd("expedition info") + " before launching — the forecast is exact")
+	w.Bullet("Failed expeditions still give partial loot")
+
+	w.Section("Prestige")
+	w.Bullet("Don't prestige too early — push past Medieval for more points")
+	w.Bullet(w.Em("Starting Food/Wood") + " upgrades help early game the most")
+	w.Bullet(w.Em("Gather Boost") + " and " + w.Em("Research Speed") + " compound over time")
+	w.Bullet("The passive +2% production and +1% tick speed per level adds up")
+	w.Bullet(w.Em("Temporal Mastery") + " upgrade gives +5% tick speed per tier")
+	w.Bullet("Each prestige is faster than the last thanks to bonuses")
+
+	w.Section("General Tips")
+	w.Bullet(w.Em("Storage is the real gate") + " — you can't hold age requirements without building storage infrastructure")
+	w.Bullet(w.Em("Building costs scale") + " — your 10th hut costs much more than your 1st. Plan purchases carefully.")
+	w.Bullet(w.Em("Wonders are worth it") + " — they take many ticks but provide powerful bonuses")
+	w.Bullet(w.Em("Diversify production") + " — don't put all workers on one resource")
+	w.Bullet(w.Em("Watch for events") + " — random events can help or hurt. Active events show in the Stats tab.")
+	w.Bullet(w.Em("Chase milestones") + " — they give permanent bonuses that compound over time")
+	w.Bullet(w.Em("Check the Ages wiki") + " — requirements turn green as you meet them, plan ahead")
+	w.Bullet(w.Em("The game is idle") + " — leave it running and check back. Resources accumulate over time.")
+	w.Bullet(w.Em("Save often") + " — type 'save' before closing")
+
+	w.Section("Production Math")
+	w.Pre(
+		"Worker gather rate:  0.35 / tick (every 2 sec)",
+		"Worker food cost:    0.10 / tick  (net food: +0.25)",
+		"Shaman gather rate:  0.20 / tick (knowledge only)",
+		"Shaman food cost:    0.20 / tick",
+		"Scholar gather rate:  0.25 / tick",
+		"Scholar food cost:   0.20 / tick",
+		"Merchant gather rate: 0.30 / tick (gold, crypto)",
+		"Merchant food cost:  0.20 / tick",
+		"Engineer gather rate: 0.35 / tick (oil, electricity, data)",
+		"Engineer food cost:  0.25 / tick",
+		"Hacker gather rate:  0.40 / tick (data, crypto)",
+		"Hacker food cost:    0.30 / tick",
+		"Astronaut gather rate: 0.50 / tick (titanium, dark matter, plasma)",
+		"Astronaut food cost: 0.40 / tick",
+		"",
+		"Per hour (1800 ticks):",
+		"  1 worker gathering:  630 resources",
+		"  1 worker food cost:  180 food",
+		"  1 building at 0.1/tick: 180 resources",
+	)
 }
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/ageforge/config"
+	"github.com/user/ageforge/game"
+)
+
+// Timelapse frame size in pixels, and how long each frame shows in the GIF
+// (hundredths of a second); the last frame holds so the loop has an ending
+const (
+	timelapseWidth     = 480
+	timelapseHeight    = 320
+	timelapseDelay     = 40
+	timelapseLastDelay = 300
+)
+
+// ExportTimelapse renders every frame of a run on the world grown from seed
+// and writes them to path: one animated GIF when format is "gif", or a
+// numbered PNG per frame in the directory path when it is "png". It returns
+// the files written.
+func ExportTimelapse(frames []game.TimelapseFrame, seed uint64, format, path string) ([]string, error) {
+	if format != "gif" && format != "png" {
+		return nil, fmt.Errorf("unknown format: %s (use gif or png)", format)
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no timelapse frames recorded yet")
+	}
+
+	dir := path
+	if format == "gif" {
+		dir = filepath.Dir(path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	// Each frame is written or palettized as soon as it is drawn, so only
+	// one full-colour image is held at a time
+	layouts := game.TimelapseLayouts(seed, frames)
+	ages := config.AgeByKey()
+	anim := &gif.GIF{}
+	var written []string
+	for i, f := range frames {
+		img := GenerateMapImage(MapGenConfig{
+			Width:       timelapseWidth,
+			Height:      timelapseHeight,
+			DetailLevel: 1,
+			AgeKey:      f.Age,
+			Settlement:  layouts[i],
+		})
+		name := f.Age
+		if a, ok := ages[f.Age]; ok {
+			name = a.Name
+		}
+		drawCaption(img, fmt.Sprintf("%s  tick %d", name, f.Tick))
+
+		if format == "png" {
+			file := filepath.Join(path, fmt.Sprintf("frame_%03d.png", i))
+			if err := writePNG(file, img); err != nil {
+				return written, err
+			}
+			written = append(written, file)
+			continue
+		}
+		pal := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(pal, img.Bounds(), img, image.Point{})
+		delay := timelapseDelay
+		if i == len(frames)-1 {
+			delay = timelapseLastDelay
+		}
+		anim.Image = append(anim.Image, pal)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	if format == "png" {
+		return written, nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+	if err := gif.EncodeAll(f, anim); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return []string{path}, nil
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ─── Captions ────────────────────────────────────────────
+
+// captionScale is the size in pixels of one dot of the caption font
+const captionScale = 2
+
+// drawCaption writes text in the bottom-left corner over a dark band
+func drawCaption(img *image.RGBA, text string) {
+	b := img.Bounds()
+	bandH := (glyphHeight + 4) * captionScale
+	band := image.Rect(b.Min.X, b.Max.Y-bandH, b.Max.X, b.Max.Y)
+	draw.Draw(img, band, image.NewUniform(color.RGBA{0, 0, 0, 160}), image.Point{}, draw.Over)
+
+	x := b.Min.X + 2*captionScale
+	y := band.Min.Y + 2*captionScale
+	for _, r := range strings.ToUpper(text) {
+		rows, ok := captionFont[r]
+		if !ok {
+			rows = captionFont['?']
+		}
+		for gy, row := range rows {
+			for gx, dot := range row {
+				if dot != '#' {
+					continue
+				}
+				px, py := x+gx*captionScale, y+gy*captionScale
+				draw.Draw(img, image.Rect(px, py, px+captionScale, py+captionScale),
+					image.NewUniform(color.RGBA{255, 215, 0, 255}), image.Point{}, draw.Src)
+			}
+		}
+		x += (glyphWidth + 1) * captionScale
+	}
+}
+
+const glyphWidth, glyphHeight = 3, 5
+
+// captionFont is a 3×5 dot font covering what captions need: age names
+// and tick numbers
+var captionFont = map[rune][glyphHeight]string{
+	' ': {"...", "...", "...", "...", "..."},
+	'?': {"##.", "..#", ".#.", "...", ".#."},
+	'-': {"...", "...", "###", "...", "..."},
+	'.': {"...", "...", "...", "...", ".#."},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"##.", "..#", ".#.", "#..", "###"},
+	'3': {"##.", "..#", ".#.", "..#", "##."},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "##.", "..#", "##."},
+	'6': {".##", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "##."},
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+}
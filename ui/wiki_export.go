@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/ageforge/config"
+	"github.com/user/ageforge/game"
+)
+
+// wikiLinkIndex maps every linkable content key to the wiki page that
+// documents it, so writers can turn keys into cross-page links
+func wikiLinkIndex(state game.GameState) map[string]string {
+	links := make(map[string]string)
+	for _, r := range config.BaseResources() {
+		links[r.Key] = "resources"
+	}
+	for _, b := range config.BaseBuildings() {
+		links[b.Key] = "buildings"
+	}
+	for _, v := range game.DefaultVillagerTypes() {
+		links[v.Key] = "villagers"
+	}
+	for _, t := range config.Technologies() {
+		links[t.Key] = "research"
+	}
+	for _, e := range config.Expeditions() {
+		links[e.Key] = "military"
+	}
+	for _, u := range config.Units() {
+		links[u.Key] = "military"
+	}
+	for _, f := range config.BaseFactions() {
+		links[f.Key] = "factions"
+	}
+	for _, a := range config.Ages() {
+		links[a.Key] = "ages"
+	}
+	for _, e := range config.RandomEvents() {
+		links[e.Key] = "events"
+	}
+	for key := range state.Milestones.Milestones {
+		links[key] = "events"
+	}
+	for key := range state.Prestige.Upgrades {
+		links[key] = "prestige"
+	}
+	return links
+}
+
+// ExportWiki writes every wiki page to dir as a static, cross-linked site in
+// the given format ("md" or "html") and returns the paths written
+func ExportWiki(state game.GameState, format, dir string) ([]string, error) {
+	if format != "md" && format != "html" {
+		return nil, fmt.Errorf("unknown format: %s (use md or html)", format)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	pages := wikiPages()
+	links := wikiLinkIndex(state)
+	var written []string
+
+	write := func(name, content string) error {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	for _, p := range pages {
+		var content string
+		if format == "md" {
+			w := newMarkdownWikiWriter(links)
+			p.render(w, state)
+			content = markdownWikiNav(pages, p.key) + "\n" + w.String()
+		} else {
+			w := newHTMLWikiWriter(links)
+			p.render(w, state)
+			content = htmlWikiDocument(pages, p, w.String())
+		}
+		if err := write(p.key+"."+format, content); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write("index."+format, wikiIndex(pages, format)); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+func markdownWikiNav(pages []wikiPage, current string) string {
+	parts := []string{"[Index](index.md)"}
+	for _, p := range pages {
+		if p.key == current {
+			parts = append(parts, "**"+p.title+"**")
+		} else {
+			parts = append(parts, fmt.Sprintf("[%s](%s.md)", p.title, p.key))
+		}
+	}
+	return strings.Join(parts, " · ") + "\n"
+}
+
+func wikiIndex(pages []wikiPage, format string) string {
+	var sb strings.Builder
+	if format == "md" {
+		sb.WriteString("# AgeForge Wiki\n\n")
+		for _, p := range pages {
+			fmt.Fprintf(&sb, "- [%s](%s.md)\n", p.title, p.key)
+		}
+		return sb.String()
+	}
+
+	sb.WriteString("<h1>AgeForge Wiki</h1>\n<ul>\n")
+	for _, p := range pages {
+		fmt.Fprintf(&sb, "<li><a href=\"%s.html\">%s</a></li>\n", p.key, html.EscapeString(p.title))
+	}
+	sb.WriteString("</ul>\n")
+	return htmlWikiDocument(pages, wikiPage{title: "Index"}, sb.String())
+}
+
+const htmlWikiStyle = `body{background:#111;color:#ddd;font-family:monospace;max-width:900px;margin:2em auto;line-height:1.4}
+nav a,nav b{margin-right:.8em}a{color:#e6c84a}h1,h2,h3{color:#ffd700}h4{color:#4cc9f0;margin-bottom:.2em}
+code{color:#4cc9f0}em{color:#ffeb3b;font-style:normal}.dim{color:#888}.live{color:#ffa500}
+.good{color:#5c5}.warn{color:#ffeb3b}.bad{color:#e55}ul.details{margin-top:0}`
+
+func htmlWikiDocument(pages []wikiPage, current wikiPage, body string) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>AgeForge Wiki — %s</title>\n", html.EscapeString(current.title))
+	fmt.Fprintf(&sb, "<style>%s</style>\n</head><body>\n<nav><a href=\"index.html\">Index</a>", htmlWikiStyle)
+	for _, p := range pages {
+		if p.key == current.key {
+			fmt.Fprintf(&sb, "<b>%s</b>", html.EscapeString(p.title))
+		} else {
+			fmt.Fprintf(&sb, "<a href=\"%s.html\">%s</a>", p.key, html.EscapeString(p.title))
+		}
+	}
+	sb.WriteString("</nav>\n<hr>\n")
+	sb.WriteString(body)
+	sb.WriteString("</body></html>\n")
+	return sb.String()
+}
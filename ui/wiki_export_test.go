@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/user/ageforge/game"
+)
+
+// bracketed matches text in square brackets along with the character after
+// it, so Markdown link text (followed by its target) can be told apart
+var bracketed = regexp.MustCompile(`\[([^\[\]\n]*)\](\(?)`)
+
+// isTviewTag reports whether the text between brackets is a tview colour,
+// style or region tag such as gray, -, ::b, black:gold or "l:hut", rather
+// than prose like a command's [count]
+func isTviewTag(inner string) bool {
+	if inner == "-" || strings.HasPrefix(inner, `"`) {
+		return true
+	}
+	parts := strings.Split(inner, ":")
+	if len(parts) > 3 || inner == "" {
+		return false
+	}
+	for i, p := range parts {
+		switch {
+		case p == "" || p == "-":
+		case i < 2 && (strings.HasPrefix(p, "#") || tcell.ColorNames[p] != tcell.ColorDefault):
+		case i == 2 && strings.Trim(p, "lbidrus") == "":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func TestExportWiki_NoTviewMarkup(t *testing.T) {
+	state := game.NewGameEngine().GetState()
+	for _, format := range []string{"md", "html"} {
+		files, err := ExportWiki(state, format, t.TempDir())
+		if err != nil {
+			t.Fatalf("ExportWiki(%s) failed: %v", format, err)
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading %s: %v", path, err)
+			}
+			for _, m := range bracketed.FindAllSubmatch(data, -1) {
+				if len(m[2]) == 0 && isTviewTag(string(m[1])) {
+					t.Errorf("%s contains tview markup %q", path, m[0])
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/user/ageforge/game"
+)
+
+const wikiMaxResults = 15
+
+// wikiIndexEntry is one searchable wiki entry (building, tech, age, ...)
+type wikiIndexEntry struct {
+	key   string
+	name  string
+	page  int
+	links []string // keys mentioned in the entry, followable as links
+}
+
+// buildWikiIndex renders every page once and collects its entries
+func buildWikiIndex(pages []wikiPage, state game.GameState) []wikiIndexEntry {
+	var index []wikiIndexEntry
+	seen := make(map[string]bool)
+	for i, p := range pages {
+		w := newTviewWikiWriter()
+		p.render(w, state)
+		for _, e := range w.entries {
+			if seen[e.key] {
+				continue
+			}
+			seen[e.key] = true
+			index = append(index, wikiIndexEntry{key: e.key, name: e.name, page: i, links: w.links[e.key]})
+		}
+	}
+	return index
+}
+
+// searchWikiIndex ranks entries whose key or name matches the query:
+// exact matches first, then prefixes, then substrings
+func searchWikiIndex(index []wikiIndexEntry, query string) []wikiIndexEntry {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil
+	}
+	qKey := strings.ReplaceAll(q, " ", "_")
+
+	type scored struct {
+		entry wikiIndexEntry
+		score int
+	}
+	var matches []scored
+	for _, e := range index {
+		name := strings.ToLower(e.name)
+		score := -1
+		switch {
+		case e.key == qKey || name == q:
+			score = 0
+		case strings.HasPrefix(e.key, qKey) || strings.HasPrefix(name, q):
+			score = 1
+		case strings.Contains(e.key, qKey) || strings.Contains(name, q):
+			score = 2
+		}
+		if score >= 0 {
+			matches = append(matches, scored{e, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].entry.name < matches[j].entry.name
+	})
+
+	results := make([]wikiIndexEntry, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m.entry)
+	}
+	return results
+}
+
+// Searching reports whether the search prompt is open
+func (t *WikiTab) Searching() bool {
+	return t.searching
+}
+
+// StartSearch opens the search prompt and indexes the current pages
+func (t *WikiTab) StartSearch() {
+	t.searching = true
+	t.query = ""
+	t.results = nil
+	t.selected = 0
+	t.index = buildWikiIndex(t.pages, t.lastState)
+	t.Refresh(t.lastState)
+}
+
+// HandleSearchKey processes a key while the search prompt is open
+func (t *WikiTab) HandleSearchKey(event *tcell.EventKey) {
+	// Redraw immediately rather than waiting for the next UI tick
+	defer t.Refresh(t.lastState)
+
+	switch event.Key() {
+	case tcell.KeyEsc:
+		t.searching = false
+		return
+	case tcell.KeyEnter:
+		if t.selected < len(t.results) {
+			t.searching = false
+			t.FocusEntry(t.results[t.selected].key)
+		}
+		return
+	case tcell.KeyUp:
+		if t.selected > 0 {
+			t.selected--
+		}
+		return
+	case tcell.KeyDown:
+		if t.selected < min(len(t.results), wikiMaxResults)-1 {
+			t.selected++
+		}
+		return
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(t.query) > 0 {
+			r := []rune(t.query)
+			t.query = string(r[:len(r)-1])
+		}
+	case tcell.KeyRune:
+		t.query += string(event.Rune())
+	default:
+		return
+	}
+	t.results = searchWikiIndex(t.index, t.query)
+	t.selected = 0
+}
+
+// FocusEntry jumps to the page holding an entry and highlights it
+func (t *WikiTab) FocusEntry(key string) bool {
+	if t.index == nil {
+		t.index = buildWikiIndex(t.pages, t.lastState)
+	}
+	for _, e := range t.index {
+		if e.key != key {
+			continue
+		}
+		if t.focus != "" && t.focus != key {
+			t.history = append(t.history, t.focus)
+		}
+		t.current = e.page
+		t.focus = key
+		// Only keep links that resolve to an indexed entry
+		t.focusLinks = nil
+		for _, l := range e.links {
+			for _, other := range t.index {
+				if other.key == l {
+					t.focusLinks = append(t.focusLinks, l)
+					break
+				}
+			}
+		}
+		t.linkIdx = 0
+		t.pendingScroll = true
+		return true
+	}
+	return false
+}
+
+// HasLinks reports whether the focused entry has links to follow
+func (t *WikiTab) HasLinks() bool {
+	return t.focus != "" && len(t.focusLinks) > 0
+}
+
+// NextLink selects the next link of the focused entry
+func (t *WikiTab) NextLink() {
+	if len(t.focusLinks) > 0 {
+		t.linkIdx = (t.linkIdx + 1) % len(t.focusLinks)
+	}
+}
+
+// PrevLink selects the previous link of the focused entry
+func (t *WikiTab) PrevLink() {
+	if len(t.focusLinks) > 0 {
+		t.linkIdx = (t.linkIdx + len(t.focusLinks) - 1) % len(t.focusLinks)
+	}
+}
+
+// FollowLink jumps to the entry the selected link points at
+func (t *WikiTab) FollowLink() {
+	if t.linkIdx < len(t.focusLinks) {
+		t.FocusEntry(t.focusLinks[t.linkIdx])
+	}
+}
+
+// Back returns to the previously focused entry. Returns false when the
+// history is empty.
+func (t *WikiTab) Back() bool {
+	if len(t.history) == 0 {
+		return false
+	}
+	prev := t.history[len(t.history)-1]
+	t.history = t.history[:len(t.history)-1]
+	// FocusEntry would push the current entry back onto the history
+	t.focus = ""
+	return t.FocusEntry(prev)
+}
+
+// clearFocus drops the focused entry, e.g. after manual page navigation
+func (t *WikiTab) clearFocus() {
+	t.focus = ""
+	t.focusLinks = nil
+	t.history = nil
+	t.content.Highlight()
+}
+
+// searchNav renders the nav pane while searching
+func (t *WikiTab) searchNav() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, " [gold]Search:[-] %s[black:white] [-:-]\n\n", tview.Escape(t.query))
+	if t.query != "" && len(t.results) == 0 {
+		sb.WriteString(" [gray]No matches[-]\n")
+	}
+	for i, e := range t.results {
+		if i >= wikiMaxResults {
+			fmt.Fprintf(&sb, " [gray]+%d more[-]\n", len(t.results)-wikiMaxResults)
+			break
+		}
+		if i == t.selected {
+			fmt.Fprintf(&sb, " [black:gold] %s [-:-]\n", e.name)
+		} else {
+			fmt.Fprintf(&sb, " %s [gray]%s[-]\n", e.name, t.pages[e.page].title)
+		}
+	}
+	sb.WriteString("\n [gray]↑↓ select  Enter go[-]")
+	sb.WriteString("\n [gray]Esc cancel[-]")
+	return sb.String()
+}
+
+// linksNav renders the links of the focused entry below the page list
+func (t *WikiTab) linksNav() string {
+	if t.focus == "" || len(t.focusLinks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n [gold]Links[-]\n")
+	for i, key := range t.focusLinks {
+		if i == t.linkIdx {
+			fmt.Fprintf(&sb, " [black:yellow] %s [-:-]\n", key)
+		} else {
+			fmt.Fprintf(&sb, " [yellow]%s[-]\n", key)
+		}
+	}
+	sb.WriteString(" [gray]←→ select  Enter follow[-]")
+	if len(t.history) > 0 {
+		sb.WriteString("\n [gray]Bksp back[-]")
+	}
+	return sb.String()
+}
@@ -0,0 +1,400 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// WikiWriter is the output surface wiki pages render into. The terminal tab
+// and the static Markdown/HTML exports each implement it, so every page has
+// a single source regardless of where it is shown.
+type WikiWriter interface {
+	// Block elements
+	Title(text string)
+	Heading(text string)
+	Section(text string)
+	Para(lines ...string)
+	Bullet(text string)
+	Step(n int, text string)
+	Entry(name, key string, extra ...string) // a linkable item (building, tech, age, ...)
+	Detail(text string)                      // a line belonging to the preceding Entry
+	Pre(lines ...string)
+
+	// Inline spans, returned for embedding in block text
+	Em(text string) string
+	Cmd(text string) string
+	Dim(text string) string
+	Live(text string) string
+	Good(text string) string
+	Warn(text string) string
+	Bad(text string) string
+	Link(key, text string) string // cross-reference to the Entry with this key
+
+	String() string
+}
+
+// ===== Terminal (tview tags) =====
+
+// Entries and links are wrapped in tview regions ("e:<key>" and "l:<key>") so
+// the wiki tab can highlight and scroll to them.
+type tviewWikiWriter struct {
+	sb     strings.Builder
+	inList bool
+
+	entries []wikiEntryRef
+	current string              // key of the Entry being written
+	links   map[string][]string // entry key -> keys it links to, in order
+}
+
+type wikiEntryRef struct {
+	key  string
+	name string
+}
+
+func newTviewWikiWriter() *tviewWikiWriter {
+	return &tviewWikiWriter{links: make(map[string][]string)}
+}
+
+func (w *tviewWikiWriter) closeList() {
+	if w.inList {
+		w.sb.WriteString("\n")
+		w.inList = false
+	}
+}
+
+func (w *tviewWikiWriter) Title(text string) {
+	fmt.Fprintf(&w.sb, "[gold]%s[-]\n\n", text)
+}
+
+func (w *tviewWikiWriter) Heading(text string) {
+	w.closeList()
+	w.current = ""
+	fmt.Fprintf(&w.sb, "[gold]%s[-]\n", text)
+}
+
+func (w *tviewWikiWriter) Section(text string) {
+	w.closeList()
+	w.current = ""
+	fmt.Fprintf(&w.sb, "[gold]── %s ──[-]\n", text)
+}
+
+func (w *tviewWikiWriter) Para(lines ...string) {
+	w.closeList()
+	for _, l := range lines {
+		w.sb.WriteString(l + "\n")
+	}
+	w.sb.WriteString("\n")
+}
+
+func (w *tviewWikiWriter) Bullet(text string) {
+	w.inList = true
+	fmt.Fprintf(&w.sb, "  • %s\n", text)
+}
+
+func (w *tviewWikiWriter) Step(n int, text string) {
+	w.inList = true
+	fmt.Fprintf(&w.sb, "  %d. %s\n", n, text)
+}
+
+func (w *tviewWikiWriter) Entry(name, key string, extra ...string) {
+	w.closeList()
+	w.current = key
+	if key != "" {
+		w.entries = append(w.entries, wikiEntryRef{key: key, name: name})
+		fmt.Fprintf(&w.sb, " [\"e:%s\"][cyan]%s[-][\"\"] [gray](%s)[-]", key, name, key)
+	} else {
+		fmt.Fprintf(&w.sb, " [cyan]%s[-]", name)
+	}
+	for _, e := range extra {
+		w.sb.WriteString(" " + e)
+	}
+	w.sb.WriteString("\n")
+	w.inList = true
+}
+
+func (w *tviewWikiWriter) Detail(text string) {
+	w.inList = true
+	fmt.Fprintf(&w.sb, "   %s\n", text)
+}
+
+func (w *tviewWikiWriter) Pre(lines ...string) {
+	w.closeList()
+	for _, l := range lines {
+		w.sb.WriteString("  " + l + "\n")
+	}
+	w.sb.WriteString("\n")
+}
+
+func (w *tviewWikiWriter) Em(text string) string   { return "[yellow]" + text + "[-]" }
+func (w *tviewWikiWriter) Cmd(text string) string  { return "[cyan]" + text + "[-]" }
+func (w *tviewWikiWriter) Dim(text string) string  { return "[gray]" + text + "[-]" }
+func (w *tviewWikiWriter) Live(text string) string { return "[orange]" + text + "[-]" }
+func (w *tviewWikiWriter) Good(text string) string { return "[green]" + text + "[-]" }
+func (w *tviewWikiWriter) Warn(text string) string { return "[yellow]" + text + "[-]" }
+func (w *tviewWikiWriter) Bad(text string) string  { return "[red]" + text + "[-]" }
+
+func (w *tviewWikiWriter) Link(key, text string) string {
+	if w.current != "" && key != w.current {
+		seen := false
+		for _, k := range w.links[w.current] {
+			if k == key {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			w.links[w.current] = append(w.links[w.current], key)
+		}
+	}
+	return fmt.Sprintf("[\"l:%s\"][yellow]%s[-][\"\"]", key, text)
+}
+
+func (w *tviewWikiWriter) String() string {
+	return strings.TrimRight(w.sb.String(), "\n")
+}
+
+// ===== Markdown =====
+
+type markdownWikiWriter struct {
+	sb     strings.Builder
+	links  map[string]string // entry key -> page key
+	inList bool
+}
+
+func newMarkdownWikiWriter(links map[string]string) *markdownWikiWriter {
+	return &markdownWikiWriter{links: links}
+}
+
+func (w *markdownWikiWriter) closeList() {
+	if w.inList {
+		w.sb.WriteString("\n")
+		w.inList = false
+	}
+}
+
+func (w *markdownWikiWriter) Title(text string) {
+	fmt.Fprintf(&w.sb, "# %s\n\n", text)
+}
+
+func (w *markdownWikiWriter) Heading(text string) {
+	w.closeList()
+	fmt.Fprintf(&w.sb, "### %s\n\n", text)
+}
+
+func (w *markdownWikiWriter) Section(text string) {
+	w.closeList()
+	fmt.Fprintf(&w.sb, "## %s\n\n", text)
+}
+
+func (w *markdownWikiWriter) Para(lines ...string) {
+	w.closeList()
+	w.sb.WriteString(strings.Join(lines, "\n") + "\n\n")
+}
+
+func (w *markdownWikiWriter) Bullet(text string) {
+	w.inList = true
+	fmt.Fprintf(&w.sb, "- %s\n", text)
+}
+
+func (w *markdownWikiWriter) Step(n int, text string) {
+	w.inList = true
+	fmt.Fprintf(&w.sb, "%d. %s\n", n, text)
+}
+
+func (w *markdownWikiWriter) Entry(name, key string, extra ...string) {
+	w.closeList()
+	if key != "" {
+		fmt.Fprintf(&w.sb, "<a id=\"%s\"></a>\n**%s** `%s`", key, name, key)
+	} else {
+		fmt.Fprintf(&w.sb, "**%s**", name)
+	}
+	for _, e := range extra {
+		w.sb.WriteString(" " + e)
+	}
+	w.sb.WriteString("\n\n")
+}
+
+func (w *markdownWikiWriter) Detail(text string) {
+	w.inList = true
+	fmt.Fprintf(&w.sb, "- %s\n", text)
+}
+
+func (w *markdownWikiWriter) Pre(lines ...string) {
+	w.closeList()
+	w.sb.WriteString("```\n" + strings.Join(lines, "\n") + "\n```\n\n")
+}
+
+func (w *markdownWikiWriter) Em(text string) string   { return "*" + text + "*" }
+func (w *markdownWikiWriter) Cmd(text string) string  { return "`" + text + "`" }
+func (w *markdownWikiWriter) Dim(text string) string  { return text }
+func (w *markdownWikiWriter) Live(text string) string { return "**" + text + "**" }
+func (w *markdownWikiWriter) Good(text string) string { return text }
+func (w *markdownWikiWriter) Warn(text string) string { return text }
+func (w *markdownWikiWriter) Bad(text string) string  { return text }
+
+func (w *markdownWikiWriter) Link(key, text string) string {
+	page, ok := w.links[key]
+	if !ok {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s.md#%s)", text, page, key)
+}
+
+func (w *markdownWikiWriter) String() string {
+	return strings.TrimRight(w.sb.String(), "\n") + "\n"
+}
+
+// ===== HTML =====
+
+// Inline spans are wrapped in these markers so block elements can escape the
+// surrounding plain text without mangling markup that is already escaped.
+const (
+	htmlSpanStart = "\x02"
+	htmlSpanEnd   = "\x03"
+)
+
+type htmlWikiWriter struct {
+	sb    strings.Builder
+	links map[string]string
+	list  string // open list tag ("ul"/"ol"), empty when none
+}
+
+func newHTMLWikiWriter(links map[string]string) *htmlWikiWriter {
+	return &htmlWikiWriter{links: links}
+}
+
+// text escapes plain text and unwraps inline spans
+func (w *htmlWikiWriter) text(s string) string {
+	var out strings.Builder
+	for {
+		i := strings.Index(s, htmlSpanStart)
+		if i < 0 {
+			out.WriteString(html.EscapeString(s))
+			break
+		}
+		out.WriteString(html.EscapeString(s[:i]))
+		s = s[i+len(htmlSpanStart):]
+		// Spans nest, so find the matching end marker
+		depth, j := 1, 0
+		for ; j < len(s) && depth > 0; j++ {
+			switch s[j] {
+			case htmlSpanStart[0]:
+				depth++
+			case htmlSpanEnd[0]:
+				depth--
+			}
+		}
+		out.WriteString(strings.NewReplacer(htmlSpanStart, "", htmlSpanEnd, "").Replace(s[:j-1]))
+		s = s[j:]
+	}
+	return out.String()
+}
+
+func (w *htmlWikiWriter) span(open, close, text string) string {
+	return htmlSpanStart + open + w.text(text) + close + htmlSpanEnd
+}
+
+func (w *htmlWikiWriter) openList(tag, class string) {
+	if w.list == tag {
+		return
+	}
+	w.closeList()
+	if class != "" {
+		fmt.Fprintf(&w.sb, "<%s class=\"%s\">\n", tag, class)
+	} else {
+		fmt.Fprintf(&w.sb, "<%s>\n", tag)
+	}
+	w.list = tag
+}
+
+func (w *htmlWikiWriter) closeList() {
+	if w.list != "" {
+		fmt.Fprintf(&w.sb, "</%s>\n", w.list)
+		w.list = ""
+	}
+}
+
+func (w *htmlWikiWriter) Title(text string) {
+	w.closeList()
+	fmt.Fprintf(&w.sb, "<h1>%s</h1>\n", w.text(text))
+}
+
+func (w *htmlWikiWriter) Heading(text string) {
+	w.closeList()
+	fmt.Fprintf(&w.sb, "<h3>%s</h3>\n", w.text(text))
+}
+
+func (w *htmlWikiWriter) Section(text string) {
+	w.closeList()
+	fmt.Fprintf(&w.sb, "<h2>%s</h2>\n", w.text(text))
+}
+
+func (w *htmlWikiWriter) Para(lines ...string) {
+	w.closeList()
+	fmt.Fprintf(&w.sb, "<p>%s</p>\n", w.text(strings.Join(lines, " ")))
+}
+
+func (w *htmlWikiWriter) Bullet(text string) {
+	w.openList("ul", "")
+	fmt.Fprintf(&w.sb, "<li>%s</li>\n", w.text(text))
+}
+
+func (w *htmlWikiWriter) Step(n int, text string) {
+	w.openList("ol", "")
+	fmt.Fprintf(&w.sb, "<li value=\"%d\">%s</li>\n", n, w.text(text))
+}
+
+func (w *htmlWikiWriter) Entry(name, key string, extra ...string) {
+	w.closeList()
+	if key != "" {
+		fmt.Fprintf(&w.sb, "<h4 id=\"%s\">%s <code>%s</code>", html.EscapeString(key), w.text(name), html.EscapeString(key))
+	} else {
+		fmt.Fprintf(&w.sb, "<h4>%s", w.text(name))
+	}
+	for _, e := range extra {
+		w.sb.WriteString(" " + w.text(e))
+	}
+	w.sb.WriteString("</h4>\n")
+}
+
+func (w *htmlWikiWriter) Detail(text string) {
+	w.openList("ul", "details")
+	fmt.Fprintf(&w.sb, "<li>%s</li>\n", w.text(text))
+}
+
+func (w *htmlWikiWriter) Pre(lines ...string) {
+	w.closeList()
+	fmt.Fprintf(&w.sb, "<pre>%s</pre>\n", w.text(strings.Join(lines, "\n")))
+}
+
+func (w *htmlWikiWriter) Em(text string) string  { return w.span("<em>", "</em>", text) }
+func (w *htmlWikiWriter) Cmd(text string) string { return w.span("<code>", "</code>", text) }
+func (w *htmlWikiWriter) Dim(text string) string {
+	return w.span(`<span class="dim">`, "</span>", text)
+}
+func (w *htmlWikiWriter) Live(text string) string {
+	return w.span(`<span class="live">`, "</span>", text)
+}
+func (w *htmlWikiWriter) Good(text string) string {
+	return w.span(`<span class="good">`, "</span>", text)
+}
+func (w *htmlWikiWriter) Warn(text string) string {
+	return w.span(`<span class="warn">`, "</span>", text)
+}
+func (w *htmlWikiWriter) Bad(text string) string {
+	return w.span(`<span class="bad">`, "</span>", text)
+}
+
+func (w *htmlWikiWriter) Link(key, text string) string {
+	page, ok := w.links[key]
+	if !ok {
+		return text
+	}
+	return w.span(fmt.Sprintf(`<a href="%s.html#%s">`, page, html.EscapeString(key)), "</a>", text)
+}
+
+func (w *htmlWikiWriter) String() string {
+	w.closeList()
+	return w.sb.String()
+}
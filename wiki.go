@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/user/ageforge/game"
+	"github.com/user/ageforge/ui"
+)
+
+// runWiki implements `ageforge wiki`: a static export of the in-game wiki
+func runWiki(args []string) int {
+	fs := flag.NewFlagSet("wiki", flag.ContinueOnError)
+	format := fs.String("format", "md", "output format: md or html")
+	out := fs.String("out", "wiki", "directory to write pages into")
+	save := fs.String("save", "", "load this save so pages include its live data")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ageforge wiki [--format md|html] [--out dir] [--save name]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	engine := game.NewGameEngine()
+	if *save != "" {
+		if err := engine.LoadGame(*save); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	files, err := ui.ExportWiki(engine.GetState(), *format, *out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Wrote %d pages to %s\n", len(files), *out)
+	return 0
+}